@@ -0,0 +1,134 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// recentAcceptances is the number of accepted blocks whose acceptance times
+// are kept for each chain
+const recentAcceptances = 1024
+
+// acceptance is when a block was accepted
+type acceptance struct {
+	blkID ids.ID
+	time  time.Time
+}
+
+// acceptTimes keeps the times the most recent [recentAcceptances] blocks or
+// vertices of a chain were accepted
+type acceptTimes struct {
+	lock sync.Mutex
+	ring [recentAcceptances]acceptance
+	// index in [ring] the next acceptance is stored at
+	next int
+	// number of acceptances stored in [ring]
+	size int
+}
+
+// Accept implements the triggers.Acceptor interface
+func (a *acceptTimes) Accept(_, containerID ids.ID, _ []byte) error {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	a.ring[a.next] = acceptance{
+		blkID: containerID,
+		time:  time.Now(),
+	}
+	a.next = (a.next + 1) % recentAcceptances
+	if a.size < recentAcceptances {
+		a.size++
+	}
+	return nil
+}
+
+// recent returns up to the [count] most recent acceptances, oldest first
+func (a *acceptTimes) recent(count int) []acceptance {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	if count > a.size {
+		count = a.size
+	}
+	acceptances := make([]acceptance, count)
+	for i := range acceptances {
+		acceptances[i] = a.ring[(a.next-count+i+recentAcceptances)%recentAcceptances]
+	}
+	return acceptances
+}
+
+// GetBlockTimesArgs are the arguments for calling GetBlockTimes
+type GetBlockTimesArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+
+	// Number of the most recently accepted blocks to return the acceptance
+	// times of, up to 1024
+	Count int `json:"count"`
+}
+
+// BlockTime is when a block was accepted
+type BlockTime struct {
+	BlockID string `json:"blockID"`
+	Time    string `json:"time"`
+}
+
+// GetBlockTimesReply are the results from calling GetBlockTimes
+type GetBlockTimesReply struct {
+	// The most recently accepted blocks, oldest first
+	Blocks []BlockTime `json:"blocks"`
+
+	// Time between the acceptance of each block and the block before it, such
+	// as "1.5s". There's one less interval than blocks.
+	Intervals []string `json:"intervals"`
+
+	// Average of [Intervals]. Empty if there are no intervals.
+	AverageInterval string `json:"averageInterval"`
+}
+
+// GetBlockTimes returns when the most recently accepted blocks of a chain were
+// accepted by this node, and the time between them. For a DAG, vertices are
+// returned. Only blocks accepted since the node started are known, so fewer
+// blocks than requested may be returned.
+func (service *Admin) GetBlockTimes(_ *http.Request, args *GetBlockTimesArgs, reply *GetBlockTimesReply) error {
+	service.log.Debug("Admin: GetBlockTimes called with %s %d", args.Chain, args.Count)
+
+	if args.Count <= 0 || args.Count > recentAcceptances {
+		return fmt.Errorf("count must be in [1, %d], but is %d", recentAcceptances, args.Count)
+	}
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+
+	reply.Blocks = []BlockTime{}
+	reply.Intervals = []string{}
+	chain, ok := service.chains.get(chainID)
+	if !ok {
+		// The chain hasn't been created yet, so it hasn't accepted anything
+		return nil
+	}
+
+	acceptances := chain.acceptTimes.recent(args.Count)
+	for i, acceptance := range acceptances {
+		reply.Blocks = append(reply.Blocks, BlockTime{
+			BlockID: acceptance.blkID.String(),
+			Time:    acceptance.time.UTC().Format(time.RFC3339Nano),
+		})
+		if i > 0 {
+			reply.Intervals = append(reply.Intervals, acceptance.time.Sub(acceptances[i-1].time).String())
+		}
+	}
+	if len(acceptances) > 1 {
+		total := acceptances[len(acceptances)-1].time.Sub(acceptances[0].time)
+		reply.AverageInterval = (total / time.Duration(len(acceptances)-1)).String()
+	}
+	return nil
+}
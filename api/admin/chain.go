@@ -4,9 +4,17 @@
 package admin
 
 import (
+	"context"
+	"fmt"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
+	"github.com/ava-labs/gecko/vms/rpcchainvm"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
 )
 
 // GetChainAliasesArgs are the arguments for Admin.GetChainAliases API call
@@ -25,3 +33,495 @@ func (service *Admin) GetChainAliases(r *http.Request, args *GetChainAliasesArgs
 	reply.Aliases = service.chainManager.Aliases(ID)
 	return nil
 }
+
+// VMChain identifies a chain running a VM whose memory usage is reported
+type VMChain struct {
+	ChainID ids.ID `json:"chainID"`
+	Alias   string `json:"alias"`
+}
+
+// VMMemory describes the estimated memory usage of a VM, summed over all the
+// chains running it
+type VMMemory struct {
+	// Import path of the package that defines the VM
+	VM     string       `json:"vm"`
+	Chains []VMChain    `json:"chains"`
+	Bytes  cjson.Uint64 `json:"bytes"`
+}
+
+// GetChainMemoryReply are the results from calling GetChainMemory
+type GetChainMemoryReply struct {
+	VMs               []VMMemory   `json:"vms"`
+	UnattributedBytes cjson.Uint64 `json:"unattributedBytes"`
+}
+
+// GetChainMemory returns an estimate of the heap memory used by each VM running
+// on this node, sorted from the most to the least memory used. The memory of
+// chains that run the same VM can't be told apart, so each VM's estimate is the
+// total over all the chains running it.
+//
+// The figures are estimates derived from the sampled heap profile as of the
+// most recent garbage collection, and should be used to compare VMs with each
+// other rather than as exact byte counts. See vmMemory for how memory is
+// attributed to VMs.
+func (service *Admin) GetChainMemory(_ *http.Request, _ *struct{}, reply *GetChainMemoryReply) error {
+	service.log.Debug("Admin: GetChainMemory called")
+
+	vmPackages, vmChains, bytes, unattributed := vmMemory(service.chains.list())
+
+	reply.VMs = make([]VMMemory, len(vmPackages))
+	for i, pkg := range vmPackages {
+		reply.VMs[i] = VMMemory{
+			VM:     pkg,
+			Chains: make([]VMChain, len(vmChains[i])),
+			Bytes:  cjson.Uint64(bytes[i]),
+		}
+		for j, chain := range vmChains[i] {
+			reply.VMs[i].Chains[j].ChainID = chain.ctx.ChainID
+			if aliases := service.chainManager.Aliases(chain.ctx.ChainID); len(aliases) > 0 {
+				reply.VMs[i].Chains[j].Alias = aliases[0]
+			}
+		}
+	}
+	sort.SliceStable(reply.VMs, func(i, j int) bool {
+		return reply.VMs[i].Bytes > reply.VMs[j].Bytes
+	})
+	reply.UnattributedBytes = cjson.Uint64(unattributed)
+	return nil
+}
+
+// PruneAliasesReply are the results from calling PruneAliases
+type PruneAliasesReply struct {
+	Aliases []string `json:"aliases"`
+}
+
+// PruneAliases removes the aliases this node created for the HTTP endpoints of
+// chains that aren't running, and returns the removed aliases. Aliases added
+// through the Admin API are never removed.
+func (service *Admin) PruneAliases(_ *http.Request, _ *struct{}, reply *PruneAliasesReply) error {
+	service.log.Debug("Admin: PruneAliases called")
+
+	// Until the chains that were waiting on the platform chain have been
+	// created, a chain that isn't running may just not have been created yet
+	if !service.chainManager.Unblocked() {
+		return errChainsNotCreated
+	}
+
+	running := ids.Set{}
+	for _, chain := range service.chains.list() {
+		running.Add(chain.ctx.ChainID)
+	}
+
+	aliases, err := service.httpServer.PruneChainAliasesWithReadLock(running.Contains)
+	reply.Aliases = aliases
+	return err
+}
+
+// ChainQueue describes the messages waiting to be processed by a chain's
+// consensus engine
+type ChainQueue struct {
+	ChainID ids.ID `json:"chainID"`
+	Alias   string `json:"alias"`
+
+	// Number of messages waiting to be processed
+	Depth cjson.Uint32 `json:"depth"`
+
+	// How long the oldest waiting message has been waiting for
+	OldestMessageAge string `json:"oldestMessageAge"`
+}
+
+// GetChainQueuesReply are the results from calling GetChainQueues
+type GetChainQueuesReply struct {
+	Chains []ChainQueue `json:"chains"`
+}
+
+// GetChainQueues returns the number of messages waiting to be processed by each
+// chain, and how long the oldest of them has been waiting for, sorted from the
+// deepest to the shallowest queue. A queue that keeps growing means the chain's
+// consensus engine can't keep up with its incoming messages.
+func (service *Admin) GetChainQueues(_ *http.Request, _ *struct{}, reply *GetChainQueuesReply) error {
+	service.log.Debug("Admin: GetChainQueues called")
+
+	now := time.Now()
+	queues := service.chainManager.Router().ChainQueues()
+	sort.Slice(queues, func(i, j int) bool {
+		if queues[i].Depth != queues[j].Depth {
+			return queues[i].Depth > queues[j].Depth
+		}
+		return queues[i].Oldest.Before(queues[j].Oldest)
+	})
+
+	reply.Chains = make([]ChainQueue, len(queues))
+	for i, queue := range queues {
+		age := time.Duration(0)
+		if queue.Depth > 0 {
+			age = now.Sub(queue.Oldest)
+		}
+		reply.Chains[i] = ChainQueue{
+			ChainID:          queue.ChainID,
+			Depth:            cjson.Uint32(queue.Depth),
+			OldestMessageAge: age.String(),
+		}
+		if aliases := service.chainManager.Aliases(queue.ChainID); len(aliases) > 0 {
+			reply.Chains[i].Alias = aliases[0]
+		}
+	}
+	return nil
+}
+
+// pluginVM is a VM that runs in a plugin process
+type pluginVM interface {
+	PluginStatus() rpcchainvm.PluginStatus
+}
+
+// PluginStatus describes the plugin process a chain's VM runs in
+type PluginStatus struct {
+	VMID    ids.ID `json:"vmID"`
+	ChainID ids.ID `json:"chainID"`
+	Alias   string `json:"alias"`
+
+	// ID of the plugin process, or 0 if it isn't known
+	PID cjson.Uint32 `json:"pid"`
+
+	// True iff the plugin process hasn't exited
+	Alive bool `json:"alive"`
+
+	// Number of times the plugin process was restarted. Crashed plugins
+	// aren't restarted, so this is always 0. A chain whose plugin isn't alive
+	// has stopped.
+	Restarts cjson.Uint32 `json:"restarts"`
+}
+
+// GetPluginStatusReply are the results from calling GetPluginStatus
+type GetPluginStatusReply struct {
+	Plugins []PluginStatus `json:"plugins"`
+}
+
+// GetPluginStatus returns the status of the plugin process of every chain whose
+// VM runs as a plugin
+func (service *Admin) GetPluginStatus(_ *http.Request, _ *struct{}, reply *GetPluginStatusReply) error {
+	service.log.Debug("Admin: GetPluginStatus called")
+
+	reply.Plugins = []PluginStatus{}
+	for _, chain := range service.chains.list() {
+		vm, ok := chain.vm.(pluginVM)
+		if !ok {
+			continue
+		}
+		status := vm.PluginStatus()
+		plugin := PluginStatus{
+			ChainID: chain.ctx.ChainID,
+			PID:     cjson.Uint32(status.PID),
+			Alive:   status.Alive,
+		}
+		if vmID, ok := service.chainManager.ChainVM(chain.ctx.ChainID); ok {
+			plugin.VMID = vmID
+		}
+		if aliases := service.chainManager.Aliases(chain.ctx.ChainID); len(aliases) > 0 {
+			plugin.Alias = aliases[0]
+		}
+		reply.Plugins = append(reply.Plugins, plugin)
+	}
+	return nil
+}
+
+// routeListingVM is a VM that runs in a plugin process and can list the routes
+// its HTTP handlers serve
+type routeListingVM interface {
+	Routes(context.Context) ([]rpcchainvm.HandlerRoutes, error)
+}
+
+// GetPluginRoutesArgs are the arguments for calling GetPluginRoutes
+type GetPluginRoutesArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// HandlerRoutes describes the routes served by one of a chain's HTTP handlers
+type HandlerRoutes struct {
+	// Extension of the chain's endpoint the handler serves
+	Endpoint string `json:"endpoint"`
+
+	// False if the handler doesn't list its routes, in which case Routes is
+	// empty
+	Introspectable bool `json:"introspectable"`
+
+	// Patterns of the routes the handler serves
+	Routes []string `json:"routes"`
+}
+
+// GetPluginRoutesReply are the results from calling GetPluginRoutes
+type GetPluginRoutesReply struct {
+	Handlers []HandlerRoutes `json:"handlers"`
+}
+
+// GetPluginRoutes returns the routes served by the HTTP handlers of a chain
+// whose VM runs as a plugin. A handler can list its routes if it implements
+// ghttp.RouteLister.
+func (service *Admin) GetPluginRoutes(r *http.Request, args *GetPluginRoutesArgs, reply *GetPluginRoutesReply) error {
+	service.log.Debug("Admin: GetPluginRoutes called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	chain, ok := service.chains.get(chainID)
+	if !ok {
+		return fmt.Errorf("chain %s hasn't been created", chainID)
+	}
+	vm, ok := chain.vm.(routeListingVM)
+	if !ok {
+		return fmt.Errorf("the VM of chain %s doesn't run as a plugin", chainID)
+	}
+	handlers, err := vm.Routes(r.Context())
+	if err != nil {
+		return fmt.Errorf("couldn't get the routes of chain %s: %w", chainID, err)
+	}
+
+	reply.Handlers = make([]HandlerRoutes, len(handlers))
+	for i, handler := range handlers {
+		routes := handler.Patterns
+		if routes == nil {
+			routes = []string{}
+		}
+		reply.Handlers[i] = HandlerRoutes{
+			Endpoint:       handler.Prefix,
+			Introspectable: handler.Introspectable,
+			Routes:         routes,
+		}
+	}
+	return nil
+}
+
+// Statuses returned by GetBootstrapProgress
+const (
+	BootstrapStatusBootstrapping = "bootstrapping"
+	BootstrapStatusBootstrapped  = "bootstrapped"
+)
+
+// GetBootstrapProgressArgs are the arguments for calling GetBootstrapProgress
+type GetBootstrapProgressArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// GetBootstrapProgressReply are the results from calling GetBootstrapProgress
+type GetBootstrapProgressReply struct {
+	// Either "bootstrapping" or "bootstrapped"
+	Status string `json:"status"`
+
+	// Estimated percentage of bootstrapping that has been completed
+	Percent float64 `json:"percent"`
+
+	// Number of blocks or vertices that have been fetched
+	BlocksFetched cjson.Uint64 `json:"blocksFetched"`
+
+	// Estimated number of blocks or vertices that still need to be fetched, or
+	// once fetching has finished, of blocks or transactions that still need
+	// to be executed
+	BlocksRemaining cjson.Uint64 `json:"blocksRemaining"`
+
+	// True iff the percentage and the number remaining are estimated. While
+	// fetching, they're only estimated once enough is known about the chain.
+	Estimated bool `json:"estimated"`
+}
+
+// GetBootstrapProgress returns an estimate of how far along a chain is in
+// bootstrapping.
+//
+// Bootstrapping fetches the chain's containers, then executes them; each is
+// counted as half of the progress. The number of containers left to fetch is
+// estimated from the heights of the vertices or blocks fetched so far. A
+// snowman chain whose blocks don't expose their height isn't estimated until
+// it has fetched every block. The percentage only grows, and is 100% once
+// bootstrapping finishes.
+func (service *Admin) GetBootstrapProgress(_ *http.Request, args *GetBootstrapProgressArgs, reply *GetBootstrapProgressReply) error {
+	service.log.Debug("Admin: GetBootstrapProgress called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	progress, ok := service.chainManager.Router().BootstrapProgress(chainID)
+	if !ok {
+		return fmt.Errorf("chain %s doesn't report its bootstrap progress", chainID)
+	}
+	reply.Status = BootstrapStatusBootstrapping
+	if progress.Bootstrapped {
+		reply.Status = BootstrapStatusBootstrapped
+	}
+	reply.Percent = progress.Percent
+	reply.BlocksFetched = cjson.Uint64(progress.Fetched)
+	reply.BlocksRemaining = cjson.Uint64(progress.Remaining)
+	reply.Estimated = progress.Estimated
+	return nil
+}
+
+// GetTxThroughputArgs are the arguments for calling GetTxThroughput
+type GetTxThroughputArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// GetTxThroughputReply are the results from calling GetTxThroughput
+type GetTxThroughputReply struct {
+	LastMinute    cjson.Uint64 `json:"lastMinute"`
+	Last5Minutes  cjson.Uint64 `json:"last5Minutes"`
+	Last15Minutes cjson.Uint64 `json:"last15Minutes"`
+}
+
+// GetTxThroughput returns the number of transactions a chain accepted in the
+// last 1, 5 and 15 minutes. For a linear chain, the transactions in the
+// accepted blocks are counted. A block is counted as a single transaction if
+// its VM doesn't report how many transactions its blocks contain. The counts
+// are kept in 10 second buckets, so each window may include up to 10 seconds
+// more than its length.
+func (service *Admin) GetTxThroughput(_ *http.Request, args *GetTxThroughputArgs, reply *GetTxThroughputReply) error {
+	service.log.Debug("Admin: GetTxThroughput called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	chain, ok := service.chains.get(chainID)
+	if !ok {
+		// The chain hasn't been created yet, so it hasn't accepted anything
+		return nil
+	}
+	reply.LastMinute = cjson.Uint64(chain.accepted.TicksIn(time.Minute))
+	reply.Last5Minutes = cjson.Uint64(chain.accepted.TicksIn(5 * time.Minute))
+	reply.Last15Minutes = cjson.Uint64(chain.accepted.TicksIn(15 * time.Minute))
+	return nil
+}
+
+// ChainCreationError describes a chain that failed to be created
+type ChainCreationError struct {
+	ChainID string `json:"chainID"`
+	VMID    string `json:"vmID"`
+	Error   string `json:"error"`
+
+	// Time the chain failed to be created at, in RFC 3339 format
+	Time string `json:"time"`
+}
+
+// GetChainCreationErrorsReply are the results from calling
+// GetChainCreationErrors
+type GetChainCreationErrorsReply struct {
+	Chains []ChainCreationError `json:"chains"`
+}
+
+// GetChainCreationErrors returns the chains that failed to be created since
+// the node started, and why, in the order they failed. Only the most recent
+// 100 failures are returned.
+func (service *Admin) GetChainCreationErrors(_ *http.Request, _ *struct{}, reply *GetChainCreationErrorsReply) error {
+	service.log.Debug("Admin: GetChainCreationErrors called")
+
+	creationErrors := service.chainManager.CreationErrors()
+	reply.Chains = make([]ChainCreationError, len(creationErrors))
+	for i, creationError := range creationErrors {
+		reply.Chains[i] = ChainCreationError{
+			ChainID: creationError.ChainID.String(),
+			VMID:    creationError.VMID,
+			Error:   creationError.Error,
+			Time:    creationError.Time.UTC().Format(time.RFC3339),
+		}
+	}
+	return nil
+}
+
+// GetConsensusParamsArgs are the arguments for calling GetConsensusParams
+type GetConsensusParamsArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// ConsensusParams are the snowball parameters a chain runs consensus with
+type ConsensusParams struct {
+	K                 cjson.Uint32 `json:"k"`
+	Alpha             cjson.Uint32 `json:"alpha"`
+	BetaVirtuous      cjson.Uint32 `json:"betaVirtuous"`
+	BetaRogue         cjson.Uint32 `json:"betaRogue"`
+	ConcurrentRepolls cjson.Uint32 `json:"concurrentRepolls"`
+}
+
+func newConsensusParams(params snowball.Parameters) ConsensusParams {
+	return ConsensusParams{
+		K:                 cjson.Uint32(params.K),
+		Alpha:             cjson.Uint32(params.Alpha),
+		BetaVirtuous:      cjson.Uint32(params.BetaVirtuous),
+		BetaRogue:         cjson.Uint32(params.BetaRogue),
+		ConcurrentRepolls: cjson.Uint32(params.ConcurrentRepolls),
+	}
+}
+
+// GetConsensusParams returns the snowball parameters a chain runs consensus
+// with
+func (service *Admin) GetConsensusParams(_ *http.Request, args *GetConsensusParamsArgs, reply *ConsensusParams) error {
+	service.log.Debug("Admin: GetConsensusParams called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	params, ok := service.chainManager.Router().ConsensusParameters(chainID)
+	if !ok {
+		return fmt.Errorf("couldn't get the consensus parameters of chain %q", args.Chain)
+	}
+	*reply = newConsensusParams(params)
+	return nil
+}
+
+// SetConsensusParamsArgs are the arguments for calling SetConsensusParams
+type SetConsensusParamsArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+
+	K            cjson.Uint32 `json:"k"`
+	Alpha        cjson.Uint32 `json:"alpha"`
+	BetaVirtuous cjson.Uint32 `json:"betaVirtuous"`
+	BetaRogue    cjson.Uint32 `json:"betaRogue"`
+
+	// If 0, the chain's current number of concurrent repolls is kept
+	ConcurrentRepolls cjson.Uint32 `json:"concurrentRepolls"`
+}
+
+// SetConsensusParams changes the snowball parameters a chain runs consensus
+// with, and returns the parameters that are now in effect. Polls that are
+// already running, and blocks that are already being decided on, keep the
+// parameters they were started with.
+//
+// The parameters must be valid: K/2 < Alpha <= K, 0 < BetaVirtuous <=
+// BetaRogue and 0 < ConcurrentRepolls <= BetaRogue. Changing the parameters of
+// a live network can halt it or make it unsafe, so this is only allowed if the
+// node was started with --api-admin-consensus-tuning-enabled.
+func (service *Admin) SetConsensusParams(_ *http.Request, args *SetConsensusParamsArgs, reply *ConsensusParams) error {
+	service.log.Info("Admin: SetConsensusParams called with %s k=%d alpha=%d betaVirtuous=%d betaRogue=%d concurrentRepolls=%d",
+		args.Chain, args.K, args.Alpha, args.BetaVirtuous, args.BetaRogue, args.ConcurrentRepolls)
+
+	if !service.consensusTuningEnabled {
+		return errConsensusTuningDisabled
+	}
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	router := service.chainManager.Router()
+	params, ok := router.ConsensusParameters(chainID)
+	if !ok {
+		return fmt.Errorf("couldn't get the consensus parameters of chain %q", args.Chain)
+	}
+
+	params.K = int(args.K)
+	params.Alpha = int(args.Alpha)
+	params.BetaVirtuous = int(args.BetaVirtuous)
+	params.BetaRogue = int(args.BetaRogue)
+	if args.ConcurrentRepolls != 0 {
+		params.ConcurrentRepolls = int(args.ConcurrentRepolls)
+	}
+	if err := router.SetConsensusParameters(chainID, params); err != nil {
+		return fmt.Errorf("couldn't set the consensus parameters of chain %q: %w", args.Chain, err)
+	}
+	*reply = newConsensusParams(params)
+	return nil
+}
@@ -0,0 +1,77 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils/logging"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// problemLogger is implemented by the loggers that count the errors and
+// warnings they're asked to log
+type problemLogger interface {
+	Problems() logging.Problems
+}
+
+// ChainErrors describes the errors and warnings a chain logged
+type ChainErrors struct {
+	ChainID ids.ID `json:"chainID"`
+	Alias   string `json:"alias"`
+
+	// Number of errors and warnings the chain logged since the node started,
+	// including those filtered out by the log level
+	Errors   cjson.Uint64 `json:"errors"`
+	Warnings cjson.Uint64 `json:"warnings"`
+
+	// The most recent error the chain logged, and when it was logged. Empty if
+	// the chain hasn't logged any errors.
+	LastError     string `json:"lastError"`
+	LastErrorTime string `json:"lastErrorTime"`
+}
+
+// GetChainErrorsReply are the results from calling GetChainErrors
+type GetChainErrorsReply struct {
+	// Sorted from the most to the fewest errors
+	Chains []ChainErrors `json:"chains"`
+}
+
+// GetChainErrors returns how many errors and warnings each chain running on
+// this node logged, and the most recent error each logged, so that chains that
+// are having problems can be found without searching their logs. Chains whose
+// loggers don't count their errors are left out.
+func (service *Admin) GetChainErrors(_ *http.Request, _ *struct{}, reply *GetChainErrorsReply) error {
+	service.log.Debug("Admin: GetChainErrors called")
+
+	reply.Chains = []ChainErrors{}
+	for _, chain := range service.chains.list() {
+		log, ok := chain.ctx.Log.(problemLogger)
+		if !ok {
+			continue
+		}
+		problems := log.Problems()
+		chainErrors := ChainErrors{
+			ChainID:   chain.ctx.ChainID,
+			Errors:    cjson.Uint64(problems.Errors),
+			Warnings:  cjson.Uint64(problems.Warnings),
+			LastError: problems.LastError,
+		}
+		if aliases := service.chainManager.Aliases(chain.ctx.ChainID); len(aliases) > 0 {
+			chainErrors.Alias = aliases[0]
+		}
+		if !problems.LastErrorTime.IsZero() {
+			chainErrors.LastErrorTime = problems.LastErrorTime.UTC().Format(time.RFC3339)
+		}
+		reply.Chains = append(reply.Chains, chainErrors)
+	}
+	sort.SliceStable(reply.Chains, func(i, j int) bool {
+		return reply.Chains[i].Errors > reply.Chains[j].Errors
+	})
+	return nil
+}
@@ -0,0 +1,95 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/gecko/database"
+)
+
+var (
+	errCompactionInProgress = errors.New("a compaction is already running")
+)
+
+// GetCompactionStatusReply are the results from calling GetCompactionStatus
+type GetCompactionStatusReply struct {
+	InProgress bool `json:"inProgress"`
+
+	// Estimate, between 0 and 1, of how much of the running compaction is
+	// done
+	Progress float64 `json:"progress"`
+
+	// Time the most recent compaction finished at. Empty if no compaction has
+	// finished since the node started.
+	LastCompleted string `json:"lastCompleted"`
+}
+
+// GetCompactionStatus returns whether the node's database is being compacted,
+// how far along the compaction is, and when the last compaction finished, so
+// that latency spikes can be correlated with compactions. Only compactions
+// requested of the database are reported, not those it runs in the
+// background on its own.
+func (service *Admin) GetCompactionStatus(_ *http.Request, _ *struct{}, reply *GetCompactionStatusReply) error {
+	service.log.Debug("Admin: GetCompactionStatus called")
+
+	db, ok := service.db.(database.CompactionReporter)
+	if !ok {
+		return fmt.Errorf("couldn't get the compaction status: %w", database.ErrNotSupported)
+	}
+	status := db.CompactionStatus()
+	reply.InProgress = status.InProgress
+	reply.Progress = status.Progress
+	if !status.LastCompleted.IsZero() {
+		reply.LastCompleted = status.LastCompleted.UTC().Format(time.RFC3339)
+	}
+	return nil
+}
+
+// TriggerCompactionReply are the results from calling TriggerCompaction
+type TriggerCompactionReply struct {
+	Success bool `json:"success"`
+}
+
+// TriggerCompaction starts compacting the node's entire database, such as
+// during a maintenance window, and returns without waiting for it to finish.
+// Its progress can be followed with GetCompactionStatus. The compaction is
+// refused if one is already running.
+func (service *Admin) TriggerCompaction(_ *http.Request, _ *struct{}, reply *TriggerCompactionReply) error {
+	service.log.Info("Admin: TriggerCompaction called")
+
+	db, ok := service.db.(database.CompactionReporter)
+	if !ok {
+		return fmt.Errorf("couldn't compact the database: %w", database.ErrNotSupported)
+	}
+
+	service.compactionLock.Lock()
+	defer service.compactionLock.Unlock()
+
+	if service.compacting || db.CompactionStatus().InProgress {
+		return errCompactionInProgress
+	}
+	service.compacting = true
+
+	go func() {
+		start := time.Now()
+		err := service.db.Compact(nil, nil)
+
+		service.compactionLock.Lock()
+		service.compacting = false
+		service.compactionLock.Unlock()
+
+		if err != nil {
+			service.log.Error("compacting the database failed: %s", err)
+			return
+		}
+		service.log.Info("compacting the database took %s", time.Since(start))
+	}()
+
+	reply.Success = true
+	return nil
+}
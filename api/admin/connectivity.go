@@ -0,0 +1,69 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/ava-labs/gecko/ids"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// ValidatorConnectivity describes how well connected this node has been to a
+// validator
+type ValidatorConnectivity struct {
+	NodeID ids.ShortID `json:"nodeID"`
+
+	// True if this node is currently connected to the validator
+	Connected bool `json:"connected"`
+
+	// Fraction of the last hour this node was connected to the validator,
+	// between 0 and 1
+	ConnectedFraction float64 `json:"connectedFraction"`
+
+	// Number of samples the fraction was computed from. Connectivity is
+	// sampled every 10 seconds, so an hour is 360 samples. Fewer are available
+	// if this node, or the validator, is new.
+	Samples cjson.Uint32 `json:"samples"`
+}
+
+// GetValidatorConnectivityReply are the results from calling
+// GetValidatorConnectivity
+type GetValidatorConnectivityReply struct {
+	// The primary network's validators, least connected first. This node
+	// isn't included.
+	Validators []ValidatorConnectivity `json:"validators"`
+}
+
+// GetValidatorConnectivity returns, for each of the primary network's
+// validators, whether this node is connected to it and the fraction of the
+// last hour it was connected to it. Validators this node keeps losing its
+// connection to are listed first, since they can slow down finalization.
+func (service *Admin) GetValidatorConnectivity(_ *http.Request, _ *struct{}, reply *GetValidatorConnectivityReply) error {
+	service.log.Debug("Admin: GetValidatorConnectivity called")
+
+	connectivity := service.networking.ValidatorConnectivity()
+	sort.Slice(connectivity, func(i, j int) bool {
+		if connectivity[i].ConnectedFraction != connectivity[j].ConnectedFraction {
+			return connectivity[i].ConnectedFraction < connectivity[j].ConnectedFraction
+		}
+		if connectivity[i].Connected != connectivity[j].Connected {
+			return !connectivity[i].Connected
+		}
+		return connectivity[i].ID.String() < connectivity[j].ID.String()
+	})
+
+	reply.Validators = make([]ValidatorConnectivity, len(connectivity))
+	for i, vdr := range connectivity {
+		reply.Validators[i] = ValidatorConnectivity{
+			NodeID:            vdr.ID,
+			Connected:         vdr.Connected,
+			ConnectedFraction: vdr.ConnectedFraction,
+			Samples:           cjson.Uint32(vdr.Samples),
+		}
+	}
+	return nil
+}
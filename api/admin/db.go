@@ -0,0 +1,219 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/database/prefixdb"
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+var (
+	// The probe's key is written under its own prefix, so it can't collide
+	// with keys written by anything else
+	dbProbePrefix = []byte("admin db health probe")
+	dbProbeKey    = []byte("probe")
+	dbProbeValue  = []byte("the quick brown fox jumps over the lazy dog")
+
+	errDBProbeMismatch = errors.New("read back a different value than was written")
+
+	// nodeDBPrefixes are the prefixes of the databases the node keeps state
+	// that doesn't belong to a chain in
+	nodeDBPrefixes = []string{"keystore", "shared memory"}
+)
+
+// GetDBHealthReply are the results from calling GetDBHealth
+type GetDBHealthReply struct {
+	// Time it took to write a small value to the database
+	WriteLatency string `json:"writeLatency"`
+
+	// Time it took to read the value back
+	ReadLatency string `json:"readLatency"`
+
+	// Number of bytes available to the node on the database's volume. 0 if
+	// the database is held in memory, or the free space can't be determined on
+	// this platform.
+	FreeBytes cjson.Uint64 `json:"freeBytes"`
+}
+
+// GetDBHealth probes the node's database by writing a small value to it,
+// reading the value back and deleting it, and reports how long the write and
+// read took along with the free space on the database's volume
+func (service *Admin) GetDBHealth(_ *http.Request, _ *struct{}, reply *GetDBHealthReply) error {
+	service.log.Debug("Admin: GetDBHealth called")
+
+	// Concurrent probes would use the same key
+	service.dbProbeLock.Lock()
+	defer service.dbProbeLock.Unlock()
+
+	db := prefixdb.New(dbProbePrefix, service.db)
+
+	start := time.Now()
+	if err := db.Put(dbProbeKey, dbProbeValue); err != nil {
+		return fmt.Errorf("couldn't write to the database: %w", err)
+	}
+	reply.WriteLatency = time.Since(start).String()
+	defer func() {
+		if err := db.Delete(dbProbeKey); err != nil {
+			service.log.Error("couldn't delete the database health probe: %s", err)
+		}
+	}()
+
+	start = time.Now()
+	value, err := db.Get(dbProbeKey)
+	if err != nil {
+		return fmt.Errorf("couldn't read from the database: %w", err)
+	}
+	reply.ReadLatency = time.Since(start).String()
+	if !bytes.Equal(value, dbProbeValue) {
+		return errDBProbeMismatch
+	}
+
+	if service.dbPath != "" {
+		freeBytes, err := freeDiskSpace(service.dbPath)
+		if err != nil {
+			service.log.Debug("couldn't get the free space of %s: %s", service.dbPath, err)
+		}
+		reply.FreeBytes = cjson.Uint64(freeBytes)
+	}
+	return nil
+}
+
+// GetDBUsageArgs are the arguments for calling GetDBUsage
+type GetDBUsageArgs struct {
+	// Exact, if true, causes the size of each prefix to be measured by reading
+	// every key in it, which may take a long time. Otherwise, the database's
+	// size estimates are used if it supports them.
+	Exact bool `json:"exact"`
+}
+
+// DBUsage is the space taken up by the keys under a database prefix
+type DBUsage struct {
+	Prefix string `json:"prefix"`
+
+	// ID of the chain the prefix belongs to. Empty if the prefix belongs to
+	// the node.
+	ChainID string `json:"chainID"`
+
+	Bytes cjson.Uint64 `json:"bytes"`
+}
+
+// GetDBUsageReply are the results from calling GetDBUsage
+type GetDBUsageReply struct {
+	// True if the sizes are estimates of the space taken up on disk, which
+	// may not include recent writes. Otherwise, the sizes are the total
+	// lengths of the keys and values, before compression.
+	Approximate bool `json:"approximate"`
+
+	// Sorted by size, largest first. Empty prefixes are omitted.
+	Usage []DBUsage `json:"usage"`
+}
+
+// GetDBUsage returns the space taken up by each of the prefixes the node and
+// its chains store their state under
+func (service *Admin) GetDBUsage(_ *http.Request, args *GetDBUsageArgs, reply *GetDBUsageReply) error {
+	service.log.Debug("Admin: GetDBUsage called with exact: %v", args.Exact)
+
+	_, estimable := service.db.(database.SizeEstimator)
+	reply.Approximate = estimable && !args.Exact
+
+	reply.Usage = []DBUsage{}
+	addUsage := func(prefix, chainID string, db *prefixdb.Database) error {
+		size, err := dbSize(db, reply.Approximate)
+		if err != nil {
+			return fmt.Errorf("couldn't get the size of prefix %q: %w", prefix, err)
+		}
+		if size > 0 {
+			reply.Usage = append(reply.Usage, DBUsage{
+				Prefix:  prefix,
+				ChainID: chainID,
+				Bytes:   cjson.Uint64(size),
+			})
+		}
+		return nil
+	}
+
+	for _, prefix := range nodeDBPrefixes {
+		if err := addUsage(prefix, "", prefixdb.New([]byte(prefix), service.db)); err != nil {
+			return err
+		}
+	}
+	for _, chain := range service.chains.list() {
+		chainID := chain.ctx.ChainID
+		chainDB := prefixdb.New(chainID.Bytes(), service.db)
+		for _, prefix := range chains.DBPrefixes {
+			if err := addUsage(prefix, chainID.String(), prefixdb.New([]byte(prefix), chainDB)); err != nil {
+				return err
+			}
+		}
+	}
+
+	sort.SliceStable(reply.Usage, func(i, j int) bool { return reply.Usage[i].Bytes > reply.Usage[j].Bytes })
+	return nil
+}
+
+// dbSize returns the size of [db], estimated by the underlying database if
+// [approximate], or otherwise the total length of its keys and values
+func dbSize(db *prefixdb.Database, approximate bool) (uint64, error) {
+	if approximate {
+		return db.EstimateSize(nil)
+	}
+
+	iter := db.NewIterator()
+	defer iter.Release()
+
+	size := uint64(0)
+	for iter.Next() {
+		size += uint64(len(iter.Key()) + len(iter.Value()))
+	}
+	return size, iter.Error()
+}
+
+// DBCacheSize is the size of the database's read cache
+type DBCacheSize struct {
+	// Capacity of the cache in bytes
+	Size cjson.Uint64 `json:"size"`
+}
+
+// GetDBCacheSize returns the capacity of the database's read cache
+func (service *Admin) GetDBCacheSize(_ *http.Request, _ *struct{}, reply *DBCacheSize) error {
+	service.log.Debug("Admin: GetDBCacheSize called")
+
+	db, ok := service.db.(database.CacheResizer)
+	if !ok {
+		return fmt.Errorf("couldn't get the cache size: %w", database.ErrNotSupported)
+	}
+	reply.Size = cjson.Uint64(db.CacheSize())
+	return nil
+}
+
+// SetDBCacheSize changes the capacity of the database's read cache, which
+// trades memory for read performance, and returns the capacity now in effect.
+// Shrinking the cache evicts entries right away. The size can't be set below
+// the database's minimum. The change lasts until the node restarts.
+func (service *Admin) SetDBCacheSize(_ *http.Request, args *DBCacheSize, reply *DBCacheSize) error {
+	service.log.Info("Admin: SetDBCacheSize called with %d", args.Size)
+
+	db, ok := service.db.(database.CacheResizer)
+	if !ok {
+		return fmt.Errorf("couldn't set the cache size: %w", database.ErrNotSupported)
+	}
+	size := int(args.Size)
+	if size < 0 || uint64(size) != uint64(args.Size) {
+		return fmt.Errorf("cache size %d is too large", args.Size)
+	}
+	if err := db.SetCacheSize(size); err != nil {
+		return fmt.Errorf("couldn't set the cache size: %w", err)
+	}
+	reply.Size = cjson.Uint64(db.CacheSize())
+	return nil
+}
@@ -0,0 +1,45 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// GetDisconnectReasonsReply are the results from calling GetDisconnectReasons.
+// Each window maps why peers disconnected to how many did. Reasons no peer
+// disconnected for are omitted.
+type GetDisconnectReasonsReply struct {
+	LastMinute    map[string]cjson.Uint64 `json:"lastMinute"`
+	Last5Minutes  map[string]cjson.Uint64 `json:"last5Minutes"`
+	Last15Minutes map[string]cjson.Uint64 `json:"last15Minutes"`
+}
+
+// GetDisconnectReasons returns why peers disconnected in the last 1, 5 and 15
+// minutes, such as "closed by peer", "timeout", "read error", "write error",
+// "invalid message", "incompatible" (another network or version), "clock
+// skew", "self" or "shutdown". Peers that disconnected during the version
+// handshake are included, so the counts may exceed the disconnects reported by
+// GetPeerChurn. The counts are kept in 10 second buckets, so each window may
+// include up to 10 seconds more than its length.
+func (service *Admin) GetDisconnectReasons(_ *http.Request, _ *struct{}, reply *GetDisconnectReasonsReply) error {
+	service.log.Debug("Admin: GetDisconnectReasons called")
+
+	reply.LastMinute = service.disconnectReasons(time.Minute)
+	reply.Last5Minutes = service.disconnectReasons(5 * time.Minute)
+	reply.Last15Minutes = service.disconnectReasons(15 * time.Minute)
+	return nil
+}
+
+func (service *Admin) disconnectReasons(window time.Duration) map[string]cjson.Uint64 {
+	reasons := service.networking.DisconnectReasons(window)
+	counts := make(map[string]cjson.Uint64, len(reasons))
+	for reason, count := range reasons {
+		counts[reason] = cjson.Uint64(count)
+	}
+	return counts
+}
@@ -0,0 +1,14 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+)
+
+var errFreeDiskSpaceUnsupported = errors.New("free disk space isn't supported on windows")
+
+// freeDiskSpace returns the number of bytes available to this process on the
+// volume [path] is on
+func freeDiskSpace(string) (uint64, error) { return 0, errFreeDiskSpaceUnsupported }
@@ -0,0 +1,76 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// minFinalizationSamples is the number of accepted decisions a chain must have
+// issued before the percentiles of their latencies are reported. With fewer,
+// the high percentiles would just be the slowest few decisions.
+const minFinalizationSamples = 100
+
+// GetFinalizationLatencyArgs are the arguments for calling
+// GetFinalizationLatency
+type GetFinalizationLatencyArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// GetFinalizationLatencyReply are the results from calling
+// GetFinalizationLatency
+type GetFinalizationLatencyReply struct {
+	// Number of recently accepted decisions the percentiles are of
+	Samples cjson.Uint32 `json:"samples"`
+
+	// True iff there were enough samples for the percentiles to be reported
+	Sufficient bool `json:"sufficient"`
+
+	// Explains why the percentiles weren't reported, if they weren't
+	Message string `json:"message,omitempty"`
+
+	// Percentiles of the time from a decision being issued into consensus to
+	// it being accepted
+	P50 string `json:"p50,omitempty"`
+	P95 string `json:"p95,omitempty"`
+	P99 string `json:"p99,omitempty"`
+}
+
+// GetFinalizationLatency returns percentiles of how long the blocks, or for
+// DAG based chains the transactions, that a chain most recently accepted took
+// to be accepted after being issued into consensus. Up to the last 1024
+// decisions are considered. Decisions accepted while the chain was
+// bootstrapping weren't issued, so they aren't considered, and the percentiles
+// aren't reported until enough decisions have been accepted since.
+func (service *Admin) GetFinalizationLatency(_ *http.Request, args *GetFinalizationLatencyArgs, reply *GetFinalizationLatencyReply) error {
+	service.log.Debug("Admin: GetFinalizationLatency called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	latencies, ok := service.chainManager.Router().AcceptLatencies(chainID)
+	if !ok {
+		return fmt.Errorf("couldn't get the finalization latencies of chain %q", args.Chain)
+	}
+
+	reply.Samples = cjson.Uint32(len(latencies))
+	if len(latencies) < minFinalizationSamples {
+		reply.Message = fmt.Sprintf("only %d decisions have been accepted since bootstrapping, at least %d are needed",
+			len(latencies), minFinalizationSamples)
+		return nil
+	}
+
+	percentiles := timer.Percentiles(latencies, 50, 95, 99)
+	reply.Sufficient = true
+	reply.P50 = percentiles[0].String()
+	reply.P95 = percentiles[1].String()
+	reply.P99 = percentiles[2].String()
+	return nil
+}
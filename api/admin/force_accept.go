@@ -0,0 +1,108 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/gecko/genesis"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+)
+
+var (
+	errForceAcceptDisabled  = errors.New("force accepting blocks is disabled")
+	errForceAcceptOnMainnet = errors.New("force accepting blocks isn't allowed on mainnet")
+	errNotLinearChain       = errors.New("only blocks of linear chains can be force accepted")
+)
+
+// blockVM is implemented by the VMs of linear chains
+type blockVM interface {
+	GetBlock(ids.ID) (snowman.Block, error)
+	SetPreference(ids.ID)
+	LastAccepted() ids.ID
+}
+
+// ForceAcceptArgs are the arguments for calling ForceAccept
+type ForceAcceptArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+
+	BlockID string `json:"blockID"`
+}
+
+// ForceAcceptReply are the results from calling ForceAccept
+type ForceAcceptReply struct {
+	Success bool `json:"success"`
+}
+
+// ForceAccept accepts a block of a linear chain without waiting for consensus,
+// to recover a test network whose consensus is stuck. The block must already
+// be known to the VM, not yet be decided, be a child of the last accepted
+// block and pass verification; unknown blocks are never accepted.
+//
+// The chain's consensus engine isn't told about the block, so it may still
+// hold conflicting blocks. The node should be restarted once the network has
+// recovered. This is only allowed if the node was started with
+// --api-admin-unsafe-force-accept-enabled, and never on mainnet.
+func (service *Admin) ForceAccept(_ *http.Request, args *ForceAcceptArgs, reply *ForceAcceptReply) error {
+	service.log.Info("Admin: ForceAccept called with %s %s", args.Chain, args.BlockID)
+
+	if !service.forceAcceptEnabled {
+		return errForceAcceptDisabled
+	}
+	if service.networkID == genesis.MainnetID {
+		return errForceAcceptOnMainnet
+	}
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	blkID, err := ids.FromString(args.BlockID)
+	if err != nil {
+		return fmt.Errorf("couldn't parse block ID: %w", err)
+	}
+	chain, ok := service.chains.get(chainID)
+	if !ok {
+		return fmt.Errorf("chain %q hasn't been created", args.Chain)
+	}
+	vm, ok := chain.vm.(blockVM)
+	if !ok {
+		return errNotLinearChain
+	}
+
+	chain.ctx.Lock.Lock()
+	defer chain.ctx.Lock.Unlock()
+
+	blk, err := vm.GetBlock(blkID)
+	if err != nil {
+		return fmt.Errorf("couldn't get block %s: %w", blkID, err)
+	}
+	if status := blk.Status(); status != choices.Processing {
+		return fmt.Errorf("block %s can't be accepted because its status is %s", blkID, status)
+	}
+	if lastAccepted := vm.LastAccepted(); !blk.Parent().ID().Equals(lastAccepted) {
+		return fmt.Errorf("block %s can't be accepted because its parent isn't the last accepted block %s", blkID, lastAccepted)
+	}
+	if err := blk.Verify(); err != nil {
+		return fmt.Errorf("block %s failed verification: %w", blkID, err)
+	}
+
+	service.log.Warn("force accepting block %s of chain %s", blkID, chainID)
+	if err := blk.Accept(); err != nil {
+		return fmt.Errorf("couldn't accept block %s: %w", blkID, err)
+	}
+	vm.SetPreference(blkID)
+
+	bytes := blk.Bytes()
+	chain.ctx.DecisionDispatcher.Accept(chainID, blkID, bytes)
+	chain.ctx.ConsensusDispatcher.Accept(chainID, blkID, bytes)
+
+	reply.Success = true
+	return nil
+}
@@ -0,0 +1,92 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/genesis"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// testChainManager looks every chain up as [chainID]
+type testChainManager struct {
+	chains.MockManager
+	chainID ids.ID
+}
+
+func (m testChainManager) Lookup(string) (ids.ID, error) { return m.chainID, nil }
+
+// newForceAcceptTestService returns a service on the network [networkID] with a
+// linear chain whose last accepted block has a processing child, which is
+// returned
+func newForceAcceptTestService(networkID uint32, forceAcceptEnabled bool) (*Admin, *testBlockVM, *testBlock) {
+	vm := newTestBlockVM(2)
+	child := &testBlock{
+		id:     ids.Empty.Prefix(2),
+		parent: vm.blocks[vm.lastAccepted.Key()],
+		status: choices.Processing,
+	}
+	vm.blocks[child.id.Key()] = child
+
+	ctx := snow.DefaultContextTest()
+	service := &Admin{
+		log:                logging.NoLog{},
+		networkID:          networkID,
+		chainManager:       testChainManager{chainID: ctx.ChainID},
+		chains:             &registry{chains: []chain{{ctx: ctx, vm: vm}}},
+		forceAcceptEnabled: forceAcceptEnabled,
+	}
+	return service, vm, child
+}
+
+func TestForceAccept(t *testing.T) {
+	service, vm, child := newForceAcceptTestService(genesis.LocalID, true)
+
+	reply := ForceAcceptReply{}
+	if err := service.ForceAccept(nil, &ForceAcceptArgs{BlockID: child.id.String()}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if !reply.Success {
+		t.Fatal("expected the block to have been force accepted")
+	}
+	if child.status != choices.Accepted {
+		t.Fatalf("expected the block to be accepted but it's %s", child.status)
+	}
+	if !vm.preference.Equals(child.id) {
+		t.Fatalf("expected the block to be preferred but %s is", vm.preference)
+	}
+}
+
+func TestForceAcceptDisabled(t *testing.T) {
+	service, _, child := newForceAcceptTestService(genesis.LocalID, false)
+
+	reply := ForceAcceptReply{}
+	if err := service.ForceAccept(nil, &ForceAcceptArgs{BlockID: child.id.String()}, &reply); err != errForceAcceptDisabled {
+		t.Fatalf("expected %q but got %v", errForceAcceptDisabled, err)
+	}
+	if child.status != choices.Processing {
+		t.Fatalf("expected the block to still be processing but it's %s", child.status)
+	}
+}
+
+func TestForceAcceptOnMainnet(t *testing.T) {
+	// Even if it's enabled, force accepting must be refused on mainnet
+	service, _, child := newForceAcceptTestService(genesis.MainnetID, true)
+
+	reply := ForceAcceptReply{}
+	if err := service.ForceAccept(nil, &ForceAcceptArgs{BlockID: child.id.String()}, &reply); err != errForceAcceptOnMainnet {
+		t.Fatalf("expected %q but got %v", errForceAcceptOnMainnet, err)
+	}
+	if child.status != choices.Processing {
+		t.Fatalf("expected the block to still be processing but it's %s", child.status)
+	}
+	if reply.Success {
+		t.Fatal("expected the block not to have been force accepted")
+	}
+}
@@ -0,0 +1,85 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Consensus types returned by GetAcceptedFrontier
+const (
+	ConsensusTypeSnowman   = "snowman"
+	ConsensusTypeAvalanche = "avalanche"
+)
+
+// Statuses returned by GetAcceptedFrontier
+const (
+	AcceptedFrontierStatusAvailable     = "available"
+	AcceptedFrontierStatusBootstrapping = "not yet available"
+)
+
+// linearVM is implemented by the VMs of chains that run snowman consensus
+type linearVM interface {
+	LastAccepted() ids.ID
+}
+
+// GetAcceptedFrontierArgs are the arguments for calling GetAcceptedFrontier
+type GetAcceptedFrontierArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// GetAcceptedFrontierReply are the results from calling GetAcceptedFrontier
+type GetAcceptedFrontierReply struct {
+	// Either "snowman" for a linear chain or "avalanche" for a DAG
+	ConsensusType string `json:"consensusType"`
+
+	// Either "available", or "not yet available" if the chain is still
+	// bootstrapping
+	Status string `json:"status"`
+
+	// The last accepted block of a linear chain, or the accepted vertices
+	// without accepted children of a DAG. Sorted, and empty while the chain
+	// is bootstrapping.
+	Frontier []ids.ID `json:"frontier"`
+}
+
+// GetAcceptedFrontier returns the IDs at a chain's accepted frontier, which
+// can be compared across nodes to find out whether they've accepted
+// conflicting containers. The frontier of a chain that's still bootstrapping
+// is partial, so it isn't returned.
+func (service *Admin) GetAcceptedFrontier(_ *http.Request, args *GetAcceptedFrontierArgs, reply *GetAcceptedFrontierReply) error {
+	service.log.Debug("Admin: GetAcceptedFrontier called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	chain, ok := service.chains.get(chainID)
+	if !ok {
+		return fmt.Errorf("chain %q hasn't been created", args.Chain)
+	}
+	reply.ConsensusType = ConsensusTypeAvalanche
+	if _, ok := chain.vm.(linearVM); ok {
+		reply.ConsensusType = ConsensusTypeSnowman
+	}
+
+	reply.Frontier = []ids.ID{}
+	router := service.chainManager.Router()
+	if progress, ok := router.BootstrapProgress(chainID); ok && !progress.Bootstrapped {
+		reply.Status = AcceptedFrontierStatusBootstrapping
+		return nil
+	}
+	frontier, ok := router.CurrentAcceptedFrontier(chainID)
+	if !ok {
+		return fmt.Errorf("chain %s doesn't report its accepted frontier", chainID)
+	}
+	reply.Status = AcceptedFrontierStatusAvailable
+	reply.Frontier = frontier.List()
+	ids.SortIDs(reply.Frontier)
+	return nil
+}
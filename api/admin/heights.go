@@ -0,0 +1,169 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// blockGetterVM is implemented by the VMs of chains that run snowman
+// consensus, and lets the chain's blocks be walked
+type blockGetterVM interface {
+	linearVM
+	GetBlock(ids.ID) (snowman.Block, error)
+}
+
+// blocksPerLock is the most blocks that are walked with a chain's lock held.
+// The lock is released between batches, so that walking a long chain doesn't
+// stall it.
+const blocksPerLock = 1024
+
+// heightCache remembers the height of a block a linear chain accepted, so that
+// the heights of the blocks accepted after it can be found without walking the
+// chain back to its genesis block
+type heightCache struct {
+	lock   sync.Mutex
+	known  bool
+	blkID  ids.ID
+	height uint64
+}
+
+// lastAcceptedHeight returns the ID and height of the last accepted block of
+// [vm]. Blocks don't expose their height, so it's found by walking back from
+// the block to the block in the cache, or to the genesis block the first
+// time. [chainLock] is the chain's lock, which is held for up to blocksPerLock
+// blocks at a time.
+func (c *heightCache) lastAcceptedHeight(chainLock sync.Locker, vm blockGetterVM) (ids.ID, uint64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	chainLock.Lock()
+	blkID := vm.LastAccepted()
+	if c.known && c.blkID.Equals(blkID) {
+		chainLock.Unlock()
+		return blkID, c.height, nil
+	}
+	blk, err := vm.GetBlock(blkID)
+	chainLock.Unlock()
+	if err != nil {
+		return ids.ID{}, 0, fmt.Errorf("couldn't get block %s: %w", blkID, err)
+	}
+
+	steps := uint64(0)
+	base := uint64(0)
+	for done := false; !done; {
+		chainLock.Lock()
+		for i := 0; i < blocksPerLock; i++ {
+			// The genesis block's parent is unknown
+			parent := blk.Parent()
+			if parent == nil || parent.Status() != choices.Accepted {
+				done = true
+				break
+			}
+			steps++
+			if c.known && c.blkID.Equals(parent.ID()) {
+				base = c.height
+				done = true
+				break
+			}
+			blk = parent
+		}
+		chainLock.Unlock()
+	}
+
+	c.known = true
+	c.blkID = blkID
+	c.height = base + steps
+	return blkID, c.height, nil
+}
+
+// ChainHeight describes how far a chain has progressed
+type ChainHeight struct {
+	Aliases []string `json:"aliases"`
+
+	// Either "snowman" for a linear chain or "avalanche" for a DAG
+	ConsensusType string `json:"consensusType"`
+
+	// Either "available", or "not yet available" if the chain is still
+	// bootstrapping. The fields below are only set if it's available.
+	Status string `json:"status"`
+
+	// The height and ID of a linear chain's last accepted block. Not set for
+	// a DAG.
+	Height       cjson.Uint64 `json:"height"`
+	LastAccepted ids.ID       `json:"lastAccepted"`
+
+	// A DAG's accepted vertices without accepted children, sorted
+	Frontier []ids.ID `json:"frontier,omitempty"`
+}
+
+// GetAllChainHeightsReply are the results from calling GetAllChainHeights
+type GetAllChainHeightsReply struct {
+	// When the chains were sampled, so that samples taken by different nodes
+	// can be compared
+	Timestamp string `json:"timestamp"`
+
+	// Every running chain, keyed by chain ID
+	Chains map[string]ChainHeight `json:"chains"`
+}
+
+// GetAllChainHeights returns the last accepted block, and its height, of every
+// running linear chain, and the accepted frontier of every running DAG, so that
+// a chain that fell behind can be spotted in one call.
+//
+// The height of a linear chain's last accepted block is found by walking the
+// chain back to a block whose height is already known, so the first call walks
+// each chain back to its genesis block.
+func (service *Admin) GetAllChainHeights(_ *http.Request, _ *struct{}, reply *GetAllChainHeightsReply) error {
+	service.log.Debug("Admin: GetAllChainHeights called")
+
+	router := service.chainManager.Router()
+	chains := service.chains.list()
+	reply.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	reply.Chains = make(map[string]ChainHeight, len(chains))
+	for _, chain := range chains {
+		chainID := chain.ctx.ChainID
+		height := ChainHeight{
+			Aliases:       service.chainManager.Aliases(chainID),
+			ConsensusType: ConsensusTypeAvalanche,
+			Status:        AcceptedFrontierStatusAvailable,
+		}
+		vm, linear := chain.vm.(blockGetterVM)
+		if linear {
+			height.ConsensusType = ConsensusTypeSnowman
+		}
+		if progress, ok := router.BootstrapProgress(chainID); ok && !progress.Bootstrapped {
+			height.Status = AcceptedFrontierStatusBootstrapping
+			reply.Chains[chainID.String()] = height
+			continue
+		}
+
+		if linear {
+			lastAccepted, blkHeight, err := chain.heights.lastAcceptedHeight(&chain.ctx.Lock, vm)
+			if err != nil {
+				return fmt.Errorf("couldn't find the height of chain %s: %w", chainID, err)
+			}
+			height.LastAccepted = lastAccepted
+			height.Height = cjson.Uint64(blkHeight)
+		} else {
+			frontier, ok := router.CurrentAcceptedFrontier(chainID)
+			if !ok {
+				return fmt.Errorf("chain %s doesn't report its accepted frontier", chainID)
+			}
+			height.Frontier = frontier.List()
+			ids.SortIDs(height.Frontier)
+		}
+		reply.Chains[chainID.String()] = height
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/ava-labs/gecko/vms/rpcchainvm"
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp"
+)
+
+// capturingVM is a VM that runs in a plugin process and can capture the
+// requests to its HTTP handlers
+type capturingVM interface {
+	HTTPCaptures() []rpcchainvm.HandlerCaptures
+	ClearHTTPCaptures()
+}
+
+// HTTPCaptureArgs are the arguments for calling GetHTTPCaptures and
+// ClearHTTPCaptures
+type HTTPCaptureArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// HTTPCapture is a request to one of a chain's HTTP handlers and the response
+// it was replied to with
+type HTTPCapture struct {
+	// Extension of the chain's endpoint the handler serves
+	Endpoint string `json:"endpoint"`
+
+	Time          string      `json:"time"`
+	Method        string      `json:"method"`
+	URL           string      `json:"url"`
+	RequestHeader http.Header `json:"requestHeader"`
+	// The part of the request body the handler read
+	RequestBody          string `json:"requestBody"`
+	RequestBodyTruncated bool   `json:"requestBodyTruncated"`
+
+	// 0 if the handler didn't reply
+	StatusCode            int         `json:"statusCode"`
+	ResponseHeader        http.Header `json:"responseHeader"`
+	ResponseBody          string      `json:"responseBody"`
+	ResponseBodyTruncated bool        `json:"responseBodyTruncated"`
+}
+
+// GetHTTPCapturesReply are the results from calling GetHTTPCaptures
+type GetHTTPCapturesReply struct {
+	// Oldest first
+	Captures []HTTPCapture `json:"captures"`
+}
+
+// GetHTTPCaptures returns the requests to the HTTP handlers of a chain whose VM
+// runs as a plugin that were captured, and their responses. Requests are only
+// captured if the node was started with --plugin-http-capture-paths, and only
+// if their paths start with one of its prefixes.
+func (service *Admin) GetHTTPCaptures(_ *http.Request, args *HTTPCaptureArgs, reply *GetHTTPCapturesReply) error {
+	service.log.Debug("Admin: GetHTTPCaptures called with %s", args.Chain)
+
+	vm, err := service.capturingVM(args.Chain)
+	if err != nil {
+		return err
+	}
+
+	type endpointCapture struct {
+		endpoint string
+		capture  ghttp.Capture
+	}
+	captures := []endpointCapture(nil)
+	for _, handler := range vm.HTTPCaptures() {
+		for _, capture := range handler.Captures {
+			captures = append(captures, endpointCapture{
+				endpoint: handler.Prefix,
+				capture:  capture,
+			})
+		}
+	}
+	sort.SliceStable(captures, func(i, j int) bool {
+		return captures[i].capture.Time.Before(captures[j].capture.Time)
+	})
+
+	reply.Captures = make([]HTTPCapture, len(captures))
+	for i, c := range captures {
+		reply.Captures[i] = HTTPCapture{
+			Endpoint:              c.endpoint,
+			Time:                  c.capture.Time.UTC().Format(time.RFC3339Nano),
+			Method:                c.capture.Method,
+			URL:                   c.capture.URL,
+			RequestHeader:         c.capture.RequestHeader,
+			RequestBody:           string(c.capture.RequestBody),
+			RequestBodyTruncated:  c.capture.RequestBodyTruncated,
+			StatusCode:            c.capture.StatusCode,
+			ResponseHeader:        c.capture.ResponseHeader,
+			ResponseBody:          string(c.capture.ResponseBody),
+			ResponseBodyTruncated: c.capture.ResponseBodyTruncated,
+		}
+	}
+	return nil
+}
+
+// ClearHTTPCapturesReply are the results from calling ClearHTTPCaptures
+type ClearHTTPCapturesReply struct {
+	Success bool `json:"success"`
+}
+
+// ClearHTTPCaptures drops the requests to the HTTP handlers of a chain whose VM
+// runs as a plugin that were captured, and their responses
+func (service *Admin) ClearHTTPCaptures(_ *http.Request, args *HTTPCaptureArgs, reply *ClearHTTPCapturesReply) error {
+	service.log.Info("Admin: ClearHTTPCaptures called with %s", args.Chain)
+
+	vm, err := service.capturingVM(args.Chain)
+	if err != nil {
+		return err
+	}
+	vm.ClearHTTPCaptures()
+	reply.Success = true
+	return nil
+}
+
+// capturingVM returns the VM of the chain with ID or alias [chain]
+func (service *Admin) capturingVM(chain string) (capturingVM, error) {
+	chainID, err := service.chainManager.Lookup(chain)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find chain %q: %w", chain, err)
+	}
+	c, ok := service.chains.get(chainID)
+	if !ok {
+		return nil, fmt.Errorf("chain %s hasn't been created", chainID)
+	}
+	vm, ok := c.vm.(capturingVM)
+	if !ok {
+		return nil, fmt.Errorf("the VM of chain %s doesn't run as a plugin", chainID)
+	}
+	return vm, nil
+}
@@ -0,0 +1,54 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// maintenanceVM is implemented by the VMs whose HTTP handlers can be put in
+// maintenance, which are those run by plugins
+type maintenanceVM interface {
+	SetMaintenance(enabled bool)
+}
+
+// SetMaintenanceModeArgs are the arguments for calling SetMaintenanceMode
+type SetMaintenanceModeArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+
+	Enabled bool `json:"enabled"`
+}
+
+// SetMaintenanceModeReply are the results from calling SetMaintenanceMode
+type SetMaintenanceModeReply struct {
+	Success bool `json:"success"`
+}
+
+// SetMaintenanceMode puts the HTTP handlers of a chain run by a plugin in, or
+// takes them out of, maintenance. While they're in maintenance, requests to
+// them are replied to with a 503 and a Retry-After without calling the plugin,
+// so that its API can be worked on. The chain keeps participating in
+// consensus.
+func (service *Admin) SetMaintenanceMode(_ *http.Request, args *SetMaintenanceModeArgs, reply *SetMaintenanceModeReply) error {
+	service.log.Info("Admin: SetMaintenanceMode called with %s %t", args.Chain, args.Enabled)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	chain, ok := service.chains.get(chainID)
+	if !ok {
+		return fmt.Errorf("chain %q hasn't been created", args.Chain)
+	}
+	vm, ok := chain.vm.(maintenanceVM)
+	if !ok {
+		return fmt.Errorf("chain %q isn't run by a plugin, so its API can't be put in maintenance", args.Chain)
+	}
+
+	vm.SetMaintenance(args.Enabled)
+	reply.Success = true
+	return nil
+}
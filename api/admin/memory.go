@@ -0,0 +1,136 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"math"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// vmMemory estimates the number of heap bytes in use by each VM that [chains]
+// run.
+//
+// Go doesn't track memory per goroutine, so the estimate is derived from the
+// sampled heap profile. Every sampled allocation is attributed to the VM whose
+// package appears closest to the top of the allocation's stack. Chains that run
+// the same VM share its package, so their allocations can't be told apart and
+// are reported as one total for the VM. Allocations that don't pass through
+// any VM package, such as those made by the consensus engines, are reported as
+// unattributed. Memory used by VMs that run in a separate plugin process isn't
+// included.
+//
+// The returned packages are in the order their VMs first appear in [chains],
+// and the returned slices are indexed the same way as the packages.
+func vmMemory(chains []chain) ([]string, [][]chain, []uint64, uint64) {
+	vmPackages := []string(nil)
+	chainsByPackage := make(map[string][]chain)
+	for _, chain := range chains {
+		pkg := vmPackage(chain.vm)
+		if pkg == "" {
+			continue
+		}
+		if _, exists := chainsByPackage[pkg]; !exists {
+			vmPackages = append(vmPackages, pkg)
+		}
+		chainsByPackage[pkg] = append(chainsByPackage[pkg], chain)
+	}
+
+	bytesByPackage := make(map[string]uint64)
+	unattributed := uint64(0)
+	for _, record := range heapProfile() {
+		inUse := scaledInUseBytes(&record)
+		if inUse == 0 {
+			continue
+		}
+		if pkg := allocatingVM(record.Stack(), vmPackages); pkg != "" {
+			bytesByPackage[pkg] += inUse
+		} else {
+			unattributed += inUse
+		}
+	}
+
+	vmChains := make([][]chain, len(vmPackages))
+	bytes := make([]uint64, len(vmPackages))
+	for i, pkg := range vmPackages {
+		vmChains[i] = chainsByPackage[pkg]
+		bytes[i] = bytesByPackage[pkg]
+	}
+	return vmPackages, vmChains, bytes, unattributed
+}
+
+// heapProfile returns the current records of the heap profile. The profile is
+// as of the most recently completed garbage collection, so it may lag behind
+// the heap's current contents.
+func heapProfile() []runtime.MemProfileRecord {
+	n, _ := runtime.MemProfile(nil, true)
+	for {
+		// Allow room for a few more records to be added between calls
+		records := make([]runtime.MemProfileRecord, n+50)
+		var ok bool
+		n, ok = runtime.MemProfile(records, true)
+		if ok {
+			return records[:n]
+		}
+	}
+}
+
+// scaledInUseBytes estimates the number of bytes in use by the allocations of
+// [record], correcting for the heap profile's sampling rate. This mirrors the
+// scaling done by runtime/pprof.
+func scaledInUseBytes(record *runtime.MemProfileRecord) uint64 {
+	count := record.InUseObjects()
+	size := record.InUseBytes()
+	if count <= 0 || size <= 0 {
+		return 0
+	}
+	rate := runtime.MemProfileRate
+	if rate <= 1 {
+		return uint64(size)
+	}
+	avgSize := float64(size) / float64(count)
+	scale := 1 / (1 - math.Exp(-avgSize/float64(rate)))
+	return uint64(float64(size) * scale)
+}
+
+// allocatingVM returns the package in [vmPackages] that is closest to the top
+// of [stack], or the empty string if none of them are in the stack.
+func allocatingVM(stack []uintptr, vmPackages []string) string {
+	frames := runtime.CallersFrames(stack)
+	for {
+		frame, more := frames.Next()
+		pkg := funcPackage(frame.Function)
+		for _, vmPkg := range vmPackages {
+			if pkg == vmPkg || strings.HasPrefix(pkg, vmPkg+"/") {
+				return vmPkg
+			}
+		}
+		if !more {
+			return ""
+		}
+	}
+}
+
+// vmPackage returns the import path of the package that defines [vm]'s type
+func vmPackage(vm interface{}) string {
+	t := reflect.TypeOf(vm)
+	if t == nil {
+		return ""
+	}
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath()
+}
+
+// funcPackage returns the import path of the package that defines the function
+// with the fully qualified name [name]
+func funcPackage(name string) string {
+	slash := strings.LastIndex(name, "/")
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+	return name
+}
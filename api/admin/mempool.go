@@ -0,0 +1,68 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// GetMempoolArgs are the arguments for calling GetMempool
+type GetMempoolArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// GetMempoolReply are the results from calling GetMempool
+type GetMempoolReply struct {
+	// False if the chain's VM doesn't keep a mempool, or can't report on it.
+	// The other fields are only set if this is true.
+	HasMempool bool `json:"hasMempool"`
+
+	// Number of transactions that haven't been put into a block yet, and
+	// their total size in bytes
+	PendingTxs cjson.Uint64 `json:"pendingTxs"`
+	Bytes      cjson.Uint64 `json:"bytes"`
+
+	// How long ago the oldest pending transaction was received, such as
+	// "1m30s". Empty if there are no pending transactions.
+	OldestAge string `json:"oldestAge"`
+}
+
+// GetMempool returns the number and size of the transactions a chain received
+// that haven't been put into a block yet. A growing number, or an old oldest
+// transaction, means transactions are backing up.
+func (service *Admin) GetMempool(_ *http.Request, args *GetMempoolArgs, reply *GetMempoolReply) error {
+	service.log.Debug("Admin: GetMempool called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	chain, ok := service.chains.get(chainID)
+	if !ok {
+		return fmt.Errorf("chain %q hasn't been created", args.Chain)
+	}
+	vm, ok := chain.vm.(common.MempoolReporter)
+	if !ok {
+		return nil
+	}
+
+	chain.ctx.Lock.Lock()
+	stats := vm.MempoolStats()
+	chain.ctx.Lock.Unlock()
+
+	reply.HasMempool = true
+	reply.PendingTxs = cjson.Uint64(stats.Txs)
+	reply.Bytes = cjson.Uint64(stats.Bytes)
+	if !stats.Oldest.IsZero() {
+		reply.OldestAge = time.Since(stats.Oldest).Round(time.Second).String()
+	}
+	return nil
+}
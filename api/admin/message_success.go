@@ -0,0 +1,68 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+
+	"github.com/ava-labs/gecko/snow/networking/timeout"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// MessageSuccessRate describes how many requests of a type were responded to
+type MessageSuccessRate struct {
+	// Number of requests that were responded to before they timed out, that
+	// failed before they could be responded to, such as because they
+	// couldn't be sent, and that timed out
+	Responded cjson.Uint64 `json:"responded"`
+	Failed    cjson.Uint64 `json:"failed"`
+	TimedOut  cjson.Uint64 `json:"timedOut"`
+
+	// Number of requests whose outcome is known, which is the sum of the
+	// above
+	Samples cjson.Uint64 `json:"samples"`
+
+	// Fraction of the requests that were responded to, between 0 and 1. 0 if
+	// there are no samples.
+	SuccessRate float64 `json:"successRate"`
+}
+
+// GetMessageSuccessRatesReply are the results from calling
+// GetMessageSuccessRates
+type GetMessageSuccessRatesReply struct {
+	// Span of time the outcomes were counted over, such as "10m0s"
+	Window string `json:"window"`
+
+	// Keyed by the type of the request, such as "get", "pullQuery" or
+	// "pushQuery". Types of which no requests were sent are left out.
+	Rates map[string]MessageSuccessRate `json:"rates"`
+}
+
+// GetMessageSuccessRates returns, for each type of request this node sends to
+// validators, the fraction of the recent requests, across every chain and
+// validator, that were responded to before they failed or timed out. A falling
+// success rate for a type of request points to a problem across the network
+// rather than with a single peer.
+func (service *Admin) GetMessageSuccessRates(_ *http.Request, _ *struct{}, reply *GetMessageSuccessRatesReply) error {
+	service.log.Debug("Admin: GetMessageSuccessRates called")
+
+	outcomes := service.chainManager.Router().RequestOutcomes()
+	reply.Window = timeout.OutcomeWindow.String()
+	reply.Rates = make(map[string]MessageSuccessRate, len(outcomes))
+	for requestType, outcome := range outcomes {
+		samples := outcome.Responded + outcome.Failed + outcome.TimedOut
+		if samples == 0 {
+			continue
+		}
+		reply.Rates[string(requestType)] = MessageSuccessRate{
+			Responded:   cjson.Uint64(outcome.Responded),
+			Failed:      cjson.Uint64(outcome.Failed),
+			TimedOut:    cjson.Uint64(outcome.TimedOut),
+			Samples:     cjson.Uint64(samples),
+			SuccessRate: float64(outcome.Responded) / float64(samples),
+		}
+	}
+	return nil
+}
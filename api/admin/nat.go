@@ -0,0 +1,106 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/gecko/nat"
+)
+
+// NAT describes the NAT router this node's ports are mapped on
+type NAT struct {
+	Router nat.Router
+
+	// Maps the node's ports on Router. Nil if the ports aren't being mapped.
+	Mapper nat.Mapper
+}
+
+// PortMapping describes a port this node mapped on its NAT router
+type PortMapping struct {
+	InternalPort uint16 `json:"internalPort"`
+	ExternalPort uint16 `json:"externalPort"`
+
+	// True if the most recent attempt to map the port succeeded
+	Mapped bool `json:"mapped"`
+	// Why the most recent attempt to map the port failed, if it failed
+	Error string `json:"error"`
+
+	// When the mapping was last refreshed, and when the router will drop it
+	// unless it's refreshed again. Empty if the port hasn't been mapped.
+	LastRefresh string `json:"lastRefresh"`
+	Expiry      string `json:"expiry"`
+}
+
+// GetNATStatusReply are the results from calling GetNATStatus
+type GetNATStatusReply struct {
+	// Protocol used to map ports on the NAT router, either "upnp" or
+	// "nat-pmp", or "none" if no NAT router was discovered
+	Router string `json:"router"`
+
+	// True if the IP this node advertises was configured rather than fetched
+	// from the NAT router
+	StaticIP bool `json:"staticIP"`
+
+	// Human readable summary of the state of the port mappings
+	Status string `json:"status"`
+
+	Mappings []PortMapping `json:"mappings"`
+}
+
+// GetNATStatus returns whether this node's ports were mapped on its NAT
+// router, and when the mappings expire
+func (service *Admin) GetNATStatus(_ *http.Request, _ *struct{}, reply *GetNATStatusReply) error {
+	service.log.Debug("Admin: GetNATStatus called")
+
+	reply.Router = nat.Kind(service.nat.Router)
+	reply.StaticIP = service.externalIP.Source == "static"
+	reply.Mappings = []PortMapping{}
+
+	if reply.Router == "none" {
+		if reply.StaticIP {
+			reply.Status = "no NAT router was discovered; using the configured static IP"
+		} else {
+			reply.Status = "no NAT router was discovered"
+		}
+		return nil
+	}
+	if service.nat.Mapper == nil {
+		reply.Status = "ports aren't being mapped"
+		return nil
+	}
+
+	failed := 0
+	for _, mapping := range service.nat.Mapper.Mappings() {
+		portMapping := PortMapping{
+			InternalPort: mapping.InternalPort,
+			ExternalPort: mapping.ExternalPort,
+			Mapped:       mapping.Mapped,
+		}
+		if mapping.Err != nil {
+			portMapping.Error = mapping.Err.Error()
+			failed++
+		}
+		if !mapping.LastRefresh.IsZero() {
+			portMapping.LastRefresh = mapping.LastRefresh.UTC().Format(time.RFC3339)
+		}
+		if !mapping.Expiry.IsZero() {
+			portMapping.Expiry = mapping.Expiry.UTC().Format(time.RFC3339)
+		}
+		reply.Mappings = append(reply.Mappings, portMapping)
+	}
+
+	switch {
+	case len(reply.Mappings) == 0:
+		reply.Status = "no ports have been mapped"
+	case failed == 0:
+		reply.Status = "mapped"
+	case failed == len(reply.Mappings):
+		reply.Status = "failed"
+	default:
+		reply.Status = "partially mapped"
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+var errNoPrimaryValidators = errors.New("the primary network's validators aren't known")
+
+// GetNetworkSizeReply are the results from calling GetNetworkSize
+type GetNetworkSizeReply struct {
+	// Number of the primary network's validators, and their total stake. This
+	// node isn't counted.
+	Validators cjson.Uint64 `json:"validators"`
+	TotalStake cjson.Uint64 `json:"totalStake"`
+
+	// Number of the validators this node is connected to, and their total
+	// stake. This node isn't counted.
+	ConnectedValidators cjson.Uint64 `json:"connectedValidators"`
+	ConnectedStake      cjson.Uint64 `json:"connectedStake"`
+
+	// Fraction of the validators, and of the stake, this node is connected
+	// to, between 0 and 1
+	ConnectedValidatorsFraction float64 `json:"connectedValidatorsFraction"`
+	ConnectedStakeFraction      float64 `json:"connectedStakeFraction"`
+}
+
+// GetNetworkSize returns how many validators the primary network has and how
+// much they staked, as this node sees them, and how many of them, and how much
+// of their stake, this node is connected to. This node is left out of every
+// count, as it can't be connected to itself. A low connected fraction means
+// this node is poorly connected to the network. If staking is disabled, this
+// node is the only validator, so no validators are counted.
+func (service *Admin) GetNetworkSize(_ *http.Request, _ *struct{}, reply *GetNetworkSizeReply) error {
+	service.log.Debug("Admin: GetNetworkSize called")
+
+	vdrs, ok := service.chainManager.SubnetValidators(primaryNetworkID)
+	if !ok {
+		return errNoPrimaryValidators
+	}
+
+	connected := map[[20]byte]bool{}
+	for _, peer := range service.networking.Peers() {
+		connected[peer.ID.Key()] = true
+	}
+
+	validators, totalStake := uint64(0), uint64(0)
+	connectedValidators, connectedStake := uint64(0), uint64(0)
+	for _, vdr := range vdrs.List() {
+		if vdr.ID().Equals(service.nodeID) {
+			continue
+		}
+		validators++
+		totalStake += vdr.Weight()
+		if connected[vdr.ID().Key()] {
+			connectedValidators++
+			connectedStake += vdr.Weight()
+		}
+	}
+
+	reply.Validators = cjson.Uint64(validators)
+	reply.TotalStake = cjson.Uint64(totalStake)
+	reply.ConnectedValidators = cjson.Uint64(connectedValidators)
+	reply.ConnectedStake = cjson.Uint64(connectedStake)
+	if validators > 0 {
+		reply.ConnectedValidatorsFraction = float64(connectedValidators) / float64(validators)
+	}
+	if totalStake > 0 {
+		reply.ConnectedStakeFraction = float64(connectedStake) / float64(totalStake)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"testing"
+
+	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/network"
+	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// testValidatorsManager reports [validators] as every subnet's validators
+type testValidatorsManager struct {
+	chains.MockManager
+	validators validators.Set
+}
+
+func (m testValidatorsManager) SubnetValidators(ids.ID) (validators.Set, bool) {
+	return m.validators, true
+}
+
+// testPeersNetwork reports [peers] as the connected peers
+type testPeersNetwork struct {
+	network.Network
+	peers []network.PeerID
+}
+
+func (n testPeersNetwork) Peers() []network.PeerID { return n.peers }
+
+func TestGetNetworkSizeExcludesSelf(t *testing.T) {
+	nodeID := ids.NewShortID([20]byte{1})
+	peerID := ids.NewShortID([20]byte{2})
+	vdrs := validators.NewSet()
+	vdrs.Add(validators.NewValidator(nodeID, 2))
+	vdrs.Add(validators.NewValidator(peerID, 1))
+	vdrs.Add(validators.NewValidator(ids.NewShortID([20]byte{3}), 1))
+
+	service := &Admin{
+		log:          logging.NoLog{},
+		nodeID:       nodeID,
+		chainManager: testValidatorsManager{validators: vdrs},
+		networking:   testPeersNetwork{peers: []network.PeerID{{ID: peerID}}},
+	}
+
+	reply := GetNetworkSizeReply{}
+	if err := service.GetNetworkSize(nil, nil, &reply); err != nil {
+		t.Fatal(err)
+	}
+	if reply.Validators != 2 || reply.TotalStake != 2 {
+		t.Fatalf("expected 2 validators with 2 stake but got %d with %d", reply.Validators, reply.TotalStake)
+	}
+	if reply.ConnectedValidators != 1 || reply.ConnectedStake != 1 {
+		t.Fatalf("expected 1 connected validator with 1 stake but got %d with %d", reply.ConnectedValidators, reply.ConnectedStake)
+	}
+	if reply.ConnectedValidatorsFraction != .5 || reply.ConnectedStakeFraction != .5 {
+		t.Fatalf("expected to be connected to half the validators and stake but got %f and %f",
+			reply.ConnectedValidatorsFraction, reply.ConnectedStakeFraction)
+	}
+}
@@ -0,0 +1,72 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+// GetOldestPendingArgs are the arguments for calling GetOldestPending
+type GetOldestPendingArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// GetOldestPendingReply are the results from calling GetOldestPending
+type GetOldestPendingReply struct {
+	// False if the chain's VM doesn't record when it saw its transactions.
+	// The other fields are only set if this is true.
+	Tracked bool `json:"tracked"`
+
+	// False if every transaction the chain saw has been accepted or rejected.
+	// The fields below are only set if this is true.
+	Pending bool `json:"pending"`
+
+	// ID of the oldest transaction that hasn't been accepted or rejected
+	TxID ids.ID `json:"txID"`
+
+	// When the transaction was first seen, and how long ago that was, such as
+	// "1m30s"
+	FirstSeen string `json:"firstSeen"`
+	Age       string `json:"age"`
+}
+
+// GetOldestPending returns the oldest transaction a chain saw that hasn't been
+// accepted or rejected yet, and how long it has been waiting. An old oldest
+// transaction means the chain is backed up, or that the transaction is stuck.
+func (service *Admin) GetOldestPending(_ *http.Request, args *GetOldestPendingArgs, reply *GetOldestPendingReply) error {
+	service.log.Debug("Admin: GetOldestPending called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	chain, ok := service.chains.get(chainID)
+	if !ok {
+		return fmt.Errorf("chain %q hasn't been created", args.Chain)
+	}
+	vm, ok := chain.vm.(common.PendingTxReporter)
+	if !ok {
+		return nil
+	}
+
+	chain.ctx.Lock.Lock()
+	txID, firstSeen, pending := vm.OldestPendingTx()
+	chain.ctx.Lock.Unlock()
+
+	reply.Tracked = true
+	if !pending {
+		return nil
+	}
+	reply.Pending = true
+	reply.TxID = txID
+	reply.FirstSeen = firstSeen.UTC().Format(time.RFC3339)
+	reply.Age = time.Since(firstSeen).Round(time.Second).String()
+	return nil
+}
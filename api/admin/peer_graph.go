@@ -0,0 +1,68 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// dotEscaper escapes strings so they can be used within double quotes in DOT
+var dotEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`"`, `\"`,
+	"\r", `\r`,
+	"\n", `\n`,
+)
+
+// dotQuote returns [s] as a quoted DOT ID
+func dotQuote(s string) string { return `"` + dotEscaper.Replace(s) + `"` }
+
+// GetPeerGraphReply are the results from calling GetPeerGraph
+type GetPeerGraphReply struct {
+	// Graph in Graphviz DOT format
+	Graph string `json:"graph"`
+}
+
+// GetPeerGraph returns this node and the peers it's connected to as a graph in
+// Graphviz DOT format, which can be rendered with `dot -Tsvg`. Nodes are
+// labeled with their ID and version. Edges point from the node that opened the
+// connection to the node that accepted it. Peers are sorted by ID, so the
+// output only changes when the peers do.
+func (service *Admin) GetPeerGraph(_ *http.Request, _ *struct{}, reply *GetPeerGraphReply) error {
+	service.log.Debug("Admin: GetPeerGraph called")
+
+	peers := service.networking.Peers()
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].ID.String() < peers[j].ID.String()
+	})
+
+	self := dotQuote(service.nodeID.String())
+	sb := strings.Builder{}
+	sb.WriteString("digraph peers {\n")
+	sb.WriteString(fmt.Sprintf("\t%s [label=%s, shape=doublecircle];\n",
+		self,
+		dotQuote(service.nodeID.String()+"\n"+service.version.String()),
+	))
+	for _, peer := range peers {
+		sb.WriteString(fmt.Sprintf("\t%s [label=%s];\n",
+			dotQuote(peer.ID.String()),
+			dotQuote(peer.ID.String()+"\n"+peer.Version),
+		))
+	}
+	for _, peer := range peers {
+		id := dotQuote(peer.ID.String())
+		if peer.Inbound {
+			sb.WriteString(fmt.Sprintf("\t%s -> %s [label=\"inbound\"];\n", id, self))
+		} else {
+			sb.WriteString(fmt.Sprintf("\t%s -> %s [label=\"outbound\"];\n", self, id))
+		}
+	}
+	sb.WriteString("}\n")
+
+	reply.Graph = sb.String()
+	return nil
+}
@@ -0,0 +1,76 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/gecko/ids"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// GetPeerPriorityArgs are the arguments for calling GetPeerPriority
+type GetPeerPriorityArgs struct {
+	NodeID string `json:"nodeID"`
+}
+
+// GetPeerPriorityReply are the results from calling GetPeerPriority
+type GetPeerPriorityReply struct {
+	// True if the peer is a validator, which makes it preferred when peer
+	// lists are gossiped
+	Validator bool `json:"validator"`
+
+	// The peer's stake, and its fraction of the total stake. 0 if the peer
+	// isn't a validator.
+	Weight        cjson.Uint64 `json:"weight"`
+	StakeFraction float64      `json:"stakeFraction"`
+
+	// Number of bytes and messages currently queued to be sent to the peer
+	PendingSendBytes    cjson.Uint64 `json:"pendingSendBytes"`
+	PendingSendMessages cjson.Uint64 `json:"pendingSendMessages"`
+
+	// Number of messages that may be queued to be sent to the peer before
+	// further messages are dropped
+	SendQueueCapacity cjson.Uint64 `json:"sendQueueCapacity"`
+
+	// Number of queued bytes the peer is allowed while rate limiting is being
+	// enforced, and the most it may have queued then
+	GuaranteedSendBytes cjson.Uint64 `json:"guaranteedSendBytes"`
+	MaxPendingSendBytes cjson.Uint64 `json:"maxPendingSendBytes"`
+
+	// True if messages to the peer are being dropped when they would put the
+	// peer or the network over their limits
+	Throttled bool `json:"throttled"`
+}
+
+// GetPeerPriority returns the factors that decide how messages to a connected
+// peer are treated, to help find out why messages to it are delayed or
+// dropped. Messages to every peer are sent in the order they were queued, so
+// the factors are the peer's stake, how full its send queue is, and whether
+// the outbound message throttler is limiting it.
+func (service *Admin) GetPeerPriority(_ *http.Request, args *GetPeerPriorityArgs, reply *GetPeerPriorityReply) error {
+	service.log.Debug("Admin: GetPeerPriority called with %s", args.NodeID)
+
+	nodeID, err := ids.ShortFromString(args.NodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't parse node ID: %w", err)
+	}
+	priority, err := service.networking.PeerPriority(nodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't get the priority of %s: %w", nodeID, err)
+	}
+
+	reply.Validator = priority.Validator
+	reply.Weight = cjson.Uint64(priority.Weight)
+	reply.StakeFraction = priority.StakeFraction
+	reply.PendingSendBytes = cjson.Uint64(priority.PendingSendBytes)
+	reply.PendingSendMessages = cjson.Uint64(priority.PendingSendMessages)
+	reply.SendQueueCapacity = cjson.Uint64(priority.SendQueueCapacity)
+	reply.GuaranteedSendBytes = cjson.Uint64(priority.GuaranteedSendBytes)
+	reply.MaxPendingSendBytes = cjson.Uint64(priority.MaxPendingSendBytes)
+	reply.Throttled = priority.Throttled
+	return nil
+}
@@ -4,32 +4,68 @@
 package admin
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
 	"os"
 	"runtime"
 	"runtime/pprof"
+	"time"
+)
+
+const (
+	// DefaultCPUProfileRate is the rate, in samples per second, that the cpu
+	// profiler samples at if no rate is provided. It is the rate pprof uses.
+	DefaultCPUProfileRate = 100
+
+	// MaxCPUProfileRate is the highest rate the cpu profiler may sample at.
+	// Higher rates can't be sampled at reliably.
+	MaxCPUProfileRate = 1000
+
+	// MaxProfileDuration is the longest a profile may be captured for
+	MaxProfileDuration = 5 * time.Minute
+
+	// cpuProfile is the type of cpu profiles, which isn't one of
+	// pprof.Profiles()
+	cpuProfile = "cpu"
 )
 
 var (
-	errCPUProfilerRunning    = errors.New("cpu profiler already running")
-	errCPUProfilerNotRunning = errors.New("cpu profiler doesn't exist")
+	errCPUProfilerRunning        = errors.New("cpu profiler already running")
+	errCPUProfilerNotRunning     = errors.New("cpu profiler doesn't exist")
+	errCPUProfileRateOutOfRange  = fmt.Errorf("cpu profile rate must be between 1 and %d", MaxCPUProfileRate)
+	errProfileDurationOutOfRange = fmt.Errorf("profile duration must be between 0 and %s", MaxProfileDuration)
 )
 
 // Performance provides helper methods for measuring the current performance of
 // the system
 type Performance struct{ cpuProfileFile *os.File }
 
-// StartCPUProfiler starts measuring the cpu utilization of this node
-func (p *Performance) StartCPUProfiler(filename string) error {
+// StartCPUProfiler starts measuring the cpu utilization of this node, sampling
+// [hz] times per second
+func (p *Performance) StartCPUProfiler(filename string, hz int) error {
 	if p.cpuProfileFile != nil {
 		return errCPUProfilerRunning
 	}
+	if hz < 1 || hz > MaxCPUProfileRate {
+		return errCPUProfileRateOutOfRange
+	}
 
 	file, err := os.Create(filename)
 	if err != nil {
 		return err
 	}
+	// pprof always tries to sample at its default rate, so a different rate
+	// must be set before pprof starts profiling. pprof's attempt to set its
+	// rate is then ignored. Once the profiler is stopped, the rate is reset.
+	if hz != DefaultCPUProfileRate {
+		runtime.SetCPUProfileRate(hz)
+	}
 	if err := pprof.StartCPUProfile(file); err != nil {
+		if hz != DefaultCPUProfileRate {
+			runtime.SetCPUProfileRate(0)
+		}
 		file.Close()
 		return err
 	}
@@ -79,3 +115,109 @@ func (p *Performance) LockProfile(filename string) error {
 	}
 	return file.Close()
 }
+
+// CaptureProfile writes a profile of type [profileType], which is "cpu" or one
+// of pprof.Profiles(), captured over [duration] to [filename]. Returns the
+// number of bytes written once the profile has been captured. If [ctx] is done
+// before [duration] has passed, the capture is stopped and the context's error
+// is returned.
+//
+// cpu profiles sample the cpu usage during [duration]. block and mutex
+// profiles record every blocking event during [duration], on top of the
+// events recorded before. Other profiles are snapshots taken once [duration]
+// has passed.
+func (p *Performance) CaptureProfile(ctx context.Context, filename, profileType string, duration time.Duration) (int64, error) {
+	if duration < 0 || duration > MaxProfileDuration {
+		return 0, errProfileDurationOutOfRange
+	}
+	profile := (*pprof.Profile)(nil)
+	if profileType != cpuProfile {
+		profile = pprof.Lookup(profileType)
+		if profile == nil {
+			return 0, fmt.Errorf("unknown profile type %q. Should be one of %v", profileType, profileTypes())
+		}
+	}
+
+	file, err := os.Create(filename)
+	if err != nil {
+		return 0, err
+	}
+	writer := &countingWriter{writer: file}
+	if err := captureProfile(ctx, writer, profile, duration); err != nil {
+		// A cancelled cpu profile is written when the profiler stops, so
+		// it's removed rather than left for a complete profile
+		file.Close()
+		os.Remove(filename)
+		return 0, err
+	}
+	return writer.written, file.Close()
+}
+
+// captureProfile writes [profile], or a cpu profile if [profile] is nil,
+// captured over [duration] to [w]
+func captureProfile(ctx context.Context, w io.Writer, profile *pprof.Profile, duration time.Duration) error {
+	if profile == nil {
+		if err := pprof.StartCPUProfile(w); err != nil {
+			return err
+		}
+		err := sleep(ctx, duration)
+		pprof.StopCPUProfile()
+		return err
+	}
+
+	var err error
+	switch profile.Name() {
+	case "block":
+		runtime.SetBlockProfileRate(1)
+		err = sleep(ctx, duration)
+		runtime.SetBlockProfileRate(0)
+	case "mutex":
+		previous := runtime.SetMutexProfileFraction(1)
+		err = sleep(ctx, duration)
+		runtime.SetMutexProfileFraction(previous)
+	case "heap", "allocs":
+		err = sleep(ctx, duration)
+		runtime.GC() // get up-to-date statistics
+	default:
+		err = sleep(ctx, duration)
+	}
+	if err != nil {
+		return err
+	}
+	return profile.WriteTo(w, 0)
+}
+
+// sleep waits for [duration] to pass. Returns the context's error if [ctx] is
+// done first.
+func sleep(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// profileTypes returns the types of profiles that can be captured
+func profileTypes() []string {
+	types := []string{cpuProfile}
+	for _, profile := range pprof.Profiles() {
+		types = append(types, profile.Name())
+	}
+	return types
+}
+
+// countingWriter counts the bytes written to [writer]
+type countingWriter struct {
+	writer  io.Writer
+	written int64
+}
+
+func (w *countingWriter) Write(b []byte) (int, error) {
+	n, err := w.writer.Write(b)
+	w.written += int64(n)
+	return n, err
+}
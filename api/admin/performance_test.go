@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"context"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCaptureProfileCancelled(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "cpu.profile")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		_, err := (&Performance{}).CaptureProfile(ctx, filename, cpuProfile, MaxProfileDuration)
+		done <- err
+	}()
+	cancel()
+
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Fatalf("expected the capture to be cancelled but got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the capture to stop once it was cancelled")
+	}
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Fatalf("expected the cancelled profile to be removed but got %v", err)
+	}
+}
+
+func TestCaptureProfile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	filename := filepath.Join(dir, "goroutine.profile")
+
+	written, err := (&Performance{}).CaptureProfile(context.Background(), filename, "goroutine", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if written == 0 || info.Size() != written {
+		t.Fatalf("expected %d bytes to be written but the profile has %d", written, info.Size())
+	}
+}
@@ -0,0 +1,160 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/snowman"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// Accepted decisions are counted in buckets of [acceptedBucketDuration],
+	// for up to [acceptedBuckets] buckets
+	acceptedBucketDuration = 10 * time.Second
+	acceptedBuckets        = 90
+)
+
+// chain describes a chain that was created on this node
+type chain struct {
+	ctx *snow.Context
+	vm  interface{}
+
+	// counts the transactions accepted by the chain recently
+	accepted *timer.BucketedMeter
+
+	// counts the transactions accepted and rejected by the chain since it was
+	// created
+	decisions *decisionCounter
+
+	// when the chain's most recent blocks or vertices were accepted
+	acceptTimes *acceptTimes
+
+	// the height of a block a linear chain accepted
+	heights *heightCache
+}
+
+// decisionCounter counts the transactions a chain accepts and rejects
+type decisionCounter struct {
+	// ticked for every transaction accepted
+	meter *timer.BucketedMeter
+
+	// the chain's VM, if the chain is linear, so that the transactions in its
+	// blocks can be counted
+	blocks blockVM
+
+	lock               sync.Mutex
+	accepted, rejected uint64
+}
+
+// Accept implements the triggers.Acceptor interface
+func (d *decisionCounter) Accept(_, containerID ids.ID, _ []byte) error {
+	numTxs := d.numTxs(containerID)
+	d.meter.TickN(numTxs)
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.accepted += uint64(numTxs)
+	return nil
+}
+
+// Reject implements the triggers.Rejector interface
+func (d *decisionCounter) Reject(_, containerID ids.ID, _ []byte) error {
+	numTxs := d.numTxs(containerID)
+
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	d.rejected += uint64(numTxs)
+	return nil
+}
+
+// numTxs returns the number of transactions decided on by the decision with
+// ID [containerID]. Decisions on a DAG based chain are transactions. A block
+// is counted as a single transaction if its VM doesn't report how many
+// transactions it contains. Decisions are dispatched while the chain's lock is
+// held, so the VM may be called.
+func (d *decisionCounter) numTxs(containerID ids.ID) int {
+	if d.blocks == nil {
+		return 1
+	}
+	blk, err := d.blocks.GetBlock(containerID)
+	if err != nil {
+		return 1
+	}
+	txBlk, ok := blk.(snowman.TxBlock)
+	if !ok {
+		return 1
+	}
+	return txBlk.NumTxs()
+}
+
+// counts returns the number of transactions accepted and rejected
+func (d *decisionCounter) counts() (accepted, rejected uint64) {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+	return d.accepted, d.rejected
+}
+
+// registry keeps track of the chains that have been created on this node. It
+// is registered with the chain manager so that it is notified of every chain
+// as it is created.
+type registry struct {
+	lock   sync.RWMutex
+	chains []chain
+}
+
+// RegisterChain implements the chains.Registrant interface
+func (r *registry) RegisterChain(ctx *snow.Context, vm interface{}) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	accepted := timer.NewBucketedMeter(acceptedBucketDuration, acceptedBuckets)
+	decisions := &decisionCounter{meter: accepted}
+	if vm, ok := vm.(blockVM); ok {
+		decisions.blocks = vm
+	}
+	if err := ctx.DecisionDispatcher.RegisterChain(ctx.ChainID, "admin", decisions); err != nil {
+		ctx.Log.Warn("couldn't count the decisions made by %s: %s", ctx.ChainID, err)
+	}
+	acceptTimes := &acceptTimes{}
+	if err := ctx.ConsensusDispatcher.RegisterChain(ctx.ChainID, "admin", acceptTimes); err != nil {
+		ctx.Log.Warn("couldn't record when the containers of %s are accepted: %s", ctx.ChainID, err)
+	}
+	r.chains = append(r.chains, chain{
+		ctx:         ctx,
+		vm:          vm,
+		accepted:    accepted,
+		decisions:   decisions,
+		acceptTimes: acceptTimes,
+		heights:     &heightCache{},
+	})
+}
+
+// list returns the chains that have been created, in the order they were
+// created
+func (r *registry) list() []chain {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	chains := make([]chain, len(r.chains))
+	copy(chains, r.chains)
+	return chains
+}
+
+// get returns the chain with ID [chainID], if it has been created
+func (r *registry) get(chainID ids.ID) (chain, bool) {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, chain := range r.chains {
+		if chain.ctx.ChainID.Equals(chainID) {
+			return chain, true
+		}
+	}
+	return chain{}, false
+}
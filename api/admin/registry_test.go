@@ -0,0 +1,65 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// testTxBlock is a block that reports how many transactions it contains
+type testTxBlock struct {
+	*testBlock
+	txs int
+}
+
+func (b *testTxBlock) NumTxs() int { return b.txs }
+
+func TestDecisionCounterCountsTxs(t *testing.T) {
+	vm := newTestBlockVM(1)
+	txBlk := &testTxBlock{
+		testBlock: &testBlock{id: ids.Empty.Prefix(1), status: choices.Accepted},
+		txs:       3,
+	}
+	counter := &decisionCounter{
+		meter:  timer.NewBucketedMeter(time.Minute, 1),
+		blocks: &testTxBlockVM{testBlockVM: vm, txBlock: txBlk},
+	}
+
+	// A block that doesn't report its transactions counts as one
+	if err := counter.Accept(ids.Empty, ids.Empty.Prefix(0), nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := counter.Accept(ids.Empty, txBlk.id, nil); err != nil {
+		t.Fatal(err)
+	}
+	if ticks := counter.meter.Ticks(); ticks != 4 {
+		t.Fatalf("expected 4 transactions to have been accepted but got %d", ticks)
+	}
+
+	if err := counter.Reject(ids.Empty, txBlk.id, nil); err != nil {
+		t.Fatal(err)
+	}
+	if accepted, rejected := counter.counts(); accepted != 4 || rejected != 3 {
+		t.Fatalf("expected 4 transactions to have been accepted and 3 rejected but got %d and %d", accepted, rejected)
+	}
+}
+
+// testTxBlockVM returns [txBlock] in place of the block with the same ID
+type testTxBlockVM struct {
+	*testBlockVM
+	txBlock *testTxBlock
+}
+
+func (vm *testTxBlockVM) GetBlock(blkID ids.ID) (snowman.Block, error) {
+	if blkID.Equals(vm.txBlock.id) {
+		return vm.txBlock, nil
+	}
+	return vm.testBlockVM.GetBlock(blkID)
+}
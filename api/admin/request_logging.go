@@ -0,0 +1,101 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp"
+)
+
+// requestLoggingVM is implemented by the VMs whose HTTP requests are logged by
+// the bridge to their plugin
+type requestLoggingVM interface {
+	HTTPRequestLogLevel() ghttp.RequestLogLevel
+	SetHTTPRequestLogLevel(level ghttp.RequestLogLevel) error
+}
+
+// requestLoggingVM returns a chain's instance of the VM [vmAlias], if the VM's
+// HTTP requests are logged by the bridge to its plugin. The level is shared by
+// the chains the plugin runs, so any of them can be used to change it.
+func (service *Admin) requestLoggingVM(vmAlias string) (requestLoggingVM, error) {
+	vmID, err := service.chainManager.LookupVM(vmAlias)
+	if err != nil {
+		return nil, fmt.Errorf("couldn't find VM %q: %w", vmAlias, err)
+	}
+	for _, chain := range service.chains.list() {
+		chainVMID, ok := service.chainManager.ChainVM(chain.ctx.ChainID)
+		if !ok || !chainVMID.Equals(vmID) {
+			continue
+		}
+		if vm, ok := chain.vm.(requestLoggingVM); ok {
+			return vm, nil
+		}
+	}
+	return nil, fmt.Errorf("no chain running VM %q is run by a plugin", vmAlias)
+}
+
+// PluginRequestLogLevelArgs are the arguments for calling
+// GetPluginRequestLogLevel
+type PluginRequestLogLevelArgs struct {
+	// ID or alias of the VM
+	VM string `json:"vm"`
+}
+
+// SetPluginRequestLogLevelArgs are the arguments for calling
+// SetPluginRequestLogLevel
+type SetPluginRequestLogLevelArgs struct {
+	// ID or alias of the VM
+	VM string `json:"vm"`
+
+	// One of "none", "metadata", "headers" or "full"
+	Level string `json:"level"`
+}
+
+// PluginRequestLogLevelReply are the results from calling
+// GetPluginRequestLogLevel and SetPluginRequestLogLevel
+type PluginRequestLogLevelReply struct {
+	// The level the plugin's requests are logged at from now on
+	Level string `json:"level"`
+}
+
+// GetPluginRequestLogLevel returns how verbosely the requests to the HTTP
+// handlers of a VM run by a plugin are logged
+func (service *Admin) GetPluginRequestLogLevel(_ *http.Request, args *PluginRequestLogLevelArgs, reply *PluginRequestLogLevelReply) error {
+	service.log.Debug("Admin: GetPluginRequestLogLevel called with %s", args.VM)
+
+	vm, err := service.requestLoggingVM(args.VM)
+	if err != nil {
+		return err
+	}
+	reply.Level = vm.HTTPRequestLogLevel().String()
+	return nil
+}
+
+// SetPluginRequestLogLevel sets how verbosely the requests to the HTTP handlers
+// of a VM run by a plugin are logged, for every chain the VM runs. "metadata"
+// logs the method, URL, status code and duration of each request at the debug
+// level. "headers" logs their headers too, and "full" the start of their
+// bodies, at the info level, so that one misbehaving plugin can be looked into
+// without raising the verbosity of the node's log. Bodies may hold secrets such
+// as keystore passwords, so "full" should only be used while debugging a
+// problem.
+func (service *Admin) SetPluginRequestLogLevel(_ *http.Request, args *SetPluginRequestLogLevelArgs, reply *PluginRequestLogLevelReply) error {
+	service.log.Info("Admin: SetPluginRequestLogLevel called with %s %s", args.VM, args.Level)
+
+	level, err := ghttp.ParseRequestLogLevel(args.Level)
+	if err != nil {
+		return err
+	}
+	vm, err := service.requestLoggingVM(args.VM)
+	if err != nil {
+		return err
+	}
+	if err := vm.SetHTTPRequestLogLevel(level); err != nil {
+		return err
+	}
+	reply.Level = vm.HTTPRequestLogLevel().String()
+	return nil
+}
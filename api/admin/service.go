@@ -4,22 +4,42 @@
 package admin
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"net"
 	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/gorilla/rpc/v2"
 
 	"github.com/ava-labs/gecko/api"
 	"github.com/ava-labs/gecko/chains"
+	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/network"
 	"github.com/ava-labs/gecko/snow/engine/common"
+	"github.com/ava-labs/gecko/utils"
+	"github.com/ava-labs/gecko/utils/labels"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/version"
 
 	cjson "github.com/ava-labs/gecko/utils/json"
 )
 
+var (
+	errThrottlerLimitTooLarge  = errors.New("throttler limit is too large")
+	errProfileCaptureRunning   = errors.New("a profile is already being captured")
+	errNoProfileCaptureRunning = errors.New("no profile is being captured")
+	errChainsNotCreated        = errors.New("chains are still being created")
+	errConsensusTuningDisabled = errors.New("changing consensus parameters is disabled")
+)
+
 // Admin is the API service for node admin management
 type Admin struct {
 	version      version.Version
@@ -30,22 +50,113 @@ type Admin struct {
 	performance  Performance
 	chainManager chains.Manager
 	httpServer   *api.Server
+	chains       *registry
+	externalIP   ExternalIP
+	tlsConfig    TLSConfig
+	nat          NAT
+
+	// The node's database, and the directory it's stored in. The directory
+	// is empty if the database is held in memory.
+	db          database.Database
+	dbPath      string
+	dbProbeLock sync.Mutex
+
+	// true while a compaction triggered by TriggerCompaction is running
+	compactionLock sync.Mutex
+	compacting     bool
+
+	// true if consensus parameters may be changed at runtime
+	consensusTuningEnabled bool
+	// true if blocks may be accepted without waiting for consensus
+	forceAcceptEnabled bool
+
+	shutdown shutdownScheduler
+
+	// The profile being captured by CaptureProfile, or the last one that was
+	profileLock    sync.Mutex
+	profileCapture profileCapture
+}
+
+// profileCapture is a profile captured in the background by CaptureProfile
+type profileCapture struct {
+	profileType, filename string
+	started, finished     time.Time
+
+	// Stops the capture. nil once the capture has finished.
+	cancel context.CancelFunc
+
+	// Set once the capture has finished
+	bytes int64
+	err   error
+}
+
+// ExternalIP describes the IP this node advertises to its peers
+type ExternalIP struct {
+	IP utils.IPDesc
+
+	// How the IP was determined. One of "static" if it was configured, "upnp"
+	// or "nat-pmp" if it was fetched from the NAT router, "none" if no NAT
+	// router was discovered to fetch it from, or "unknown" if the NAT router
+	// couldn't provide it
+	Source string
+
+	// Time the IP was determined at
+	Time time.Time
+}
+
+// Config is the configuration of the admin API service
+type Config struct {
+	Version      version.Version
+	NodeID       ids.ShortID
+	NetworkID    uint32
+	Log          logging.Logger
+	ChainManager chains.Manager
+	Networking   network.Network
+	HTTPServer   *api.Server
+	ExternalIP   ExternalIP
+	TLSConfig    TLSConfig
+	NAT          NAT
+
+	// The node's database, and the directory it's stored in. The directory
+	// is empty if the database is held in memory.
+	DB     database.Database
+	DBPath string
+
+	// True if consensus parameters may be changed at runtime
+	ConsensusTuningEnabled bool
+	// True if blocks may be accepted without waiting for consensus
+	ForceAcceptEnabled bool
+
+	// Shuts the node down gracefully
+	Shutdown func()
 }
 
 // NewService returns a new admin API service
-func NewService(version version.Version, nodeID ids.ShortID, networkID uint32, log logging.Logger, chainManager chains.Manager, peers network.Network, httpServer *api.Server) *common.HTTPHandler {
+func NewService(config Config) *common.HTTPHandler {
 	newServer := rpc.NewServer()
 	codec := cjson.NewCodec()
 	newServer.RegisterCodec(codec, "application/json")
 	newServer.RegisterCodec(codec, "application/json;charset=UTF-8")
+	chains := &registry{}
+	config.ChainManager.AddRegistrant(chains)
 	newServer.RegisterService(&Admin{
-		version:      version,
-		nodeID:       nodeID,
-		networkID:    networkID,
-		log:          log,
-		chainManager: chainManager,
-		networking:   peers,
-		httpServer:   httpServer,
+		version:      config.Version,
+		nodeID:       config.NodeID,
+		networkID:    config.NetworkID,
+		log:          config.Log,
+		chainManager: config.ChainManager,
+		networking:   config.Networking,
+		httpServer:   config.HTTPServer,
+		chains:       chains,
+		externalIP:   config.ExternalIP,
+		tlsConfig:    config.TLSConfig,
+		nat:          config.NAT,
+		db:           config.DB,
+		dbPath:       config.DBPath,
+
+		consensusTuningEnabled: config.ConsensusTuningEnabled,
+		forceAcceptEnabled:     config.ForceAcceptEnabled,
+		shutdown:               shutdownScheduler{shutdown: config.Shutdown},
 	}, "admin")
 	return &common.HTTPHandler{Handler: newServer}
 }
@@ -133,21 +244,478 @@ func (service *Admin) Peers(_ *http.Request, _ *struct{}, reply *PeersReply) err
 	return nil
 }
 
+// GetPeersPlainReply are the results from calling GetPeersPlain
+type GetPeersPlainReply struct {
+	Peers string `json:"peers"`
+}
+
+// GetPeersPlain returns the peers this node is connected to as plain text,
+// for use with shell tools. Each line is of the form "IP NodeID Version", where
+// IP is the address the peer is connected from without its port. Lines are
+// sorted by node ID.
+func (service *Admin) GetPeersPlain(_ *http.Request, _ *struct{}, reply *GetPeersPlainReply) error {
+	service.log.Debug("Admin: GetPeersPlain called")
+
+	peers := service.networking.Peers()
+	sort.Slice(peers, func(i, j int) bool {
+		return peers[i].ID.String() < peers[j].ID.String()
+	})
+
+	sb := strings.Builder{}
+	for _, peer := range peers {
+		host, _, err := net.SplitHostPort(peer.IP)
+		if err != nil {
+			host = peer.IP
+		}
+		sb.WriteString(fmt.Sprintf("%s %s %s\n", host, peer.ID, peer.Version))
+	}
+	reply.Peers = sb.String()
+	return nil
+}
+
+// GetPeerLatencyHistogramArgs are the arguments for calling
+// GetPeerLatencyHistogram
+type GetPeerLatencyHistogramArgs struct {
+	NodeID string `json:"nodeID"`
+}
+
+// LatencyBucket counts the round trips that took at most UpperBound, and more
+// than the previous bucket's UpperBound
+type LatencyBucket struct {
+	// Empty for the last bucket, which has no upper bound
+	UpperBound string       `json:"upperBound"`
+	Count      cjson.Uint64 `json:"count"`
+}
+
+// GetPeerLatencyHistogramReply are the results from calling
+// GetPeerLatencyHistogram
+type GetPeerLatencyHistogramReply struct {
+	Buckets []LatencyBucket `json:"buckets"`
+}
+
+// GetPeerLatencyHistogram returns a histogram of the times between sending
+// consensus requests, such as a Get, to a peer and receiving its response, such
+// as a Put. Requests that timed out aren't counted.
+func (service *Admin) GetPeerLatencyHistogram(_ *http.Request, args *GetPeerLatencyHistogramArgs, reply *GetPeerLatencyHistogramReply) error {
+	service.log.Debug("Admin: GetPeerLatencyHistogram called with %s", args.NodeID)
+
+	nodeID, err := ids.ShortFromString(args.NodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't parse node ID: %w", err)
+	}
+
+	latencies := service.chainManager.Router().Latencies(nodeID)
+	reply.Buckets = make([]LatencyBucket, len(latencies.Counts))
+	for i, count := range latencies.Counts {
+		reply.Buckets[i].Count = cjson.Uint64(count)
+		if i < len(latencies.Bounds) {
+			reply.Buckets[i].UpperBound = latencies.Bounds[i].String()
+		}
+	}
+	return nil
+}
+
+// GetThrottlerStateReply are the results from calling GetThrottlerState
+type GetThrottlerStateReply struct {
+	Throttler network.ThrottlerState `json:"throttler"`
+}
+
+// GetThrottlerState returns the limits and current usage of the outbound
+// message throttler
+func (service *Admin) GetThrottlerState(_ *http.Request, _ *struct{}, reply *GetThrottlerStateReply) error {
+	service.log.Debug("Admin: GetThrottlerState called")
+	reply.Throttler = service.networking.ThrottlerState()
+	return nil
+}
+
+// SetThrottlerLimitsArgs are the arguments for calling SetThrottlerLimits
+type SetThrottlerLimitsArgs struct {
+	MaxPendingSendBytes         cjson.Uint64 `json:"maxPendingSendBytes"`
+	PendingSendBytesToRateLimit cjson.Uint64 `json:"pendingSendBytesToRateLimit"`
+}
+
+// SetThrottlerLimitsReply are the results from calling SetThrottlerLimits
+type SetThrottlerLimitsReply struct {
+	Success bool `json:"success"`
+}
+
+// SetThrottlerLimits replaces the limits of the outbound message throttler.
+// This is intended to be used to temporarily loosen throttling while a node is
+// catching up. The original limits can be restored with ResetThrottlerLimits.
+func (service *Admin) SetThrottlerLimits(_ *http.Request, args *SetThrottlerLimitsArgs, reply *SetThrottlerLimitsReply) error {
+	service.log.Debug("Admin: SetThrottlerLimits called with MaxPendingSendBytes: %d, PendingSendBytesToRateLimit: %d",
+		args.MaxPendingSendBytes,
+		args.PendingSendBytesToRateLimit)
+
+	if uint64(args.MaxPendingSendBytes) > math.MaxInt32 || uint64(args.PendingSendBytesToRateLimit) > math.MaxInt32 {
+		return errThrottlerLimitTooLarge
+	}
+	if err := service.networking.SetThrottlerLimits(int(args.MaxPendingSendBytes), int(args.PendingSendBytesToRateLimit)); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// ResetThrottlerLimitsReply are the results from calling ResetThrottlerLimits
+type ResetThrottlerLimitsReply struct {
+	Success bool `json:"success"`
+}
+
+// ResetThrottlerLimits restores the limits the outbound message throttler was
+// started with
+func (service *Admin) ResetThrottlerLimits(_ *http.Request, _ *struct{}, reply *ResetThrottlerLimitsReply) error {
+	service.log.Debug("Admin: ResetThrottlerLimits called")
+	service.networking.ResetThrottlerLimits()
+	reply.Success = true
+	return nil
+}
+
+// GetExternalIPReply are the results from calling GetExternalIP
+type GetExternalIPReply struct {
+	// IP and port this node advertises to its peers
+	IP string `json:"ip"`
+
+	// False if the IP couldn't be determined, in which case peers can't
+	// connect to this node
+	Known bool `json:"known"`
+
+	// How the IP was determined: "static", "upnp", "nat-pmp", "none" or
+	// "unknown". The IP isn't known if it's "none" or "unknown".
+	Source string `json:"source"`
+
+	// Time the IP was determined at, in RFC 3339 format
+	LastUpdated string `json:"lastUpdated"`
+}
+
+// GetExternalIP returns the IP this node advertises to its peers and how it was
+// determined. The IP is determined once, when the node starts; it isn't
+// updated from what peers observe.
+func (service *Admin) GetExternalIP(_ *http.Request, _ *struct{}, reply *GetExternalIPReply) error {
+	service.log.Debug("Admin: GetExternalIP called")
+
+	reply.IP = service.externalIP.IP.String()
+	reply.Known = !service.externalIP.IP.IsZero()
+	reply.Source = service.externalIP.Source
+	reply.LastUpdated = service.externalIP.Time.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// GetClockStatusReply are the results from calling GetClockStatus
+type GetClockStatusReply struct {
+	// Estimated amount of time this node's clock is ahead of its peers'
+	OffsetEstimate string `json:"offsetEstimate"`
+
+	// Number of recent peer measurements the estimate is the median of
+	SampleCount cjson.Uint32 `json:"sampleCount"`
+}
+
+// GetClockStatus returns the estimated offset of this node's clock from the
+// clocks of its peers
+func (service *Admin) GetClockStatus(_ *http.Request, _ *struct{}, reply *GetClockStatusReply) error {
+	service.log.Debug("Admin: GetClockStatus called")
+	status := service.networking.ClockStatus()
+	reply.OffsetEstimate = status.OffsetEstimate.String()
+	reply.SampleCount = cjson.Uint32(status.SampleCount)
+	return nil
+}
+
+// GetDroppedMessagesReply are the results from calling GetDroppedMessages
+type GetDroppedMessagesReply struct {
+	// Number of messages dropped since the node started, by reason. Reasons
+	// nothing was dropped for are omitted.
+	Inbound  map[string]cjson.Uint64 `json:"inbound"`
+	Outbound map[string]cjson.Uint64 `json:"outbound"`
+}
+
+// GetDroppedMessages returns the number of peer to peer messages the node
+// dropped, by direction and reason. Inbound messages are dropped if they're
+// "invalid" or arrive before the handshake, when the peer is "not connected".
+// Outbound messages are dropped if the peer's send "queue full", the peer is
+// "peer gone", or too many bytes are pending and the message is "throttled".
+func (service *Admin) GetDroppedMessages(_ *http.Request, _ *struct{}, reply *GetDroppedMessagesReply) error {
+	service.log.Debug("Admin: GetDroppedMessages called")
+
+	dropped := service.networking.DroppedMessages()
+	reply.Inbound = make(map[string]cjson.Uint64, len(dropped.Inbound))
+	for reason, count := range dropped.Inbound {
+		reply.Inbound[reason] = cjson.Uint64(count)
+	}
+	reply.Outbound = make(map[string]cjson.Uint64, len(dropped.Outbound))
+	for reason, count := range dropped.Outbound {
+		reply.Outbound[reason] = cjson.Uint64(count)
+	}
+	return nil
+}
+
+// PeerChurn is the number of peers that connected and disconnected during a
+// window of time
+type PeerChurn struct {
+	Connects    cjson.Uint64 `json:"connects"`
+	Disconnects cjson.Uint64 `json:"disconnects"`
+}
+
+// GetPeerChurnReply are the results from calling GetPeerChurn
+type GetPeerChurnReply struct {
+	LastMinute    PeerChurn `json:"lastMinute"`
+	Last5Minutes  PeerChurn `json:"last5Minutes"`
+	Last15Minutes PeerChurn `json:"last15Minutes"`
+}
+
+// GetPeerChurn returns the number of peers that connected and disconnected in
+// the last 1, 5 and 15 minutes. Many of both indicate that the network, or
+// this node's connection to it, is unstable. The counts are kept in 10 second
+// buckets, so each window may include up to 10 seconds more than its length.
+func (service *Admin) GetPeerChurn(_ *http.Request, _ *struct{}, reply *GetPeerChurnReply) error {
+	service.log.Debug("Admin: GetPeerChurn called")
+
+	reply.LastMinute = service.peerChurn(time.Minute)
+	reply.Last5Minutes = service.peerChurn(5 * time.Minute)
+	reply.Last15Minutes = service.peerChurn(15 * time.Minute)
+	return nil
+}
+
+func (service *Admin) peerChurn(window time.Duration) PeerChurn {
+	churn := service.networking.PeerChurn(window)
+	return PeerChurn{
+		Connects:    cjson.Uint64(churn.Connects),
+		Disconnects: cjson.Uint64(churn.Disconnects),
+	}
+}
+
+// GossipConfig describes how aggressively the node gossips
+type GossipConfig struct {
+	// Time between two rounds of peer list gossip, such as "1m30s"
+	PeerListGossipInterval string `json:"peerListGossipInterval"`
+
+	// Number of peers a peer list is gossiped to in each round
+	PeerListGossipSize cjson.Uint32 `json:"peerListGossipSize"`
+
+	// Number of peers an accepted container is gossiped to
+	AcceptedGossipSize cjson.Uint32 `json:"acceptedGossipSize"`
+}
+
+// GetGossipConfigReply are the results from calling GetGossipConfig
+type GetGossipConfigReply struct {
+	Gossip GossipConfig `json:"gossip"`
+}
+
+// GetGossipConfig returns the parameters that currently control gossiping
+func (service *Admin) GetGossipConfig(_ *http.Request, _ *struct{}, reply *GetGossipConfigReply) error {
+	service.log.Debug("Admin: GetGossipConfig called")
+	config := service.networking.GossipConfig()
+	reply.Gossip = GossipConfig{
+		PeerListGossipInterval: config.PeerListGossipSpacing.String(),
+		PeerListGossipSize:     cjson.Uint32(config.PeerListGossipSize),
+		AcceptedGossipSize:     cjson.Uint32(config.GossipSize),
+	}
+	return nil
+}
+
+// SetGossipConfigReply are the results from calling SetGossipConfig
+type SetGossipConfigReply struct {
+	Success bool `json:"success"`
+}
+
+// SetGossipConfig replaces the parameters that control gossiping. Fields that
+// are left empty keep their current value.
+func (service *Admin) SetGossipConfig(_ *http.Request, args *GossipConfig, reply *SetGossipConfigReply) error {
+	service.log.Debug("Admin: SetGossipConfig called")
+
+	config := service.networking.GossipConfig()
+	if args.PeerListGossipInterval != "" {
+		interval, err := time.ParseDuration(args.PeerListGossipInterval)
+		if err != nil {
+			return fmt.Errorf("couldn't parse peer list gossip interval: %w", err)
+		}
+		config.PeerListGossipSpacing = interval
+	}
+	if args.PeerListGossipSize != 0 {
+		config.PeerListGossipSize = int(args.PeerListGossipSize)
+	}
+	if args.AcceptedGossipSize != 0 {
+		config.GossipSize = int(args.AcceptedGossipSize)
+	}
+
+	if err := service.networking.SetGossipConfig(config); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
+
+// GossipTarget is a peer that was gossiped to
+type GossipTarget struct {
+	NodeID string `json:"nodeID"`
+
+	// true if the peer was a validator when it was gossiped to
+	Validator bool `json:"validator"`
+}
+
+// GossipSample is the peers that were gossiped to in a round of gossip
+type GossipSample struct {
+	// Time of the round, or empty if there hasn't been a round yet
+	Time string `json:"time"`
+
+	Peers []GossipTarget `json:"peers"`
+}
+
+func newGossipSample(sample network.GossipSample) GossipSample {
+	reply := GossipSample{Peers: make([]GossipTarget, len(sample.Peers))}
+	if !sample.Time.IsZero() {
+		reply.Time = sample.Time.Format(time.RFC3339)
+	}
+	for i, peer := range sample.Peers {
+		reply.Peers[i] = GossipTarget{
+			NodeID:    peer.ID.String(),
+			Validator: peer.Validator,
+		}
+	}
+	return reply
+}
+
+// GetGossipSampleReply are the results from calling GetGossipSample
+type GetGossipSampleReply struct {
+	// Peers the peer list was gossiped to in the most recent round
+	PeerList GossipSample `json:"peerList"`
+
+	// Peers the most recently accepted container was gossiped to
+	Accepted GossipSample `json:"accepted"`
+}
+
+// GetGossipSample returns the peers that were gossiped to in the most recent
+// rounds of gossip, and whether each of them is a validator
+func (service *Admin) GetGossipSample(_ *http.Request, _ *struct{}, reply *GetGossipSampleReply) error {
+	service.log.Debug("Admin: GetGossipSample called")
+
+	samples := service.networking.GossipSamples()
+	reply.PeerList = newGossipSample(samples.PeerList)
+	reply.Accepted = newGossipSample(samples.Accepted)
+	return nil
+}
+
+// RefreshPeersReply are the results from calling RefreshPeers
+type RefreshPeersReply struct {
+	// Number of connected peers whose peer lists were requested
+	Requested cjson.Uint32 `json:"requested"`
+}
+
+// RefreshPeers requests the peer lists of every connected peer immediately,
+// rather than waiting for them to be gossiped, and returns without waiting for
+// the replies. The number of new peers learned of since can be followed with
+// GetPeerRefreshStatus. New peers are connected to in the background.
+func (service *Admin) RefreshPeers(_ *http.Request, _ *struct{}, reply *RefreshPeersReply) error {
+	service.log.Info("Admin: RefreshPeers called")
+
+	requested, err := service.networking.RefreshPeers()
+	reply.Requested = cjson.Uint32(requested)
+	return err
+}
+
+// GetPeerRefreshStatusReply are the results from calling GetPeerRefreshStatus
+type GetPeerRefreshStatusReply struct {
+	// Time the peer lists were last requested by RefreshPeers, in RFC 3339
+	// format. Empty if they haven't been since the node started.
+	Time string `json:"time,omitempty"`
+
+	// Number of connected peers whose peer lists were requested
+	Requested cjson.Uint32 `json:"requested"`
+
+	// Number of peers that were learned of since the peer lists were
+	// requested, and weren't already known
+	NewPeers cjson.Uint32 `json:"newPeers"`
+}
+
+// GetPeerRefreshStatus returns when RefreshPeers last requested the peer lists
+// of this node's peers, and how many new peers were learned of since
+func (service *Admin) GetPeerRefreshStatus(_ *http.Request, _ *struct{}, reply *GetPeerRefreshStatusReply) error {
+	service.log.Debug("Admin: GetPeerRefreshStatus called")
+
+	refresh := service.networking.LastPeerRefresh()
+	if !refresh.Time.IsZero() {
+		reply.Time = refresh.Time.UTC().Format(time.RFC3339)
+	}
+	reply.Requested = cjson.Uint32(refresh.Requested)
+	reply.NewPeers = cjson.Uint32(refresh.NewPeers)
+	return nil
+}
+
+// RecoveredPanic describes a panic raised by an API handler that was recovered
+// from
+type RecoveredPanic struct {
+	// Time the panic was recovered from, in RFC 3339 format
+	Time string `json:"time"`
+
+	// Where the panic was raised, such as "http:/ext/admin" or
+	// "plugin:/ext/bc/<chain ID>"
+	Subsystem string `json:"subsystem"`
+
+	// Value the handler panicked with
+	Value string `json:"value"`
+
+	// Stack of the goroutine that panicked
+	Stack string `json:"stack"`
+}
+
+// GetRecentPanicsReply are the results from calling GetRecentPanics
+type GetRecentPanicsReply struct {
+	// Most recent panics, from oldest to newest
+	Panics []RecoveredPanic `json:"panics"`
+
+	// Number of panics that were recovered from since the node started
+	Total cjson.Uint64 `json:"total"`
+}
+
+// GetRecentPanics returns the most recent panics raised by API handlers,
+// including handlers run by plugins, that were recovered from. Panics raised
+// elsewhere stop the node, so they are only reported in its logs.
+func (service *Admin) GetRecentPanics(_ *http.Request, _ *struct{}, reply *GetRecentPanicsReply) error {
+	service.log.Debug("Admin: GetRecentPanics called")
+
+	panics, total := service.httpServer.RecentPanics()
+	reply.Panics = make([]RecoveredPanic, len(panics))
+	for i, p := range panics {
+		reply.Panics[i] = RecoveredPanic{
+			Time:      p.Time.Format(time.RFC3339),
+			Subsystem: p.Subsystem,
+			Value:     p.Value,
+			Stack:     p.Stack,
+		}
+	}
+	reply.Total = cjson.Uint64(total)
+	return nil
+}
+
 // StartCPUProfilerArgs are the arguments for calling StartCPUProfiler
 type StartCPUProfilerArgs struct {
 	Filename string `json:"filename"`
+
+	// Number of samples to take per second. Defaults to 100.
+	HZ int `json:"hz"`
 }
 
 // StartCPUProfilerReply are the results from calling StartCPUProfiler
 type StartCPUProfilerReply struct {
 	Success bool `json:"success"`
+
+	// Number of samples being taken per second
+	HZ cjson.Uint32 `json:"hz"`
 }
 
 // StartCPUProfiler starts a cpu profile writing to the specified file
 func (service *Admin) StartCPUProfiler(_ *http.Request, args *StartCPUProfilerArgs, reply *StartCPUProfilerReply) error {
-	service.log.Debug("Admin: StartCPUProfiler called with %s", args.Filename)
+	service.log.Debug("Admin: StartCPUProfiler called with %s at %dHz", args.Filename, args.HZ)
+
+	hz := args.HZ
+	if hz == 0 {
+		hz = DefaultCPUProfileRate
+	}
+	if err := service.performance.StartCPUProfiler(args.Filename, hz); err != nil {
+		return err
+	}
 	reply.Success = true
-	return service.performance.StartCPUProfiler(args.Filename)
+	reply.HZ = cjson.Uint32(hz)
+	return nil
 }
 
 // StopCPUProfilerReply are the results from calling StopCPUProfiler
@@ -196,6 +764,142 @@ func (service *Admin) LockProfile(_ *http.Request, args *LockProfileArgs, reply
 	return service.performance.LockProfile(args.Filename)
 }
 
+// CaptureProfileArgs are the arguments for calling CaptureProfile
+type CaptureProfileArgs struct {
+	// One of "cpu", "heap", "allocs", "goroutine", "threadcreate", "block" or
+	// "mutex"
+	Type string `json:"type"`
+
+	// Time to capture the profile over, such as "30s". Defaults to 0, which
+	// is only useful for profiles that aren't time-bounded.
+	Duration string `json:"duration"`
+
+	Filename string `json:"filename"`
+}
+
+// CaptureProfileReply are the results from calling CaptureProfile
+type CaptureProfileReply struct {
+	Success bool `json:"success"`
+}
+
+// CaptureProfile starts capturing a profile of the given type over the given
+// duration, to be written to the specified file, and returns without waiting
+// for it to be captured. Its progress can be followed with
+// GetProfileCaptureStatus, and it can be stopped early with
+// CancelProfileCapture. The capture is refused if one is already running.
+func (service *Admin) CaptureProfile(_ *http.Request, args *CaptureProfileArgs, reply *CaptureProfileReply) error {
+	service.log.Info("Admin: CaptureProfile called with %s over %q to %s", args.Type, args.Duration, args.Filename)
+
+	duration := time.Duration(0)
+	if args.Duration != "" {
+		var err error
+		duration, err = time.ParseDuration(args.Duration)
+		if err != nil {
+			return fmt.Errorf("couldn't parse duration: %w", err)
+		}
+	}
+
+	service.profileLock.Lock()
+	defer service.profileLock.Unlock()
+
+	if service.profileCapture.cancel != nil {
+		return errProfileCaptureRunning
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	service.profileCapture = profileCapture{
+		profileType: args.Type,
+		filename:    args.Filename,
+		started:     time.Now(),
+		cancel:      cancel,
+	}
+
+	go func() {
+		written, err := service.performance.CaptureProfile(ctx, args.Filename, args.Type, duration)
+		cancel()
+		if err != nil {
+			service.log.Warn("couldn't capture %s profile: %s", args.Type, err)
+		}
+
+		service.profileLock.Lock()
+		defer service.profileLock.Unlock()
+
+		service.profileCapture.finished = time.Now()
+		service.profileCapture.cancel = nil
+		service.profileCapture.bytes = written
+		service.profileCapture.err = err
+	}()
+
+	reply.Success = true
+	return nil
+}
+
+// GetProfileCaptureStatusReply are the results from calling
+// GetProfileCaptureStatus
+type GetProfileCaptureStatusReply struct {
+	InProgress bool `json:"inProgress"`
+
+	// The profile being captured, or the last one that was. Empty if no
+	// profile has been captured since the node started.
+	Type     string `json:"type,omitempty"`
+	Filename string `json:"filename,omitempty"`
+
+	// When the capture started and, if it has, finished, in RFC 3339 format
+	Started  string `json:"started,omitempty"`
+	Finished string `json:"finished,omitempty"`
+
+	// Number of bytes written to the file, once the capture has finished
+	Bytes cjson.Uint64 `json:"bytes"`
+
+	// Why the capture failed, if it did
+	Error string `json:"error,omitempty"`
+}
+
+// GetProfileCaptureStatus returns whether a profile is being captured by
+// CaptureProfile, or how the last capture went
+func (service *Admin) GetProfileCaptureStatus(_ *http.Request, _ *struct{}, reply *GetProfileCaptureStatusReply) error {
+	service.log.Debug("Admin: GetProfileCaptureStatus called")
+
+	service.profileLock.Lock()
+	defer service.profileLock.Unlock()
+
+	capture := service.profileCapture
+	reply.InProgress = capture.cancel != nil
+	reply.Type = capture.profileType
+	reply.Filename = capture.filename
+	if !capture.started.IsZero() {
+		reply.Started = capture.started.UTC().Format(time.RFC3339)
+	}
+	if !capture.finished.IsZero() {
+		reply.Finished = capture.finished.UTC().Format(time.RFC3339)
+	}
+	reply.Bytes = cjson.Uint64(capture.bytes)
+	if capture.err != nil {
+		reply.Error = capture.err.Error()
+	}
+	return nil
+}
+
+// CancelProfileCaptureReply are the results from calling CancelProfileCapture
+type CancelProfileCaptureReply struct {
+	Success bool `json:"success"`
+}
+
+// CancelProfileCapture stops the profile being captured by CaptureProfile.
+// The profile isn't written.
+func (service *Admin) CancelProfileCapture(_ *http.Request, _ *struct{}, reply *CancelProfileCaptureReply) error {
+	service.log.Info("Admin: CancelProfileCapture called")
+
+	service.profileLock.Lock()
+	defer service.profileLock.Unlock()
+
+	if service.profileCapture.cancel == nil {
+		return errNoProfileCaptureRunning
+	}
+	service.profileCapture.cancel()
+	reply.Success = true
+	return nil
+}
+
 // AliasArgs are the arguments for calling Alias
 type AliasArgs struct {
 	Endpoint string `json:"endpoint"`
@@ -255,3 +959,30 @@ func (service *Admin) Stacktrace(_ *http.Request, _ *struct{}, reply *Stacktrace
 	reply.Stacktrace = logging.Stacktrace{Global: true}.String()
 	return nil
 }
+
+// GetLabeledGoroutinesReply are the results from calling GetLabeledGoroutines
+type GetLabeledGoroutinesReply struct {
+	Total cjson.Uint32 `json:"total"`
+
+	// Number of goroutines with each label set, such as
+	// "chain=X" or "subsystem=network". Goroutines without labels are counted
+	// as "unlabeled".
+	Goroutines map[string]cjson.Uint32 `json:"goroutines"`
+}
+
+// GetLabeledGoroutines returns the number of goroutines with each set of pprof
+// labels, which attribute them to the chain or subsystem that spawned them
+func (service *Admin) GetLabeledGoroutines(_ *http.Request, _ *struct{}, reply *GetLabeledGoroutinesReply) error {
+	service.log.Debug("Admin: GetLabeledGoroutines called")
+
+	counts, total, err := labels.Count()
+	if err != nil {
+		return fmt.Errorf("couldn't count goroutines: %w", err)
+	}
+	reply.Total = cjson.Uint32(total)
+	reply.Goroutines = make(map[string]cjson.Uint32, len(counts))
+	for labelSet, count := range counts {
+		reply.Goroutines[labelSet] = cjson.Uint32(count)
+	}
+	return nil
+}
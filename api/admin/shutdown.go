@@ -0,0 +1,144 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var (
+	errShuttingDown          = errors.New("the node is already shutting down")
+	errNegativeShutdownDelay = errors.New("shutdown delay can't be negative")
+	errNoShutdownScheduled   = errors.New("no shutdown is scheduled")
+)
+
+// shutdownScheduler shuts down the node once a deadline passes. The deadline
+// can only be moved earlier.
+type shutdownScheduler struct {
+	// shuts down the node, blocking until it's done
+	shutdown func()
+
+	lock     sync.Mutex
+	timer    *time.Timer
+	deadline time.Time
+	// incremented whenever the timer is replaced, so that a replaced timer that
+	// fired anyway doesn't shut down the node
+	generation uint64
+	// true once the node started shutting down
+	started bool
+}
+
+// schedule shuts down the node after [delay], unless it's already scheduled to
+// shut down sooner. Returns the time the node will shut down at.
+func (s *shutdownScheduler) schedule(delay time.Duration) (time.Time, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.started {
+		return time.Time{}, errShuttingDown
+	}
+	deadline := time.Now().Add(delay)
+	if s.timer != nil {
+		if !deadline.Before(s.deadline) {
+			return s.deadline, nil
+		}
+		s.timer.Stop()
+	}
+
+	s.generation++
+	generation := s.generation
+	s.deadline = deadline
+	s.timer = time.AfterFunc(delay, func() { s.fire(generation) })
+	return deadline, nil
+}
+
+// cancel the scheduled shutdown. Fails if none is scheduled, or the node has
+// started shutting down.
+func (s *shutdownScheduler) cancel() error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	switch {
+	case s.started:
+		return errShuttingDown
+	case s.timer == nil:
+		return errNoShutdownScheduled
+	}
+	s.timer.Stop()
+	s.timer = nil
+	s.deadline = time.Time{}
+	s.generation++
+	return nil
+}
+
+// fire shuts down the node if the timer of [generation] is still the current
+// one
+func (s *shutdownScheduler) fire(generation uint64) {
+	s.lock.Lock()
+	if s.started || generation != s.generation {
+		s.lock.Unlock()
+		return
+	}
+	s.started = true
+	s.lock.Unlock()
+
+	s.shutdown()
+}
+
+// ShutdownArgs are the arguments for calling Shutdown
+type ShutdownArgs struct {
+	// Seconds to wait before shutting down. Defaults to 0.
+	DelaySeconds int `json:"delaySeconds"`
+}
+
+// ShutdownReply are the results from calling Shutdown
+type ShutdownReply struct {
+	Success bool `json:"success"`
+
+	// Time the node will start shutting down at, in RFC 3339 format
+	ShutdownTime string `json:"shutdownTime"`
+}
+
+// Shutdown gracefully shuts down the node after a delay. Once the delay has
+// passed, the chains' APIs stop accepting requests, the chains are shut down,
+// most recently created first, and the node exits. Returns once the shutdown is
+// scheduled. If a shutdown is already scheduled, it's only moved earlier, never
+// later. A scheduled shutdown can be cancelled with CancelShutdown until it
+// starts. Fails once the node has started shutting down.
+func (service *Admin) Shutdown(_ *http.Request, args *ShutdownArgs, reply *ShutdownReply) error {
+	service.log.Debug("Admin: Shutdown called with a delay of %ds", args.DelaySeconds)
+
+	if args.DelaySeconds < 0 {
+		return errNegativeShutdownDelay
+	}
+	shutdownTime, err := service.shutdown.schedule(time.Duration(args.DelaySeconds) * time.Second)
+	if err != nil {
+		return err
+	}
+	service.log.Info("the node will shut down at %s", shutdownTime)
+
+	reply.Success = true
+	reply.ShutdownTime = shutdownTime.UTC().Format(time.RFC3339)
+	return nil
+}
+
+// CancelShutdownReply are the results from calling CancelShutdown
+type CancelShutdownReply struct {
+	Success bool `json:"success"`
+}
+
+// CancelShutdown cancels the shutdown scheduled by Shutdown. Fails if no
+// shutdown is scheduled, or the node has started shutting down.
+func (service *Admin) CancelShutdown(_ *http.Request, _ *struct{}, reply *CancelShutdownReply) error {
+	service.log.Info("Admin: CancelShutdown called")
+
+	if err := service.shutdown.cancel(); err != nil {
+		return err
+	}
+	reply.Success = true
+	return nil
+}
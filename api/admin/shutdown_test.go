@@ -0,0 +1,133 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// newTestShutdownScheduler returns a scheduler that closes the returned
+// channel when it shuts down the node
+func newTestShutdownScheduler() (*shutdownScheduler, chan struct{}) {
+	shutdown := make(chan struct{})
+	return &shutdownScheduler{shutdown: func() { close(shutdown) }}, shutdown
+}
+
+func TestShutdownSchedulerSchedule(t *testing.T) {
+	s, shutdown := newTestShutdownScheduler()
+
+	start := time.Now()
+	if _, err := s.schedule(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-shutdown:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the node to have been shut down")
+	}
+	if elapsed := time.Since(start); elapsed < 10*time.Millisecond {
+		t.Fatalf("expected the node to be shut down after the delay but it was after %s", elapsed)
+	}
+
+	if _, err := s.schedule(0); err != errShuttingDown {
+		t.Fatalf("expected %q but got %v", errShuttingDown, err)
+	}
+	if err := s.cancel(); err != errShuttingDown {
+		t.Fatalf("expected %q but got %v", errShuttingDown, err)
+	}
+}
+
+func TestShutdownSchedulerReschedule(t *testing.T) {
+	s, shutdown := newTestShutdownScheduler()
+
+	deadline, err := s.schedule(time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A later deadline shouldn't move the shutdown
+	laterDeadline, err := s.schedule(2 * time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !laterDeadline.Equal(deadline) {
+		t.Fatalf("expected the deadline to stay at %s but it's %s", deadline, laterDeadline)
+	}
+
+	// An earlier deadline should move the shutdown
+	earlierDeadline, err := s.schedule(10 * time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !earlierDeadline.Before(deadline) {
+		t.Fatalf("expected the deadline to move before %s but it's %s", deadline, earlierDeadline)
+	}
+	select {
+	case <-shutdown:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the node to have been shut down at the earlier deadline")
+	}
+}
+
+func TestShutdownSchedulerCancel(t *testing.T) {
+	s, shutdown := newTestShutdownScheduler()
+
+	if err := s.cancel(); err != errNoShutdownScheduled {
+		t.Fatalf("expected %q but got %v", errNoShutdownScheduled, err)
+	}
+
+	if _, err := s.schedule(10 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.cancel(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-shutdown:
+		t.Fatal("expected the cancelled shutdown not to happen")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// A shutdown can be scheduled again once cancelled, even for later than
+	// the cancelled one
+	if _, err := s.schedule(20 * time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-shutdown:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the node to have been shut down")
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	service := &Admin{
+		log:      logging.NoLog{},
+		shutdown: shutdownScheduler{shutdown: func() {}},
+	}
+
+	start := time.Now().Truncate(time.Second)
+	reply := ShutdownReply{}
+	if err := service.Shutdown(nil, &ShutdownArgs{DelaySeconds: 3600}, &reply); err != nil {
+		t.Fatal(err)
+	}
+	defer service.CancelShutdown(nil, nil, &CancelShutdownReply{})
+
+	if !reply.Success {
+		t.Fatal("expected the shutdown to be scheduled")
+	}
+	shutdownTime, err := time.Parse(time.RFC3339, reply.ShutdownTime)
+	if err != nil {
+		t.Fatalf("expected the shutdown time in RFC 3339 format but got %q", reply.ShutdownTime)
+	}
+	if shutdownTime.Location() != time.UTC {
+		t.Fatalf("expected the shutdown time in UTC but got %q", reply.ShutdownTime)
+	}
+	if shutdownTime.Before(start.Add(time.Hour)) || shutdownTime.After(time.Now().Add(time.Hour)) {
+		t.Fatalf("expected the shutdown to be in an hour but it's at %s", reply.ShutdownTime)
+	}
+}
@@ -0,0 +1,57 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+var errNoStakingCert = errors.New("the node has no staking certificate, as P2P TLS is disabled")
+
+// GetStakingCertExpiryReply are the results from calling GetStakingCertExpiry
+type GetStakingCertExpiryReply struct {
+	// The period the certificate is valid for, in RFC 3339 format
+	NotBefore string `json:"notBefore"`
+	NotAfter  string `json:"notAfter"`
+
+	// Whole days until the certificate expires. Negative once it has expired.
+	DaysRemaining int `json:"daysRemaining"`
+
+	// True once the certificate has expired
+	Expired bool `json:"expired"`
+
+	// True if the certificate expires within [WarningWindow], or has expired
+	Warning       bool   `json:"warning"`
+	WarningWindow string `json:"warningWindow"`
+
+	// True if renewing the certificate changes the node's ID. The ID is a hash
+	// of the whole certificate, so a renewed certificate gives the node a new
+	// ID even if it keeps the same key. The node then stops being the
+	// validator it was registered as.
+	RenewalChangesNodeID bool `json:"renewalChangesNodeID"`
+}
+
+// GetStakingCertExpiry returns when the node's staking certificate expires.
+// The node's identity is tied to the certificate, so replacing an expiring
+// certificate must be planned for, as it gives the node a new ID.
+func (service *Admin) GetStakingCertExpiry(_ *http.Request, _ *struct{}, reply *GetStakingCertExpiryReply) error {
+	service.log.Debug("Admin: GetStakingCertExpiry called")
+
+	cert := service.tlsConfig.Certificate
+	if cert == nil {
+		return errNoStakingCert
+	}
+
+	remaining := time.Until(cert.NotAfter)
+	reply.NotBefore = cert.NotBefore.UTC().Format(time.RFC3339)
+	reply.NotAfter = cert.NotAfter.UTC().Format(time.RFC3339)
+	reply.DaysRemaining = int(remaining / (24 * time.Hour))
+	reply.Expired = remaining <= 0
+	reply.Warning = remaining <= service.tlsConfig.CertExpiryWarning
+	reply.WarningWindow = service.tlsConfig.CertExpiryWarning.String()
+	reply.RenewalChangesNodeID = true
+	return nil
+}
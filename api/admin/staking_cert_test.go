@@ -0,0 +1,73 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+func TestGetStakingCertExpiryNoCert(t *testing.T) {
+	service := &Admin{log: logging.NoLog{}}
+
+	reply := GetStakingCertExpiryReply{}
+	if err := service.GetStakingCertExpiry(nil, nil, &reply); err != errNoStakingCert {
+		t.Fatalf("expected %q but got %v", errNoStakingCert, err)
+	}
+}
+
+func TestGetStakingCertExpiry(t *testing.T) {
+	const day = 24 * time.Hour
+
+	tests := []struct {
+		name          string
+		expiresIn     time.Duration
+		daysRemaining int
+		expired       bool
+		warning       bool
+	}{
+		{name: "valid", expiresIn: 100*day + time.Hour, daysRemaining: 100},
+		{name: "expiring", expiresIn: 10*day + time.Hour, daysRemaining: 10, warning: true},
+		{name: "expired", expiresIn: -2*day - time.Hour, daysRemaining: -2, expired: true, warning: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			notAfter := time.Now().Add(test.expiresIn)
+			service := &Admin{
+				log: logging.NoLog{},
+				tlsConfig: TLSConfig{
+					Enabled: true,
+					Certificate: &x509.Certificate{
+						NotBefore: notAfter.Add(-365 * day),
+						NotAfter:  notAfter,
+					},
+					CertExpiryWarning: 30 * day,
+				},
+			}
+
+			reply := GetStakingCertExpiryReply{}
+			if err := service.GetStakingCertExpiry(nil, nil, &reply); err != nil {
+				t.Fatal(err)
+			}
+			if reply.DaysRemaining != test.daysRemaining {
+				t.Fatalf("expected %d days remaining but got %d", test.daysRemaining, reply.DaysRemaining)
+			}
+			if reply.Expired != test.expired {
+				t.Fatalf("expected expired to be %t but it's %t", test.expired, reply.Expired)
+			}
+			if reply.Warning != test.warning {
+				t.Fatalf("expected warning to be %t but it's %t", test.warning, reply.Warning)
+			}
+			if expected := notAfter.UTC().Format(time.RFC3339); reply.NotAfter != expected {
+				t.Fatalf("expected the certificate to expire at %s but got %s", expected, reply.NotAfter)
+			}
+			if !reply.RenewalChangesNodeID {
+				t.Fatal("expected renewing the certificate to change the node's ID")
+			}
+		})
+	}
+}
@@ -0,0 +1,166 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/gecko/ids"
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// primaryNetworkID is the ID of the default subnet, which validates the
+// platform chain and every node tracks
+var primaryNetworkID = ids.Empty
+
+const (
+	// this node holds one of the subnet's control keys
+	controllerRole = "controller"
+	// this node validates the subnet
+	validatorRole = "validator"
+	// this node runs the subnet's chains without validating them
+	nonValidatorRole = "non-validator"
+)
+
+// subnetControlVM is implemented by the platform chain's VM
+type subnetControlVM interface {
+	SubnetControlKeys(subnetID ids.ID) ([]ids.ShortID, error)
+}
+
+// TrackedSubnet describes a subnet whose chains this node runs
+type TrackedSubnet struct {
+	ID ids.ID `json:"id"`
+
+	// Number of the subnet's chains that have been created on this node
+	Chains cjson.Uint32 `json:"chains"`
+
+	// Number of the subnet's validators this node is connected to
+	ConnectedValidators cjson.Uint32 `json:"connectedValidators"`
+}
+
+// GetTrackedSubnetsReply are the results from calling GetTrackedSubnets
+type GetTrackedSubnetsReply struct {
+	// Sorted by ID
+	Subnets []TrackedSubnet `json:"subnets"`
+}
+
+// GetTrackedSubnets returns the subnets this node runs chains of, which are
+// the subnets it validates, or every subnet if staking is disabled. The primary
+// network is always included.
+func (service *Admin) GetTrackedSubnets(_ *http.Request, _ *struct{}, reply *GetTrackedSubnetsReply) error {
+	service.log.Debug("Admin: GetTrackedSubnets called")
+
+	chains := map[[32]byte]uint32{primaryNetworkID.Key(): 0}
+	for _, chain := range service.chains.list() {
+		if subnetID, ok := service.chainManager.ChainSubnet(chain.ctx.ChainID); ok {
+			chains[subnetID.Key()]++
+		}
+	}
+
+	subnetIDs := make([]ids.ID, 0, len(chains))
+	for key := range chains {
+		subnetIDs = append(subnetIDs, ids.NewID(key))
+	}
+	ids.SortIDs(subnetIDs)
+
+	peers := service.networking.Peers()
+	reply.Subnets = make([]TrackedSubnet, len(subnetIDs))
+	for i, subnetID := range subnetIDs {
+		subnet := TrackedSubnet{
+			ID:     subnetID,
+			Chains: cjson.Uint32(chains[subnetID.Key()]),
+		}
+		if vdrs, ok := service.chainManager.SubnetValidators(subnetID); ok {
+			connected := uint32(0)
+			for _, peer := range peers {
+				if vdrs.Contains(peer.ID) {
+					connected++
+				}
+			}
+			subnet.ConnectedValidators = cjson.Uint32(connected)
+		}
+		reply.Subnets[i] = subnet
+	}
+	return nil
+}
+
+// SubnetRole is this node's role in a subnet
+type SubnetRole struct {
+	SubnetID string `json:"subnetID"`
+
+	// One of "controller", "validator" or "non-validator"
+	Role string `json:"role"`
+}
+
+// GetSubnetRolesReply are the results from calling GetSubnetRoles
+type GetSubnetRolesReply struct {
+	// Sorted by subnet ID
+	Subnets []SubnetRole `json:"subnets"`
+}
+
+// GetSubnetRoles returns this node's role in each subnet it tracks. If this
+// node's ID is one of the subnet's control keys, it's the subnet's
+// "controller". Otherwise, it's a "validator" if it's currently validating the
+// subnet, or a "non-validator" if it only runs the subnet's chains. The
+// primary network has no control keys, so this node is either a validator or
+// a non-validator of it.
+func (service *Admin) GetSubnetRoles(_ *http.Request, _ *struct{}, reply *GetSubnetRolesReply) error {
+	service.log.Debug("Admin: GetSubnetRoles called")
+
+	subnetIDs := ids.Set{}
+	subnetIDs.Add(primaryNetworkID)
+	var platform *chain
+	for _, c := range service.chains.list() {
+		c := c
+		if subnetID, ok := service.chainManager.ChainSubnet(c.ctx.ChainID); ok {
+			subnetIDs.Add(subnetID)
+		}
+		if _, ok := c.vm.(subnetControlVM); ok {
+			platform = &c
+		}
+	}
+
+	sortedIDs := subnetIDs.List()
+	ids.SortIDs(sortedIDs)
+	reply.Subnets = make([]SubnetRole, len(sortedIDs))
+	for i, subnetID := range sortedIDs {
+		role := nonValidatorRole
+		if vdrs, ok := service.chainManager.SubnetValidators(subnetID); ok && vdrs.Contains(service.nodeID) {
+			role = validatorRole
+		}
+		if !subnetID.Equals(primaryNetworkID) && platform != nil {
+			controller, err := service.controlsSubnet(platform, subnetID)
+			if err != nil {
+				return err
+			}
+			if controller {
+				role = controllerRole
+			}
+		}
+		reply.Subnets[i] = SubnetRole{
+			SubnetID: subnetID.String(),
+			Role:     role,
+		}
+	}
+	return nil
+}
+
+// controlsSubnet returns true if this node's ID is one of the control keys of
+// the subnet with ID [subnetID], according to the platform chain [platform]
+func (service *Admin) controlsSubnet(platform *chain, subnetID ids.ID) (bool, error) {
+	platform.ctx.Lock.Lock()
+	defer platform.ctx.Lock.Unlock()
+
+	controlKeys, err := platform.vm.(subnetControlVM).SubnetControlKeys(subnetID)
+	if err != nil {
+		return false, fmt.Errorf("couldn't get the control keys of subnet %s: %w", subnetID, err)
+	}
+	for _, key := range controlKeys {
+		if key.Equals(service.nodeID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
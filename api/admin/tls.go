@@ -0,0 +1,129 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/network"
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// TLSConfig describes how connections to peers are secured
+type TLSConfig struct {
+	// true if connections to peers use TLS
+	Enabled bool
+
+	// Oldest TLS version peers may connect with
+	MinVersion uint16
+
+	// The certificate the node's ID is derived from. nil if TLS is disabled.
+	Certificate *x509.Certificate
+
+	// How long before [Certificate] expires a warning is given
+	CertExpiryWarning time.Duration
+}
+
+// GetPeerTLSArgs are the arguments for calling GetPeerTLS
+type GetPeerTLSArgs struct {
+	NodeID string `json:"nodeID"`
+}
+
+// GetPeerTLSReply are the results from calling GetPeerTLS
+type GetPeerTLSReply struct {
+	// Such as "TLS 1.3"
+	Version            string `json:"version"`
+	HandshakeComplete  bool   `json:"handshakeComplete"`
+	DidResume          bool   `json:"didResume"`
+	CipherSuite        string `json:"cipherSuite"`
+	NegotiatedProtocol string `json:"negotiatedProtocol"`
+	ServerName         string `json:"serverName"`
+
+	// SHA-256 fingerprints of the certificates the peer presented, leaf first
+	PeerCertificates []string `json:"peerCertificates"`
+
+	// SHA-256 fingerprints of the certificates of each chain the peer's
+	// certificate was verified with. Staking certificates are self-signed and
+	// identified by their hash, so this is usually empty.
+	VerifiedChains [][]string `json:"verifiedChains"`
+}
+
+// GetPeerTLS returns the state of the TLS connection to a peer
+func (service *Admin) GetPeerTLS(_ *http.Request, args *GetPeerTLSArgs, reply *GetPeerTLSReply) error {
+	service.log.Debug("Admin: GetPeerTLS called with %s", args.NodeID)
+
+	nodeID, err := ids.ShortFromString(args.NodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't parse node ID: %w", err)
+	}
+	state, err := service.networking.PeerTLS(nodeID)
+	if err != nil {
+		return fmt.Errorf("couldn't get the TLS state of %s: %w", nodeID, err)
+	}
+
+	reply.Version = network.TLSVersionName(state.Version)
+	reply.HandshakeComplete = state.HandshakeComplete
+	reply.DidResume = state.DidResume
+	reply.CipherSuite = tls.CipherSuiteName(state.CipherSuite)
+	reply.NegotiatedProtocol = state.NegotiatedProtocol
+	reply.ServerName = state.ServerName
+	reply.PeerCertificates = fingerprints(state.PeerCertificates)
+	reply.VerifiedChains = make([][]string, len(state.VerifiedChains))
+	for i, chain := range state.VerifiedChains {
+		reply.VerifiedChains[i] = fingerprints(chain)
+	}
+	return nil
+}
+
+// fingerprints returns the SHA-256 fingerprints of [certs], formatted the same
+// way as by `openssl x509 -fingerprint -sha256`
+func fingerprints(certs []*x509.Certificate) []string {
+	fingerprints := make([]string, len(certs))
+	for i, cert := range certs {
+		hash := sha256.Sum256(cert.Raw)
+		hexBytes := make([]string, len(hash))
+		for j, b := range hash {
+			hexBytes[j] = fmt.Sprintf("%02X", b)
+		}
+		fingerprints[i] = strings.Join(hexBytes, ":")
+	}
+	return fingerprints
+}
+
+// GetTLSConfigReply are the results from calling GetTLSConfig
+type GetTLSConfigReply struct {
+	Enabled bool `json:"enabled"`
+
+	// Oldest TLS version peers may connect with, such as "TLS 1.2". Empty if
+	// TLS is disabled.
+	MinVersion string `json:"minVersion"`
+
+	// Number of connections that couldn't be upgraded since the node started,
+	// by reason. Peers that only support TLS versions older than the minimum
+	// are counted under "unsupported version".
+	HandshakeFailures map[string]cjson.Uint64 `json:"handshakeFailures"`
+}
+
+// GetTLSConfig returns how connections to peers are secured
+func (service *Admin) GetTLSConfig(_ *http.Request, _ *struct{}, reply *GetTLSConfigReply) error {
+	service.log.Debug("Admin: GetTLSConfig called")
+
+	reply.Enabled = service.tlsConfig.Enabled
+	if service.tlsConfig.Enabled {
+		reply.MinVersion = network.TLSVersionName(service.tlsConfig.MinVersion)
+	}
+	failures := service.networking.HandshakeFailures()
+	reply.HandshakeFailures = make(map[string]cjson.Uint64, len(failures))
+	for reason, count := range failures {
+		reply.HandshakeFailures[reason] = cjson.Uint64(count)
+	}
+	return nil
+}
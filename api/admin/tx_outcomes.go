@@ -0,0 +1,72 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/ava-labs/gecko/snow/engine/common"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+// conflictRejection is the reason given for decisions that consensus rejected,
+// because a conflicting decision was accepted
+const conflictRejection = "conflict"
+
+// GetTxOutcomesArgs are the arguments for calling GetTxOutcomes
+type GetTxOutcomesArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// GetTxOutcomesReply are the results from calling GetTxOutcomes
+type GetTxOutcomesReply struct {
+	// Number of transactions accepted since the node started
+	Accepted cjson.Uint64 `json:"accepted"`
+
+	// Number of transactions rejected since the node started, keyed by why
+	// they were rejected. "conflict" counts those rejected by consensus. The
+	// other reasons are reported by the chain's VM, for transactions it
+	// rejected before issuing them to consensus.
+	Rejected map[string]cjson.Uint64 `json:"rejected"`
+}
+
+// GetTxOutcomes returns how many transactions a chain accepted and rejected
+// since the node started, and why the rejected ones were rejected. On linear
+// chains, consensus decides on blocks, so the transactions in the accepted and
+// rejected blocks are counted. A block is counted as a single transaction if
+// its VM doesn't report how many transactions its blocks contain.
+func (service *Admin) GetTxOutcomes(_ *http.Request, args *GetTxOutcomesArgs, reply *GetTxOutcomesReply) error {
+	service.log.Debug("Admin: GetTxOutcomes called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	chain, ok := service.chains.get(chainID)
+	if !ok {
+		return fmt.Errorf("chain %q hasn't been created", args.Chain)
+	}
+
+	accepted, rejected := chain.decisions.counts()
+	reply.Accepted = cjson.Uint64(accepted)
+	reply.Rejected = map[string]cjson.Uint64{
+		conflictRejection: cjson.Uint64(rejected),
+	}
+
+	vm, ok := chain.vm.(common.TxRejectionReporter)
+	if !ok {
+		return nil
+	}
+	chain.ctx.Lock.Lock()
+	rejections := vm.TxRejections()
+	chain.ctx.Lock.Unlock()
+
+	for reason, count := range rejections {
+		reply.Rejected[reason] += cjson.Uint64(count)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/vms/platformvm"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+const (
+	// this node is validating the primary network
+	validatorStatus = "validator"
+	// this node will start validating the primary network in the future
+	pendingValidatorStatus = "pending"
+	// this node isn't, and won't be, validating the primary network
+	notValidatorStatus = "not a validator"
+)
+
+var errNoPlatformChain = errors.New("the platform chain hasn't been created")
+
+// stakerVM is implemented by the platform chain's VM
+type stakerVM interface {
+	DefaultSubnetStaker(nodeID ids.ShortID) (platformvm.StakerInfo, bool, error)
+	FormatAddress(ids.ShortID) string
+}
+
+// GetValidatorInfoReply are the results from calling GetValidatorInfo
+type GetValidatorInfoReply struct {
+	// One of "validator", "pending" or "not a validator". The other fields are
+	// only set if this node is a validator or a pending validator.
+	Status string `json:"status"`
+
+	StakeAmount   cjson.Uint64 `json:"stakeAmount"`
+	RewardAddress string       `json:"rewardAddress"`
+	StartTime     string       `json:"startTime"`
+	EndTime       string       `json:"endTime"`
+
+	// How long until this node stops validating, such as "312h0m0s"
+	RemainingDuration string `json:"remainingDuration"`
+}
+
+// GetValidatorInfo returns whether this node is validating the primary
+// network, and if so, how much it staked, where its reward is sent and how
+// long it has left to validate. Stake delegated to this node isn't included.
+func (service *Admin) GetValidatorInfo(_ *http.Request, _ *struct{}, reply *GetValidatorInfoReply) error {
+	service.log.Debug("Admin: GetValidatorInfo called")
+
+	for _, chain := range service.chains.list() {
+		vm, ok := chain.vm.(stakerVM)
+		if !ok {
+			continue
+		}
+
+		chain.ctx.Lock.Lock()
+		defer chain.ctx.Lock.Unlock()
+
+		staker, isStaker, err := vm.DefaultSubnetStaker(service.nodeID)
+		if err != nil {
+			return err
+		}
+		if !isStaker {
+			reply.Status = notValidatorStatus
+			return nil
+		}
+
+		reply.Status = validatorStatus
+		if staker.Pending {
+			reply.Status = pendingValidatorStatus
+		}
+		reply.StakeAmount = cjson.Uint64(staker.StakeAmount)
+		reply.RewardAddress = vm.FormatAddress(staker.Destination)
+		reply.StartTime = staker.StartTime.UTC().Format(time.RFC3339)
+		reply.EndTime = staker.EndTime.UTC().Format(time.RFC3339)
+
+		remaining := time.Until(staker.EndTime)
+		if remaining < 0 {
+			remaining = 0
+		}
+		reply.RemainingDuration = remaining.Round(time.Second).String()
+		return nil
+	}
+	return errNoPlatformChain
+}
@@ -0,0 +1,143 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+	"github.com/ava-labs/gecko/snow/engine/common"
+
+	cjson "github.com/ava-labs/gecko/utils/json"
+)
+
+const (
+	// the chain's VM checked its own state
+	vmStateCheck = "vm"
+	// the accepted blocks were checked to link back to the genesis block
+	blockLinkageCheck = "accepted block linkage"
+)
+
+// VerifyChainStateArgs are the arguments for calling VerifyChainState
+type VerifyChainStateArgs struct {
+	// ID or alias of the chain
+	Chain string `json:"chain"`
+}
+
+// VerifyChainStateReply are the results from calling VerifyChainState
+type VerifyChainStateReply struct {
+	Passed bool `json:"passed"`
+
+	// The check that was run. Either "vm" if the chain's VM checked its own
+	// state, or "accepted block linkage".
+	Check string `json:"check"`
+
+	// Number of accepted blocks that were checked, if the linkage of the
+	// accepted blocks was checked
+	BlocksChecked cjson.Uint64 `json:"blocksChecked"`
+
+	// Description of the inconsistency that was found. Empty if the check
+	// passed.
+	Mismatch string `json:"mismatch"`
+}
+
+// VerifyChainState checks the consistency of a chain's stored state without
+// modifying it, such as before rejoining consensus after an unclean shutdown.
+// If the chain's VM can check its own state, it does. Otherwise, if the chain
+// is linear, the accepted blocks are checked to all be stored, be accepted and
+// link back to the genesis block. The chain is locked while its VM checks its
+// state. The accepted blocks are checked in batches, with the chain unlocked
+// between batches. The check stops if the request is cancelled.
+func (service *Admin) VerifyChainState(r *http.Request, args *VerifyChainStateArgs, reply *VerifyChainStateReply) error {
+	service.log.Info("Admin: VerifyChainState called with %s", args.Chain)
+
+	chainID, err := service.chainManager.Lookup(args.Chain)
+	if err != nil {
+		return fmt.Errorf("couldn't find chain %q: %w", args.Chain, err)
+	}
+	chain, ok := service.chains.get(chainID)
+	if !ok {
+		return fmt.Errorf("chain %q hasn't been created", args.Chain)
+	}
+
+	ctx := r.Context()
+	switch vm := chain.vm.(type) {
+	case common.StateVerifier:
+		reply.Check = vmStateCheck
+		chain.ctx.Lock.Lock()
+		err = vm.VerifyState(ctx)
+		chain.ctx.Lock.Unlock()
+	case blockVM:
+		reply.Check = blockLinkageCheck
+		var checked uint64
+		checked, err = verifyBlockLinkage(ctx, &chain.ctx.Lock, vm)
+		reply.BlocksChecked = cjson.Uint64(checked)
+	default:
+		return fmt.Errorf("chain %q doesn't support verifying its state", args.Chain)
+	}
+
+	// The check didn't fail, it was cut short
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return fmt.Errorf("couldn't finish verifying the state of chain %q: %w", args.Chain, ctxErr)
+	}
+	if err != nil {
+		service.log.Warn("the state of chain %s is inconsistent: %s", chainID, err)
+		reply.Mismatch = err.Error()
+		return nil
+	}
+	reply.Passed = true
+	return nil
+}
+
+// verifyBlockLinkage walks the accepted blocks of [vm] from the last accepted
+// block back to the genesis block, whose parent is ids.Empty, and checks that
+// every block is stored and accepted. Returns the number of blocks checked.
+// [chainLock] is the chain's lock, which is held for up to blocksPerLock blocks
+// at a time. Blocks accepted once the walk has started aren't checked.
+func verifyBlockLinkage(ctx context.Context, chainLock sync.Locker, vm blockVM) (uint64, error) {
+	chainLock.Lock()
+	blkID := vm.LastAccepted()
+	chainLock.Unlock()
+
+	seen := ids.Set{}
+	checked := uint64(0)
+	for !blkID.Equals(ids.Empty) {
+		if err := ctx.Err(); err != nil {
+			return checked, err
+		}
+
+		var err error
+		chainLock.Lock()
+		for i := 0; i < blocksPerLock && !blkID.Equals(ids.Empty); i++ {
+			if seen.Contains(blkID) {
+				err = fmt.Errorf("accepted block %s is its own ancestor", blkID)
+				break
+			}
+			seen.Add(blkID)
+
+			var blk snowman.Block
+			blk, err = vm.GetBlock(blkID)
+			if err != nil {
+				err = fmt.Errorf("couldn't get accepted block %s: %w", blkID, err)
+				break
+			}
+			if status := blk.Status(); status != choices.Accepted {
+				err = fmt.Errorf("block %s is an ancestor of the last accepted block, but its status is %s", blkID, status)
+				break
+			}
+			checked++
+			blkID = blk.Parent().ID()
+		}
+		chainLock.Unlock()
+		if err != nil {
+			return checked, err
+		}
+	}
+	return checked, nil
+}
@@ -0,0 +1,143 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowman"
+)
+
+type testBlock struct {
+	snowman.Block
+	id     ids.ID
+	parent *testBlock
+	status choices.Status
+}
+
+func (b *testBlock) ID() ids.ID             { return b.id }
+func (b *testBlock) Status() choices.Status { return b.status }
+func (b *testBlock) Verify() error          { return nil }
+func (b *testBlock) Bytes() []byte          { return b.id.Bytes() }
+func (b *testBlock) Accept() error          { b.status = choices.Accepted; return nil }
+func (b *testBlock) Parent() snowman.Block {
+	if b.parent == nil {
+		return &testBlock{id: ids.Empty, status: choices.Unknown}
+	}
+	return b.parent
+}
+
+type testBlockVM struct {
+	blocks       map[[32]byte]*testBlock
+	lastAccepted ids.ID
+	preference   ids.ID
+}
+
+// newTestBlockVM returns a VM that accepted a chain of [length] blocks, the
+// first of which is the genesis block
+func newTestBlockVM(length int) *testBlockVM {
+	vm := &testBlockVM{blocks: make(map[[32]byte]*testBlock)}
+	var parent *testBlock
+	for i := 0; i < length; i++ {
+		blk := &testBlock{
+			id:     ids.Empty.Prefix(uint64(i)),
+			parent: parent,
+			status: choices.Accepted,
+		}
+		vm.blocks[blk.id.Key()] = blk
+		vm.lastAccepted = blk.id
+		parent = blk
+	}
+	return vm
+}
+
+func (vm *testBlockVM) GetBlock(blkID ids.ID) (snowman.Block, error) {
+	blk, ok := vm.blocks[blkID.Key()]
+	if !ok {
+		return nil, errors.New("unknown block")
+	}
+	return blk, nil
+}
+func (vm *testBlockVM) SetPreference(blkID ids.ID) { vm.preference = blkID }
+func (vm *testBlockVM) LastAccepted() ids.ID       { return vm.lastAccepted }
+
+// countingLocker counts the times it's locked
+type countingLocker struct {
+	sync.Mutex
+	locks int
+}
+
+func (l *countingLocker) Lock() {
+	l.Mutex.Lock()
+	l.locks++
+}
+
+func TestVerifyBlockLinkage(t *testing.T) {
+	length := 2*blocksPerLock + 1
+	vm := newTestBlockVM(length)
+
+	lock := &countingLocker{}
+	checked, err := verifyBlockLinkage(context.Background(), lock, vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checked != uint64(length) {
+		t.Fatalf("expected %d blocks to be checked but %d were", length, checked)
+	}
+	// Once to get the last accepted block, then once for each batch
+	if lock.locks != 4 {
+		t.Fatalf("expected the chain to be locked 4 times but it was locked %d times", lock.locks)
+	}
+
+	// A block in the middle of the chain that isn't accepted
+	vm.blocks[ids.Empty.Prefix(1).Key()].status = choices.Processing
+	if _, err := verifyBlockLinkage(context.Background(), &sync.Mutex{}, vm); err == nil {
+		t.Fatal("expected a block that isn't accepted to be reported")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := verifyBlockLinkage(ctx, &sync.Mutex{}, vm); err != context.Canceled {
+		t.Fatalf("expected the check to be cancelled but got %v", err)
+	}
+}
+
+func TestLastAcceptedHeight(t *testing.T) {
+	length := 2*blocksPerLock + 1
+	vm := newTestBlockVM(length)
+
+	cache := &heightCache{}
+	blkID, height, err := cache.lastAcceptedHeight(&sync.Mutex{}, vm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !blkID.Equals(vm.lastAccepted) || height != uint64(length-1) {
+		t.Fatalf("expected %s at height %d but got %s at height %d", vm.lastAccepted, length-1, blkID, height)
+	}
+
+	// Only the blocks accepted since are walked
+	next := &testBlock{
+		id:     ids.Empty.Prefix(uint64(length)),
+		parent: vm.blocks[vm.lastAccepted.Key()],
+		status: choices.Accepted,
+	}
+	vm.blocks[next.id.Key()] = next
+	vm.lastAccepted = next.id
+
+	lock := &countingLocker{}
+	if _, height, err = cache.lastAcceptedHeight(lock, vm); err != nil {
+		t.Fatal(err)
+	}
+	if height != uint64(length) {
+		t.Fatalf("expected height %d but got %d", length, height)
+	}
+	if lock.locks != 2 {
+		t.Fatalf("expected the chain to be locked twice but it was locked %d times", lock.locks)
+	}
+}
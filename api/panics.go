@@ -0,0 +1,124 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+	"time"
+)
+
+// maxRecentPanics is the number of recovered panics that are remembered
+const maxRecentPanics = 64
+
+// Panic describes a panic raised by a handler that was recovered from
+type Panic struct {
+	Time time.Time
+
+	// Where the panic was raised, such as "http:/ext/admin" for a handler run
+	// by this process, or "plugin:/ext/bc/<chain ID>" for a handler run by a
+	// plugin
+	Subsystem string
+
+	// Value the handler panicked with
+	Value string
+
+	// Stack of the goroutine that panicked
+	Stack string
+}
+
+// PluginPanic is implemented by panic values that report a panic raised by a
+// handler run by a plugin process
+type PluginPanic interface {
+	PluginStacktrace() string
+}
+
+// PanicResponder is implemented by panic values that provide the body of the
+// 500 that is replied with, such as one describing the panic. If the body is
+// empty, the usual body is replied with.
+type PanicResponder interface {
+	PanicResponse() string
+}
+
+// panics remembers the most recent panics that were recovered from
+type panics struct {
+	lock   sync.Mutex
+	recent []Panic // ring buffer of at most [maxRecentPanics] panics
+	next   int     // index in [recent] the next panic is written to
+	total  uint64
+}
+
+func (p *panics) add(panic Panic) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if len(p.recent) < maxRecentPanics {
+		p.recent = append(p.recent, panic)
+	} else {
+		p.recent[p.next] = panic
+	}
+	p.next = (p.next + 1) % maxRecentPanics
+	p.total++
+}
+
+// list returns the remembered panics from oldest to newest, and the number of
+// panics that were ever recovered from
+func (p *panics) list() ([]Panic, uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	recent := make([]Panic, 0, len(p.recent))
+	if len(p.recent) == maxRecentPanics {
+		recent = append(recent, p.recent[p.next:]...)
+		recent = append(recent, p.recent[:p.next]...)
+	} else {
+		recent = append(recent, p.recent...)
+	}
+	return recent, p.total
+}
+
+// recoveryHandler recovers from panics raised by [handler], records them, and
+// replies with a 500
+type recoveryHandler struct {
+	route   string
+	handler http.Handler
+	server  *Server
+}
+
+func (h recoveryHandler) ServeHTTP(writer http.ResponseWriter, request *http.Request) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		if recovered == http.ErrAbortHandler {
+			// The handler is intentionally aborting the response
+			panic(recovered)
+		}
+
+		p := Panic{
+			Time:      time.Now(),
+			Subsystem: "http:" + h.route,
+			Value:     fmt.Sprint(recovered),
+			Stack:     string(debug.Stack()),
+		}
+		if pluginPanic, ok := recovered.(PluginPanic); ok {
+			p.Subsystem = "plugin:" + h.route
+			p.Stack = pluginPanic.PluginStacktrace()
+		}
+		h.server.panics.add(p)
+		h.server.log.Error("recovered from panic in %s: %s\n%s", p.Subsystem, p.Value, p.Stack)
+
+		body := http.StatusText(http.StatusInternalServerError)
+		if responder, ok := recovered.(PanicResponder); ok {
+			if response := responder.PanicResponse(); response != "" {
+				body = response
+			}
+		}
+		http.Error(writer, body, http.StatusInternalServerError)
+	}()
+	h.handler.ServeHTTP(writer, request)
+}
@@ -130,3 +130,52 @@ func (r *router) AddAlias(base string, aliases ...string) error {
 	}
 	return err
 }
+
+func (r *router) RemoveAlias(base string, aliases ...string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.routeLock.Lock()
+	defer r.routeLock.Unlock()
+
+	toRemove := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		toRemove[alias] = true
+	}
+
+	remaining := []string(nil)
+	for _, alias := range r.aliases[base] {
+		if toRemove[alias] {
+			delete(toRemove, alias)
+		} else {
+			remaining = append(remaining, alias)
+		}
+	}
+	for alias := range toRemove {
+		return fmt.Errorf("couldn't remove alias %s as it isn't an alias of %s", alias, base)
+	}
+	for _, alias := range aliases {
+		if _, exists := r.aliases[alias]; exists {
+			return fmt.Errorf("couldn't remove alias %s as it has been aliased", alias)
+		}
+	}
+
+	for _, alias := range aliases {
+		delete(r.reservedRoutes, alias)
+		delete(r.routes, alias)
+	}
+	if len(remaining) == 0 {
+		delete(r.aliases, base)
+	} else {
+		r.aliases[base] = remaining
+	}
+
+	// mux doesn't support removing routes, so the routes that remain are
+	// registered on a new mux
+	r.router = mux.NewRouter()
+	for base, endpoints := range r.routes {
+		for endpoint, handler := range endpoints {
+			r.router.Handle(base+endpoint, handler)
+		}
+	}
+	return nil
+}
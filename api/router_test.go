@@ -67,3 +67,44 @@ func TestBlock(t *testing.T) {
 		t.Fatalf("Permanently locked %s", "1")
 	}
 }
+
+func TestRemoveAlias(t *testing.T) {
+	r := newRouter()
+
+	handler1 := &testHandler{}
+	if err := r.AddRouter("1", "", handler1); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddAlias("1", "2", "3"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.AddAlias("3", "4"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.RemoveAlias("1", "5"); err == nil {
+		t.Fatalf("Removed %s which isn't an alias", "5")
+	}
+	if err := r.RemoveAlias("1", "3"); err == nil {
+		t.Fatalf("Removed %s which has been aliased", "3")
+	}
+	if err := r.RemoveAlias("1", "2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, exists := r.routes["2"]; exists {
+		t.Fatalf("Should have removed %s", "2")
+	}
+	if r.reservedRoutes["2"] {
+		t.Fatalf("Should have released %s", "2")
+	}
+	if handler, exists := r.routes["3"][""]; !exists {
+		t.Fatalf("Should have kept %s", "3")
+	} else if handler != handler1 {
+		t.Fatalf("Registered unknown handler")
+	}
+
+	if err := r.AddRouter("2", "", handler1); err != nil {
+		t.Fatal(err)
+	}
+}
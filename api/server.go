@@ -11,11 +11,13 @@ import (
 	"net/http"
 	"net/url"
 	"sync"
+	"sync/atomic"
 
 	"github.com/gorilla/handlers"
 
 	"github.com/rs/cors"
 
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/utils/logging"
@@ -33,6 +35,18 @@ type Server struct {
 	factory       logging.Factory
 	router        *router
 	listenAddress string
+
+	// Maps a chain's ID to the aliases of its endpoint that were added with
+	// AddChainAliases
+	chainAliasesLock sync.Mutex
+	chainAliases     map[[32]byte][]string
+
+	// Panics raised by handlers that were recovered from
+	panics panics
+
+	// 1 if the server is draining, in which case requests to chains' APIs are
+	// rejected. Accessed atomically.
+	draining uint32
 }
 
 // Initialize creates the API server at the provided host and port
@@ -41,6 +55,7 @@ func (s *Server) Initialize(log logging.Logger, factory logging.Factory, host st
 	s.factory = factory
 	s.listenAddress = fmt.Sprintf("%s:%d", host, port)
 	s.router = newRouter()
+	s.chainAliases = make(map[[32]byte][]string)
 }
 
 // Dispatch starts the API server
@@ -76,7 +91,7 @@ func (s *Server) RegisterChain(ctx *snow.Context, vmIntf interface{}) {
 
 	// all subroutes to a chain begin with "bc/<the chain's ID>"
 	chainID := ctx.ChainID.String()
-	defaultEndpoint := "bc/" + chainID
+	defaultEndpoint := chainEndpoint(ctx.ChainID)
 	httpLogger, err := s.factory.MakeChain(chainID, "http")
 	if err != nil {
 		s.log.Error("Failed to create new http logger: %s", err)
@@ -94,7 +109,7 @@ func (s *Server) RegisterChain(ctx *snow.Context, vmIntf interface{}) {
 			continue
 		}
 		s.log.Verbo("adding API endpoint: %s", defaultEndpoint+extension)
-		if err := s.AddRoute(service, &ctx.Lock, defaultEndpoint, extension, httpLogger); err != nil {
+		if err := s.addRoute(service, &ctx.Lock, defaultEndpoint, extension, httpLogger, true); err != nil {
 			s.log.Error("error adding route: %s", err)
 		}
 	}
@@ -102,29 +117,78 @@ func (s *Server) RegisterChain(ctx *snow.Context, vmIntf interface{}) {
 
 // AddRoute registers the appropriate endpoint for the vm given an endpoint
 func (s *Server) AddRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base, endpoint string, log logging.Logger) error {
+	return s.addRoute(handler, lock, base, endpoint, log, false)
+}
+
+// addRoute is AddRoute, with requests rejected once the server is draining if
+// [drainable]
+func (s *Server) addRoute(handler *common.HTTPHandler, lock *sync.RWMutex, base, endpoint string, log logging.Logger, drainable bool) error {
 	url := fmt.Sprintf("%s/%s", baseURL, base)
 	s.log.Info("adding route %s%s", url, endpoint)
-	h := handlers.CombinedLoggingHandler(log, handler.Handler)
+	h := handlers.CombinedLoggingHandler(log, recoveryHandler{
+		route:   url + endpoint,
+		handler: handler.Handler,
+		server:  s,
+	})
+
+	var routeHandler http.Handler
 	switch handler.LockOptions {
 	case common.WriteLock:
-		return s.router.AddRouter(url, endpoint, middlewareHandler{
+		routeHandler = middlewareHandler{
 			before:  lock.Lock,
 			after:   lock.Unlock,
 			handler: h,
-		})
+		}
 	case common.ReadLock:
-		return s.router.AddRouter(url, endpoint, middlewareHandler{
+		routeHandler = middlewareHandler{
 			before:  lock.RLock,
 			after:   lock.RUnlock,
 			handler: h,
-		})
+		}
 	case common.NoLock:
-		return s.router.AddRouter(url, endpoint, h)
+		routeHandler = h
 	default:
 		return errUnknownLockOption
 	}
+
+	// Requests are rejected before the chain's lock is grabbed, so that they
+	// don't wait on a chain that is shutting down
+	if drainable {
+		routeHandler = drainingHandler{
+			handler: routeHandler,
+			server:  s,
+		}
+	}
+	return s.router.AddRouter(url, endpoint, routeHandler)
 }
 
+// Drain causes requests to chains' APIs to be rejected from now on, so that
+// the chains can be shut down without requests being cut off. The node's own
+// APIs are still served.
+func (s *Server) Drain() { atomic.StoreUint32(&s.draining, 1) }
+
+// Draining returns true if Drain has been called
+func (s *Server) Draining() bool { return atomic.LoadUint32(&s.draining) == 1 }
+
+// drainingHandler rejects requests once the server is draining
+type drainingHandler struct {
+	handler http.Handler
+	server  *Server
+}
+
+func (h drainingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if h.server.Draining() {
+		http.Error(w, "the node is shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	h.handler.ServeHTTP(w, r)
+}
+
+// RecentPanics returns the most recent panics raised by handlers that were
+// recovered from, from oldest to newest, and the number of panics that were
+// recovered from since the server was initialized
+func (s *Server) RecentPanics() ([]Panic, uint64) { return s.panics.list() }
+
 // AddAliases registers aliases to the server
 func (s *Server) AddAliases(endpoint string, aliases ...string) error {
 	url := fmt.Sprintf("%s/%s", baseURL, endpoint)
@@ -147,6 +211,60 @@ func (s *Server) AddAliasesWithReadLock(endpoint string, aliases ...string) erro
 	return s.AddAliases(endpoint, aliases...)
 }
 
+// AddChainAliases registers aliases to the endpoint of the chain with ID
+// [chainID]. Unlike aliases added with AddAliases, these aliases are tracked so
+// that they can be pruned if the chain isn't running.
+func (s *Server) AddChainAliases(chainID ids.ID, aliases ...string) error {
+	if err := s.AddAliases(chainEndpoint(chainID), aliases...); err != nil {
+		return err
+	}
+
+	s.chainAliasesLock.Lock()
+	defer s.chainAliasesLock.Unlock()
+
+	key := chainID.Key()
+	s.chainAliases[key] = append(s.chainAliases[key], aliases...)
+	return nil
+}
+
+// PruneChainAliasesWithReadLock removes the aliases that were added with
+// AddChainAliases to the endpoints of chains for which [isRunning] returns
+// false. Returns the removed aliases. Assumes the http read lock is currently
+// held.
+func (s *Server) PruneChainAliasesWithReadLock(isRunning func(ids.ID) bool) ([]string, error) {
+	// See AddAliasesWithReadLock
+	s.router.lock.RUnlock()
+	defer s.router.lock.RLock()
+
+	s.chainAliasesLock.Lock()
+	defer s.chainAliasesLock.Unlock()
+
+	pruned := []string(nil)
+	for key, aliases := range s.chainAliases {
+		chainID := ids.NewID(key)
+		if isRunning(chainID) {
+			continue
+		}
+
+		url := fmt.Sprintf("%s/%s", baseURL, chainEndpoint(chainID))
+		endpoints := make([]string, len(aliases))
+		for i, alias := range aliases {
+			endpoints[i] = fmt.Sprintf("%s/%s", baseURL, alias)
+		}
+		if err := s.router.RemoveAlias(url, endpoints...); err != nil {
+			return pruned, err
+		}
+
+		s.log.Info("pruned aliases %s of %s", aliases, url)
+		delete(s.chainAliases, key)
+		pruned = append(pruned, aliases...)
+	}
+	return pruned, nil
+}
+
+// chainEndpoint returns the endpoint of the chain with ID [chainID]
+func chainEndpoint(chainID ids.ID) string { return "bc/" + chainID.String() }
+
 // Call ...
 func (s *Server) Call(
 	writer http.ResponseWriter,
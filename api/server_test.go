@@ -5,8 +5,10 @@ package api
 
 import (
 	"bytes"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync"
 	"testing"
 
@@ -58,3 +60,76 @@ func TestCall(t *testing.T) {
 		t.Fatalf("Should have been called")
 	}
 }
+
+func TestRecoverPanic(t *testing.T) {
+	s := Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, "localhost", 8080)
+
+	lock := new(sync.RWMutex)
+	handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic("oops") })
+	if err := s.AddRoute(&common.HTTPHandler{Handler: handler}, lock, "vm/lol", "", logging.NoLog{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < maxRecentPanics+1; i++ {
+		writer := httptest.NewRecorder()
+		if err := s.Call(writer, "POST", "lol", "", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if writer.Code != http.StatusInternalServerError {
+			t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, writer.Code)
+		}
+	}
+
+	// The chain's lock should have been released
+	lock.Lock()
+	lock.Unlock()
+
+	panics, total := s.RecentPanics()
+	if total != maxRecentPanics+1 {
+		t.Fatalf("expected %d panics but got %d", maxRecentPanics+1, total)
+	}
+	if len(panics) != maxRecentPanics {
+		t.Fatalf("expected %d recent panics but got %d", maxRecentPanics, len(panics))
+	}
+	if panics[0].Subsystem != "http:/ext/vm/lol" || panics[0].Value != "oops" {
+		t.Fatalf("unexpected panic %+v", panics[0])
+	}
+}
+
+// panicResponse is a panic value that provides the body of the 500
+type panicResponse string
+
+func (p panicResponse) PanicResponse() string { return string(p) }
+
+func TestRecoverPanicResponse(t *testing.T) {
+	s := Server{}
+	s.Initialize(logging.NoLog{}, logging.NoFactory{}, "localhost", 8080)
+
+	tests := []struct {
+		value panicResponse
+		body  string
+	}{
+		{value: "", body: http.StatusText(http.StatusInternalServerError)},
+		{value: "oops: the details", body: "oops: the details"},
+	}
+	for i, test := range tests {
+		value := test.value
+		handler := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { panic(value) })
+		endpoint := fmt.Sprintf("vm/%d", i)
+		if err := s.AddRoute(&common.HTTPHandler{Handler: handler}, new(sync.RWMutex), endpoint, "", logging.NoLog{}); err != nil {
+			t.Fatal(err)
+		}
+
+		writer := httptest.NewRecorder()
+		if err := s.Call(writer, "POST", fmt.Sprint(i), "", nil, nil); err != nil {
+			t.Fatal(err)
+		}
+		if writer.Code != http.StatusInternalServerError {
+			t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, writer.Code)
+		}
+		if body := strings.TrimSpace(writer.Body.String()); body != test.body {
+			t.Fatalf("expected body %q but got %q", test.body, body)
+		}
+	}
+}
@@ -4,7 +4,9 @@
 package chains
 
 import (
+	"errors"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/gecko/api"
@@ -25,6 +27,7 @@ import (
 	"github.com/ava-labs/gecko/snow/networking/timeout"
 	"github.com/ava-labs/gecko/snow/triggers"
 	"github.com/ava-labs/gecko/snow/validators"
+	"github.com/ava-labs/gecko/utils/labels"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/math"
 	"github.com/ava-labs/gecko/vms"
@@ -41,8 +44,34 @@ const (
 	requestTimeout     = 4 * time.Second
 	gossipFrequency    = 10 * time.Second
 	shutdownTimeout    = 1 * time.Second
+
+	// maxCreationErrors is the number of chain creation errors remembered.
+	// Older errors are forgotten.
+	maxCreationErrors = 100
+)
+
+// Prefixes of the databases a chain is stored in, within the database prefixed
+// by the chain's ID
+const (
+	vmDBPrefix                  = "vm"
+	vertexDBPrefix              = "vertex"
+	vertexBootstrappingDBPrefix = "vertex_bs"
+	txBootstrappingDBPrefix     = "tx_bs"
+	bootstrappingDBPrefix       = "bs"
 )
 
+// DBPrefixes are the prefixes of the databases a chain may be stored in,
+// within the database prefixed by the chain's ID
+var DBPrefixes = []string{
+	vmDBPrefix,
+	vertexDBPrefix,
+	vertexBootstrappingDBPrefix,
+	txBootstrappingDBPrefix,
+	bootstrappingDBPrefix,
+}
+
+var errUnknownVMType = errors.New("the vm should have type avalanche.DAGVM or snowman.ChainVM")
+
 // Manager manages the chains running on this node.
 // It can:
 //   * Create a chain
@@ -76,6 +105,32 @@ type Manager interface {
 	// Add an alias to a chain
 	Alias(ids.ID, string) error
 
+	// Returns the ID of the VM the chain with the given ID is running, and
+	// false if no such chain has been created. Thread safe.
+	ChainVM(ids.ID) (ids.ID, bool)
+
+	// Returns the ID of the subnet that validates the chain with the given ID,
+	// and false if no such chain has been created. Thread safe.
+	ChainSubnet(ids.ID) (ids.ID, bool)
+
+	// Returns the most recent chains that failed to be created, and why, in
+	// the order they failed. Thread safe.
+	CreationErrors() []CreationError
+
+	// Returns the validators of the chains of the subnet with the given ID, and
+	// false if the subnet doesn't exist. If staking is disabled, every subnet is
+	// validated by the default subnet's validators. Thread safe.
+	SubnetValidators(ids.ID) (validators.Set, bool)
+
+	// Returns true iff the chains that were waiting for the platform chain to
+	// finish bootstrapping have been created. Chains that failed to be created
+	// won't be running. Thread safe.
+	Unblocked() bool
+
+	// Shuts down the chains one at a time, most recently created first, so
+	// that chains are shut down before the chain that created them
+	ShutdownChains()
+
 	Shutdown()
 }
 
@@ -116,6 +171,37 @@ type manager struct {
 
 	unblocked     bool
 	blockedChains []ChainParameters
+
+	// true iff the blocked chains have been created
+	createdBlockedChainsLock sync.RWMutex
+	createdBlockedChains     bool
+
+	// Key: The key underlying a created chain's ID
+	// Value: The ID of the VM that chain is running
+	chainVMsLock sync.RWMutex
+	chainVMs     map[[32]byte]ids.ID
+	// Key: The key underlying a created chain's ID
+	// Value: The ID of the subnet that validates that chain
+	// Guarded by [chainVMsLock]
+	chainSubnets map[[32]byte]ids.ID
+	// IDs of the created chains, in the order they were created. Guarded by
+	// [chainVMsLock]
+	chainOrder []ids.ID
+
+	// The most recent chain creation errors, stored in a ring of up to
+	// [maxCreationErrors]. [nextCreationError] is the index the next error is
+	// stored at, which holds the oldest error once the ring is full.
+	creationErrorsLock sync.Mutex
+	creationErrors     []CreationError
+	nextCreationError  int
+}
+
+// CreationError describes a chain that failed to be created
+type CreationError struct {
+	ChainID ids.ID
+	VMID    string
+	Error   string
+	Time    time.Time
 }
 
 // New returns a new Manager where:
@@ -143,7 +229,7 @@ func New(
 ) Manager {
 	timeoutManager := timeout.Manager{}
 	timeoutManager.Initialize(requestTimeout)
-	go log.RecoverAndPanic(timeoutManager.Dispatch)
+	go labels.Do(func() { log.RecoverAndPanic(timeoutManager.Dispatch) }, labels.Subsystem, "timeouts")
 
 	router.Initialize(log, &timeoutManager, gossipFrequency, shutdownTimeout)
 
@@ -165,6 +251,8 @@ func New(
 		server:          server,
 		keystore:        keystore,
 		sharedMemory:    sharedMemory,
+		chainVMs:        make(map[[32]byte]ids.ID),
+		chainSubnets:    make(map[[32]byte]ids.ID),
 	}
 	m.Initialize()
 	return m
@@ -191,17 +279,39 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 		chain.VMAlias,
 	)
 
+	// The goroutines spawned for the chain, including those of its VM, are
+	// labeled with its ID so that they can be attributed to it
+	err := error(nil)
+	labels.Do(func() { err = m.createChain(chain) }, labels.Chain, chain.ID.String())
+	if err != nil {
+		m.log.Error("chain %s not created: %s", chain.ID, err)
+
+		// Report the ID of the VM rather than the alias it was created with,
+		// if the alias is known
+		vmID := chain.VMAlias
+		if id, err := m.vmManager.Lookup(chain.VMAlias); err == nil {
+			vmID = id.String()
+		}
+		m.addCreationError(CreationError{
+			ChainID: chain.ID,
+			VMID:    vmID,
+			Error:   err.Error(),
+			Time:    time.Now(),
+		})
+	}
+}
+
+// createChain creates the chain described by [chain]
+func (m *manager) createChain(chain ChainParameters) error {
 	// Assert that there isn't already a chain with an alias in [chain].Aliases
 	// (Recall that the string repr. of a chain's ID is also an alias for a chain)
 	if alias, isRepeat := m.isChainWithAlias(chain.ID.String()); isRepeat {
-		m.log.Error("there is already a chain with alias '%s'. Chain not created.", alias)
-		return
+		return fmt.Errorf("there is already a chain with alias '%s'", alias)
 	}
 
 	vmID, err := m.vmManager.Lookup(chain.VMAlias)
 	if err != nil {
-		m.log.Error("error while looking up VM: %s", err)
-		return
+		return fmt.Errorf("error while looking up VM: %w", err)
 	}
 
 	primaryAlias, err := m.PrimaryAlias(chain.ID)
@@ -212,8 +322,7 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 	// Create the log and context of the chain
 	chainLog, err := m.logFactory.MakeChain(primaryAlias, "")
 	if err != nil {
-		m.log.Error("error while creating chain's log %s", err)
-		return
+		return fmt.Errorf("error while creating chain's log: %w", err)
 	}
 
 	ctx := &snow.Context{
@@ -232,15 +341,13 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 	// Get a factory for the vm we want to use on our chain
 	vmFactory, err := m.vmManager.GetVMFactory(vmID)
 	if err != nil {
-		m.log.Error("error while getting vmFactory: %s", err)
-		return
+		return fmt.Errorf("error while getting vmFactory: %w", err)
 	}
 
 	// Create the chain
 	vm, err := vmFactory.New(ctx)
 	if err != nil {
-		m.log.Error("error while creating vm: %s", err)
-		return
+		return fmt.Errorf("error while creating vm: %w", err)
 	}
 	// TODO: Shutdown VM if an error occurs
 
@@ -248,21 +355,18 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 	for i, fxAlias := range chain.FxAliases {
 		fxID, err := m.vmManager.Lookup(fxAlias)
 		if err != nil {
-			m.log.Error("error while looking up Fx: %s", err)
-			return
+			return fmt.Errorf("error while looking up Fx: %w", err)
 		}
 
 		// Get a factory for the fx we want to use on our chain
 		fxFactory, err := m.vmManager.GetVMFactory(fxID)
 		if err != nil {
-			m.log.Error("error while getting fxFactory: %s", err)
-			return
+			return fmt.Errorf("error while getting fxFactory: %w", err)
 		}
 
 		fx, err := fxFactory.New(ctx)
 		if err != nil {
-			m.log.Error("error while creating fx: %s", err)
-			return
+			return fmt.Errorf("error while creating fx: %w", err)
 		}
 
 		// Create the fx
@@ -276,16 +380,9 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 	consensusParams.Namespace = fmt.Sprintf("gecko_%s", primaryAlias)
 
 	// The validators of this blockchain
-	var validators validators.Set // Validators validating this blockchain
-	var ok bool
-	if m.stakingEnabled {
-		validators, ok = m.validators.GetValidatorSet(chain.SubnetID)
-	} else { // Staking is disabled. Every peer validates every subnet.
-		validators, ok = m.validators.GetValidatorSet(ids.Empty) // ids.Empty is the default subnet ID. TODO: Move to const package so we can use it here.
-	}
+	validators, ok := m.SubnetValidators(chain.SubnetID)
 	if !ok {
-		m.log.Error("couldn't get validator set of subnet with ID %s. The subnet may not exist", chain.SubnetID)
-		return
+		return fmt.Errorf("couldn't get validator set of subnet with ID %s. The subnet may not exist", chain.SubnetID)
 	}
 
 	beacons := validators
@@ -305,8 +402,7 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 			consensusParams,
 		)
 		if err != nil {
-			m.log.Error("error while creating new avalanche vm %s", err)
-			return
+			return fmt.Errorf("error while creating new avalanche vm: %w", err)
 		}
 	case smeng.ChainVM:
 		err := m.createSnowmanChain(
@@ -319,19 +415,24 @@ func (m *manager) ForceCreateChain(chain ChainParameters) {
 			consensusParams.Parameters,
 		)
 		if err != nil {
-			m.log.Error("error while creating new snowman vm %s", err)
-			return
+			return fmt.Errorf("error while creating new snowman vm: %w", err)
 		}
 	default:
-		m.log.Error("the vm should have type avalanche.DAGVM or snowman.ChainVM. Chain not created")
-		return
+		return errUnknownVMType
 	}
 
 	// Associate the newly created chain with its default alias
 	m.log.AssertNoError(m.Alias(chain.ID, chain.ID.String()))
 
+	m.chainVMsLock.Lock()
+	m.chainVMs[chain.ID.Key()] = vmID
+	m.chainSubnets[chain.ID.Key()] = chain.SubnetID
+	m.chainOrder = append(m.chainOrder, chain.ID)
+	m.chainVMsLock.Unlock()
+
 	// Notify those that registered to be notified when a new chain is created
 	m.notifyRegistrants(ctx, vm)
+	return nil
 }
 
 // Implements Manager.AddRegistrant
@@ -344,6 +445,71 @@ func (m *manager) unblockChains() {
 	for _, chain := range blocked {
 		m.ForceCreateChain(chain)
 	}
+
+	m.createdBlockedChainsLock.Lock()
+	m.createdBlockedChains = true
+	m.createdBlockedChainsLock.Unlock()
+}
+
+// Unblocked implements the Manager interface
+func (m *manager) Unblocked() bool {
+	m.createdBlockedChainsLock.RLock()
+	defer m.createdBlockedChainsLock.RUnlock()
+
+	return m.createdBlockedChains
+}
+
+// ChainVM implements the Manager interface
+func (m *manager) ChainVM(chainID ids.ID) (ids.ID, bool) {
+	m.chainVMsLock.RLock()
+	defer m.chainVMsLock.RUnlock()
+
+	vmID, ok := m.chainVMs[chainID.Key()]
+	return vmID, ok
+}
+
+// ChainSubnet implements the Manager interface
+func (m *manager) ChainSubnet(chainID ids.ID) (ids.ID, bool) {
+	m.chainVMsLock.RLock()
+	defer m.chainVMsLock.RUnlock()
+
+	subnetID, ok := m.chainSubnets[chainID.Key()]
+	return subnetID, ok
+}
+
+// CreationErrors implements the Manager interface
+func (m *manager) CreationErrors() []CreationError {
+	m.creationErrorsLock.Lock()
+	defer m.creationErrorsLock.Unlock()
+
+	creationErrors := make([]CreationError, 0, len(m.creationErrors))
+	if len(m.creationErrors) < maxCreationErrors {
+		return append(creationErrors, m.creationErrors...)
+	}
+	creationErrors = append(creationErrors, m.creationErrors[m.nextCreationError:]...)
+	return append(creationErrors, m.creationErrors[:m.nextCreationError]...)
+}
+
+// addCreationError records [creationError], forgetting the oldest error if
+// [maxCreationErrors] are already recorded
+func (m *manager) addCreationError(creationError CreationError) {
+	m.creationErrorsLock.Lock()
+	defer m.creationErrorsLock.Unlock()
+
+	if len(m.creationErrors) < maxCreationErrors {
+		m.creationErrors = append(m.creationErrors, creationError)
+	} else {
+		m.creationErrors[m.nextCreationError] = creationError
+	}
+	m.nextCreationError = (m.nextCreationError + 1) % maxCreationErrors
+}
+
+// SubnetValidators implements the Manager interface
+func (m *manager) SubnetValidators(subnetID ids.ID) (validators.Set, bool) {
+	if !m.stakingEnabled { // Staking is disabled. Every peer validates every subnet.
+		subnetID = ids.Empty // ids.Empty is the default subnet ID. TODO: Move to const package so we can use it here.
+	}
+	return m.validators.GetValidatorSet(subnetID)
 }
 
 // Create a DAG-based blockchain that uses Avalanche
@@ -360,10 +526,10 @@ func (m *manager) createAvalancheChain(
 	defer ctx.Lock.Unlock()
 
 	db := prefixdb.New(ctx.ChainID.Bytes(), m.db)
-	vmDB := prefixdb.New([]byte("vm"), db)
-	vertexDB := prefixdb.New([]byte("vertex"), db)
-	vertexBootstrappingDB := prefixdb.New([]byte("vertex_bs"), db)
-	txBootstrappingDB := prefixdb.New([]byte("tx_bs"), db)
+	vmDB := prefixdb.New([]byte(vmDBPrefix), db)
+	vertexDB := prefixdb.New([]byte(vertexDBPrefix), db)
+	vertexBootstrappingDB := prefixdb.New([]byte(vertexBootstrappingDBPrefix), db)
+	txBootstrappingDB := prefixdb.New([]byte(txBootstrappingDBPrefix), db)
 
 	vtxBlocker, err := queue.New(vertexBootstrappingDB)
 	if err != nil {
@@ -472,8 +638,8 @@ func (m *manager) createSnowmanChain(
 	defer ctx.Lock.Unlock()
 
 	db := prefixdb.New(ctx.ChainID.Bytes(), m.db)
-	vmDB := prefixdb.New([]byte("vm"), db)
-	bootstrappingDB := prefixdb.New([]byte("bs"), db)
+	vmDB := prefixdb.New([]byte(vmDBPrefix), db)
+	bootstrappingDB := prefixdb.New([]byte(bootstrappingDBPrefix), db)
 
 	blocked, err := queue.New(bootstrappingDB)
 	if err != nil {
@@ -552,6 +718,19 @@ func (m *manager) createSnowmanChain(
 // Shutdown stops all the chains
 func (m *manager) Shutdown() { m.chainRouter.Shutdown() }
 
+// ShutdownChains stops the chains one at a time, most recently created first
+func (m *manager) ShutdownChains() {
+	m.chainVMsLock.RLock()
+	chainOrder := make([]ids.ID, len(m.chainOrder))
+	copy(chainOrder, m.chainOrder)
+	m.chainVMsLock.RUnlock()
+
+	for i := len(chainOrder) - 1; i >= 0; i-- {
+		m.log.Info("shutting down chain %s", chainOrder[i])
+		m.chainRouter.RemoveChain(chainOrder[i])
+	}
+}
+
 // LookupVM returns the ID of the VM associated with an alias
 func (m *manager) LookupVM(alias string) (ids.ID, error) { return m.vmManager.Lookup(alias) }
 
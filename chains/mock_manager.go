@@ -3,6 +3,7 @@ package chains
 import (
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow/networking/router"
+	"github.com/ava-labs/gecko/snow/validators"
 )
 
 // MockManager implements Manager but does nothing. Always returns nil error.
@@ -33,5 +34,23 @@ func (mm MockManager) Aliases(ids.ID) []string { return nil }
 // Alias ...
 func (mm MockManager) Alias(ids.ID, string) error { return nil }
 
+// ChainVM ...
+func (mm MockManager) ChainVM(ids.ID) (ids.ID, bool) { return ids.ID{}, false }
+
+// ChainSubnet ...
+func (mm MockManager) ChainSubnet(ids.ID) (ids.ID, bool) { return ids.ID{}, false }
+
+// CreationErrors ...
+func (mm MockManager) CreationErrors() []CreationError { return nil }
+
+// SubnetValidators ...
+func (mm MockManager) SubnetValidators(ids.ID) (validators.Set, bool) { return nil, false }
+
+// Unblocked ...
+func (mm MockManager) Unblocked() bool { return false }
+
+// ShutdownChains ...
+func (mm MockManager) ShutdownChains() {}
+
 // Shutdown ...
 func (mm MockManager) Shutdown() {}
@@ -9,6 +9,7 @@ package database
 
 import (
 	"io"
+	"time"
 )
 
 // KeyValueReader wraps the Has and Get method of a backing data store.
@@ -49,6 +50,51 @@ type Compacter interface {
 	Compact(start []byte, limit []byte) error
 }
 
+// SizeEstimator wraps the EstimateSize method of a backing data store. Not
+// every data store supports it.
+type SizeEstimator interface {
+	// EstimateSize returns the approximate number of bytes on disk taken up
+	// by the keys that start with [prefix] and their values. Recent writes
+	// may not be included until they're flushed to disk.
+	EstimateSize(prefix []byte) (uint64, error)
+}
+
+// CacheResizer wraps the methods of a backing data store whose read cache can
+// be resized at runtime. Not every data store supports it.
+type CacheResizer interface {
+	// CacheSize returns the capacity, in bytes, of the read cache
+	CacheSize() int
+
+	// SetCacheSize changes the capacity of the read cache to [size] bytes,
+	// evicting entries right away if the cache is shrunk. Returns an error
+	// if [size] is below the data store's minimum.
+	SetCacheSize(size int) error
+}
+
+// CompactionReporter wraps the CompactionStatus method of a backing data store
+// that tracks the compactions requested with Compact. Not every data store
+// supports it.
+type CompactionReporter interface {
+	// CompactionStatus returns the state of the compactions requested with
+	// Compact. Compactions the data store runs in the background on its own
+	// aren't included.
+	CompactionStatus() CompactionStatus
+}
+
+// CompactionStatus describes the compactions of a backing data store
+type CompactionStatus struct {
+	// True if a compaction is running
+	InProgress bool
+
+	// Estimate, between 0 and 1, of how much of the running compactions is
+	// done. 0 if no compaction is running.
+	Progress float64
+
+	// Time the most recent compaction finished at. Zero if no compaction has
+	// finished.
+	LastCompleted time.Time
+}
+
 // Database contains all the methods required to allow handling different
 // key-value data stores backing the database.
 type Database interface {
@@ -5,10 +5,14 @@ package leveldb
 
 import (
 	"bytes"
+	"fmt"
+	"sync"
+	"time"
 
 	"github.com/ava-labs/gecko/database"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/cache"
 	"github.com/syndtr/goleveldb/leveldb/errors"
 	"github.com/syndtr/goleveldb/leveldb/filter"
 	"github.com/syndtr/goleveldb/leveldb/iterator"
@@ -33,7 +37,23 @@ const (
 // Database is a persistent key-value store. Apart from basic data storage
 // functionality it also supports batch writes and iterating over the keyspace
 // in binary-alphabetical order.
-type Database struct{ *leveldb.DB }
+type Database struct {
+	*leveldb.DB
+
+	// the block cache, which is kept to be able to resize it. Nil if the
+	// database had to be recovered, as it's then opened with the default
+	// options.
+	blockCache cache.Cacher
+
+	// state of the compactions requested with Compact. The sizes are the
+	// estimated sizes of the key ranges being compacted, plus one per range
+	// so that empty ranges count towards the progress.
+	compactionLock    sync.Mutex
+	compactions       int
+	compactionSize    uint64
+	compactedSize     uint64
+	lastCompactedTime time.Time
+}
 
 // New returns a wrapped LevelDB object.
 func New(file string, blockCacheSize, writeBufferSize, handleCap int) (*Database, error) {
@@ -48,21 +68,32 @@ func New(file string, blockCacheSize, writeBufferSize, handleCap int) (*Database
 		handleCap = minHandleCap
 	}
 
-	// Open the db and recover any potential corruptions
-	db, err := leveldb.OpenFile(file, &opt.Options{
+	var blockCache cache.Cacher
+	options := &opt.Options{
 		OpenFilesCacheCapacity: handleCap,
 		BlockCacheCapacity:     blockCacheSize,
+		BlockCacher: &opt.CacherFunc{NewFunc: func(capacity int) cache.Cacher {
+			blockCache = cache.NewLRU(capacity)
+			return blockCache
+		}},
 		// There are two buffers of size WriteBuffer used.
 		WriteBuffer: writeBufferSize / 2,
 		Filter:      filter.NewBloomFilter(10),
-	})
+	}
+
+	// Open the db and recover any potential corruptions
+	db, err := leveldb.OpenFile(file, options)
 	if _, corrupted := err.(*errors.ErrCorrupted); corrupted {
+		blockCache = nil
 		db, err = leveldb.RecoverFile(file, nil)
 	}
 	if err != nil {
 		return nil, err
 	}
-	return &Database{DB: db}, nil
+	return &Database{
+		DB:         db,
+		blockCache: blockCache,
+	}, nil
 }
 
 // Has returns if the key is set in the database
@@ -134,8 +165,123 @@ func (db *Database) Stat(property string) (string, error) {
 // A nil start is treated as a key before all keys in the DB.
 // And a nil limit is treated as a key after all keys in the DB.
 // Therefore if both are nil then it will compact entire DB.
+//
+// A compaction of the entire DB is split into ranges by the first byte of the
+// keys, so that its progress can be estimated.
 func (db *Database) Compact(start []byte, limit []byte) error {
-	return updateError(db.DB.CompactRange(util.Range{Start: start, Limit: limit}))
+	ranges := []util.Range{{Start: start, Limit: limit}}
+	if start == nil && limit == nil {
+		ranges = firstByteRanges()
+	}
+	sizes, err := db.DB.SizeOf(ranges)
+	if err != nil {
+		return updateError(err)
+	}
+
+	total := uint64(0)
+	for _, size := range sizes {
+		total += uint64(size) + 1
+	}
+	db.startCompaction(total)
+
+	for i, r := range ranges {
+		if err := db.DB.CompactRange(r); err != nil {
+			db.finishCompaction(false)
+			return updateError(err)
+		}
+		db.compacted(uint64(sizes[i]) + 1)
+	}
+	db.finishCompaction(true)
+	return nil
+}
+
+// firstByteRanges returns the ranges of keys that start with each byte, which
+// together cover every key
+func firstByteRanges() []util.Range {
+	ranges := make([]util.Range, 256)
+	for i := range ranges {
+		if i > 0 {
+			ranges[i].Start = []byte{byte(i)}
+		}
+		if i < 255 {
+			ranges[i].Limit = []byte{byte(i + 1)}
+		}
+	}
+	return ranges
+}
+
+func (db *Database) startCompaction(size uint64) {
+	db.compactionLock.Lock()
+	defer db.compactionLock.Unlock()
+
+	db.compactions++
+	db.compactionSize += size
+}
+
+func (db *Database) compacted(size uint64) {
+	db.compactionLock.Lock()
+	defer db.compactionLock.Unlock()
+
+	db.compactedSize += size
+}
+
+func (db *Database) finishCompaction(completed bool) {
+	db.compactionLock.Lock()
+	defer db.compactionLock.Unlock()
+
+	db.compactions--
+	if db.compactions == 0 {
+		db.compactionSize = 0
+		db.compactedSize = 0
+	}
+	if completed {
+		db.lastCompactedTime = time.Now()
+	}
+}
+
+// CompactionStatus implements the CompactionReporter interface
+func (db *Database) CompactionStatus() database.CompactionStatus {
+	db.compactionLock.Lock()
+	defer db.compactionLock.Unlock()
+
+	status := database.CompactionStatus{
+		InProgress:    db.compactions > 0,
+		LastCompleted: db.lastCompactedTime,
+	}
+	if db.compactionSize > 0 {
+		status.Progress = float64(db.compactedSize) / float64(db.compactionSize)
+	}
+	return status
+}
+
+// EstimateSize implements the SizeEstimator interface
+func (db *Database) EstimateSize(prefix []byte) (uint64, error) {
+	sizes, err := db.DB.SizeOf([]util.Range{*util.BytesPrefix(prefix)})
+	if err != nil {
+		return 0, updateError(err)
+	}
+	return uint64(sizes.Sum()), nil
+}
+
+// CacheSize implements the CacheResizer interface. Returns 0 if the block
+// cache can't be resized.
+func (db *Database) CacheSize() int {
+	if db.blockCache == nil {
+		return 0
+	}
+	return db.blockCache.Capacity()
+}
+
+// SetCacheSize implements the CacheResizer interface
+func (db *Database) SetCacheSize(size int) error {
+	if db.blockCache == nil {
+		return fmt.Errorf("the block cache of a recovered database can't be resized: %w", database.ErrNotSupported)
+	}
+	if size < minBlockCacheSize {
+		return fmt.Errorf("cache size %d is below the minimum of %d bytes", size, minBlockCacheSize)
+	}
+	db.blockCache.SetCapacity(size)
+	return nil
 }
 
 // Close implements the Database interface
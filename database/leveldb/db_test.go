@@ -25,3 +25,100 @@ func TestInterface(t *testing.T) {
 		test(t, db)
 	}
 }
+
+func TestEstimateSize(t *testing.T) {
+	folder := "dbsize"
+	db, err := New(folder, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("leveldb.New(%s, 0, 0) errored with %s", folder, err)
+	}
+	defer os.RemoveAll(folder)
+	defer db.Close()
+
+	value := make([]byte, 1024)
+	for i := 0; i < 100; i++ {
+		if err := db.Put([]byte(fmt.Sprintf("large%d", i)), value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Flush the writes to disk, so that they're included in the estimates
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if size, err := db.EstimateSize([]byte("large")); err != nil {
+		t.Fatal(err)
+	} else if size == 0 {
+		t.Fatal("expected the written keys to take up space")
+	}
+	if size, err := db.EstimateSize([]byte("missing")); err != nil {
+		t.Fatal(err)
+	} else if size != 0 {
+		t.Fatalf("expected a prefix without keys to take up no space, got %d bytes", size)
+	}
+}
+
+func TestSetCacheSize(t *testing.T) {
+	folder := "dbcache"
+	db, err := New(folder, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("leveldb.New(%s, 0, 0) errored with %s", folder, err)
+	}
+	defer os.RemoveAll(folder)
+	defer db.Close()
+
+	if size := db.CacheSize(); size != minBlockCacheSize {
+		t.Fatalf("expected the cache to start at the minimum size %d, got %d", minBlockCacheSize, size)
+	}
+	if err := db.SetCacheSize(4 * minBlockCacheSize); err != nil {
+		t.Fatal(err)
+	}
+	if size := db.CacheSize(); size != 4*minBlockCacheSize {
+		t.Fatalf("expected a cache size of %d, got %d", 4*minBlockCacheSize, size)
+	}
+	if err := db.SetCacheSize(0); err == nil {
+		t.Fatal("expected a cache size below the minimum to be rejected")
+	}
+	if size := db.CacheSize(); size != 4*minBlockCacheSize {
+		t.Fatalf("expected a rejected size to leave the cache at %d, got %d", 4*minBlockCacheSize, size)
+	}
+}
+
+func TestCompactionStatus(t *testing.T) {
+	folder := "dbcompaction"
+	db, err := New(folder, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("leveldb.New(%s, 0, 0) errored with %s", folder, err)
+	}
+	defer os.RemoveAll(folder)
+	defer db.Close()
+
+	if status := db.CompactionStatus(); status.InProgress || !status.LastCompleted.IsZero() {
+		t.Fatalf("expected no compactions to have run, got %+v", status)
+	}
+
+	for i := 0; i < 100; i++ {
+		if err := db.Put([]byte{byte(i), byte(i)}, []byte("value")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := db.Compact(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	status := db.CompactionStatus()
+	if status.InProgress {
+		t.Fatal("expected the compaction to have finished")
+	}
+	if status.Progress != 0 {
+		t.Fatalf("expected no progress to be reported once the compaction finished, got %f", status.Progress)
+	}
+	if status.LastCompleted.IsZero() {
+		t.Fatal("expected the compaction's completion time to be recorded")
+	}
+	if value, err := db.Get([]byte{50, 50}); err != nil {
+		t.Fatal(err)
+	} else if string(value) != "value" {
+		t.Fatalf("expected the compaction to keep the values, got %q", value)
+	}
+}
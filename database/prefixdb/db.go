@@ -142,6 +142,22 @@ func (db *Database) Compact(start, limit []byte) error {
 	return db.db.Compact(db.prefix(start), db.prefix(limit))
 }
 
+// EstimateSize implements the SizeEstimator interface. Returns
+// database.ErrNotSupported if the underlying database can't estimate sizes.
+func (db *Database) EstimateSize(prefix []byte) (uint64, error) {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	if db.db == nil {
+		return 0, database.ErrClosed
+	}
+	estimator, ok := db.db.(database.SizeEstimator)
+	if !ok {
+		return 0, database.ErrNotSupported
+	}
+	return estimator.EstimateSize(db.prefix(prefix))
+}
+
 // Close implements the Database interface
 func (db *Database) Close() error {
 	db.lock.Lock()
@@ -4,6 +4,7 @@
 package prefixdb
 
 import (
+	"bytes"
 	"testing"
 
 	"github.com/ava-labs/gecko/database"
@@ -21,3 +22,28 @@ func TestInterface(t *testing.T) {
 		test(t, NewNested([]byte("ld"), New([]byte("wor"), db)))
 	}
 }
+
+type estimatorDB struct {
+	database.Database
+	prefix []byte
+}
+
+func (db *estimatorDB) EstimateSize(prefix []byte) (uint64, error) {
+	db.prefix = prefix
+	return 1, nil
+}
+
+func TestEstimateSize(t *testing.T) {
+	db := &estimatorDB{Database: memdb.New()}
+	prefixDB := New([]byte("hello"), db)
+	if _, err := prefixDB.EstimateSize([]byte("key")); err != nil {
+		t.Fatal(err)
+	}
+	if expected := prefixDB.prefix([]byte("key")); !bytes.Equal(db.prefix, expected) {
+		t.Fatalf("expected the size of prefix %x to be estimated, got %x", expected, db.prefix)
+	}
+
+	if _, err := New([]byte("hello"), memdb.New()).EstimateSize(nil); err != database.ErrNotSupported {
+		t.Fatalf("expected %s, got %v", database.ErrNotSupported, err)
+	}
+}
@@ -73,6 +73,7 @@ func main() {
 
 	mapper.MapPort(Config.StakingIP.Port, Config.StakingIP.Port)
 	mapper.MapPort(Config.HTTPPort, Config.HTTPPort)
+	Config.NatMapper = mapper
 
 	node := node.Node{}
 
@@ -12,12 +12,14 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ava-labs/gecko/database/leveldb"
 	"github.com/ava-labs/gecko/database/memdb"
 	"github.com/ava-labs/gecko/genesis"
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/nat"
+	"github.com/ava-labs/gecko/network"
 	"github.com/ava-labs/gecko/node"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/staking"
@@ -27,6 +29,7 @@ import (
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/random"
 	"github.com/ava-labs/gecko/utils/wrappers"
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp"
 )
 
 const (
@@ -52,6 +55,7 @@ var (
 var (
 	errBootstrapMismatch  = errors.New("more bootstrap IDs provided than bootstrap IPs")
 	errStakingRequiresTLS = errors.New("if staking is enabled, network TLS must also be enabled")
+	errForceAcceptMainnet = errors.New("force accepting blocks can't be enabled on mainnet")
 )
 
 // GetIPs returns the default IPs for each network
@@ -207,9 +211,54 @@ func init() {
 	fs.BoolVar(&Config.EnableP2PTLS, "p2p-tls-enabled", true, "Require TLS to authenticate network communication")
 	fs.StringVar(&Config.StakingKeyFile, "staking-tls-key-file", defaultStakingKeyPath, "TLS private key for staking")
 	fs.StringVar(&Config.StakingCertFile, "staking-tls-cert-file", defaultStakingCertPath, "TLS certificate for staking")
+	fs.DurationVar(&Config.StakingCertExpiryWarning, "staking-tls-cert-expiry-warning", 30*24*time.Hour, "How long before the staking certificate expires the Admin API warns that it's about to. The node's ID is derived from the certificate, so it changes when the certificate is renewed")
+	stakingTLSMinVersion := fs.String("staking-tls-min-version", "1.2", "Oldest TLS version peers may connect with. One of {1.0, 1.1, 1.2, 1.3}. Setting this higher than the version older peers support isolates the node from them")
 
 	// Plugins:
 	fs.StringVar(&Config.PluginDir, "plugin-dir", defaultPluginDirs[0], "Plugin directory for Ava VMs")
+	fs.BoolVar(&Config.PluginHTTPConfig.GenerateRequestIDs, "plugin-http-generate-request-ids", false, "If true, an X-Request-ID is generated for plugin HTTP requests that don't have one")
+	fs.BoolVar(&Config.PluginHTTPConfig.CompressResponses, "plugin-http-compression", false, "If true, compressible plugin HTTP responses are gzipped for clients that accept it")
+	pluginHTTPCompressibleTypes := fs.String("plugin-http-compressible-types", strings.Join(ghttp.DefaultCompressibleContentTypes, ","), "Comma separated list of content types that plugin HTTP responses may be compressed for. Types ending in / match every subtype")
+	fs.IntVar(&Config.PluginHTTPConfig.CompressMinSize, "plugin-http-compression-min-size", ghttp.DefaultCompressMinSize, "Size, in bytes, of the smallest plugin HTTP response body that is gzipped when plugin-http-compression is enabled")
+	fs.BoolVar(&Config.PluginHTTPConfig.BridgeCompression, "plugin-http-bridge-compression", false, "If true, plugins gzip large parts of HTTP responses when sending them to the node")
+	fs.IntVar(&Config.PluginHTTPConfig.BridgeCompressionMinSize, "plugin-http-bridge-compression-min-size", ghttp.DefaultBridgeCompressionMinSize, "Size, in bytes, of the smallest part of a plugin HTTP response that is compressed when sent to the node")
+	fs.BoolVar(&Config.PluginHTTPConfig.StreamResponses, "plugin-http-stream-responses", false, "If true, plugin HTTP responses are streamed to clients in chunks as plugins write them")
+	fs.IntVar(&Config.PluginHTTPConfig.StreamChunkSize, "plugin-http-stream-chunk-size", ghttp.DefaultStreamChunkSize, "Size, in bytes, of the chunks plugin HTTP responses are streamed in")
+	fs.DurationVar(&Config.PluginHTTPConfig.IdleTimeout, "plugin-http-idle-timeout", 0, "Time a plugin HTTP request may go without reading its body or writing its response before it is cancelled. If 0, requests never time out")
+	fs.DurationVar(&Config.PluginHTTPConfig.WriteTimeout, "plugin-http-write-timeout", 0, "Time writing part of a plugin HTTP response to the client may take before the request is aborted. If 0, writes never time out")
+	fs.DurationVar(&Config.PluginHTTPConfig.HandlerTimeout, "plugin-http-handler-timeout", 0, "Time a plugin may take to handle an HTTP request before it is cancelled, unless plugin-http-route-timeouts sets a timeout for the request's path. If 0, requests never time out")
+	fs.BoolVar(&Config.PluginHTTPConfig.ConveyRequestStart, "plugin-http-convey-request-start", false, "If true, plugins are told when each HTTP request was received, so they can tell how much of its latency budget remains")
+	pluginHTTPRouteTimeouts := fs.String("plugin-http-route-timeouts", "", "JSON object of the timeouts of plugin HTTP requests whose paths start with each prefix, such as {\"/ext/bc/X/query\":\"2m\"}. The longest matching prefix is used")
+	fs.DurationVar(&Config.PluginHTTPConfig.Keepalive.Time, "plugin-http-keepalive-time", ghttp.DefaultKeepaliveTime, "Time a connection bridging plugin HTTP requests may go without activity before the plugin is pinged")
+	fs.DurationVar(&Config.PluginHTTPConfig.Keepalive.Timeout, "plugin-http-keepalive-timeout", ghttp.DefaultKeepaliveTimeout, "Time a ping of a plugin may go unanswered before the connection bridging its HTTP requests is closed")
+	fs.BoolVar(&Config.PluginHTTPConfig.Keepalive.PermitWithoutStream, "plugin-http-keepalive-permit-without-stream", false, "If true, plugins may ping the connections bridging their HTTP requests while no requests are in flight")
+	fs.BoolVar(&Config.PluginHTTPConfig.PanicDetails, "plugin-http-panic-details", false, "If true, the 500 replied when a plugin's HTTP handler panics includes the panic and the plugin's stack. Should only be used during development")
+	pluginHTTPIsolatedVMs := fs.String("plugin-http-isolate-panics-vms", "", "Comma separated list of IDs of VMs whose plugins' HTTP handler panics are logged and replied to with a 500 by the plugin's handler, rather than raised again in the node's HTTP server")
+	fs.BoolVar(&Config.PluginHTTPMetrics, "plugin-http-metrics", false, "If true, the number, status and latency of plugin HTTP requests are recorded in the node's metrics, by the endpoint the plugin names or by path")
+	fs.IntVar(&Config.PluginHTTPMaxMetricsEndpoints, "plugin-http-max-metrics-endpoints", ghttp.DefaultMaxMetricsEndpoints, "Number of distinct endpoints a plugin's HTTP requests are recorded under. Requests to further endpoints are recorded under \"other\"")
+	pluginHTTPRequestLogLevel := fs.String("plugin-http-request-log-level", ghttp.RequestLogNone.String(), "How verbosely plugin HTTP requests are logged: none, metadata, headers or full. Can be changed for each plugin through the admin API")
+	fs.IntVar(&Config.PluginHTTPRequestLogMaxBodySize, "plugin-http-request-log-max-body-size", ghttp.DefaultRequestLogMaxBodySize, "Number of bytes of each plugin HTTP request and response body that are logged at the full request log level")
+	fs.IntVar(&Config.PluginHTTPConfig.MaxConcurrentRequests, "plugin-http-max-concurrent-requests", 0, "Number of HTTP requests a plugin may handle at once. If 0, the number isn't limited")
+	fs.IntVar(&Config.PluginHTTPConfig.MaxQueuedRequests, "plugin-http-max-queued-requests", 0, "Number of HTTP requests that may wait to be handled by a plugin if plugin-http-max-concurrent-requests is set. Requests beyond this are rejected. If 0, the number isn't limited")
+	fs.Int64Var(&Config.PluginHTTPConfig.MaxResponseBodySize, "plugin-http-max-response-body-size", ghttp.DefaultMaxResponseBodySize, "Size, in bytes, of the largest HTTP response body a plugin may write")
+	fs.BoolVar(&Config.PluginHTTPConfig.TruncateLargeResponses, "plugin-http-truncate-large-responses", false, "If true, plugin HTTP response bodies larger than plugin-http-max-response-body-size are truncated. Otherwise, they're replied to with a 500, or dropped if the response was started")
+	fs.BoolVar(&Config.PluginHTTPConfig.ETags, "plugin-http-etags", false, "If true, plugin HTTP responses to GET requests are tagged with a hash of their body, and requests whose If-None-Match matches the tag are replied to with a 304")
+	fs.IntVar(&Config.PluginHTTPConfig.ETagMaxBodySize, "plugin-http-etag-max-body-size", ghttp.DefaultETagMaxBodySize, "Size, in bytes, of the largest plugin HTTP response body that is tagged when plugin-http-etags is set")
+	pluginHTTPCORSOrigins := fs.String("plugin-http-cors-allowed-origins", "", "Comma separated list of origins that CORS preflight requests to plugins are answered for without calling the plugin. If empty, plugins handle CORS themselves")
+	pluginHTTPCORSMethods := fs.String("plugin-http-cors-allowed-methods", "", "Comma separated list of methods allowed in cross origin requests to plugins. If empty, GET, POST and HEAD are allowed")
+	pluginHTTPCORSHeaders := fs.String("plugin-http-cors-allowed-headers", "", "Comma separated list of headers allowed in cross origin requests to plugins")
+	pluginHTTPTrustedProxies := fs.String("plugin-http-trusted-proxies", "", "Comma separated list of IPs and CIDR ranges of proxies whose Forwarded headers are honored for plugin HTTP requests")
+	pluginHTTPResponseHeaders := fs.String("plugin-http-response-headers", "", "JSON object of headers added to every plugin HTTP response, such as {\"X-Content-Type-Options\":\"nosniff\"}. Headers set by plugins take precedence")
+	pluginHTTPDedicatedVMs := fs.String("plugin-http-dedicated-vms", "", "Comma separated list of IDs of VMs whose plugins get dedicated, pre-established connections for their HTTP requests. Each connection costs memory in the node and the plugin. Other plugins share one connection per handler")
+	fs.IntVar(&Config.PluginHTTPDedicatedConnections, "plugin-http-dedicated-connections", 4, "Number of connections each HTTP handler of the plugins in plugin-http-dedicated-vms gets")
+	pluginHTTPIdempotentVMs := fs.String("plugin-http-idempotent-vms", "", "Comma separated list of IDs of VMs whose plugins' responses to requests with an Idempotency-Key header are replayed to retries of the requests")
+	fs.DurationVar(&Config.PluginHTTPConfig.Idempotency.TTL, "plugin-http-idempotency-ttl", ghttp.DefaultIdempotencyTTL, "Time a plugin's response to a request with an Idempotency-Key header is replayed for")
+	fs.IntVar(&Config.PluginHTTPConfig.Idempotency.MaxKeys, "plugin-http-idempotency-max-keys", ghttp.DefaultIdempotencyMaxKeys, "Number of responses to requests with an Idempotency-Key header that are kept for each plugin HTTP handler")
+	fs.IntVar(&Config.PluginHTTPConfig.Retry.MaxRetries, "plugin-http-max-retries", 0, "Number of times a plugin HTTP GET or HEAD request without a body is retried if the plugin replies with a 503 or is unavailable. If 0, requests aren't retried")
+	fs.DurationVar(&Config.PluginHTTPConfig.Retry.Backoff, "plugin-http-retry-backoff", ghttp.DefaultRetryBackoff, "Time waited before the first retry of a plugin HTTP request. Doubles for every further retry")
+	pluginHTTPCapturePaths := fs.String("plugin-http-capture-paths", "", "Comma separated list of path prefixes of plugin HTTP requests whose bodies and responses are kept in memory for debugging, and can be fetched with the admin API. Captures may hold secrets. If empty, nothing is captured")
+	fs.IntVar(&Config.PluginHTTPConfig.Capture.MaxBodySize, "plugin-http-capture-max-body-size", ghttp.DefaultCaptureMaxBodySize, "Number of bytes of each captured plugin HTTP request and response body that are kept")
+	fs.IntVar(&Config.PluginHTTPConfig.Capture.MaxPairs, "plugin-http-capture-max-pairs", ghttp.DefaultCaptureMaxPairs, "Number of captured plugin HTTP requests and responses that are kept for each plugin HTTP handler")
 
 	// Logging:
 	logsDir := fs.String("log-dir", "", "Logging directory for Ava")
@@ -226,6 +275,8 @@ func init() {
 
 	// Enable/Disable APIs:
 	fs.BoolVar(&Config.AdminAPIEnabled, "api-admin-enabled", true, "If true, this node exposes the Admin API")
+	fs.BoolVar(&Config.AdminConsensusTuningEnabled, "api-admin-consensus-tuning-enabled", false, "If true, the Admin API may change the consensus parameters of chains at runtime. Should only be used on test networks")
+	fs.BoolVar(&Config.AdminForceAcceptEnabled, "api-admin-unsafe-force-accept-enabled", false, "If true, the Admin API may accept blocks without waiting for consensus, to recover a stuck test network. Not allowed on mainnet")
 	fs.BoolVar(&Config.KeystoreAPIEnabled, "api-keystore-enabled", true, "If true, this node exposes the Keystore API")
 	fs.BoolVar(&Config.MetricsAPIEnabled, "api-metrics-enabled", true, "If true, this node exposes the Metrics API")
 	fs.BoolVar(&Config.HealthAPIEnabled, "api-health-enabled", true, "If true, this node exposes the Health API")
@@ -267,6 +318,11 @@ func init() {
 
 	Config.NetworkID = networkID
 
+	if Config.AdminForceAcceptEnabled && networkID == genesis.MainnetID {
+		errs.Add(errForceAcceptMainnet)
+		return
+	}
+
 	// DB:
 	if *db {
 		*dbDir = os.ExpandEnv(*dbDir) // parse any env variables
@@ -277,6 +333,7 @@ func init() {
 			return
 		}
 		Config.DB = db
+		Config.DBPath = dbPath
 	} else {
 		Config.DB = memdb.New()
 	}
@@ -287,12 +344,21 @@ func init() {
 	// If public IP is not specified, get it using shell command dig
 	if *consensusIP == "" {
 		ip, err = Config.Nat.IP()
-		if err != nil {
+		switch kind := nat.Kind(Config.Nat); {
+		case err == nil:
+			Config.StakingIPSource = kind
+		case kind == "none":
+			ip = net.IPv4zero // No NAT router to get my IP from...set to 0.0.0.0
+			Config.StakingIPSource = "none"
+		default:
 			ip = net.IPv4zero // Couldn't get my IP...set to 0.0.0.0
+			Config.StakingIPSource = "unknown"
 		}
 	} else {
-		ip = net.ParseIP(*consensusIP)
+		ip, _ = utils.ToIP(*consensusIP) // a nil ip is reported below
+		Config.StakingIPSource = "static"
 	}
+	Config.StakingIPTime = time.Now()
 
 	if ip == nil {
 		errs.Add(fmt.Errorf("Invalid IP Address %s", *consensusIP))
@@ -337,6 +403,12 @@ func init() {
 	}
 
 	if Config.EnableP2PTLS {
+		Config.StakingTLSMinVersion, err = network.ParseTLSVersion(*stakingTLSMinVersion)
+		if err != nil {
+			errs.Add(err)
+			return
+		}
+
 		i := 0
 		cb58 := formatting.CB58{}
 		for _, id := range strings.Split(*bootstrapIDs, ",") {
@@ -379,6 +451,43 @@ func init() {
 		}
 	}
 
+	Config.PluginHTTPConfig.CompressibleContentTypes = splitList(*pluginHTTPCompressibleTypes)
+	Config.PluginHTTPConfig.CORSAllowedOrigins = splitList(*pluginHTTPCORSOrigins)
+	Config.PluginHTTPConfig.CORSAllowedMethods = splitList(*pluginHTTPCORSMethods)
+	Config.PluginHTTPConfig.CORSAllowedHeaders = splitList(*pluginHTTPCORSHeaders)
+	Config.PluginHTTPConfig.Capture.Paths = splitList(*pluginHTTPCapturePaths)
+	trustedProxies, err := ghttp.ParseTrustedProxies(splitList(*pluginHTTPTrustedProxies))
+	if errs.Add(err); err != nil {
+		return
+	}
+	Config.PluginHTTPConfig.TrustedProxies = trustedProxies
+	responseHeaders, err := ghttp.ParseResponseHeaders(*pluginHTTPResponseHeaders)
+	if errs.Add(err); err != nil {
+		return
+	}
+	Config.PluginHTTPConfig.ResponseHeaders = responseHeaders
+	Config.PluginHTTPConfig.RouteTimeouts, err = ghttp.ParseRouteTimeouts(*pluginHTTPRouteTimeouts)
+	if errs.Add(err); err != nil {
+		return
+	}
+	Config.PluginHTTPDedicatedVMs, err = parseVMIDs(*pluginHTTPDedicatedVMs)
+	if errs.Add(err); err != nil {
+		return
+	}
+	Config.PluginHTTPIdempotentVMs, err = parseVMIDs(*pluginHTTPIdempotentVMs)
+	if errs.Add(err); err != nil {
+		return
+	}
+	Config.PluginHTTPIsolatedVMs, err = parseVMIDs(*pluginHTTPIsolatedVMs)
+	if errs.Add(err); err != nil {
+		return
+	}
+
+	Config.PluginHTTPRequestLogLevel, err = ghttp.ParseRequestLogLevel(*pluginHTTPRequestLogLevel)
+	if errs.Add(err); err != nil {
+		return
+	}
+
 	// Staking
 	Config.StakingCertFile = os.ExpandEnv(Config.StakingCertFile) // parse any env variable
 	Config.StakingKeyFile = os.ExpandEnv(Config.StakingKeyFile)
@@ -431,3 +540,27 @@ func init() {
 	// Router used for consensus
 	Config.ConsensusRouter = &router.ChainRouter{}
 }
+
+// parseVMIDs parses a comma separated list of VM IDs
+func parseVMIDs(list string) (ids.Set, error) {
+	vmIDs := ids.Set{}
+	for _, vm := range splitList(list) {
+		vmID, err := ids.FromString(vm)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse VM ID %q: %w", vm, err)
+		}
+		vmIDs.Add(vmID)
+	}
+	return vmIDs, nil
+}
+
+// splitList returns the non-empty elements of the comma separated list [list]
+func splitList(list string) []string {
+	elements := []string(nil)
+	for _, element := range strings.Split(list, ",") {
+		if element = strings.TrimSpace(element); element != "" {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
@@ -20,6 +20,28 @@ const (
 type Mapper interface {
 	MapPort(newInternalPort, newExternalPort uint16) error
 	UnmapAllPorts() error
+
+	// Mappings returns the state of each port mapping that was requested
+	Mappings() []Mapping
+}
+
+// Mapping describes the state of a port mapping
+type Mapping struct {
+	InternalPort uint16
+	ExternalPort uint16
+
+	// True if the most recent attempt to map the port succeeded
+	Mapped bool
+	// The error the most recent attempt to map the port failed with, if it
+	// failed
+	Err error
+
+	// Time the port was most recently attempted to be mapped at. Zero if it
+	// hasn't been attempted yet.
+	LastRefresh time.Time
+	// Time the router will drop the mapping at unless it's refreshed. Zero if
+	// the port isn't mapped.
+	Expiry time.Time
 }
 
 type mapper struct {
@@ -34,6 +56,9 @@ type mapper struct {
 	wg      sync.WaitGroup
 	errLock sync.Mutex
 	errs    wrappers.Errs
+
+	mappingsLock sync.Mutex
+	mappings     []*Mapping
 }
 
 // NewMapper returns a new mapper that can map ports on a router
@@ -77,12 +102,23 @@ func NewDefaultMapper(
 // MapPort maps a local port to a port on the router until UnmapAllPorts is
 // called.
 func (m *mapper) MapPort(newInternalPort, newExternalPort uint16) error {
+	mapping := &Mapping{
+		InternalPort: newInternalPort,
+		ExternalPort: newExternalPort,
+	}
+	m.mappingsLock.Lock()
+	m.mappings = append(m.mappings, mapping)
+	m.mappingsLock.Unlock()
+
 	m.wg.Add(1)
-	go m.mapPort(newInternalPort, newExternalPort)
+	go m.mapPort(mapping)
 	return nil
 }
 
-func (m *mapper) mapPort(newInternalPort, newExternalPort uint16) {
+func (m *mapper) mapPort(mapping *Mapping) {
+	newInternalPort := mapping.InternalPort
+	newExternalPort := mapping.ExternalPort
+
 	// duration is set to 0 here so that the select case will execute
 	// immediately
 	updateTimer := time.NewTimer(0)
@@ -112,6 +148,7 @@ func (m *mapper) mapPort(newInternalPort, newExternalPort uint16) {
 				newExternalPort,
 				m.mappingNames,
 				m.mappingTimeout)
+			m.refreshed(mapping, err)
 
 			if err != nil {
 				m.errLock.Lock()
@@ -141,3 +178,30 @@ func (m *mapper) UnmapAllPorts() error {
 	m.wg.Wait()
 	return m.errs.Err
 }
+
+// refreshed records that an attempt to map [mapping] finished with [err]
+func (m *mapper) refreshed(mapping *Mapping, err error) {
+	m.mappingsLock.Lock()
+	defer m.mappingsLock.Unlock()
+
+	mapping.LastRefresh = time.Now()
+	mapping.Mapped = err == nil
+	mapping.Err = err
+	if err == nil {
+		mapping.Expiry = mapping.LastRefresh.Add(m.mappingTimeout)
+	} else if !mapping.Expiry.IsZero() && !mapping.LastRefresh.Before(mapping.Expiry) {
+		// The previous lease has run out, so the port is no longer mapped
+		mapping.Expiry = time.Time{}
+	}
+}
+
+func (m *mapper) Mappings() []Mapping {
+	m.mappingsLock.Lock()
+	defer m.mappingsLock.Unlock()
+
+	mappings := make([]Mapping, len(m.mappings))
+	for i, mapping := range m.mappings {
+		mappings[i] = *mapping
+	}
+	return mappings
+}
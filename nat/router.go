@@ -63,3 +63,16 @@ func NewRouter() Router {
 	}
 	return noRouter{}
 }
+
+// Kind returns the protocol that [router] uses to communicate with the NAT
+// router, either "upnp" or "nat-pmp", or "none" if no router was discovered
+func Kind(router Router) string {
+	switch router.(type) {
+	case *upnpRouter:
+		return "upnp"
+	case *pmpClient:
+		return "nat-pmp"
+	default:
+		return "none"
+	}
+}
@@ -0,0 +1,45 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+const (
+	// Peer connects and disconnects are counted in buckets of
+	// [churnBucketDuration], for up to [churnBuckets] buckets
+	churnBucketDuration = 10 * time.Second
+	churnBuckets        = 90
+)
+
+// PeerChurn is the number of peers that connected and disconnected during a
+// window of time
+type PeerChurn struct {
+	Connects    int
+	Disconnects int
+}
+
+// churnMeters count the peers that connect and disconnect. The meters are safe
+// to use concurrently and don't require any locks to be held.
+type churnMeters struct {
+	connects, disconnects *timer.BucketedMeter
+}
+
+func newChurnMeters() churnMeters {
+	return churnMeters{
+		connects:    timer.NewBucketedMeter(churnBucketDuration, churnBuckets),
+		disconnects: timer.NewBucketedMeter(churnBucketDuration, churnBuckets),
+	}
+}
+
+// PeerChurn implements the Network interface
+func (n *network) PeerChurn(window time.Duration) PeerChurn {
+	return PeerChurn{
+		Connects:    n.churn.connects.TicksIn(window),
+		Disconnects: n.churn.disconnects.TicksIn(window),
+	}
+}
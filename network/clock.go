@@ -0,0 +1,73 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sort"
+	"time"
+)
+
+// maxClockSkewSamples is the number of recent peer clock skew measurements
+// that are used to estimate this node's clock offset.
+const maxClockSkewSamples = 128
+
+// ClockStatus describes how far this node's clock is estimated to be from the
+// clocks of its peers.
+type ClockStatus struct {
+	// Estimated amount of time this node's clock is ahead of the network's. A
+	// negative offset means this node's clock is behind.
+	OffsetEstimate time.Duration
+
+	// Number of peer measurements the estimate was derived from
+	SampleCount int
+}
+
+// clockSkews holds the most recent clock skew measurements made during peer
+// handshakes. Measurements are made before deciding whether to keep the
+// connection, so peers disconnected for having a clock that is too far out of
+// sync are still counted. This is what lets a node whose own clock is wrong
+// notice it.
+type clockSkews struct {
+	samples []time.Duration
+	next    int
+}
+
+// add records that this node's clock was [skew] ahead of a peer's
+func (c *clockSkews) add(skew time.Duration) {
+	if len(c.samples) < maxClockSkewSamples {
+		c.samples = append(c.samples, skew)
+		return
+	}
+	c.samples[c.next] = skew
+	c.next = (c.next + 1) % maxClockSkewSamples
+}
+
+// status returns the median of the recorded skews. The median is used so that
+// a few peers with badly wrong clocks can't move the estimate.
+func (c *clockSkews) status() ClockStatus {
+	status := ClockStatus{SampleCount: len(c.samples)}
+	if status.SampleCount == 0 {
+		return status
+	}
+
+	sorted := make([]time.Duration, len(c.samples))
+	copy(sorted, c.samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 1 {
+		status.OffsetEstimate = sorted[mid]
+	} else {
+		status.OffsetEstimate = sorted[mid-1] + (sorted[mid]-sorted[mid-1])/2
+	}
+	return status
+}
+
+// ClockStatus implements the Network interface
+func (n *network) ClockStatus() ClockStatus {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	return n.clockSkews.status()
+}
@@ -0,0 +1,46 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+	"time"
+)
+
+func TestClockSkewsMedian(t *testing.T) {
+	skews := clockSkews{}
+	if status := skews.status(); status.SampleCount != 0 || status.OffsetEstimate != 0 {
+		t.Fatalf("expected no samples but got %+v", status)
+	}
+
+	for _, skew := range []time.Duration{3 * time.Second, -time.Hour, 2 * time.Second, 4 * time.Second, time.Hour} {
+		skews.add(skew)
+	}
+	if status := skews.status(); status.SampleCount != 5 || status.OffsetEstimate != 3*time.Second {
+		t.Fatalf("expected an offset of 3s from 5 samples but got %+v", status)
+	}
+
+	skews.add(6 * time.Second)
+	if status := skews.status(); status.SampleCount != 6 || status.OffsetEstimate != 3500*time.Millisecond {
+		t.Fatalf("expected an offset of 3.5s from 6 samples but got %+v", status)
+	}
+}
+
+func TestClockSkewsBounded(t *testing.T) {
+	skews := clockSkews{}
+	for i := 0; i < maxClockSkewSamples; i++ {
+		skews.add(time.Hour)
+	}
+	for i := 0; i < maxClockSkewSamples/2+1; i++ {
+		skews.add(time.Second)
+	}
+
+	status := skews.status()
+	if status.SampleCount != maxClockSkewSamples {
+		t.Fatalf("expected %d samples but got %d", maxClockSkewSamples, status.SampleCount)
+	}
+	if status.OffsetEstimate != time.Second {
+		t.Fatalf("expected the oldest samples to be replaced but got an offset of %s", status.OffsetEstimate)
+	}
+}
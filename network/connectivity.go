@@ -0,0 +1,135 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+const (
+	// Whether this node is connected to each validator is sampled every
+	// [connectivitySampleSpacing], and the most recent [connectivitySamples]
+	// samples are kept, which covers the last hour
+	connectivitySampleSpacing = 10 * time.Second
+	connectivitySamples       = 360
+)
+
+// ValidatorConnectivity describes how well connected this node has been to a
+// validator
+type ValidatorConnectivity struct {
+	ID ids.ShortID
+
+	// True if this node is currently connected to the validator
+	Connected bool
+
+	// Fraction of the samples, taken over the last hour, in which this node
+	// was connected to the validator, between 0 and 1
+	ConnectedFraction float64
+
+	// Number of samples the fraction was computed from. Fewer than an hour's
+	// worth are available if the node, or the validator, is new.
+	Samples int
+}
+
+// connectivityWindow holds the most recent samples of whether this node was
+// connected to a validator
+type connectivityWindow struct {
+	samples   []bool
+	next      int
+	connected int
+}
+
+// add records whether this node was [connected] to the validator
+func (w *connectivityWindow) add(connected bool) {
+	if connected {
+		w.connected++
+	}
+	if len(w.samples) < connectivitySamples {
+		w.samples = append(w.samples, connected)
+		return
+	}
+	if w.samples[w.next] {
+		w.connected--
+	}
+	w.samples[w.next] = connected
+	w.next = (w.next + 1) % connectivitySamples
+}
+
+// fraction returns the fraction of the samples in which this node was
+// connected to the validator
+func (w *connectivityWindow) fraction() float64 {
+	if len(w.samples) == 0 {
+		return 0
+	}
+	return float64(w.connected) / float64(len(w.samples))
+}
+
+// sampleConnectivity records whether this node is connected to each
+// validator every [connectivitySampleSpacing].
+// assumes the stateLock is not held. Only returns after the network is closed.
+func (n *network) sampleConnectivity() {
+	t := time.NewTicker(connectivitySampleSpacing)
+	defer t.Stop()
+
+	for range t.C {
+		n.stateLock.Lock()
+		if n.closed {
+			n.stateLock.Unlock()
+			return
+		}
+		n.sampleConnectivityOnce()
+		n.stateLock.Unlock()
+	}
+}
+
+// sampleConnectivityOnce records whether this node is currently connected to
+// each validator. Windows of nodes that stopped validating are dropped.
+// assumes the stateLock is held
+func (n *network) sampleConnectivityOnce() {
+	windows := make(map[[20]byte]*connectivityWindow, len(n.connectivity))
+	for _, vdr := range n.vdrs.List() {
+		vdrID := vdr.ID()
+		if vdrID.Equals(n.id) {
+			continue
+		}
+		key := vdrID.Key()
+
+		window, ok := n.connectivity[key]
+		if !ok {
+			window = &connectivityWindow{}
+		}
+		peer, ok := n.peers[key]
+		window.add(ok && peer.connected)
+		windows[key] = window
+	}
+	n.connectivity = windows
+}
+
+// ValidatorConnectivity implements the Network interface
+func (n *network) ValidatorConnectivity() []ValidatorConnectivity {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	connectivity := []ValidatorConnectivity{}
+	for _, vdr := range n.vdrs.List() {
+		vdrID := vdr.ID()
+		if vdrID.Equals(n.id) {
+			continue
+		}
+		key := vdrID.Key()
+
+		vdrConnectivity := ValidatorConnectivity{ID: vdrID}
+		if peer, ok := n.peers[key]; ok {
+			vdrConnectivity.Connected = peer.connected
+		}
+		if window, ok := n.connectivity[key]; ok {
+			vdrConnectivity.ConnectedFraction = window.fraction()
+			vdrConnectivity.Samples = len(window.samples)
+		}
+		connectivity = append(connectivity, vdrConnectivity)
+	}
+	return connectivity
+}
@@ -0,0 +1,46 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"testing"
+)
+
+func TestConnectivityWindowFraction(t *testing.T) {
+	window := connectivityWindow{}
+	if fraction := window.fraction(); fraction != 0 {
+		t.Fatalf("expected no connectivity without samples but got %f", fraction)
+	}
+
+	for _, connected := range []bool{true, false, true, true} {
+		window.add(connected)
+	}
+	if fraction := window.fraction(); fraction != 0.75 {
+		t.Fatalf("expected a connected fraction of 0.75 but got %f", fraction)
+	}
+}
+
+func TestConnectivityWindowBounded(t *testing.T) {
+	window := connectivityWindow{}
+	for i := 0; i < connectivitySamples; i++ {
+		window.add(false)
+	}
+	for i := 0; i < connectivitySamples/2; i++ {
+		window.add(true)
+	}
+
+	if len(window.samples) != connectivitySamples {
+		t.Fatalf("expected %d samples but got %d", connectivitySamples, len(window.samples))
+	}
+	if fraction := window.fraction(); fraction != 0.5 {
+		t.Fatalf("expected the oldest samples to be replaced but got a connected fraction of %f", fraction)
+	}
+
+	for i := 0; i < connectivitySamples; i++ {
+		window.add(true)
+	}
+	if fraction := window.fraction(); fraction != 1 {
+		t.Fatalf("expected a connected fraction of 1 but got %f", fraction)
+	}
+}
@@ -0,0 +1,118 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"io"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// disconnectReason is why the connection to a peer was closed
+type disconnectReason int32
+
+const (
+	// no reason was recorded
+	disconnectOther disconnectReason = iota
+	// the peer closed the connection
+	disconnectClosedByPeer
+	// reading from or writing to the connection timed out
+	disconnectTimeout
+	// reading from the connection failed
+	disconnectReadError
+	// writing to the connection failed
+	disconnectWriteError
+	// the peer sent a message that was too large or couldn't be parsed
+	disconnectInvalidMessage
+	// the peer is on another network, or runs an incompatible version
+	disconnectIncompatible
+	// the peer's clock is too far out of sync with this node's
+	disconnectClockSkew
+	// the peer is this node
+	disconnectSelf
+	// this node is shutting down
+	disconnectShutdown
+
+	numDisconnectReasons
+)
+
+func (r disconnectReason) String() string {
+	switch r {
+	case disconnectClosedByPeer:
+		return "closed by peer"
+	case disconnectTimeout:
+		return "timeout"
+	case disconnectReadError:
+		return "read error"
+	case disconnectWriteError:
+		return "write error"
+	case disconnectInvalidMessage:
+		return "invalid message"
+	case disconnectIncompatible:
+		return "incompatible"
+	case disconnectClockSkew:
+		return "clock skew"
+	case disconnectSelf:
+		return "self"
+	case disconnectShutdown:
+		return "shutdown"
+	default:
+		return "other"
+	}
+}
+
+// classifyConnError returns why the connection to a peer was closed after an
+// operation on it failed with [err]. [fallback] is returned for errors that
+// aren't recognized.
+func classifyConnError(err error, fallback disconnectReason) disconnectReason {
+	if err == io.EOF {
+		return disconnectClosedByPeer
+	}
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return disconnectTimeout
+	}
+	return fallback
+}
+
+// disconnectMeters count the peers that disconnected, by reason. The meters
+// are safe to use concurrently and don't require any locks to be held.
+type disconnectMeters [numDisconnectReasons]*timer.BucketedMeter
+
+func newDisconnectMeters() disconnectMeters {
+	meters := disconnectMeters{}
+	for reason := range meters {
+		// Disconnects are counted like churn, so the two can be compared
+		meters[reason] = timer.NewBucketedMeter(churnBucketDuration, churnBuckets)
+	}
+	return meters
+}
+
+// setDisconnectReason records [reason] as why the connection to the peer is
+// closed, unless a reason was already recorded. The first failure is the
+// cause; the failures it causes on the connection's other routine aren't.
+func (p *peer) setDisconnectReason(reason disconnectReason) {
+	atomic.CompareAndSwapInt32(&p.disconnectReason, int32(disconnectOther), int32(reason))
+}
+
+// closeWithReason closes the connection to the peer, recording [reason] as why
+// unless a reason was already recorded.
+// assumes the stateLock is not held
+func (p *peer) closeWithReason(reason disconnectReason) {
+	p.setDisconnectReason(reason)
+	p.Close()
+}
+
+// DisconnectReasons implements the Network interface
+func (n *network) DisconnectReasons(window time.Duration) map[string]int {
+	reasons := make(map[string]int, numDisconnectReasons)
+	for reason, meter := range n.disconnects {
+		if count := meter.TicksIn(window); count > 0 {
+			reasons[disconnectReason(reason).String()] = count
+		}
+	}
+	return reasons
+}
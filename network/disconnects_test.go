@@ -0,0 +1,47 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func TestClassifyConnError(t *testing.T) {
+	assert.Equal(t, disconnectClosedByPeer, classifyConnError(io.EOF, disconnectReadError))
+	assert.Equal(t, disconnectTimeout, classifyConnError(timeoutError{}, disconnectReadError))
+	assert.Equal(t, disconnectReadError, classifyConnError(errors.New("connection reset"), disconnectReadError))
+	assert.Equal(t, disconnectWriteError, classifyConnError(errors.New("broken pipe"), disconnectWriteError))
+}
+
+func TestDisconnectReasons(t *testing.T) {
+	n := &network{disconnects: newDisconnectMeters()}
+	assert.Empty(t, n.DisconnectReasons(time.Minute))
+
+	// The first reason recorded is the one that's counted
+	p := &peer{net: n}
+	p.setDisconnectReason(disconnectClockSkew)
+	p.setDisconnectReason(disconnectReadError)
+	n.disconnects[p.disconnectReason].Tick()
+
+	// Peers without a recorded reason are counted too
+	n.disconnects[(&peer{net: n}).disconnectReason].Tick()
+
+	expected := map[string]int{
+		"clock skew": 1,
+		"other":      1,
+	}
+	assert.Equal(t, expected, n.DisconnectReasons(time.Minute))
+	assert.Equal(t, expected, n.DisconnectReasons(15*time.Minute))
+}
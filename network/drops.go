@@ -0,0 +1,81 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"sync/atomic"
+)
+
+// dropReason is why a message was dropped
+type dropReason int
+
+const (
+	// the peer's send queue was full
+	dropQueueFull dropReason = iota
+	// the peer disconnected, or was never connected
+	dropPeerGone
+	// the message couldn't be parsed, or had an unknown op
+	dropInvalid
+	// too many bytes were pending to be sent
+	dropThrottled
+	// the message was received before the version handshake completed
+	dropNotConnected
+
+	numDropReasons
+)
+
+func (r dropReason) String() string {
+	switch r {
+	case dropQueueFull:
+		return "queue full"
+	case dropPeerGone:
+		return "peer gone"
+	case dropInvalid:
+		return "invalid"
+	case dropThrottled:
+		return "throttled"
+	case dropNotConnected:
+		return "not connected"
+	default:
+		return "unknown"
+	}
+}
+
+// DroppedMessages is the number of messages the network dropped since it
+// started, by reason
+type DroppedMessages struct {
+	Inbound  map[string]uint64
+	Outbound map[string]uint64
+}
+
+// dropCounters counts dropped messages. Counting is safe to do concurrently
+// and doesn't require any locks to be held.
+type dropCounters struct {
+	inbound, outbound [numDropReasons]uint64
+}
+
+func (d *dropCounters) droppedInbound(reason dropReason) {
+	atomic.AddUint64(&d.inbound[reason], 1)
+}
+
+func (d *dropCounters) droppedOutbound(reason dropReason) {
+	atomic.AddUint64(&d.outbound[reason], 1)
+}
+
+// DroppedMessages implements the Network interface
+func (n *network) DroppedMessages() DroppedMessages {
+	dropped := DroppedMessages{
+		Inbound:  make(map[string]uint64, numDropReasons),
+		Outbound: make(map[string]uint64, numDropReasons),
+	}
+	for reason := dropReason(0); reason < numDropReasons; reason++ {
+		if count := atomic.LoadUint64(&n.drops.inbound[reason]); count > 0 {
+			dropped.Inbound[reason.String()] = count
+		}
+		if count := atomic.LoadUint64(&n.drops.outbound[reason]); count > 0 {
+			dropped.Outbound[reason.String()] = count
+		}
+	}
+	return dropped
+}
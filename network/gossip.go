@@ -0,0 +1,190 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Bounds on the gossip parameters that may be set while the network is
+// running. They keep an operator from setting a fanout that would flood the
+// network, or an interval that would spam or starve it.
+const (
+	minPeerListGossipSpacing = time.Second
+	maxPeerListGossipSpacing = time.Hour
+	maxGossipSize            = 256
+)
+
+var (
+	errPeerListGossipSpacingOutOfRange = errors.New("peer list gossip interval must be between 1s and 1h")
+	errPeerListGossipSizeOutOfRange    = errors.New("peer list gossip size must be between 1 and 256")
+	errGossipSizeOutOfRange            = errors.New("accepted container gossip size must be between 1 and 256")
+)
+
+// GossipConfig describes how aggressively the network gossips.
+type GossipConfig struct {
+	// Time between two rounds of peer list gossip
+	PeerListGossipSpacing time.Duration
+
+	// Number of peers a peer list is gossiped to in each round
+	PeerListGossipSize int
+
+	// Number of peers an accepted container is gossiped to
+	GossipSize int
+}
+
+// GossipConfig implements the Network interface
+func (n *network) GossipConfig() GossipConfig {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	return GossipConfig{
+		PeerListGossipSpacing: n.peerListGossipSpacing,
+		PeerListGossipSize:    n.peerListGossipSize,
+		GossipSize:            n.gossipSize,
+	}
+}
+
+// SetGossipConfig implements the Network interface
+func (n *network) SetGossipConfig(config GossipConfig) error {
+	switch {
+	case config.PeerListGossipSpacing < minPeerListGossipSpacing || config.PeerListGossipSpacing > maxPeerListGossipSpacing:
+		return errPeerListGossipSpacingOutOfRange
+	case config.PeerListGossipSize < 1 || config.PeerListGossipSize > maxGossipSize:
+		return errPeerListGossipSizeOutOfRange
+	case config.GossipSize < 1 || config.GossipSize > maxGossipSize:
+		return errGossipSizeOutOfRange
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	spacingChanged := n.peerListGossipSpacing != config.PeerListGossipSpacing
+	n.peerListGossipSpacing = config.PeerListGossipSpacing
+	n.peerListGossipSize = config.PeerListGossipSize
+	n.gossipSize = config.GossipSize
+
+	if spacingChanged {
+		// Wake up the gossip loop so it doesn't keep waiting out the old
+		// interval
+		select {
+		case n.peerListGossipSpacingChanged <- struct{}{}:
+		default:
+		}
+	}
+	return nil
+}
+
+// PeerRefresh is the most recent request for the peer lists of every
+// connected peer
+type PeerRefresh struct {
+	// Time the peer lists were requested at. Zero if they never were.
+	Time time.Time
+
+	// Number of peers whose peer lists were requested
+	Requested int
+
+	// Number of IPs that were learned of since the peer lists were requested,
+	// and weren't already known
+	NewPeers int
+}
+
+// peerRefresh is a request for the peer lists of every connected peer
+type peerRefresh struct {
+	time      time.Time
+	requested int
+	// the number of IPs that had been learned of when the request was made
+	numLearnedIPs int
+}
+
+// RefreshPeers implements the Network interface
+func (n *network) RefreshPeers() (int, error) {
+	msg, err := n.b.GetPeerList()
+	if err != nil {
+		return 0, err
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	numRequested := 0
+	for _, peer := range n.peers {
+		if peer.connected && peer.send(msg) {
+			numRequested++
+		}
+	}
+	n.peerRefresh = peerRefresh{
+		time:          n.clock.Time(),
+		requested:     numRequested,
+		numLearnedIPs: n.numLearnedIPs,
+	}
+
+	n.log.Debug("requested peer lists from %d peers", numRequested)
+	return numRequested, nil
+}
+
+// LastPeerRefresh implements the Network interface
+func (n *network) LastPeerRefresh() PeerRefresh {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	refresh := PeerRefresh{
+		Time:      n.peerRefresh.time,
+		Requested: n.peerRefresh.requested,
+	}
+	if !refresh.Time.IsZero() {
+		refresh.NewPeers = n.numLearnedIPs - n.peerRefresh.numLearnedIPs
+	}
+	return refresh
+}
+
+// GossipTarget is a peer that was gossiped to
+type GossipTarget struct {
+	ID ids.ShortID
+
+	// true if the peer was a validator when it was gossiped to
+	Validator bool
+}
+
+// GossipSample is the peers that were gossiped to in a round of gossip
+type GossipSample struct {
+	// Time of the round. Zero if there hasn't been a round yet.
+	Time time.Time
+
+	Peers []GossipTarget
+}
+
+// GossipSamples is the peers that were gossiped to in the most recent rounds
+// of peer list and accepted container gossip
+type GossipSamples struct {
+	PeerList GossipSample
+	Accepted GossipSample
+}
+
+// GossipSamples implements the Network interface
+func (n *network) GossipSamples() GossipSamples {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	return n.gossipSamples
+}
+
+// newGossipSample returns the sample of a round of gossip to [peers]. Assumes
+// the stateLock is held.
+func (n *network) newGossipSample(peers []*peer) GossipSample {
+	sample := GossipSample{
+		Time:  n.clock.Time(),
+		Peers: make([]GossipTarget, len(peers)),
+	}
+	for i, peer := range peers {
+		sample.Peers[i] = GossipTarget{
+			ID:        peer.id,
+			Validator: n.vdrs.Contains(peer.id),
+		}
+	}
+	return sample
+}
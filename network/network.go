@@ -4,6 +4,7 @@
 package network
 
 import (
+	"crypto/tls"
 	"fmt"
 	"math"
 	"math/rand"
@@ -78,6 +79,83 @@ type Network interface {
 	// to externally. Thread safety must be managed internally to the network.
 	Peers() []PeerID
 
+	// Returns the current limits and usage of the outbound message throttler.
+	// Thread safety must be managed internally to the network.
+	ThrottlerState() ThrottlerState
+
+	// Replaces the limits of the outbound message throttler. The new limits are
+	// used by every send decision made after this call returns. Thread safety
+	// must be managed internally to the network.
+	SetThrottlerLimits(maxPendingSendBytes, pendingSendBytesToRateLimit int) error
+
+	// Restores the outbound message throttler limits that the network was
+	// created with. Thread safety must be managed internally to the network.
+	ResetThrottlerLimits()
+
+	// Returns the estimated offset of this node's clock from its peers'
+	// clocks. Thread safety must be managed internally to the network.
+	ClockStatus() ClockStatus
+
+	// Returns the number of messages dropped since the network started, by
+	// direction and reason. Thread safety must be managed internally to the
+	// network.
+	DroppedMessages() DroppedMessages
+
+	// Returns the number of connections that couldn't be upgraded since the
+	// network started, by reason. Thread safety must be managed internally to
+	// the network.
+	HandshakeFailures() map[string]uint64
+
+	// Returns the number of peers that connected and disconnected in the most
+	// recent [window], which is rounded up to a multiple of 10 seconds and
+	// capped at 15 minutes. Thread safety must be managed internally to the
+	// network.
+	PeerChurn(window time.Duration) PeerChurn
+
+	// Returns the state of the TLS connection to the peer with the given ID.
+	// Returns an error if the peer isn't connected, or the connection isn't
+	// using TLS. Thread safety must be managed internally to the network.
+	PeerTLS(ids.ShortID) (tls.ConnectionState, error)
+
+	// Returns the factors that decide how messages to the peer with the given
+	// ID are treated. Returns an error if the peer isn't connected. Thread
+	// safety must be managed internally to the network.
+	PeerPriority(ids.ShortID) (PeerPriority, error)
+
+	// Requests the peer lists of every connected peer, and returns the number
+	// of peers that were asked. Doesn't wait for the replies. Thread safety
+	// must be managed internally to the network.
+	RefreshPeers() (int, error)
+
+	// Returns the most recent request for peer lists made by RefreshPeers,
+	// and how many IPs were learned of since. Thread safety must be managed
+	// internally to the network.
+	LastPeerRefresh() PeerRefresh
+
+	// Returns the peers that were gossiped to in the most recent rounds of
+	// gossip. Thread safety must be managed internally to the network.
+	GossipSamples() GossipSamples
+
+	// Returns, for each validator, whether this node is connected to it and
+	// the fraction of the last hour it was connected to it. Thread safety must
+	// be managed internally to the network.
+	ValidatorConnectivity() []ValidatorConnectivity
+
+	// Returns the number of peers that disconnected in the most recent
+	// [window], by why they disconnected. Peers that disconnected during the
+	// version handshake are included. Thread safety must be managed
+	// internally to the network.
+	DisconnectReasons(window time.Duration) map[string]int
+
+	// Returns the parameters that currently control gossiping. Thread safety
+	// must be managed internally to the network.
+	GossipConfig() GossipConfig
+
+	// Replaces the parameters that control gossiping. The new parameters are
+	// used by every gossip round started after this call returns. Thread
+	// safety must be managed internally to the network.
+	SetGossipConfig(config GossipConfig) error
+
 	// Close this network and all existing connections it has. Thread safety
 	// must be managed internally to the network. Calling close multiple times
 	// will return a nil error.
@@ -88,6 +166,15 @@ type network struct {
 	// The metrics that this network tracks
 	metrics
 
+	// The number of messages dropped, by reason
+	drops dropCounters
+	// The number of connections that couldn't be upgraded, by reason
+	handshakeFailures [numHandshakeFailureReasons]uint64
+	// The number of peers that connected and disconnected recently
+	churn churnMeters
+	// The number of peers that disconnected recently, by reason
+	disconnects disconnectMeters
+
 	log            logging.Logger
 	id             ids.ShortID
 	ip             utils.IPDesc
@@ -120,8 +207,15 @@ type network struct {
 	allowPrivateIPs                    bool
 	gossipSize                         int
 
+	// the throttler limits the network was created with
+	initialMaxNetworkPendingSendBytes         int
+	initialNetworkPendingSendBytesToRateLimit int
+
 	executor timer.Executor
 
+	// signalled when [peerListGossipSpacing] is changed
+	peerListGossipSpacingChanged chan struct{}
+
 	b Builder
 
 	stateLock       sync.Mutex
@@ -131,9 +225,18 @@ type network struct {
 	connectedIPs    map[string]struct{}
 	retryDelay      map[string]time.Duration
 	// TODO: bound the size of [myIPs] to avoid DoS. LRU caching would be ideal
-	myIPs    map[string]struct{} // set of IPs that resulted in my ID.
-	peers    map[[20]byte]*peer
-	handlers []Handler
+	myIPs      map[string]struct{} // set of IPs that resulted in my ID.
+	peers      map[[20]byte]*peer
+	handlers   []Handler
+	clockSkews clockSkews
+	// number of IPs that have been learned of, and weren't already known
+	numLearnedIPs int
+	// the most recent request for the peer lists of every connected peer
+	peerRefresh peerRefresh
+	// the peers that were gossiped to most recently
+	gossipSamples GossipSamples
+	// recent samples of whether this node was connected to each validator
+	connectivity map[[20]byte]*connectivityWindow
 }
 
 // NewDefaultNetwork returns a new Network implementation with the provided
@@ -240,11 +343,20 @@ func NewNetwork(
 		allowPrivateIPs:                    allowPrivateIPs,
 		gossipSize:                         gossipSize,
 
+		initialMaxNetworkPendingSendBytes:         maxNetworkPendingSendBytes,
+		initialNetworkPendingSendBytesToRateLimit: networkPendingSendBytesToRateLimit,
+
+		peerListGossipSpacingChanged: make(chan struct{}, 1),
+
 		disconnectedIPs: make(map[string]struct{}),
 		connectedIPs:    make(map[string]struct{}),
 		retryDelay:      make(map[string]time.Duration),
 		myIPs:           map[string]struct{}{ip.String(): {}},
 		peers:           make(map[[20]byte]*peer),
+		connectivity:    make(map[[20]byte]*connectivityWindow),
+
+		churn:       newChurnMeters(),
+		disconnects: newDisconnectMeters(),
 	}
 	net.initialize(registerer)
 	net.executor.Initialize()
@@ -262,10 +374,7 @@ func (n *network) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID,
 
 	for _, validatorID := range validatorIDs.List() {
 		vID := validatorID
-		peer, sent := n.peers[vID.Key()]
-		if sent {
-			sent = peer.send(msg)
-		}
+		sent := n.sendTo(vID, msg)
 		if !sent {
 			n.executor.Add(func() { n.router.GetAcceptedFrontierFailed(vID, chainID, requestID) })
 			n.getAcceptedFrontier.numFailed.Inc()
@@ -275,6 +384,17 @@ func (n *network) GetAcceptedFrontier(validatorIDs ids.ShortSet, chainID ids.ID,
 	}
 }
 
+// sendTo sends [msg] to the peer [validatorID]. Returns false if the message
+// was dropped. Assumes the stateLock is held.
+func (n *network) sendTo(validatorID ids.ShortID, msg Msg) bool {
+	peer, ok := n.peers[validatorID.Key()]
+	if !ok {
+		n.drops.droppedOutbound(dropPeerGone)
+		return false
+	}
+	return peer.send(msg)
+}
+
 // AcceptedFrontier implements the Sender interface.
 func (n *network) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requestID uint32, containerIDs ids.Set) {
 	msg, err := n.b.AcceptedFrontier(chainID, requestID, containerIDs)
@@ -290,10 +410,7 @@ func (n *network) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID, requ
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
-	peer, sent := n.peers[validatorID.Key()]
-	if sent {
-		sent = peer.send(msg)
-	}
+	sent := n.sendTo(validatorID, msg)
 	if !sent {
 		n.log.Debug("failed to send AcceptedFrontier(%s, %s, %d, %s)",
 			validatorID,
@@ -327,10 +444,7 @@ func (n *network) GetAccepted(validatorIDs ids.ShortSet, chainID ids.ID, request
 
 	for _, validatorID := range validatorIDs.List() {
 		vID := validatorID
-		peer, sent := n.peers[vID.Key()]
-		if sent {
-			sent = peer.send(msg)
-		}
+		sent := n.sendTo(vID, msg)
 		if !sent {
 			n.log.Debug("failed to send GetAccepted(%s, %s, %d, %s)",
 				validatorID,
@@ -360,10 +474,7 @@ func (n *network) Accepted(validatorID ids.ShortID, chainID ids.ID, requestID ui
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
-	peer, sent := n.peers[validatorID.Key()]
-	if sent {
-		sent = peer.send(msg)
-	}
+	sent := n.sendTo(validatorID, msg)
 	if !sent {
 		n.log.Debug("failed to send Accepted(%s, %s, %d, %s)",
 			validatorID,
@@ -387,10 +498,7 @@ func (n *network) GetAncestors(validatorID ids.ShortID, chainID ids.ID, requestI
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
-	peer, sent := n.peers[validatorID.Key()]
-	if sent {
-		sent = peer.send(msg)
-	}
+	sent := n.sendTo(validatorID, msg)
 	if !sent {
 		n.log.Debug("failed to send GetAncestors(%s, %s, %d, %s)",
 			validatorID,
@@ -415,10 +523,7 @@ func (n *network) MultiPut(validatorID ids.ShortID, chainID ids.ID, requestID ui
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
-	peer, sent := n.peers[validatorID.Key()]
-	if sent {
-		sent = peer.send(msg)
-	}
+	sent := n.sendTo(validatorID, msg)
 	if !sent {
 		n.log.Debug("failed to send MultiPut(%s, %s, %d, %d)",
 			validatorID,
@@ -439,10 +544,7 @@ func (n *network) Get(validatorID ids.ShortID, chainID ids.ID, requestID uint32,
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
-	peer, sent := n.peers[validatorID.Key()]
-	if sent {
-		sent = peer.send(msg)
-	}
+	sent := n.sendTo(validatorID, msg)
 	if !sent {
 		n.log.Debug("failed to send Get(%s, %s, %d, %s)",
 			validatorID,
@@ -472,10 +574,7 @@ func (n *network) Put(validatorID ids.ShortID, chainID ids.ID, requestID uint32,
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
-	peer, sent := n.peers[validatorID.Key()]
-	if sent {
-		sent = peer.send(msg)
-	}
+	sent := n.sendTo(validatorID, msg)
 	if !sent {
 		n.log.Debug("failed to send Put(%s, %s, %d, %s)",
 			validatorID,
@@ -512,10 +611,7 @@ func (n *network) PushQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 
 	for _, validatorID := range validatorIDs.List() {
 		vID := validatorID
-		peer, sent := n.peers[vID.Key()]
-		if sent {
-			sent = peer.send(msg)
-		}
+		sent := n.sendTo(vID, msg)
 		if !sent {
 			n.log.Debug("failed to send PushQuery(%s, %s, %d, %s)",
 				validatorID,
@@ -541,10 +637,7 @@ func (n *network) PullQuery(validatorIDs ids.ShortSet, chainID ids.ID, requestID
 
 	for _, validatorID := range validatorIDs.List() {
 		vID := validatorID
-		peer, sent := n.peers[vID.Key()]
-		if sent {
-			sent = peer.send(msg)
-		}
+		sent := n.sendTo(vID, msg)
 		if !sent {
 			n.log.Debug("failed to send PullQuery(%s, %s, %d, %s)",
 				validatorID,
@@ -574,10 +667,7 @@ func (n *network) Chits(validatorID ids.ShortID, chainID ids.ID, requestID uint3
 	n.stateLock.Lock()
 	defer n.stateLock.Unlock()
 
-	peer, sent := n.peers[validatorID.Key()]
-	if sent {
-		sent = peer.send(msg)
-	}
+	sent := n.sendTo(validatorID, msg)
 	if !sent {
 		n.log.Debug("failed to send Chits(%s, %s, %d, %s)",
 			validatorID,
@@ -613,6 +703,7 @@ func (n *network) GetHeartbeat() int64 { return atomic.LoadInt64(&n.lastHeartbea
 // to this node.
 func (n *network) Dispatch() error {
 	go n.gossip()
+	go n.sampleConnectivity()
 	for {
 		conn, err := n.listener.Accept()
 		if err != nil {
@@ -627,8 +718,9 @@ func (n *network) Dispatch() error {
 			continue
 		}
 		go n.upgrade(&peer{
-			net:  n,
-			conn: conn,
+			net:     n,
+			conn:    conn,
+			inbound: true,
 		}, n.serverUpgrader)
 	}
 }
@@ -660,12 +752,14 @@ func (n *network) Peers() []PeerID {
 	for _, peer := range n.peers {
 		if peer.connected {
 			peers = append(peers, PeerID{
-				IP:           peer.conn.RemoteAddr().String(),
+				IP:           addrString(peer.conn.RemoteAddr()),
 				PublicIP:     peer.ip.String(),
 				ID:           peer.id,
 				Version:      peer.versionStr,
 				LastSent:     time.Unix(atomic.LoadInt64(&peer.lastSent), 0),
 				LastReceived: time.Unix(atomic.LoadInt64(&peer.lastReceived), 0),
+				IPMismatch:   ipMismatch(peer.conn.RemoteAddr(), peer.claimedIP),
+				Inbound:      peer.inbound,
 			})
 		}
 	}
@@ -690,7 +784,7 @@ func (n *network) Close() error {
 	n.stateLock.Unlock()
 
 	for _, peer := range peersToClose {
-		peer.Close() // Grabs the stateLock
+		peer.closeWithReason(disconnectShutdown) // Grabs the stateLock
 	}
 	return err
 }
@@ -723,14 +817,18 @@ func (n *network) gossipContainer(chainID, containerID ids.ID, container []byte)
 		numToGossip = len(allPeers)
 	}
 
+	sample := make([]*peer, 0, numToGossip)
 	sampler := random.Uniform{N: len(allPeers)}
 	for i := 0; i < numToGossip; i++ {
-		if allPeers[sampler.Sample()].send(msg) {
+		peer := allPeers[sampler.Sample()]
+		sample = append(sample, peer)
+		if peer.send(msg) {
 			n.put.numSent.Inc()
 		} else {
 			n.put.numFailed.Inc()
 		}
 	}
+	n.gossipSamples.Accepted = n.newGossipSample(sample)
 	return nil
 }
 
@@ -751,16 +849,29 @@ func (n *network) track(ip utils.IPDesc) {
 		return
 	}
 	n.disconnectedIPs[str] = struct{}{}
+	n.numLearnedIPs++
 
 	go n.connectTo(ip)
 }
 
 // assumes the stateLock is not held. Only returns after the network is closed.
 func (n *network) gossip() {
+	n.stateLock.Lock()
 	t := time.NewTicker(n.peerListGossipSpacing)
-	defer t.Stop()
+	n.stateLock.Unlock()
+	defer func() { t.Stop() }()
+
+	for {
+		select {
+		case <-t.C:
+		case <-n.peerListGossipSpacingChanged:
+			n.stateLock.Lock()
+			t.Stop()
+			t = time.NewTicker(n.peerListGossipSpacing)
+			n.stateLock.Unlock()
+			continue
+		}
 
-	for range t.C {
 		ips := n.validatorIPs()
 		if len(ips) == 0 {
 			n.log.Debug("skipping validator gossiping as no public validators are connected")
@@ -799,15 +910,21 @@ func (n *network) gossip() {
 			numNonStakersToSend = len(nonStakers)
 		}
 
+		sample := make([]*peer, 0, numStakersToSend+numNonStakersToSend)
 		sampler := random.Uniform{N: len(stakers)}
 		for i := 0; i < numStakersToSend; i++ {
-			stakers[sampler.Sample()].send(msg)
+			peer := stakers[sampler.Sample()]
+			sample = append(sample, peer)
+			peer.send(msg)
 		}
 		sampler.N = len(nonStakers)
 		sampler.Replace()
 		for i := 0; i < numNonStakersToSend; i++ {
-			nonStakers[sampler.Sample()].send(msg)
+			peer := nonStakers[sampler.Sample()]
+			sample = append(sample, peer)
+			peer.send(msg)
 		}
+		n.gossipSamples.PeerList = n.newGossipSample(sample)
 		n.stateLock.Unlock()
 	}
 }
@@ -886,6 +1003,7 @@ func (n *network) upgrade(p *peer, upgrader Upgrader) error {
 	id, conn, err := upgrader.Upgrade(p.conn)
 	if err != nil {
 		n.log.Verbo("failed to upgrade connection with %s", err)
+		atomic.AddUint64(&n.handshakeFailures[classifyHandshakeFailure(err)], 1)
 		return err
 	}
 	p.sender = make(chan []byte, n.sendQueueSize)
@@ -959,6 +1077,7 @@ func (n *network) validatorIPs() []utils.IPDesc {
 // called after disconnected is called with this peer.
 func (n *network) connected(p *peer) {
 	n.log.Debug("connected to %s at %s", p.id, p.ip)
+	n.churn.connects.Tick()
 	if !p.ip.IsZero() {
 		str := p.ip.String()
 
@@ -985,6 +1104,7 @@ func (n *network) disconnected(p *peer) {
 	key := p.id.Key()
 	delete(n.peers, key)
 	n.numPeers.Set(float64(len(n.peers)))
+	n.disconnects[atomic.LoadInt32(&p.disconnectReason)].Tick()
 
 	if !p.ip.IsZero() {
 		str := p.ip.String()
@@ -996,6 +1116,7 @@ func (n *network) disconnected(p *peer) {
 	}
 
 	if p.connected {
+		n.churn.disconnects.Tick()
 		for i := 0; i < len(n.handlers); {
 			if n.handlers[i].Disconnected(p.id) {
 				newLen := len(n.handlers) - 1
@@ -1037,3 +1037,303 @@ func TestTrackConnectedRace(t *testing.T) {
 	err = net1.Close()
 	assert.NoError(t, err)
 }
+
+// newTestNetwork returns a network that isn't connected to any peers. It must
+// be closed with closeTestNetwork.
+func newTestNetwork(t *testing.T) Network {
+	log := logging.NoLog{}
+	ip := utils.IPDesc{
+		IP:   net.IPv6loopback,
+		Port: 0,
+	}
+	id := ids.NewShortID(hashing.ComputeHash160Array([]byte(ip.String())))
+	networkID := uint32(0)
+	appVersion := version.NewDefaultVersion("app", 0, 1, 0)
+	versionParser := version.NewDefaultParser()
+
+	listener := &testListener{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		inbound: make(chan net.Conn, 1<<10),
+		closed:  make(chan struct{}),
+	}
+	caller := &testDialer{
+		addr: &net.TCPAddr{
+			IP:   net.IPv6loopback,
+			Port: 0,
+		},
+		outbounds: make(map[string]*testListener),
+	}
+	serverUpgrader := NewIPUpgrader()
+	clientUpgrader := NewIPUpgrader()
+
+	vdrs := validators.NewSet()
+	handler := router.Router(nil)
+
+	net := NewDefaultNetwork(
+		prometheus.NewRegistry(),
+		log,
+		id,
+		ip,
+		networkID,
+		appVersion,
+		versionParser,
+		listener,
+		caller,
+		serverUpgrader,
+		clientUpgrader,
+		vdrs,
+		handler,
+	)
+	assert.NotNil(t, net)
+	return net
+}
+
+// closeTestNetwork closes [net] and waits for it to stop dispatching
+func closeTestNetwork(t *testing.T, net Network) {
+	go func() {
+		err := net.Close()
+		assert.NoError(t, err)
+	}()
+
+	err := net.Dispatch()
+	assert.Error(t, err)
+}
+
+func TestThrottlerLimits(t *testing.T) {
+	net := newTestNetwork(t)
+
+	state := net.ThrottlerState()
+	assert.Equal(t, defaultMaxNetworkPendingSendBytes, state.MaxPendingSendBytes)
+	assert.Equal(t, defaultNetworkPendingSendBytesToRateLimit, state.PendingSendBytesToRateLimit)
+	assert.Equal(t, defaultMaxNetworkPendingSendBytes/peerPendingSendBytesFraction, state.MaxPeerPendingSendBytes)
+	assert.Zero(t, state.PendingSendBytes)
+	assert.False(t, state.RateLimiting)
+	assert.Empty(t, state.Peers)
+
+	assert.Error(t, net.SetThrottlerLimits(0, 1))
+	assert.Error(t, net.SetThrottlerLimits(1, 2))
+
+	err := net.SetThrottlerLimits(2*defaultMaxNetworkPendingSendBytes, defaultMaxNetworkPendingSendBytes)
+	assert.NoError(t, err)
+
+	state = net.ThrottlerState()
+	assert.Equal(t, 2*defaultMaxNetworkPendingSendBytes, state.MaxPendingSendBytes)
+	assert.Equal(t, defaultMaxNetworkPendingSendBytes, state.PendingSendBytesToRateLimit)
+	assert.Equal(t, 2*defaultMaxNetworkPendingSendBytes/peerPendingSendBytesFraction, state.MaxPeerPendingSendBytes)
+
+	net.ResetThrottlerLimits()
+
+	state = net.ThrottlerState()
+	assert.Equal(t, defaultMaxNetworkPendingSendBytes, state.MaxPendingSendBytes)
+	assert.Equal(t, defaultNetworkPendingSendBytesToRateLimit, state.PendingSendBytesToRateLimit)
+
+	closeTestNetwork(t, net)
+}
+
+func TestClosedPeerReleasesPendingBytes(t *testing.T) {
+	net := newTestNetwork(t)
+
+	n := net.(*network)
+	p := &peer{
+		net:       n,
+		id:        ids.NewShortID([20]byte{1}),
+		conn:      &testConn{closed: make(chan struct{})},
+		sender:    make(chan []byte, 1),
+		connected: true,
+	}
+	msg, err := n.b.GetPeerList()
+	assert.NoError(t, err)
+
+	n.stateLock.Lock()
+	n.peers[p.id.Key()] = p
+	n.connected(p)
+	assert.True(t, p.send(msg))
+	n.stateLock.Unlock()
+
+	assert.Equal(t, len(msg.Bytes()), net.ThrottlerState().PendingSendBytes)
+
+	// The message is never written, as the peer closes first
+	p.Close()
+	assert.Zero(t, net.ThrottlerState().PendingSendBytes)
+
+	closeTestNetwork(t, net)
+}
+
+func TestPeerPriority(t *testing.T) {
+	net := newTestNetwork(t)
+
+	n := net.(*network)
+	validatorID := ids.NewShortID([20]byte{1})
+	nonValidatorID := ids.NewShortID([20]byte{2})
+	n.vdrs.Add(validators.NewValidator(validatorID, 3))
+	n.vdrs.Add(validators.NewValidator(ids.NewShortID([20]byte{3}), 1))
+
+	_, err := net.PeerPriority(validatorID)
+	assert.Error(t, err, "a peer that isn't connected has no priority")
+
+	msg, err := n.b.GetPeerList()
+	assert.NoError(t, err)
+
+	n.stateLock.Lock()
+	for _, id := range []ids.ShortID{validatorID, nonValidatorID} {
+		p := &peer{
+			net:       n,
+			id:        id,
+			conn:      &testConn{closed: make(chan struct{})},
+			sender:    make(chan []byte, 2),
+			connected: true,
+		}
+		n.peers[p.id.Key()] = p
+		n.connected(p)
+	}
+	assert.True(t, n.peers[validatorID.Key()].send(msg))
+	n.stateLock.Unlock()
+
+	priority, err := net.PeerPriority(validatorID)
+	assert.NoError(t, err)
+	assert.True(t, priority.Validator)
+	assert.Equal(t, uint64(3), priority.Weight)
+	assert.Equal(t, .75, priority.StakeFraction)
+	assert.Equal(t, len(msg.Bytes()), priority.PendingSendBytes)
+	assert.Equal(t, 1, priority.PendingSendMessages)
+	assert.Equal(t, 2, priority.SendQueueCapacity)
+	assert.False(t, priority.Throttled)
+
+	priority, err = net.PeerPriority(nonValidatorID)
+	assert.NoError(t, err)
+	assert.False(t, priority.Validator)
+	assert.Zero(t, priority.Weight)
+	assert.Zero(t, priority.StakeFraction)
+	assert.Zero(t, priority.PendingSendBytes)
+	assert.Zero(t, priority.PendingSendMessages)
+
+	closeTestNetwork(t, net)
+}
+
+func TestDroppedMessages(t *testing.T) {
+	net := newTestNetwork(t)
+
+	dropped := net.DroppedMessages()
+	assert.Empty(t, dropped.Inbound)
+	assert.Empty(t, dropped.Outbound)
+
+	unknownPeer := ids.NewShortID([20]byte{1})
+	net.AcceptedFrontier(unknownPeer, ids.Empty, 0, ids.Set{})
+	net.AcceptedFrontier(unknownPeer, ids.Empty, 1, ids.Set{})
+
+	dropped = net.DroppedMessages()
+	assert.Empty(t, dropped.Inbound)
+	assert.Equal(t, map[string]uint64{"peer gone": 2}, dropped.Outbound)
+
+	closeTestNetwork(t, net)
+}
+
+func TestGossipSamples(t *testing.T) {
+	net := newTestNetwork(t)
+
+	samples := net.GossipSamples()
+	assert.True(t, samples.PeerList.Time.IsZero())
+	assert.True(t, samples.Accepted.Time.IsZero())
+
+	err := net.(*network).gossipContainer(ids.Empty, ids.Empty, []byte{1})
+	assert.NoError(t, err)
+
+	samples = net.GossipSamples()
+	assert.True(t, samples.PeerList.Time.IsZero())
+	assert.False(t, samples.Accepted.Time.IsZero())
+	assert.Empty(t, samples.Accepted.Peers)
+
+	closeTestNetwork(t, net)
+}
+
+func TestPeerChurn(t *testing.T) {
+	net := newTestNetwork(t)
+
+	assert.Equal(t, PeerChurn{}, net.PeerChurn(time.Minute))
+
+	n := net.(*network)
+	p := &peer{
+		net:       n,
+		id:        ids.NewShortID([20]byte{1}),
+		connected: true,
+	}
+	n.stateLock.Lock()
+	n.peers[p.id.Key()] = p
+	n.connected(p)
+	n.disconnected(p)
+	n.stateLock.Unlock()
+
+	assert.Equal(t, PeerChurn{Connects: 1, Disconnects: 1}, net.PeerChurn(time.Minute))
+	assert.Equal(t, PeerChurn{Connects: 1, Disconnects: 1}, net.PeerChurn(15*time.Minute))
+
+	closeTestNetwork(t, net)
+}
+
+func TestGossipConfig(t *testing.T) {
+	net := newTestNetwork(t)
+
+	config := net.GossipConfig()
+	assert.Equal(t, defaultPeerListGossipSpacing, config.PeerListGossipSpacing)
+	assert.Equal(t, defaultPeerListGossipSize, config.PeerListGossipSize)
+	assert.Equal(t, defaultGossipSize, config.GossipSize)
+
+	assert.Error(t, net.SetGossipConfig(GossipConfig{
+		PeerListGossipSpacing: time.Millisecond,
+		PeerListGossipSize:    defaultPeerListGossipSize,
+		GossipSize:            defaultGossipSize,
+	}))
+	assert.Error(t, net.SetGossipConfig(GossipConfig{
+		PeerListGossipSpacing: defaultPeerListGossipSpacing,
+		PeerListGossipSize:    maxGossipSize + 1,
+		GossipSize:            defaultGossipSize,
+	}))
+	assert.Error(t, net.SetGossipConfig(GossipConfig{
+		PeerListGossipSpacing: defaultPeerListGossipSpacing,
+		PeerListGossipSize:    defaultPeerListGossipSize,
+		GossipSize:            0,
+	}))
+	assert.Equal(t, config, net.GossipConfig())
+
+	newConfig := GossipConfig{
+		PeerListGossipSpacing: 10 * time.Second,
+		PeerListGossipSize:    10,
+		GossipSize:            5,
+	}
+	err := net.SetGossipConfig(newConfig)
+	assert.NoError(t, err)
+	assert.Equal(t, newConfig, net.GossipConfig())
+
+	closeTestNetwork(t, net)
+}
+
+func TestRefreshPeers(t *testing.T) {
+	net0 := newTestNetwork(t)
+
+	refresh := net0.LastPeerRefresh()
+	assert.True(t, refresh.Time.IsZero())
+
+	requested, err := net0.RefreshPeers()
+	assert.NoError(t, err)
+	assert.Equal(t, 0, requested)
+
+	refresh = net0.LastPeerRefresh()
+	assert.False(t, refresh.Time.IsZero())
+	assert.Equal(t, 0, refresh.NewPeers)
+
+	// Learn of a peer after the peer lists were requested
+	n := net0.(*network)
+	n.stateLock.Lock()
+	n.track(utils.IPDesc{
+		IP:   net.IPv4(1, 2, 3, 4),
+		Port: 9651,
+	})
+	n.stateLock.Unlock()
+
+	refresh = net0.LastPeerRefresh()
+	assert.Equal(t, 1, refresh.NewPeers)
+
+	closeTestNetwork(t, net0)
+}
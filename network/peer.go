@@ -4,7 +4,6 @@
 package network
 
 import (
-	"bytes"
 	"math"
 	"net"
 	"sync"
@@ -43,17 +42,29 @@ type peer struct {
 	// on the connection's reader routine with the network state lock held.
 	ip utils.IPDesc
 
+	// the IP the peer claimed to be reachable at during the handshake. is only
+	// modified on the connection's reader routine with the network state lock
+	// held.
+	claimedIP utils.IPDesc
+
 	// id should be set when the peer is first started.
 	id ids.ShortID
 
 	// the connection object that is used to read/write messages from
 	conn net.Conn
 
+	// true if the peer connected to this node, rather than this node
+	// connecting to the peer
+	inbound bool
+
 	// version that the peer reported during the handshake
 	versionStr string
 
 	// unix time of the last message sent and received respectively
 	lastSent, lastReceived int64
+
+	// why the connection was closed, accessed atomically
+	disconnectReason int32
 }
 
 // assume the stateLock is held
@@ -94,6 +105,7 @@ func (p *peer) ReadMessages() {
 		read, err := p.conn.Read(readBuffer)
 		if err != nil {
 			p.net.log.Verbo("error on connection read to %s %s", p.id, err)
+			p.setDisconnectReason(classifyConnError(err, disconnectReadError))
 			return
 		}
 
@@ -111,6 +123,7 @@ func (p *peer) ReadMessages() {
 				// so we should terminate this connection
 
 				p.net.log.Verbo("error reading too many bytes on %s %s", p.id, err)
+				p.setDisconnectReason(disconnectInvalidMessage)
 				return
 			}
 
@@ -130,6 +143,7 @@ func (p *peer) ReadMessages() {
 			// should terminate this connection
 
 			p.net.log.Verbo("error reading too many bytes on %s %s", p.id, err)
+			p.setDisconnectReason(disconnectInvalidMessage)
 			return
 		}
 
@@ -143,6 +157,8 @@ func (p *peer) ReadMessages() {
 				p.id,
 				formatting.DumpBytes{Bytes: msgBytes},
 				err)
+			p.net.drops.droppedInbound(dropInvalid)
+			p.setDisconnectReason(disconnectInvalidMessage)
 			return
 		}
 
@@ -160,7 +176,14 @@ func (p *peer) WriteMessages() {
 			formatting.DumpBytes{Bytes: msg})
 
 		p.net.stateLock.Lock()
+		if p.closed {
+			// The bytes of the messages that were still queued were released
+			// when the peer was closed
+			p.net.stateLock.Unlock()
+			return
+		}
 		p.pendingBytes -= len(msg)
+		p.net.pendingBytes -= len(msg)
 		p.net.stateLock.Unlock()
 
 		packer := wrappers.Packer{Bytes: make([]byte, len(msg)+wrappers.IntLen)}
@@ -170,6 +193,7 @@ func (p *peer) WriteMessages() {
 			written, err := p.conn.Write(msg)
 			if err != nil {
 				p.net.log.Verbo("error writing to %s at %s due to: %s", p.id, p.ip, err)
+				p.setDisconnectReason(classifyConnError(err, disconnectWriteError))
 				return
 			}
 			msg = msg[written:]
@@ -190,6 +214,7 @@ func (p *peer) Send(msg Msg) bool {
 func (p *peer) send(msg Msg) bool {
 	if p.closed {
 		p.net.log.Debug("dropping message to %s due to a closed connection", p.id)
+		p.net.drops.droppedOutbound(dropPeerGone)
 		return false
 	}
 
@@ -199,8 +224,9 @@ func (p *peer) send(msg Msg) bool {
 	if newPendingBytes > p.net.networkPendingSendBytesToRateLimit && // Check to see if we should be enforcing any rate limiting
 		uint32(p.pendingBytes) > p.net.maxMessageSize && // this connection should have a minimum allowed bandwidth
 		(newPendingBytes > p.net.maxNetworkPendingSendBytes || // Check to see if this message would put too much memory into the network
-			newConnPendingBytes > p.net.maxNetworkPendingSendBytes/20) { // Check to see if this connection is using too much memory
+			newConnPendingBytes > p.net.maxPeerPendingSendBytes()) { // Check to see if this connection is using too much memory
 		p.net.log.Debug("dropping message to %s due to a send queue with too many bytes", p.id)
+		p.net.drops.droppedOutbound(dropThrottled)
 		return false
 	}
 
@@ -211,6 +237,7 @@ func (p *peer) send(msg Msg) bool {
 		return true
 	default:
 		p.net.log.Debug("dropping message to %s due to a full send queue", p.id)
+		p.net.drops.droppedOutbound(dropQueueFull)
 		return false
 	}
 }
@@ -224,6 +251,7 @@ func (p *peer) handle(msg Msg) {
 	msgMetrics := p.net.message(op)
 	if msgMetrics == nil {
 		p.net.log.Debug("dropping an unknown message from %s with op %s", p.id, op.String())
+		p.net.drops.droppedInbound(dropInvalid)
 		return
 	}
 	msgMetrics.numReceived.Inc()
@@ -238,6 +266,7 @@ func (p *peer) handle(msg Msg) {
 	}
 	if !p.connected {
 		p.net.log.Debug("dropping message from %s because the connection hasn't been established yet", p.id)
+		p.net.drops.droppedInbound(dropNotConnected)
 
 		// send a get version message so that the peer's future messages are hopefully not dropped
 		p.GetVersion()
@@ -272,6 +301,7 @@ func (p *peer) handle(msg Msg) {
 		p.chits(msg)
 	default:
 		p.net.log.Debug("dropping an unknown message from %s with op %s", p.id, op.String())
+		p.net.drops.droppedInbound(dropInvalid)
 	}
 }
 
@@ -286,6 +316,9 @@ func (p *peer) close() {
 	p.closed = true
 	p.conn.Close()
 	close(p.sender)
+	// The messages that are still queued will never be sent
+	p.net.pendingBytes -= p.pendingBytes
+	p.pendingBytes = 0
 	p.net.disconnected(p)
 }
 
@@ -351,7 +384,7 @@ func (p *peer) version(msg Msg) {
 			p.ip = utils.IPDesc{}
 			p.net.stateLock.Unlock()
 		}
-		p.Close()
+		p.closeWithReason(disconnectIncompatible)
 		return
 	}
 
@@ -367,12 +400,18 @@ func (p *peer) version(msg Msg) {
 			p.ip = utils.IPDesc{}
 			p.net.stateLock.Unlock()
 		}
-		p.Close()
+		p.closeWithReason(disconnectSelf)
 		return
 	}
 
 	myTime := float64(p.net.clock.Unix())
-	if peerTime := float64(msg.Get(MyTime).(uint64)); math.Abs(peerTime-myTime) > p.net.maxClockDifference.Seconds() {
+	peerTime := float64(msg.Get(MyTime).(uint64))
+
+	p.net.stateLock.Lock()
+	p.net.clockSkews.add(time.Duration(myTime-peerTime) * time.Second)
+	p.net.stateLock.Unlock()
+
+	if math.Abs(peerTime-myTime) > p.net.maxClockDifference.Seconds() {
 		p.net.log.Debug("peer's clock is too far out of sync with mine. Peer's = %d, Ours = %d (seconds)",
 			uint64(peerTime),
 			uint64(myTime))
@@ -384,7 +423,7 @@ func (p *peer) version(msg Msg) {
 			p.ip = utils.IPDesc{}
 			p.net.stateLock.Unlock()
 		}
-		p.Close()
+		p.closeWithReason(disconnectClockSkew)
 		return
 	}
 
@@ -400,7 +439,7 @@ func (p *peer) version(msg Msg) {
 			p.ip = utils.IPDesc{}
 			p.net.stateLock.Unlock()
 		}
-		p.Close()
+		p.closeWithReason(disconnectIncompatible)
 		return
 	}
 
@@ -419,7 +458,7 @@ func (p *peer) version(msg Msg) {
 			p.ip = utils.IPDesc{}
 			p.net.stateLock.Unlock()
 		}
-		p.Close()
+		p.closeWithReason(disconnectIncompatible)
 		return
 	}
 
@@ -432,7 +471,7 @@ func (p *peer) version(msg Msg) {
 		if err == nil {
 			// If we have no clue what the peer's IP is, we can't perform any
 			// verification
-			if bytes.Equal(peerIP.IP, localPeerIP.IP) {
+			if peerIP.IP.Equal(localPeerIP.IP) {
 				// if the IPs match, add this ip:port pair to be tracked
 				p.net.stateLock.Lock()
 				p.ip = peerIP
@@ -453,6 +492,7 @@ func (p *peer) version(msg Msg) {
 	}
 
 	p.versionStr = peerVersion.String()
+	p.claimedIP = msg.Get(IP).(utils.IPDesc)
 
 	p.connected = true
 	p.net.connected(p)
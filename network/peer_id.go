@@ -4,9 +4,11 @@
 package network
 
 import (
+	"net"
 	"time"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/utils"
 )
 
 // PeerID ...
@@ -17,4 +19,41 @@ type PeerID struct {
 	Version      string      `json:"version"`
 	LastSent     time.Time   `json:"lastSent"`
 	LastReceived time.Time   `json:"lastReceived"`
+
+	// IPMismatch is true if the IP the peer claimed to be reachable at differs
+	// from the IP its connection was observed to come from. This often
+	// indicates a NAT misconfiguration or a spoofed IP.
+	IPMismatch bool `json:"ipMismatch"`
+
+	// Inbound is true if the peer connected to this node, rather than this
+	// node connecting to the peer
+	Inbound bool `json:"inbound"`
+}
+
+// addrString returns [addr] in the same canonical form that utils.IPDesc uses,
+// so that IPv6 addresses are always bracketed and IPv4-mapped IPv6 addresses
+// are rendered as IPv4 addresses.
+func addrString(addr net.Addr) string {
+	if ip, err := utils.ToIPDesc(addr.String()); err == nil {
+		return ip.String()
+	}
+	return addr.String()
+}
+
+// ipMismatch returns true if [claimed] refers to a different host than
+// [observed]. Ports are ignored, as the port of an inbound connection is
+// ephemeral. If either IP is unknown, or both are loopback addresses, the IPs
+// aren't reported as mismatched.
+func ipMismatch(observed net.Addr, claimed utils.IPDesc) bool {
+	if observed == nil || claimed.IsZero() {
+		return false
+	}
+	observedIP, err := utils.ToIPDesc(observed.String())
+	if err != nil {
+		return false
+	}
+	if observedIP.IP.IsLoopback() && claimed.IP.IsLoopback() {
+		return false
+	}
+	return !observedIP.IP.Equal(claimed.IP)
 }
@@ -0,0 +1,102 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"net"
+	"testing"
+
+	"github.com/ava-labs/gecko/utils"
+)
+
+func TestIPMismatch(t *testing.T) {
+	tests := []struct {
+		name     string
+		observed net.Addr
+		claimed  utils.IPDesc
+		mismatch bool
+	}{
+		{
+			name:     "unknown claimed IP",
+			observed: &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 5000},
+			claimed:  utils.IPDesc{},
+			mismatch: false,
+		},
+		{
+			name:     "same host different port",
+			observed: &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 5000},
+			claimed:  utils.IPDesc{IP: net.IPv4(1, 2, 3, 4), Port: 9651},
+			mismatch: false,
+		},
+		{
+			name:     "same host different encoding",
+			observed: &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4).To4(), Port: 5000},
+			claimed:  utils.IPDesc{IP: net.IPv4(1, 2, 3, 4).To16(), Port: 9651},
+			mismatch: false,
+		},
+		{
+			name:     "both loopback",
+			observed: &net.TCPAddr{IP: net.IPv6loopback, Port: 5000},
+			claimed:  utils.IPDesc{IP: net.IPv4(127, 0, 0, 1), Port: 9651},
+			mismatch: false,
+		},
+		{
+			name:     "different hosts",
+			observed: &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 5000},
+			claimed:  utils.IPDesc{IP: net.IPv4(5, 6, 7, 8), Port: 9651},
+			mismatch: true,
+		},
+		{
+			name:     "loopback claimed by remote host",
+			observed: &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 5000},
+			claimed:  utils.IPDesc{IP: net.IPv4(127, 0, 0, 1), Port: 9651},
+			mismatch: true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if mismatch := ipMismatch(test.observed, test.claimed); mismatch != test.mismatch {
+				t.Fatalf("expected mismatch to be %v but was %v", test.mismatch, mismatch)
+			}
+		})
+	}
+}
+
+func TestAddrString(t *testing.T) {
+	tests := []struct {
+		addr net.Addr
+		str  string
+	}{
+		{
+			addr: &net.TCPAddr{IP: net.IPv4(1, 2, 3, 4), Port: 9651},
+			str:  "1.2.3.4:9651",
+		},
+		{
+			addr: &net.TCPAddr{IP: net.ParseIP("2001:db8::1"), Port: 9651},
+			str:  "[2001:db8::1]:9651",
+		},
+		{
+			addr: &net.TCPAddr{IP: net.ParseIP("::ffff:1.2.3.4"), Port: 9651},
+			str:  "1.2.3.4:9651",
+		},
+		{
+			addr: &net.TCPAddr{IP: net.ParseIP("fe80::1"), Port: 9651, Zone: "eth0"},
+			str:  "[fe80::1]:9651",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.str, func(t *testing.T) {
+			if str := addrString(test.addr); str != test.str {
+				t.Fatalf("expected %q but got %q", test.str, str)
+			}
+			claimed, err := utils.ToIPDesc(test.str)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if str := claimed.String(); str != test.str {
+				t.Fatalf("expected %q to render as itself but got %q", test.str, str)
+			}
+		})
+	}
+}
@@ -0,0 +1,78 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"github.com/ava-labs/gecko/ids"
+)
+
+// PeerPriority describes the factors that decide how messages to a peer are
+// treated. Messages are sent to each peer in the order they were queued, and
+// peers aren't benched, so a peer's messages are only delayed or dropped by
+// its send queue and the outbound message throttler.
+type PeerPriority struct {
+	// True if the peer is a validator, which makes it preferred when peer
+	// lists are gossiped
+	Validator bool
+
+	// The peer's stake, and its fraction of the total stake. 0 if the peer
+	// isn't a validator.
+	Weight        uint64
+	StakeFraction float64
+
+	// Number of bytes and messages currently queued to be sent to the peer
+	PendingSendBytes    int
+	PendingSendMessages int
+
+	// Number of messages that may be queued to be sent to the peer before
+	// further messages are dropped
+	SendQueueCapacity int
+
+	// Number of queued bytes the peer is allowed while rate limiting is being
+	// enforced, whatever the network's usage
+	GuaranteedSendBytes int
+
+	// Maximum number of bytes that may be queued to be sent to the peer while
+	// rate limiting is being enforced
+	MaxPendingSendBytes int
+
+	// True if rate limiting is being enforced and the peer has used up its
+	// guaranteed bytes, so that messages to it are dropped if they would put
+	// the peer or the network over their limits
+	Throttled bool
+}
+
+// PeerPriority implements the Network interface
+func (n *network) PeerPriority(peerID ids.ShortID) (PeerPriority, error) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	peer, ok := n.peers[peerID.Key()]
+	if !ok || !peer.connected {
+		return PeerPriority{}, errPeerNotConnected
+	}
+
+	priority := PeerPriority{
+		PendingSendBytes:    peer.pendingBytes,
+		PendingSendMessages: len(peer.sender),
+		SendQueueCapacity:   cap(peer.sender),
+		GuaranteedSendBytes: int(n.maxMessageSize),
+		MaxPendingSendBytes: n.maxPeerPendingSendBytes(),
+		Throttled: n.pendingBytes > n.networkPendingSendBytesToRateLimit &&
+			uint32(peer.pendingBytes) > n.maxMessageSize,
+	}
+	if vdr, ok := n.vdrs.Get(peerID); ok {
+		priority.Validator = true
+		priority.Weight = vdr.Weight()
+
+		totalWeight := uint64(0)
+		for _, vdr := range n.vdrs.List() {
+			totalWeight += vdr.Weight()
+		}
+		if totalWeight > 0 {
+			priority.StakeFraction = float64(priority.Weight) / float64(totalWeight)
+		}
+	}
+	return priority, nil
+}
@@ -0,0 +1,32 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto/tls"
+	"errors"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+var (
+	errPeerNotConnected = errors.New("not connected to the peer")
+	errPeerNotTLS       = errors.New("the connection to the peer isn't using TLS")
+)
+
+// PeerTLS implements the Network interface
+func (n *network) PeerTLS(peerID ids.ShortID) (tls.ConnectionState, error) {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	peer, ok := n.peers[peerID.Key()]
+	if !ok || !peer.connected {
+		return tls.ConnectionState{}, errPeerNotConnected
+	}
+	conn, ok := peer.conn.(*tls.Conn)
+	if !ok {
+		return tls.ConnectionState{}, errPeerNotTLS
+	}
+	return conn.ConnectionState(), nil
+}
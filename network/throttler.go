@@ -0,0 +1,113 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"errors"
+
+	"github.com/ava-labs/gecko/ids"
+)
+
+// Once rate limiting is being enforced, a single peer may only use
+// 1/peerPendingSendBytesFraction of the network's maximum pending send bytes.
+const peerPendingSendBytesFraction = 20
+
+var (
+	errNonPositiveThrottlerLimit = errors.New("throttler limits must be positive")
+	errRateLimitAboveMax         = errors.New("rate limiting threshold can't exceed the maximum pending send bytes")
+)
+
+// ThrottlerState describes the limits and the usage of the outbound message
+// throttler.
+type ThrottlerState struct {
+	// Maximum number of bytes that may be queued to be sent across all peers
+	MaxPendingSendBytes int `json:"maxPendingSendBytes"`
+
+	// Number of queued bytes after which messages may be dropped
+	PendingSendBytesToRateLimit int `json:"pendingSendBytesToRateLimit"`
+
+	// Maximum number of bytes that may be queued to be sent to a single peer
+	// while rate limiting is being enforced
+	MaxPeerPendingSendBytes int `json:"maxPeerPendingSendBytes"`
+
+	// Number of bytes currently queued to be sent across all peers
+	PendingSendBytes int `json:"pendingSendBytes"`
+
+	// True iff the number of queued bytes is above the rate limiting threshold
+	RateLimiting bool `json:"rateLimiting"`
+
+	Peers []PeerThrottlerState `json:"peers"`
+}
+
+// PeerThrottlerState describes the outbound message throttler's usage by a
+// single peer.
+type PeerThrottlerState struct {
+	ID ids.ShortID `json:"id"`
+
+	// Number of bytes currently queued to be sent to this peer
+	PendingSendBytes int `json:"pendingSendBytes"`
+
+	// Number of messages currently queued to be sent to this peer
+	PendingSendMessages int `json:"pendingSendMessages"`
+}
+
+// ThrottlerState implements the Network interface
+func (n *network) ThrottlerState() ThrottlerState {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	state := ThrottlerState{
+		MaxPendingSendBytes:         n.maxNetworkPendingSendBytes,
+		PendingSendBytesToRateLimit: n.networkPendingSendBytesToRateLimit,
+		MaxPeerPendingSendBytes:     n.maxPeerPendingSendBytes(),
+		PendingSendBytes:            n.pendingBytes,
+		RateLimiting:                n.pendingBytes > n.networkPendingSendBytesToRateLimit,
+		Peers:                       make([]PeerThrottlerState, 0, len(n.peers)),
+	}
+	for _, peer := range n.peers {
+		state.Peers = append(state.Peers, PeerThrottlerState{
+			ID:                  peer.id,
+			PendingSendBytes:    peer.pendingBytes,
+			PendingSendMessages: len(peer.sender),
+		})
+	}
+	return state
+}
+
+// SetThrottlerLimits implements the Network interface
+func (n *network) SetThrottlerLimits(maxPendingSendBytes, pendingSendBytesToRateLimit int) error {
+	switch {
+	case maxPendingSendBytes <= 0 || pendingSendBytesToRateLimit <= 0:
+		return errNonPositiveThrottlerLimit
+	case pendingSendBytesToRateLimit > maxPendingSendBytes:
+		return errRateLimitAboveMax
+	}
+
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.log.Info("setting throttler limits to %d max pending send bytes and %d pending send bytes to rate limit",
+		maxPendingSendBytes,
+		pendingSendBytesToRateLimit)
+
+	n.maxNetworkPendingSendBytes = maxPendingSendBytes
+	n.networkPendingSendBytesToRateLimit = pendingSendBytesToRateLimit
+	return nil
+}
+
+// ResetThrottlerLimits implements the Network interface
+func (n *network) ResetThrottlerLimits() {
+	n.stateLock.Lock()
+	defer n.stateLock.Unlock()
+
+	n.log.Info("resetting throttler limits")
+
+	n.maxNetworkPendingSendBytes = n.initialMaxNetworkPendingSendBytes
+	n.networkPendingSendBytesToRateLimit = n.initialNetworkPendingSendBytesToRateLimit
+}
+
+// assumes the stateLock is held
+func (n *network) maxPeerPendingSendBytes() int {
+	return n.maxNetworkPendingSendBytes / peerPendingSendBytesFraction
+}
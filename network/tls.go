@@ -0,0 +1,96 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto/tls"
+	"fmt"
+	"strings"
+	"sync/atomic"
+)
+
+// tlsVersions maps the names of the TLS versions that peers may be required to
+// use to their values
+var tlsVersions = map[string]uint16{
+	"1.0": tls.VersionTLS10,
+	"1.1": tls.VersionTLS11,
+	"1.2": tls.VersionTLS12,
+	"1.3": tls.VersionTLS13,
+}
+
+// ParseTLSVersion parses a TLS version such as "1.2"
+func ParseTLSVersion(version string) (uint16, error) {
+	v, ok := tlsVersions[strings.TrimPrefix(version, "TLS ")]
+	if !ok {
+		return 0, fmt.Errorf("unknown TLS version %q. Should be one of {1.0, 1.1, 1.2, 1.3}", version)
+	}
+	return v, nil
+}
+
+// TLSVersionName returns the name of TLS version [version], such as "TLS 1.2",
+// or its hex value if it's unknown
+func TLSVersionName(version uint16) string {
+	for name, v := range tlsVersions {
+		if v == version {
+			return "TLS " + name
+		}
+	}
+	return fmt.Sprintf("0x%04X", version)
+}
+
+// handshakeFailureReason is why a connection couldn't be upgraded
+type handshakeFailureReason int
+
+const (
+	// the peer only supports TLS versions older than the minimum, or the
+	// other way around
+	handshakeUnsupportedVersion handshakeFailureReason = iota
+	// the peer didn't present a certificate
+	handshakeNoCert
+	// any other reason, such as the connection closing mid-handshake
+	handshakeOther
+
+	numHandshakeFailureReasons
+)
+
+func (r handshakeFailureReason) String() string {
+	switch r {
+	case handshakeUnsupportedVersion:
+		return "unsupported version"
+	case handshakeNoCert:
+		return "no certificate"
+	case handshakeOther:
+		return "other"
+	default:
+		return "unknown"
+	}
+}
+
+// classifyHandshakeFailure returns the reason the handshake failed with [err].
+// crypto/tls doesn't export its errors, so they're recognized by their text.
+func classifyHandshakeFailure(err error) handshakeFailureReason {
+	if err == errNoCert {
+		return handshakeNoCert
+	}
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "unsupported versions"),
+		strings.Contains(msg, "unsupported protocol version"),
+		strings.Contains(msg, "protocol version not supported"):
+		return handshakeUnsupportedVersion
+	default:
+		return handshakeOther
+	}
+}
+
+// HandshakeFailures implements the Network interface
+func (n *network) HandshakeFailures() map[string]uint64 {
+	failures := make(map[string]uint64, numHandshakeFailureReasons)
+	for reason := handshakeFailureReason(0); reason < numHandshakeFailureReasons; reason++ {
+		if count := atomic.LoadUint64(&n.handshakeFailures[reason]); count > 0 {
+			failures[reason.String()] = count
+		}
+	}
+	return failures
+}
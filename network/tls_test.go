@@ -0,0 +1,90 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package network
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"math/big"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTLSVersion(t *testing.T) {
+	for _, name := range []string{"1.0", "1.1", "1.2", "1.3"} {
+		version, err := ParseTLSVersion(name)
+		assert.NoError(t, err)
+		assert.Equal(t, "TLS "+name, TLSVersionName(version))
+	}
+
+	version, err := ParseTLSVersion("TLS 1.3")
+	assert.NoError(t, err)
+	assert.Equal(t, uint16(tls.VersionTLS13), version)
+
+	_, err = ParseTLSVersion("1.4")
+	assert.Error(t, err)
+
+	assert.Equal(t, "0x0300", TLSVersionName(0x0300))
+}
+
+func TestClassifyHandshakeFailure(t *testing.T) {
+	cert := testCertificate(t)
+	serverConn, clientConn := net.Pipe()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		_, _, err := NewTLSServerUpgrader(&tls.Config{
+			Certificates: []tls.Certificate{cert},
+			ClientAuth:   tls.RequireAnyClientCert,
+			MinVersion:   tls.VersionTLS13,
+		}).Upgrade(serverConn)
+		serverConn.Close()
+		serverErr <- err
+	}()
+
+	_, _, err := NewTLSClientUpgrader(&tls.Config{
+		Certificates:       []tls.Certificate{cert},
+		InsecureSkipVerify: true,
+		MaxVersion:         tls.VersionTLS12,
+	}).Upgrade(clientConn)
+	clientConn.Close()
+
+	assert.Error(t, err)
+	assert.Equal(t, handshakeUnsupportedVersion, classifyHandshakeFailure(err))
+
+	err = <-serverErr
+	assert.Error(t, err)
+	assert.Equal(t, handshakeUnsupportedVersion, classifyHandshakeFailure(err))
+
+	assert.Equal(t, handshakeNoCert, classifyHandshakeFailure(errNoCert))
+	assert.Equal(t, handshakeOther, classifyHandshakeFailure(errors.New("EOF")))
+}
+
+// testCertificate returns a self-signed certificate
+func testCertificate(t *testing.T) tls.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tls.Certificate{
+		Certificate: [][]byte{certBytes},
+		PrivateKey:  key,
+	}
+}
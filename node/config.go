@@ -4,18 +4,24 @@
 package node
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/nat"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
 	"github.com/ava-labs/gecko/snow/networking/router"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp"
 )
 
 // Config contains all of the configurations of an Ava node.
 type Config struct {
 	// protocol to use for opening the network interface
 	Nat nat.Router
+	// Maps the node's ports on Nat
+	NatMapper nat.Mapper
 
 	// ID of the network this node should connect to
 	NetworkID uint32
@@ -31,13 +37,23 @@ type Config struct {
 
 	// Database to use for the node
 	DB database.Database
+	// Directory the database is stored in. Empty if it's held in memory.
+	DBPath string
 
 	// Staking configuration
-	StakingIP       utils.IPDesc
+	StakingIP utils.IPDesc
+	// How StakingIP was determined, and when
+	StakingIPSource string
+	StakingIPTime   time.Time
 	EnableP2PTLS    bool
 	EnableStaking   bool
 	StakingKeyFile  string
 	StakingCertFile string
+	// Oldest TLS version peers may connect with
+	StakingTLSMinVersion uint16
+	// How long before the staking certificate expires the Admin API warns
+	// that it's about to
+	StakingCertExpiryWarning time.Duration
 
 	// Bootstrapping configuration
 	BootstrapPeers []*Peer
@@ -55,12 +71,45 @@ type Config struct {
 	MetricsAPIEnabled  bool
 	HealthAPIEnabled   bool
 
+	// If true, the Admin API may change the consensus parameters of chains
+	AdminConsensusTuningEnabled bool
+	// If true, the Admin API may accept blocks without waiting for consensus
+	AdminForceAcceptEnabled bool
+
 	// Logging configuration
 	LoggingConfig logging.Config
 
 	// Plugin directory
 	PluginDir string
 
+	// Plugin HTTP configuration
+	PluginHTTPConfig ghttp.Config
+
+	// VMs whose plugins get dedicated connections for their HTTP requests, and
+	// the number of connections each of their handlers gets
+	PluginHTTPDedicatedVMs         ids.Set
+	PluginHTTPDedicatedConnections int
+
+	// VMs whose plugins' responses to requests with idempotency keys are
+	// replayed to retries of the requests
+	PluginHTTPIdempotentVMs ids.Set
+
+	// VMs whose plugins' HTTP handler panics are replied to by the handler
+	// rather than raised again in the node
+	PluginHTTPIsolatedVMs ids.Set
+
+	// If true, the requests handled by plugins' HTTP handlers are recorded in
+	// the node's metrics, under up to [PluginHTTPMaxMetricsEndpoints] distinct
+	// endpoints per VM
+	PluginHTTPMetrics             bool
+	PluginHTTPMaxMetricsEndpoints int
+
+	// How verbosely the requests to each plugin's HTTP handlers are logged
+	// until it's changed through the admin API, and the number of bytes of
+	// each body that are logged at the most verbose level
+	PluginHTTPRequestLogLevel       ghttp.RequestLogLevel
+	PluginHTTPRequestLogMaxBodySize int
+
 	// Consensus configuration
 	ConsensusParams avalanche.Parameters
 
@@ -12,6 +12,7 @@ import (
 	"net"
 	"os"
 	"path"
+	"strings"
 	"sync"
 	"time"
 
@@ -32,6 +33,7 @@ import (
 	"github.com/ava-labs/gecko/snow/validators"
 	"github.com/ava-labs/gecko/utils"
 	"github.com/ava-labs/gecko/utils/hashing"
+	"github.com/ava-labs/gecko/utils/labels"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/wrappers"
 	"github.com/ava-labs/gecko/version"
@@ -41,6 +43,7 @@ import (
 	"github.com/ava-labs/gecko/vms/platformvm"
 	"github.com/ava-labs/gecko/vms/propertyfx"
 	"github.com/ava-labs/gecko/vms/rpcchainvm"
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp"
 	"github.com/ava-labs/gecko/vms/secp256k1fx"
 	"github.com/ava-labs/gecko/vms/spchainvm"
 	"github.com/ava-labs/gecko/vms/spdagvm"
@@ -70,6 +73,10 @@ type Node struct {
 	// (in consensus, for example)
 	ID ids.ShortID
 
+	// The certificate this node's ID is derived from. nil if P2P TLS is
+	// disabled.
+	stakingCert *x509.Certificate
+
 	// Storage for this node
 	DB database.Database
 
@@ -128,6 +135,7 @@ func (n *Node) initNetworking() error {
 		tlsConfig := &tls.Config{
 			Certificates: []tls.Certificate{cert},
 			ClientAuth:   tls.RequireAnyClientCert,
+			MinVersion:   n.Config.StakingTLSMinVersion,
 			// We do not use TLS's CA functionality, we just require an
 			// authenticated channel. Therefore, we can safely skip verification
 			// here.
@@ -197,16 +205,20 @@ func (i *insecureValidatorManager) Disconnected(vdrID ids.ShortID) bool {
 // Dispatch starts the node's servers.
 // Returns when the node exits.
 func (n *Node) Dispatch() error {
-	// Add bootstrap nodes to the peer network
-	for _, peer := range n.Config.BootstrapPeers {
-		if !peer.IP.Equal(n.Config.StakingIP) {
-			n.Net.Track(peer.IP)
-		} else {
-			n.Log.Error("can't add self as a bootstrapper")
+	err := error(nil)
+	labels.Do(func() {
+		// Add bootstrap nodes to the peer network
+		for _, peer := range n.Config.BootstrapPeers {
+			if !peer.IP.Equal(n.Config.StakingIP) {
+				n.Net.Track(peer.IP)
+			} else {
+				n.Log.Error("can't add self as a bootstrapper")
+			}
 		}
-	}
 
-	return n.Net.Dispatch()
+		err = n.Net.Dispatch()
+	}, labels.Subsystem, "network")
+	return err
 }
 
 /*
@@ -273,6 +285,7 @@ func (n *Node) initNodeID() error {
 	if err != nil {
 		return fmt.Errorf("problem deriving staker ID from certificate: %w", err)
 	}
+	n.stakingCert = cert
 	n.Log.Info("Set node's ID to %s", n.ID)
 	return nil
 }
@@ -295,7 +308,10 @@ func (n *Node) initVMManager() error {
 			AVA:      avaAssetID,
 			Platform: ids.Empty,
 		}),
-		n.vmManager.RegisterVMFactory(genesis.EVMID, &rpcchainvm.Factory{Path: path.Join(n.Config.PluginDir, "evm")}),
+		n.vmManager.RegisterVMFactory(genesis.EVMID, &rpcchainvm.Factory{
+			Path: path.Join(n.Config.PluginDir, "evm"),
+			HTTP: n.pluginHTTPConfig(genesis.EVMID, "evm"),
+		}),
 		n.vmManager.RegisterVMFactory(spdagvm.ID, &spdagvm.Factory{TxFee: n.Config.AvaTxFee}),
 		n.vmManager.RegisterVMFactory(spchainvm.ID, &spchainvm.Factory{}),
 		n.vmManager.RegisterVMFactory(timestampvm.ID, &timestampvm.Factory{}),
@@ -306,6 +322,28 @@ func (n *Node) initVMManager() error {
 	return errs.Err
 }
 
+// pluginHTTPConfig returns the options used to serve the HTTP handlers of the
+// plugin that implements VM [vmID]. The VM's requests are recorded in metrics
+// namespaced by [name].
+func (n *Node) pluginHTTPConfig(vmID ids.ID, name string) ghttp.Config {
+	config := n.Config.PluginHTTPConfig
+	if n.Config.PluginHTTPDedicatedVMs.Contains(vmID) {
+		config.Connections = n.Config.PluginHTTPDedicatedConnections
+	}
+	config.Idempotency.Enabled = n.Config.PluginHTTPIdempotentVMs.Contains(vmID)
+	config.IsolatePanics = n.Config.PluginHTTPIsolatedVMs.Contains(vmID)
+	config.RequestLogging = ghttp.NewRequestLogging(n.Config.PluginHTTPRequestLogLevel, n.Config.PluginHTTPRequestLogMaxBodySize)
+	if n.Config.PluginHTTPMetrics {
+		metrics, err := ghttp.NewMetrics(fmt.Sprintf("gecko_%s_http", name), n.Config.ConsensusParams.Metrics, n.Config.PluginHTTPMaxMetricsEndpoints)
+		if err != nil {
+			n.Log.Error("couldn't record the HTTP requests of %s: %s", name, err)
+		} else {
+			config.Metrics = metrics
+		}
+	}
+	return config
+}
+
 // Create the EventDispatcher used for hooking events
 // into the general process flow.
 func (n *Node) initEventDispatcher() {
@@ -387,19 +425,22 @@ func (n *Node) initAPIServer() {
 
 	n.APIServer.Initialize(n.Log, n.LogFactory, n.Config.HTTPHost, n.Config.HTTPPort)
 
-	go n.Log.RecoverAndPanic(func() {
-		if n.Config.EnableHTTPS {
-			n.Log.Debug("Initializing API server with TLS Enabled")
-			err := n.APIServer.DispatchTLS(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile)
-			n.Log.Warn("Secure API server initialization failed with %s, attempting to create insecure API server", err)
-		}
+	go labels.Do(func() { n.Log.RecoverAndPanic(n.dispatchAPIServer) }, labels.Subsystem, "api")
+}
 
-		n.Log.Debug("Initializing API server")
-		err := n.APIServer.Dispatch()
+// dispatchAPIServer serves the API server until it exits
+func (n *Node) dispatchAPIServer() {
+	if n.Config.EnableHTTPS {
+		n.Log.Debug("Initializing API server with TLS Enabled")
+		err := n.APIServer.DispatchTLS(n.Config.HTTPSCertFile, n.Config.HTTPSKeyFile)
+		n.Log.Warn("Secure API server initialization failed with %s, attempting to create insecure API server", err)
+	}
 
-		n.Log.Fatal("API server initialization failed with %s", err)
-		n.Net.Close()
-	})
+	n.Log.Debug("Initializing API server")
+	err := n.APIServer.Dispatch()
+
+	n.Log.Fatal("API server initialization failed with %s", err)
+	n.Net.Close()
 }
 
 // Assumes n.DB, n.vdrs all initialized (non-nil)
@@ -461,7 +502,35 @@ func (n *Node) initMetricsAPI() {
 func (n *Node) initAdminAPI() {
 	if n.Config.AdminAPIEnabled {
 		n.Log.Info("initializing Admin API")
-		service := admin.NewService(Version, n.ID, n.Config.NetworkID, n.Log, n.chainManager, n.Net, &n.APIServer)
+		service := admin.NewService(admin.Config{
+			Version:      Version,
+			NodeID:       n.ID,
+			NetworkID:    n.Config.NetworkID,
+			Log:          n.Log,
+			ChainManager: n.chainManager,
+			Networking:   n.Net,
+			HTTPServer:   &n.APIServer,
+			ExternalIP: admin.ExternalIP{
+				IP:     n.Config.StakingIP,
+				Source: n.Config.StakingIPSource,
+				Time:   n.Config.StakingIPTime,
+			},
+			TLSConfig: admin.TLSConfig{
+				Enabled:           n.Config.EnableP2PTLS,
+				MinVersion:        n.Config.StakingTLSMinVersion,
+				Certificate:       n.stakingCert,
+				CertExpiryWarning: n.Config.StakingCertExpiryWarning,
+			},
+			NAT: admin.NAT{
+				Router: n.Config.Nat,
+				Mapper: n.Config.NatMapper,
+			},
+			DB:                     n.DB,
+			DBPath:                 n.Config.DBPath,
+			ConsensusTuningEnabled: n.Config.AdminConsensusTuningEnabled,
+			ForceAcceptEnabled:     n.Config.AdminForceAcceptEnabled,
+			Shutdown:               n.GracefulShutdown,
+		})
 		n.APIServer.AddRoute(service, &sync.RWMutex{}, "admin", "", n.HTTPLog)
 	}
 }
@@ -514,6 +583,18 @@ func (n *Node) initAliases() error {
 		}
 	}
 	for url, aliases := range defaultAliases {
+		// Aliases of chain endpoints are tracked so that they can be pruned if
+		// the chain fails to start
+		if chainIDStr := strings.TrimPrefix(url, "bc/"); chainIDStr != url {
+			chainID, err := ids.FromString(chainIDStr)
+			if err != nil {
+				return err
+			}
+			if err := n.APIServer.AddChainAliases(chainID, aliases...); err != nil {
+				return err
+			}
+			continue
+		}
 		if err := n.APIServer.AddAliases(url, aliases...); err != nil {
 			return err
 		}
@@ -571,6 +652,15 @@ func (n *Node) Initialize(Config *Config, logger logging.Logger, logFactory logg
 	return n.initChains() // Start the Platform chain
 }
 
+// GracefulShutdown stops serving the chains' APIs, shuts down the chains one at
+// a time and then closes the network, which causes Dispatch to return
+func (n *Node) GracefulShutdown() {
+	n.Log.Info("gracefully shutting down the node")
+	n.APIServer.Drain()
+	n.chainManager.ShutdownChains()
+	n.Net.Close()
+}
+
 // Shutdown this node
 func (n *Node) Shutdown() {
 	n.Log.Info("shutting down the node")
@@ -4,6 +4,8 @@
 package avalanche
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
@@ -29,6 +31,10 @@ type Consensus interface {
 	// Returns the parameters that describe this avalanche instance
 	Parameters() Parameters
 
+	// Changes the parameters of this avalanche instance. Returns an error if
+	// the parameters aren't valid.
+	SetParameters(Parameters) error
+
 	// Returns true if the transaction is virtuous.
 	// That is, no transaction has been added that conflicts with it
 	IsVirtuous(snowstorm.Tx) bool
@@ -68,6 +74,12 @@ type Consensus interface {
 	// finalized. Note, it is possible that after returning finalized, a new
 	// decision may be added such that this instance is no longer finalized.
 	Finalized() bool
+
+	// AcceptLatencies returns how long each of the most recently accepted
+	// transactions was processing for, from being issued to being accepted,
+	// oldest first. Transactions, rather than vertices, are what clients
+	// wait on.
+	AcceptLatencies() []time.Duration
 }
 
 // Vertex is a collection of multiple transactions tied to other vertices
@@ -4,6 +4,8 @@
 package avalanche
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
@@ -80,6 +82,18 @@ func (ta *Topological) Initialize(ctx *snow.Context, params Parameters, frontier
 // Parameters implements the Avalanche interface
 func (ta *Topological) Parameters() Parameters { return ta.params }
 
+// SetParameters implements the Avalanche interface
+func (ta *Topological) SetParameters(params Parameters) error {
+	if err := params.Valid(); err != nil {
+		return err
+	}
+	if err := ta.cg.SetParameters(params.Parameters); err != nil {
+		return err
+	}
+	ta.params = params
+	return nil
+}
+
 // IsVirtuous implements the Avalanche interface
 func (ta *Topological) IsVirtuous(tx snowstorm.Tx) bool { return ta.cg.IsVirtuous(tx) }
 
@@ -152,6 +166,9 @@ func (ta *Topological) Quiesce() bool { return ta.cg.Quiesce() }
 // Finalized implements the Avalanche interface
 func (ta *Topological) Finalized() bool { return ta.cg.Finalized() }
 
+// AcceptLatencies implements the Avalanche interface
+func (ta *Topological) AcceptLatencies() []time.Duration { return ta.cg.AcceptLatencies() }
+
 // Takes in a list of votes and sets up the topological ordering. Returns the
 // reachable section of the graph annotated with the number of inbound edges and
 // the non-transitively applied votes. Also returns the list of leaf nodes.
@@ -4,6 +4,8 @@
 package snowman
 
 import (
+	"time"
+
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/consensus/snowball"
@@ -18,6 +20,11 @@ type Consensus interface {
 	// Returns the parameters that describe this snowman instance
 	Parameters() snowball.Parameters
 
+	// Changes the parameters of this snowman instance. Decisions that already
+	// have a snowball instance keep the parameters it was created with.
+	// Returns an error if the parameters aren't valid.
+	SetParameters(snowball.Parameters) error
+
 	// Adds a new decision. Assumes the dependency has already been added.
 	// Returns if a critical error has occurred.
 	Add(Block) error
@@ -37,4 +44,9 @@ type Consensus interface {
 	// finalized. Note, it is possible that after returning finalized, a new
 	// decision may be added such that this instance is no longer finalized.
 	Finalized() bool
+
+	// AcceptLatencies returns how long each of the most recently accepted
+	// blocks was processing for, from being issued to being accepted, oldest
+	// first
+	AcceptLatencies() []time.Duration
 }
@@ -24,6 +24,7 @@ var (
 
 	Tests = []func(*testing.T, Factory){
 		InitializeTest,
+		SetParametersTest,
 		AddToTailTest,
 		AddToNonTailTest,
 		AddToUnknownTest,
@@ -33,6 +34,7 @@ var (
 		IssuedIssuedTest,
 		RecordPollAcceptSingleBlockTest,
 		RecordPollAcceptAndRejectTest,
+		AcceptLatenciesTest,
 		RecordPollWhenFinalizedTest,
 		RecordPollRejectTransitivelyTest,
 		RecordPollTransitivelyResetConfidenceTest,
@@ -80,6 +82,43 @@ func InitializeTest(t *testing.T, factory Factory) {
 	}
 }
 
+// Make sure that only valid parameters can be set
+func SetParametersTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	ctx := snow.DefaultContextTest()
+	params := snowball.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 1,
+		Alpha:             1,
+		BetaVirtuous:      3,
+		BetaRogue:         5,
+		ConcurrentRepolls: 1,
+	}
+	sm.Initialize(ctx, params, GenesisID)
+
+	invalidParams := params
+	invalidParams.K = 3
+	invalidParams.Alpha = 1
+	if err := sm.SetParameters(invalidParams); err == nil {
+		t.Fatalf("Should have refused an alpha that isn't a majority of k")
+	}
+	if p := sm.Parameters(); p != params {
+		t.Fatalf("Shouldn't have changed the parameters")
+	}
+
+	newParams := params
+	newParams.K = 3
+	newParams.Alpha = 2
+	newParams.BetaVirtuous = 4
+	if err := sm.SetParameters(newParams); err != nil {
+		t.Fatal(err)
+	}
+	if p := sm.Parameters(); p != newParams {
+		t.Fatalf("Wrong returned parameters")
+	}
+}
+
 // Make sure that adding a block to the tail updates the preference
 func AddToTailTest(t *testing.T, factory Factory) {
 	sm := factory.New()
@@ -322,6 +361,56 @@ func RecordPollAcceptSingleBlockTest(t *testing.T, factory Factory) {
 	}
 }
 
+// Make sure that only the latencies of accepted blocks are remembered
+func AcceptLatenciesTest(t *testing.T, factory Factory) {
+	sm := factory.New()
+
+	ctx := snow.DefaultContextTest()
+	params := snowball.Parameters{
+		Metrics:           prometheus.NewRegistry(),
+		K:                 1,
+		Alpha:             1,
+		BetaVirtuous:      1,
+		BetaRogue:         2,
+		ConcurrentRepolls: 1,
+	}
+	sm.Initialize(ctx, params, GenesisID)
+
+	if latencies := sm.AcceptLatencies(); len(latencies) != 0 {
+		t.Fatalf("Shouldn't have any latencies before a block is accepted")
+	}
+
+	firstBlock := &TestBlock{
+		parent: Genesis,
+		id:     ids.Empty.Prefix(1),
+		status: choices.Processing,
+	}
+	secondBlock := &TestBlock{
+		parent: Genesis,
+		id:     ids.Empty.Prefix(2),
+		status: choices.Processing,
+	}
+
+	sm.Add(firstBlock)
+	sm.Add(secondBlock)
+
+	votes := ids.Bag{}
+	votes.Add(firstBlock.id)
+
+	sm.RecordPoll(votes)
+	sm.RecordPoll(votes)
+
+	if status := firstBlock.Status(); status != choices.Accepted {
+		t.Fatalf("Block should have been accepted")
+	} else if status := secondBlock.Status(); status != choices.Rejected {
+		t.Fatalf("Block should have been rejected")
+	} else if latencies := sm.AcceptLatencies(); len(latencies) != 1 {
+		t.Fatalf("Should have remembered the latency of only the accepted block, got %d latencies", len(latencies))
+	} else if latencies[0] < 0 {
+		t.Fatalf("Latency shouldn't be negative")
+	}
+}
+
 func RecordPollAcceptAndRejectTest(t *testing.T, factory Factory) {
 	sm := factory.New()
 
@@ -14,17 +14,25 @@ import (
 	"github.com/ava-labs/gecko/utils/timer"
 )
 
+// numAcceptLatencies is the number of the most recently accepted blocks whose
+// latencies are remembered
+const numAcceptLatencies = 1024
+
 type metrics struct {
 	numProcessing            prometheus.Gauge
 	latAccepted, latRejected prometheus.Histogram
 
 	clock      timer.Clock
 	processing map[[32]byte]time.Time
+
+	// latencies of the most recently accepted blocks
+	acceptLatencies *timer.LatencyWindow
 }
 
 // Initialize implements the Engine interface
 func (m *metrics) Initialize(log logging.Logger, namespace string, registerer prometheus.Registerer) error {
 	m.processing = make(map[[32]byte]time.Time)
+	m.acceptLatencies = timer.NewLatencyWindow(numAcceptLatencies)
 
 	m.numProcessing = prometheus.NewGauge(
 		prometheus.GaugeOpts{
@@ -72,6 +80,7 @@ func (m *metrics) Accepted(id ids.ID) {
 	delete(m.processing, key)
 
 	m.latAccepted.Observe(float64(end.Sub(start).Milliseconds()))
+	m.acceptLatencies.Observe(end.Sub(start))
 	m.numProcessing.Dec()
 }
 
@@ -85,3 +94,9 @@ func (m *metrics) Rejected(id ids.ID) {
 	m.latRejected.Observe(float64(end.Sub(start).Milliseconds()))
 	m.numProcessing.Dec()
 }
+
+// AcceptLatencies returns how long each of the most recently accepted blocks
+// was processing for, from being issued to being accepted, oldest first
+func (m *metrics) AcceptLatencies() []time.Duration {
+	return m.acceptLatencies.Latencies()
+}
@@ -76,6 +76,15 @@ func (ts *Topological) Initialize(ctx *snow.Context, params snowball.Parameters,
 // Parameters implements the Snowman interface
 func (ts *Topological) Parameters() snowball.Parameters { return ts.params }
 
+// SetParameters implements the Snowman interface
+func (ts *Topological) SetParameters(params snowball.Parameters) error {
+	if err := params.Valid(); err != nil {
+		return err
+	}
+	ts.params = params
+	return nil
+}
+
 // Add implements the Snowman interface
 func (ts *Topological) Add(blk Block) error {
 	parent := blk.Parent()
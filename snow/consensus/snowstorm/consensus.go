@@ -5,6 +5,7 @@ package snowstorm
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
@@ -24,6 +25,10 @@ type Consensus interface {
 	// Returns the parameters that describe this snowstorm instance
 	Parameters() snowball.Parameters
 
+	// Changes the parameters of this snowstorm instance. Returns an error if
+	// the parameters aren't valid.
+	SetParameters(snowball.Parameters) error
+
 	// Returns true if transaction <Tx> is virtuous.
 	// That is, no transaction has been added that conflicts with <Tx>
 	IsVirtuous(Tx) bool
@@ -58,6 +63,11 @@ type Consensus interface {
 	// possible that after returning finalized, a new decision may be added such
 	// that this instance is no longer finalized.
 	Finalized() bool
+
+	// AcceptLatencies returns how long each of the most recently accepted
+	// transactions was processing for, from being issued to being accepted,
+	// oldest first
+	AcceptLatencies() []time.Duration
 }
 
 // Tx consumes state.
@@ -86,6 +86,15 @@ func (dg *Directed) Initialize(ctx *snow.Context, params snowball.Parameters) {
 // Parameters implements the Snowstorm interface
 func (dg *Directed) Parameters() snowball.Parameters { return dg.params }
 
+// SetParameters implements the Snowstorm interface
+func (dg *Directed) SetParameters(params snowball.Parameters) error {
+	if err := params.Valid(); err != nil {
+		return err
+	}
+	dg.params = params
+	return nil
+}
+
 // IsVirtuous implements the Consensus interface
 func (dg *Directed) IsVirtuous(tx Tx) bool {
 	id := tx.ID()
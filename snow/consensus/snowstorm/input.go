@@ -81,6 +81,15 @@ func (ig *Input) Initialize(ctx *snow.Context, params snowball.Parameters) {
 // Parameters implements the Snowstorm interface
 func (ig *Input) Parameters() snowball.Parameters { return ig.params }
 
+// SetParameters implements the Snowstorm interface
+func (ig *Input) SetParameters(params snowball.Parameters) error {
+	if err := params.Valid(); err != nil {
+		return err
+	}
+	ig.params = params
+	return nil
+}
+
 // IsVirtuous implements the ConflictGraph interface
 func (ig *Input) IsVirtuous(tx Tx) bool {
 	id := tx.ID()
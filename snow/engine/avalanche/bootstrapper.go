@@ -43,6 +43,10 @@ type bootstrapper struct {
 	// number of vertices fetched so far
 	numFetched uint32
 
+	// greatest and least heights of the vertices fetched so far, used to
+	// estimate how many vertices remain to be fetched
+	maxFetchedHeight, minFetchedHeight uint64
+
 	// tracks which validators were asked for which containers in which requests
 	outstandingRequests common.Requests
 
@@ -149,6 +153,8 @@ func (b *bootstrapper) process(vtx avalanche.Vertex) error {
 			}); err == nil {
 				b.numBSBlockedVtx.Inc()
 				b.numFetched++ // Progress tracker
+				b.Progress.Fetched()
+				b.Progress.Queued()
 				if b.numFetched%common.StatusUpdateFrequency == 0 {
 					b.BootstrapConfig.Context.Log.Info("fetched %d vertices", b.numFetched)
 				}
@@ -163,10 +169,12 @@ func (b *bootstrapper) process(vtx avalanche.Vertex) error {
 					tx:          tx,
 				}); err == nil {
 					b.numBSBlockedTx.Inc()
+					b.Progress.Queued()
 				} else {
 					b.BootstrapConfig.Context.Log.Verbo("couldn't push to txBlocked: %s", err)
 				}
 			}
+			b.updateFetchedFraction(vtx)
 			for _, parent := range vtx.Parents() {
 				toProcess = append(toProcess, parent)
 			}
@@ -187,6 +195,25 @@ func (b *bootstrapper) process(vtx avalanche.Vertex) error {
 	return nil
 }
 
+// updateFetchedFraction estimates the fraction of the vertices to fetch that
+// have been fetched. Vertices are fetched from the accepted frontier towards
+// genesis, so the height of the lowest vertex fetched so far, relative to the
+// height of the highest, indicates how much of the DAG has been fetched.
+func (b *bootstrapper) updateFetchedFraction(vtx avalanche.Vertex) {
+	height := vtx.Height()
+	if b.numFetched <= 1 || height > b.maxFetchedHeight {
+		b.maxFetchedHeight = height
+	}
+	if b.numFetched <= 1 || height < b.minFetchedHeight {
+		b.minFetchedHeight = height
+	}
+	if b.maxFetchedHeight == 0 {
+		b.Progress.SetFetchedFraction(1)
+		return
+	}
+	b.Progress.SetFetchedFraction(float64(b.maxFetchedHeight-b.minFetchedHeight) / float64(b.maxFetchedHeight))
+}
+
 // MultiPut handles the receipt of multiple containers. Should be received in response to a GetAncestors message to [vdr]
 // with request ID [requestID]. Expects vtxs[0] to be the vertex requested in the corresponding GetAncestors.
 func (b *bootstrapper) MultiPut(vdr ids.ShortID, requestID uint32, vtxs [][]byte) error {
@@ -266,6 +293,7 @@ func (b *bootstrapper) finish() error {
 		return nil
 	}
 	b.BootstrapConfig.Context.Log.Info("finished fetching vertices. executing transaction state transitions...")
+	b.Progress.DoneFetching()
 
 	if err := b.executeAll(b.TxBlocked, b.numBSBlockedTx); err != nil {
 		return err
@@ -287,6 +315,7 @@ func (b *bootstrapper) finish() error {
 		return err
 	}
 	b.finished = true
+	b.Progress.Bootstrapped()
 	return nil
 }
 
@@ -303,6 +332,7 @@ func (b *bootstrapper) executeAll(jobs *queue.Jobs, numBlocked prometheus.Gauge)
 			return err
 		}
 		numExecuted++
+		b.Progress.Executed()
 		if numExecuted%common.StatusUpdateFrequency == 0 { // Periodically print progress
 			b.BootstrapConfig.Context.Log.Info("executed %d operations", numExecuted)
 		}
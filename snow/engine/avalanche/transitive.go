@@ -11,6 +11,7 @@ import (
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
 	"github.com/ava-labs/gecko/snow/consensus/avalanche"
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/consensus/snowstorm"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/events"
@@ -81,6 +82,37 @@ func (t *Transitive) finishBootstrapping() error {
 	return nil
 }
 
+// ConsensusParameters returns the snowball parameters consensus is run with
+func (t *Transitive) ConsensusParameters() snowball.Parameters { return t.Params.Parameters }
+
+// SetConsensusParameters changes the snowball parameters consensus is run
+// with. Polls that are already running keep the parameters they were started
+// with. Returns an error if the parameters aren't valid.
+func (t *Transitive) SetConsensusParameters(params snowball.Parameters) error {
+	avalancheParams := t.Params
+	avalancheParams.Parameters = params
+	if t.bootstrapped {
+		if err := t.Consensus.SetParameters(avalancheParams); err != nil {
+			return err
+		}
+	} else if err := avalancheParams.Valid(); err != nil {
+		return err
+	}
+	t.Params = avalancheParams
+	return nil
+}
+
+// AcceptLatencies returns how long each of the most recently accepted
+// transactions was processing for, from being issued to being accepted, oldest
+// first. Transactions executed while bootstrapping weren't issued, so none are
+// returned until bootstrapping has finished.
+func (t *Transitive) AcceptLatencies() []time.Duration {
+	if !t.bootstrapped {
+		return nil
+	}
+	return t.Consensus.AcceptLatencies()
+}
+
 // Gossip implements the Engine interface
 func (t *Transitive) Gossip() error {
 	edge := t.Config.State.Edge()
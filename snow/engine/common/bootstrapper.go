@@ -37,6 +37,9 @@ type Bootstrapper struct {
 	acceptedVotes   map[[32]byte]uint64
 
 	RequestID uint32
+
+	// Progress tracks how far along bootstrapping is
+	Progress ProgressTracker
 }
 
 // Initialize implements the Engine interface.
@@ -68,6 +71,9 @@ func (b *Bootstrapper) Startup() error {
 	return nil
 }
 
+// BootstrapProgress returns how far along bootstrapping is
+func (b *Bootstrapper) BootstrapProgress() BootstrapProgress { return b.Progress.Progress() }
+
 // GetAcceptedFrontier implements the Engine interface.
 func (b *Bootstrapper) GetAcceptedFrontier(validatorID ids.ShortID, requestID uint32) error {
 	b.Sender.AcceptedFrontier(validatorID, requestID, b.Bootstrapable.CurrentAcceptedFrontier())
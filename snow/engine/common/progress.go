@@ -0,0 +1,140 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"sync"
+)
+
+// BootstrapProgress describes how far along a chain is in bootstrapping.
+//
+// Bootstrapping first fetches containers, then executes them. Fetching is
+// counted as the first half of bootstrapping and executing as the second half.
+type BootstrapProgress struct {
+	// True iff bootstrapping has finished
+	Bootstrapped bool
+
+	// Number of containers that have been fetched
+	Fetched uint64
+
+	// Estimated number of containers that still need to be fetched, or once
+	// fetching has finished, that still need to be executed
+	Remaining uint64
+
+	// True iff Remaining and Percent are estimated. False while fetching, until
+	// there's enough known about the chain to estimate how much of it remains
+	// to be fetched.
+	Estimated bool
+
+	// Estimated percentage of bootstrapping that has been completed, between 0
+	// and 100
+	Percent float64
+}
+
+// ProgressTracker tracks the progress of bootstrapping. It may be read from
+// any goroutine, including while the chain's lock is held by the bootstrapper.
+type ProgressTracker struct {
+	lock sync.Mutex
+
+	fetched uint64
+	// estimated fraction of the containers to fetch that have been fetched, or
+	// 0 if it isn't known
+	fetchedFraction float64
+	doneFetching    bool
+
+	toExecute    uint64
+	executed     uint64
+	bootstrapped bool
+}
+
+// Fetched records that a container was fetched
+func (p *ProgressTracker) Fetched() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.fetched++
+}
+
+// SetFetchedFraction records an estimate of the fraction of the containers to
+// fetch that have been fetched. Estimates smaller than the previous estimate
+// are ignored, so the reported progress doesn't go backwards.
+func (p *ProgressTracker) SetFetchedFraction(fraction float64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if fraction > 1 {
+		fraction = 1
+	}
+	if fraction > p.fetchedFraction {
+		p.fetchedFraction = fraction
+	}
+}
+
+// Queued records that a job that will be executed once fetching finishes was
+// queued
+func (p *ProgressTracker) Queued() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.toExecute++
+}
+
+// DoneFetching records that every container has been fetched
+func (p *ProgressTracker) DoneFetching() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.doneFetching = true
+}
+
+// Executed records that a queued job was executed
+func (p *ProgressTracker) Executed() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.executed++
+	if p.executed > p.toExecute {
+		// Jobs queued before a restart weren't counted when they were queued
+		p.toExecute = p.executed
+	}
+}
+
+// Bootstrapped records that bootstrapping has finished
+func (p *ProgressTracker) Bootstrapped() {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.bootstrapped = true
+}
+
+// Progress returns the current progress of bootstrapping
+func (p *ProgressTracker) Progress() BootstrapProgress {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	progress := BootstrapProgress{
+		Bootstrapped: p.bootstrapped,
+		Fetched:      p.fetched,
+	}
+	switch {
+	case p.bootstrapped:
+		progress.Percent = 100
+		progress.Estimated = true
+	case p.doneFetching:
+		executedFraction := 1.0
+		if p.toExecute > 0 {
+			executedFraction = float64(p.executed) / float64(p.toExecute)
+		}
+		progress.Percent = 50 + 50*executedFraction
+		progress.Remaining = p.toExecute - p.executed
+		progress.Estimated = true
+	case p.fetchedFraction > 0:
+		progress.Percent = 50 * p.fetchedFraction
+		if estimate := uint64(float64(p.fetched) / p.fetchedFraction); estimate > p.fetched {
+			progress.Remaining = estimate - p.fetched
+		}
+		progress.Estimated = true
+	}
+	return progress
+}
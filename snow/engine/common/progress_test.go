@@ -0,0 +1,62 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProgressTracker(t *testing.T) {
+	p := ProgressTracker{}
+
+	progress := p.Progress()
+	assert.False(t, progress.Bootstrapped, "shouldn't have finished bootstrapping")
+	assert.Equal(t, 0.0, progress.Percent, "shouldn't have made progress")
+	assert.False(t, progress.Estimated, "shouldn't have estimated the progress")
+
+	for i := 0; i < 4; i++ {
+		p.Fetched()
+		p.Queued()
+	}
+	p.SetFetchedFraction(.5)
+	progress = p.Progress()
+	assert.Equal(t, uint64(4), progress.Fetched, "should have fetched 4 containers")
+	assert.True(t, progress.Estimated, "should have estimated the progress")
+	assert.Equal(t, uint64(4), progress.Remaining, "should have estimated 4 containers left to fetch")
+	assert.Equal(t, 25.0, progress.Percent, "should be halfway through fetching")
+
+	p.Fetched()
+	p.Queued()
+	p.SetFetchedFraction(.625)
+	progress = p.Progress()
+	assert.Equal(t, uint64(3), progress.Remaining, "should have estimated 3 containers left to fetch")
+
+	p.SetFetchedFraction(.25)
+	progress = p.Progress()
+	assert.Equal(t, 31.25, progress.Percent, "shouldn't have gone backwards")
+
+	p.DoneFetching()
+	progress = p.Progress()
+	assert.Equal(t, 50.0, progress.Percent, "should have finished fetching")
+	assert.Equal(t, uint64(5), progress.Remaining, "should have 5 containers to execute")
+
+	p.Executed()
+	progress = p.Progress()
+	assert.Equal(t, 60.0, progress.Percent, "should have executed a fifth of the containers")
+	assert.Equal(t, uint64(4), progress.Remaining, "should have 4 containers to execute")
+
+	for i := 0; i < 4; i++ {
+		p.Executed()
+	}
+	progress = p.Progress()
+	assert.Equal(t, 100.0, progress.Percent, "should have executed every container")
+	assert.Equal(t, uint64(0), progress.Remaining, "shouldn't have containers to execute")
+
+	p.Bootstrapped()
+	progress = p.Progress()
+	assert.True(t, progress.Bootstrapped, "should have finished bootstrapping")
+	assert.Equal(t, 100.0, progress.Percent, "should have finished bootstrapping")
+}
@@ -4,7 +4,11 @@
 package common
 
 import (
+	"context"
+	"time"
+
 	"github.com/ava-labs/gecko/database"
+	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
 )
 
@@ -78,3 +82,50 @@ type StaticVM interface {
 	// genesis bytes this VM can interpret.
 	CreateStaticHandlers() map[string]*HTTPHandler
 }
+
+// StateVerifier can be implemented by a VM to let operators check the
+// consistency of its stored state, such as after an unclean shutdown.
+type StateVerifier interface {
+	// Checks that the VM's stored state is consistent, without modifying it.
+	// Returns an error describing the inconsistency if it isn't. Should stop
+	// early and return [ctx]'s error once [ctx] is done.
+	VerifyState(ctx context.Context) error
+}
+
+// MempoolStats describes the transactions a VM received that haven't been
+// issued into a block yet
+type MempoolStats struct {
+	// Number of pending transactions, and their total size in bytes
+	Txs   int
+	Bytes int
+
+	// When the oldest pending transaction was received. Zero if there are no
+	// pending transactions.
+	Oldest time.Time
+}
+
+// MempoolReporter can be implemented by a VM that keeps the transactions it
+// receives until they're issued into a block, to let operators check whether
+// transactions are backing up.
+type MempoolReporter interface {
+	MempoolStats() MempoolStats
+}
+
+// PendingTxReporter can be implemented by a VM that records when it first saw
+// each transaction that hasn't been decided yet, to let operators check
+// whether a transaction is stuck.
+type PendingTxReporter interface {
+	// OldestPendingTx returns the ID of the oldest transaction that was seen
+	// but hasn't been accepted or rejected, and when it was first seen.
+	// Returns false if every transaction that was seen has been decided.
+	OldestPendingTx() (ids.ID, time.Time, bool)
+}
+
+// TxRejectionReporter can be implemented by a VM that rejects transactions it
+// receives before they're issued to consensus, to let operators see why
+// submissions are being rejected.
+type TxRejectionReporter interface {
+	// TxRejections returns the number of transactions rejected since the VM
+	// started, keyed by why they were rejected
+	TxRejections() map[string]uint64
+}
@@ -27,6 +27,16 @@ type BootstrapConfig struct {
 	Bootstrapped func()
 }
 
+// HeightedBlock is a block that knows its height. If a chain's blocks are
+// HeightedBlocks, bootstrapping estimates how many blocks remain to be fetched
+// from their heights.
+type HeightedBlock interface {
+	snowman.Block
+
+	// Height returns the number of ancestors of this block
+	Height() uint64
+}
+
 type bootstrapper struct {
 	BootstrapConfig
 	metrics
@@ -38,6 +48,11 @@ type bootstrapper struct {
 	// Number of blocks fetched
 	numFetched uint32
 
+	// greatest and least heights of the blocks fetched so far, and the height
+	// of the accepted block fetching stopped at, used to estimate how many
+	// blocks remain to be fetched
+	maxFetchedHeight, minFetchedHeight, acceptedHeight uint64
+
 	// tracks which validators were asked for which containers in which requests
 	outstandingRequests common.Requests
 
@@ -189,7 +204,10 @@ func (b *bootstrapper) process(blk snowman.Block) error {
 			blk:         blk,
 		}); err == nil {
 			b.numBlocked.Inc()
-			b.numFetched++                                      // Progress tracker
+			b.numFetched++ // Progress tracker
+			b.Progress.Fetched()
+			b.Progress.Queued()
+			b.updateFetchedFraction(blk)
 			if b.numFetched%common.StatusUpdateFrequency == 0 { // Periodically print progress
 				b.BootstrapConfig.Context.Log.Info("fetched %d blocks", b.numFetched)
 			}
@@ -206,6 +224,10 @@ func (b *bootstrapper) process(blk snowman.Block) error {
 	}
 
 	switch status := blk.Status(); status {
+	case choices.Accepted:
+		if blk, ok := blk.(HeightedBlock); ok && blk.Height() > b.acceptedHeight {
+			b.acceptedHeight = blk.Height()
+		}
 	case choices.Unknown:
 		if err := b.fetch(blkID); err != nil {
 			return err
@@ -220,11 +242,38 @@ func (b *bootstrapper) process(blk snowman.Block) error {
 	return nil
 }
 
+// updateFetchedFraction estimates the fraction of the blocks to fetch that have
+// been fetched, if the chain's blocks know their height. Blocks are fetched
+// from the accepted frontier towards the last accepted block, so the heights
+// of the highest and lowest blocks fetched so far, relative to the height of
+// the last accepted block, indicate how much of the chain has been fetched.
+// Until fetching reaches an accepted block, the chain is assumed to have been
+// fetched from genesis.
+func (b *bootstrapper) updateFetchedFraction(blk snowman.Block) {
+	heighted, ok := blk.(HeightedBlock)
+	if !ok {
+		return
+	}
+	height := heighted.Height()
+	if b.numFetched <= 1 || height > b.maxFetchedHeight {
+		b.maxFetchedHeight = height
+	}
+	if b.numFetched <= 1 || height < b.minFetchedHeight {
+		b.minFetchedHeight = height
+	}
+	if b.maxFetchedHeight <= b.acceptedHeight {
+		return
+	}
+	fetched := b.maxFetchedHeight - b.minFetchedHeight + 1
+	b.Progress.SetFetchedFraction(float64(fetched) / float64(b.maxFetchedHeight-b.acceptedHeight))
+}
+
 func (b *bootstrapper) finish() error {
 	if b.finished {
 		return nil
 	}
 	b.BootstrapConfig.Context.Log.Info("bootstrapping finished fetching blocks. executing state transitions...")
+	b.Progress.DoneFetching()
 
 	if err := b.executeAll(b.Blocked, b.numBlocked); err != nil {
 		return err
@@ -240,6 +289,7 @@ func (b *bootstrapper) finish() error {
 		return err
 	}
 	b.finished = true
+	b.Progress.Bootstrapped()
 
 	if b.Bootstrapped != nil {
 		b.Bootstrapped()
@@ -258,6 +308,7 @@ func (b *bootstrapper) executeAll(jobs *queue.Jobs, numBlocked prometheus.Gauge)
 			return err
 		}
 		numExecuted++
+		b.Progress.Executed()
 		if numExecuted%common.StatusUpdateFrequency == 0 { // Periodically print progress
 			b.BootstrapConfig.Context.Log.Info("executed %d blocks", numExecuted)
 		}
@@ -413,6 +413,92 @@ func TestBootstrapperPartialFetch(t *testing.T) {
 	}
 }
 
+func TestBootstrapperProgress(t *testing.T) {
+	config, peerID, sender, vm := newConfig(t)
+
+	blkID0 := ids.Empty.Prefix(0)
+	blkID1 := ids.Empty.Prefix(1)
+	blkID2 := ids.Empty.Prefix(2)
+	blkID3 := ids.Empty.Prefix(3)
+
+	blkBytes1 := []byte{1}
+	blkBytes2 := []byte{2}
+
+	blk0 := &Blk{
+		id:     blkID0,
+		height: 0,
+		status: choices.Accepted,
+	}
+	blk1 := &Blk{
+		parent: blk0,
+		id:     blkID1,
+		height: 1,
+		status: choices.Unknown,
+		bytes:  blkBytes1,
+	}
+	blk2 := &Blk{
+		parent: blk1,
+		id:     blkID2,
+		height: 2,
+		status: choices.Unknown,
+		bytes:  blkBytes2,
+	}
+	blk3 := &Blk{
+		parent: blk2,
+		id:     blkID3,
+		height: 3,
+		status: choices.Processing,
+	}
+
+	bs := bootstrapper{}
+	bs.metrics.Initialize(config.Context.Log, fmt.Sprintf("gecko_%s", config.Context.ChainID), prometheus.NewRegistry())
+	bs.Initialize(config)
+	bs.onFinished = func() error { return nil }
+
+	acceptedIDs := ids.Set{}
+	acceptedIDs.Add(blkID3)
+
+	vm.GetBlockF = func(blkID ids.ID) (snowman.Block, error) {
+		switch {
+		case blkID.Equals(blkID3):
+			return blk3, nil
+		default:
+			return nil, errUnknownBlock
+		}
+	}
+	vm.ParseBlockF = func(blkBytes []byte) (snowman.Block, error) {
+		switch {
+		case bytes.Equal(blkBytes, blkBytes1):
+			blk1.status = choices.Processing
+			return blk1, nil
+		case bytes.Equal(blkBytes, blkBytes2):
+			blk2.status = choices.Processing
+			return blk2, nil
+		}
+		t.Fatal(errUnknownBlock)
+		return nil, errUnknownBlock
+	}
+
+	requestID := new(uint32)
+	sender.GetAncestorsF = func(vdr ids.ShortID, reqID uint32, vtxID ids.ID) { *requestID = reqID }
+
+	vm.CantBootstrapping = false
+
+	if err := bs.ForceAccepted(acceptedIDs); err != nil { // should request blk2
+		t.Fatal(err)
+	}
+	if progress := bs.BootstrapProgress(); !progress.Estimated || progress.Fetched != 1 || progress.Remaining != 2 {
+		t.Fatalf("expected 1 block to have been fetched and 2 to remain but got %+v", progress)
+	}
+
+	if err := bs.MultiPut(peerID, *requestID, [][]byte{blkBytes2}); err != nil { // should request blk1
+		t.Fatal(err)
+	}
+	if progress := bs.BootstrapProgress(); !progress.Estimated || progress.Fetched != 2 || progress.Remaining != 1 {
+		t.Fatalf("expected 2 blocks to have been fetched and 1 to remain but got %+v", progress)
+	}
+}
+
 // There are multiple needed blocks and MultiPut returns all at once
 func TestBootstrapperMultiPut(t *testing.T) {
 	config, peerID, sender, vm := newConfig(t)
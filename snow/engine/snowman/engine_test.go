@@ -39,6 +39,7 @@ func (b *Blk) Reject() error          { b.status = choices.Rejected; return nil
 func (b *Blk) Status() choices.Status { return b.status }
 func (b *Blk) Verify() error          { return b.validity }
 func (b *Blk) Bytes() []byte          { return b.bytes }
+func (b *Blk) Height() uint64         { return uint64(b.height) }
 
 type sortBks []*Blk
 
@@ -10,6 +10,7 @@ import (
 	"github.com/ava-labs/gecko/network"
 	"github.com/ava-labs/gecko/snow"
 	"github.com/ava-labs/gecko/snow/choices"
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/consensus/snowman"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/events"
@@ -109,6 +110,36 @@ func (t *Transitive) finishBootstrapping() error {
 	return nil
 }
 
+// ConsensusParameters returns the snowball parameters consensus is run with
+func (t *Transitive) ConsensusParameters() snowball.Parameters { return t.Params }
+
+// SetConsensusParameters changes the snowball parameters consensus is run
+// with. Polls that are already running keep the parameters they were started
+// with. Returns an error if the parameters aren't valid.
+func (t *Transitive) SetConsensusParameters(params snowball.Parameters) error {
+	if t.bootstrapped {
+		if err := t.Consensus.SetParameters(params); err != nil {
+			return err
+		}
+	} else if err := params.Valid(); err != nil {
+		return err
+	}
+	t.Params = params
+	t.polls.alpha = params.Alpha
+	return nil
+}
+
+// AcceptLatencies returns how long each of the most recently accepted blocks
+// was processing for, from being issued to being accepted, oldest first.
+// Blocks executed while bootstrapping weren't issued, so none are returned
+// until bootstrapping has finished.
+func (t *Transitive) AcceptLatencies() []time.Duration {
+	if !t.bootstrapped {
+		return nil
+	}
+	return t.Consensus.AcceptLatencies()
+}
+
 // Gossip implements the Engine interface
 func (t *Transitive) Gossip() error {
 	blkID := t.Config.VM.LastAccepted()
@@ -84,6 +84,29 @@ func TestEngineShutdown(t *testing.T) {
 	}
 }
 
+func TestEngineSetConsensusParameters(t *testing.T) {
+	_, _, _, _, te, _ := setup(t)
+
+	params := te.ConsensusParameters()
+	params.BetaRogue = params.BetaVirtuous - 1
+	if err := te.SetConsensusParameters(params); err == nil {
+		t.Fatalf("Should have refused a rogue beta smaller than the virtuous beta")
+	}
+
+	params = te.ConsensusParameters()
+	params.BetaVirtuous++
+	params.BetaRogue++
+	if err := te.SetConsensusParameters(params); err != nil {
+		t.Fatal(err)
+	}
+	if p := te.Consensus.Parameters(); p != params {
+		t.Fatalf("Consensus should be using the new parameters")
+	}
+	if p := te.ConsensusParameters(); p != params {
+		t.Fatalf("Wrong returned parameters")
+	}
+}
+
 func TestEngineAdd(t *testing.T) {
 	vdr, _, sender, vm, te, _ := setup(t)
 
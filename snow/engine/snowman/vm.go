@@ -55,3 +55,13 @@ type ChainVM interface {
 	// returned.
 	LastAccepted() ids.ID
 }
+
+// TxBlock is a block that knows how many transactions it contains. If a
+// chain's blocks are TxBlocks, the transactions the chain decides on can be
+// counted, rather than only its blocks.
+type TxBlock interface {
+	snowman.Block
+
+	// NumTxs returns the number of transactions this block decides on
+	NumTxs() int
+}
@@ -4,13 +4,17 @@
 package router
 
 import (
+	"fmt"
 	"sync"
 	"time"
 
 	"github.com/ava-labs/gecko/utils/formatting"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
+	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
+	"github.com/ava-labs/gecko/utils/labels"
 	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/utils/timer"
 )
@@ -48,7 +52,7 @@ func (sr *ChainRouter) Initialize(
 	sr.gossiper = timer.NewRepeater(sr.Gossip, gossipFrequency)
 	sr.closeTimeout = closeTimeout
 
-	go log.RecoverAndPanic(sr.gossiper.Dispatch)
+	go labels.Do(func() { log.RecoverAndPanic(sr.gossiper.Dispatch) }, labels.Subsystem, "router")
 }
 
 // AddChain registers the specified chain so that incoming
@@ -87,6 +91,109 @@ func (sr *ChainRouter) RemoveChain(chainID ids.ID) {
 	ticker.Stop()
 }
 
+// ChainQueues returns the status of the message queue of every registered chain
+func (sr *ChainRouter) ChainQueues() []ChainQueue {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	queues := make([]ChainQueue, 0, len(sr.chains))
+	for _, chain := range sr.chains {
+		depth, oldest := chain.QueueStatus()
+		queues = append(queues, ChainQueue{
+			ChainID: chain.Context().ChainID,
+			Depth:   depth,
+			Oldest:  oldest,
+		})
+	}
+	return queues
+}
+
+// BootstrapProgress returns how far along the chain with ID [chainID] is in
+// bootstrapping. Returns false if the chain isn't registered or its consensus
+// engine doesn't report its progress.
+func (sr *ChainRouter) BootstrapProgress(chainID ids.ID) (common.BootstrapProgress, bool) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	chain, exists := sr.chains[chainID.Key()]
+	if !exists {
+		return common.BootstrapProgress{}, false
+	}
+	return chain.BootstrapProgress()
+}
+
+// CurrentAcceptedFrontier returns the IDs of the accepted containers of the
+// chain with ID [chainID] that have no accepted children. Returns false if the
+// chain isn't registered or its consensus engine doesn't report its accepted
+// frontier.
+func (sr *ChainRouter) CurrentAcceptedFrontier(chainID ids.ID) (ids.Set, bool) {
+	chain, exists := sr.chain(chainID)
+	if !exists {
+		return nil, false
+	}
+	return chain.CurrentAcceptedFrontier()
+}
+
+// AcceptLatencies returns how long each of the decisions the chain with ID
+// [chainID] most recently accepted was processing for, oldest first. Returns
+// false if the chain isn't registered or its consensus engine doesn't report
+// its latencies.
+func (sr *ChainRouter) AcceptLatencies(chainID ids.ID) ([]time.Duration, bool) {
+	chain, exists := sr.chain(chainID)
+	if !exists {
+		return nil, false
+	}
+	return chain.AcceptLatencies()
+}
+
+// ConsensusParameters returns the snowball parameters the chain with ID
+// [chainID] runs consensus with. Returns false if the chain isn't registered or
+// its consensus engine doesn't report its parameters.
+func (sr *ChainRouter) ConsensusParameters(chainID ids.ID) (snowball.Parameters, bool) {
+	chain, exists := sr.chain(chainID)
+	if !exists {
+		return snowball.Parameters{}, false
+	}
+	return chain.ConsensusParameters()
+}
+
+// SetConsensusParameters changes the snowball parameters the chain with ID
+// [chainID] runs consensus with
+func (sr *ChainRouter) SetConsensusParameters(chainID ids.ID, params snowball.Parameters) error {
+	chain, exists := sr.chain(chainID)
+	if !exists {
+		return fmt.Errorf("chain %s isn't registered", chainID)
+	}
+	return chain.SetConsensusParameters(params)
+}
+
+// chain returns the handler of the chain with ID [chainID], if it's
+// registered.
+//
+// The handler's methods that grab the chain's lock must be called after the
+// router's lock is released. A chain that holds its lock can register another
+// chain, such as when the P-Chain accepts a CreateChainTx, so holding the
+// router's lock while waiting on the chain's would deadlock.
+func (sr *ChainRouter) chain(chainID ids.ID) (*Handler, bool) {
+	sr.lock.RLock()
+	defer sr.lock.RUnlock()
+
+	chain, exists := sr.chains[chainID.Key()]
+	return chain, exists
+}
+
+// Latencies returns a histogram of the round trip times of requests to the
+// validator with ID [validatorID] that were responded to
+func (sr *ChainRouter) Latencies(validatorID ids.ShortID) timeout.Histogram {
+	return sr.timeouts.Latencies(validatorID)
+}
+
+// RequestOutcomes returns, for each type of request that was sent, the number
+// of requests that were responded to and that timed out recently
+func (sr *ChainRouter) RequestOutcomes() map[timeout.RequestType]timeout.Outcomes {
+	return sr.timeouts.Outcomes()
+}
+
 // GetAcceptedFrontier routes an incoming GetAcceptedFrontier request from the
 // validator with ID [validatorID]  to the consensus engine working on the
 // chain with ID [chainID]
@@ -110,7 +217,7 @@ func (sr *ChainRouter) AcceptedFrontier(validatorID ids.ShortID, chainID ids.ID,
 
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		if chain.AcceptedFrontier(validatorID, requestID, containerIDs) {
-			sr.timeouts.Cancel(validatorID, chainID, requestID)
+			sr.timeouts.Respond(validatorID, chainID, requestID)
 		}
 	} else {
 		sr.log.Debug("AcceptedFrontier(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, containerIDs)
@@ -136,7 +243,7 @@ func (sr *ChainRouter) GetAcceptedFrontierFailed(validatorID ids.ShortID, chainI
 	} else {
 		sr.log.Error("GetAcceptedFrontierFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
 	}
-	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	sr.timeouts.Fail(validatorID, chainID, requestID)
 }
 
 // GetAccepted routes an incoming GetAccepted request from the
@@ -162,7 +269,7 @@ func (sr *ChainRouter) Accepted(validatorID ids.ShortID, chainID ids.ID, request
 
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		if chain.Accepted(validatorID, requestID, containerIDs) {
-			sr.timeouts.Cancel(validatorID, chainID, requestID)
+			sr.timeouts.Respond(validatorID, chainID, requestID)
 		}
 	} else {
 		sr.log.Debug("Accepted(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, containerIDs)
@@ -187,7 +294,7 @@ func (sr *ChainRouter) GetAcceptedFailed(validatorID ids.ShortID, chainID ids.ID
 	} else {
 		sr.log.Error("GetAcceptedFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
 	}
-	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	sr.timeouts.Fail(validatorID, chainID, requestID)
 }
 
 // GetAncestors routes an incoming GetAncestors message from the validator with ID [validatorID]
@@ -214,7 +321,7 @@ func (sr *ChainRouter) MultiPut(validatorID ids.ShortID, chainID ids.ID, request
 	// message we set a timeout. Since we got a response, cancel the timeout.
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		if chain.MultiPut(validatorID, requestID, containers) {
-			sr.timeouts.Cancel(validatorID, chainID, requestID)
+			sr.timeouts.Respond(validatorID, chainID, requestID)
 		}
 	} else {
 		sr.log.Debug("MultiPut(%s, %s, %d, %d) dropped due to unknown chain", validatorID, chainID, requestID, len(containers))
@@ -238,7 +345,7 @@ func (sr *ChainRouter) GetAncestorsFailed(validatorID ids.ShortID, chainID ids.I
 	} else {
 		sr.log.Error("GetAncestorsFailed(%s, %s, %d, %d) dropped due to unknown chain", validatorID, chainID, requestID)
 	}
-	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	sr.timeouts.Fail(validatorID, chainID, requestID)
 }
 
 // Get routes an incoming Get request from the validator with ID [validatorID]
@@ -264,7 +371,7 @@ func (sr *ChainRouter) Put(validatorID ids.ShortID, chainID ids.ID, requestID ui
 	// message we set a timeout. Since we got a response, cancel the timeout.
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		if chain.Put(validatorID, requestID, containerID, container) {
-			sr.timeouts.Cancel(validatorID, chainID, requestID)
+			sr.timeouts.Respond(validatorID, chainID, requestID)
 		}
 	} else {
 		sr.log.Debug("Put(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, containerID)
@@ -289,7 +396,7 @@ func (sr *ChainRouter) GetFailed(validatorID ids.ShortID, chainID ids.ID, reques
 	} else {
 		sr.log.Error("GetFailed(%s, %s, %d) dropped due to unknown chain", validatorID, chainID, requestID)
 	}
-	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	sr.timeouts.Fail(validatorID, chainID, requestID)
 }
 
 // PushQuery routes an incoming PushQuery request from the validator with ID [validatorID]
@@ -328,7 +435,7 @@ func (sr *ChainRouter) Chits(validatorID ids.ShortID, chainID ids.ID, requestID
 	// Cancel timeout we set when sent the message asking for these Chits
 	if chain, exists := sr.chains[chainID.Key()]; exists {
 		if chain.Chits(validatorID, requestID, votes) {
-			sr.timeouts.Cancel(validatorID, chainID, requestID)
+			sr.timeouts.Respond(validatorID, chainID, requestID)
 		}
 	} else {
 		sr.log.Debug("Chits(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID, votes)
@@ -352,7 +459,7 @@ func (sr *ChainRouter) QueryFailed(validatorID ids.ShortID, chainID ids.ID, requ
 	} else {
 		sr.log.Error("QueryFailed(%s, %s, %d, %s) dropped due to unknown chain", validatorID, chainID, requestID)
 	}
-	sr.timeouts.Cancel(validatorID, chainID, requestID)
+	sr.timeouts.Fail(validatorID, chainID, requestID)
 }
 
 // Shutdown shuts down this router
@@ -4,14 +4,19 @@
 package router
 
 import (
+	"errors"
+	"sync"
 	"time"
 
 	"github.com/ava-labs/gecko/ids"
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
 	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/prometheus/client_golang/prometheus"
 )
 
+var errParametersUnsupported = errors.New("the chain's consensus engine doesn't support changing its parameters")
+
 // Handler passes incoming messages from the network to the consensus engine
 // (Actually, it receives the incoming messages from a ChainRouter, but same difference)
 type Handler struct {
@@ -22,6 +27,13 @@ type Handler struct {
 	engine  common.Engine
 	msgChan <-chan common.Message
 
+	// queueLock ensures that [queueTimes] is kept in the same order as [msgs]
+	queueLock sync.Mutex
+	// queueTimes contains the time each message in [msgs] was queued at,
+	// oldest first. A shutdown message is included while it waits for room
+	// in [msgs].
+	queueTimes []time.Time
+
 	toClose func()
 }
 
@@ -60,6 +72,7 @@ func (h *Handler) Dispatch() {
 				return
 			}
 			h.metrics.pending.Dec()
+			h.dequeued()
 			if closing {
 				log.Debug("dropping message due to closing:\n%s", msg)
 				continue
@@ -341,17 +354,141 @@ func (h *Handler) Notify(msg common.Message) bool {
 
 // Shutdown shuts down the dispatcher
 func (h *Handler) Shutdown() {
+	// The message is counted before it's sent, as sending it blocks until
+	// there's room in [msgs], and [queueLock] mustn't be held meanwhile
+	h.queueLock.Lock()
 	h.metrics.pending.Inc()
+	h.queueTimes = append(h.queueTimes, time.Now())
+	h.queueLock.Unlock()
+
 	h.msgs <- message{messageType: shutdownMsg}
 }
 
+// QueueStatus returns the number of messages waiting to be passed to the
+// consensus engine and the time the oldest of them was queued at. If no
+// messages are waiting, the returned time is the zero time.
+func (h *Handler) QueueStatus() (int, time.Time) {
+	h.queueLock.Lock()
+	defer h.queueLock.Unlock()
+
+	if len(h.queueTimes) == 0 {
+		return 0, time.Time{}
+	}
+	return len(h.queueTimes), h.queueTimes[0]
+}
+
+// BootstrapProgress returns how far along the consensus engine is in
+// bootstrapping. Returns false if the engine doesn't report its progress.
+//
+// The chain's lock isn't grabbed, so this can be called while the engine is
+// executing bootstrapped containers.
+func (h *Handler) BootstrapProgress() (common.BootstrapProgress, bool) {
+	engine, ok := h.engine.(bootstrapProgressReporter)
+	if !ok {
+		return common.BootstrapProgress{}, false
+	}
+	return engine.BootstrapProgress(), true
+}
+
+type bootstrapProgressReporter interface {
+	BootstrapProgress() common.BootstrapProgress
+}
+
+// CurrentAcceptedFrontier returns the IDs of the accepted containers that have
+// no accepted children. Returns false if the engine doesn't report its
+// accepted frontier.
+func (h *Handler) CurrentAcceptedFrontier() (ids.Set, bool) {
+	engine, ok := h.engine.(acceptedFrontierReporter)
+	if !ok {
+		return nil, false
+	}
+
+	ctx := h.engine.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return engine.CurrentAcceptedFrontier(), true
+}
+
+type acceptedFrontierReporter interface {
+	CurrentAcceptedFrontier() ids.Set
+}
+
+// AcceptLatencies returns how long each of the most recently accepted
+// decisions was processing for, from being issued to being accepted, oldest
+// first. Returns false if the engine doesn't report its latencies.
+func (h *Handler) AcceptLatencies() ([]time.Duration, bool) {
+	engine, ok := h.engine.(acceptLatencyReporter)
+	if !ok {
+		return nil, false
+	}
+
+	ctx := h.engine.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return engine.AcceptLatencies(), true
+}
+
+type acceptLatencyReporter interface {
+	AcceptLatencies() []time.Duration
+}
+
+// ConsensusParameters returns the snowball parameters the engine runs
+// consensus with. Returns false if the engine doesn't report its parameters.
+func (h *Handler) ConsensusParameters() (snowball.Parameters, bool) {
+	engine, ok := h.engine.(consensusParameterizer)
+	if !ok {
+		return snowball.Parameters{}, false
+	}
+
+	ctx := h.engine.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return engine.ConsensusParameters(), true
+}
+
+// SetConsensusParameters changes the snowball parameters the engine runs
+// consensus with
+func (h *Handler) SetConsensusParameters(params snowball.Parameters) error {
+	engine, ok := h.engine.(consensusParameterizer)
+	if !ok {
+		return errParametersUnsupported
+	}
+
+	ctx := h.engine.Context()
+	ctx.Lock.Lock()
+	defer ctx.Lock.Unlock()
+
+	return engine.SetConsensusParameters(params)
+}
+
+type consensusParameterizer interface {
+	ConsensusParameters() snowball.Parameters
+	SetConsensusParameters(snowball.Parameters) error
+}
+
 func (h *Handler) sendMsg(msg message) bool {
+	h.queueLock.Lock()
+	defer h.queueLock.Unlock()
+
 	select {
 	case h.msgs <- msg:
 		h.metrics.pending.Inc()
+		h.queueTimes = append(h.queueTimes, time.Now())
 		return true
 	default:
 		h.metrics.dropped.Inc()
 		return false
 	}
 }
+
+// dequeued records that the oldest queued message was removed from [msgs]
+func (h *Handler) dequeued() {
+	h.queueLock.Lock()
+	defer h.queueLock.Unlock()
+
+	h.queueTimes[0] = time.Time{}
+	h.queueTimes = h.queueTimes[1:]
+}
@@ -0,0 +1,65 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package router
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+// TestHandlerShutdownFullQueue checks that messages can still be sent to, and
+// the queue inspected on, a handler that's waiting for room in its queue to
+// shut down
+func TestHandlerShutdownFullQueue(t *testing.T) {
+	ctx := snow.DefaultContextTest()
+	engine := &common.EngineTest{T: t}
+	engine.Default(false)
+	engine.ContextF = func() *snow.Context { return ctx }
+
+	handler := &Handler{}
+	handler.Initialize(engine, make(chan common.Message), 1, "", prometheus.NewRegistry())
+
+	if !handler.Gossip() {
+		t.Fatal("expected the gossip message to be queued")
+	}
+
+	shutdown := make(chan struct{})
+	go func() {
+		handler.Shutdown()
+		close(shutdown)
+	}()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		// Wait for the shutdown message to be waiting for room in the queue
+		for {
+			if numQueued, _ := handler.QueueStatus(); numQueued == 2 {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+		if handler.Gossip() {
+			t.Error("expected the gossip message to be dropped, as the queue is full")
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the handler was blocked while waiting to shut down")
+	}
+
+	go handler.Dispatch()
+	select {
+	case <-shutdown:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the shutdown message was never queued")
+	}
+}
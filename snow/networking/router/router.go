@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/consensus/snowball"
+	"github.com/ava-labs/gecko/snow/engine/common"
 	"github.com/ava-labs/gecko/snow/networking/timeout"
 	"github.com/ava-labs/gecko/utils/logging"
 )
@@ -19,6 +21,14 @@ type Router interface {
 
 	AddChain(chain *Handler)
 	RemoveChain(chainID ids.ID)
+	ChainQueues() []ChainQueue
+	BootstrapProgress(chainID ids.ID) (common.BootstrapProgress, bool)
+	CurrentAcceptedFrontier(chainID ids.ID) (ids.Set, bool)
+	AcceptLatencies(chainID ids.ID) ([]time.Duration, bool)
+	Latencies(validatorID ids.ShortID) timeout.Histogram
+	RequestOutcomes() map[timeout.RequestType]timeout.Outcomes
+	ConsensusParameters(chainID ids.ID) (snowball.Parameters, bool)
+	SetConsensusParameters(chainID ids.ID, params snowball.Parameters) error
 	Shutdown()
 	Initialize(
 		log logging.Logger,
@@ -28,6 +38,19 @@ type Router interface {
 	)
 }
 
+// ChainQueue describes the messages that are waiting to be processed by the
+// consensus engine of a chain
+type ChainQueue struct {
+	ChainID ids.ID
+
+	// Number of messages waiting to be processed
+	Depth int
+
+	// Time the oldest waiting message was queued at. The zero time if no
+	// messages are waiting.
+	Oldest time.Time
+}
+
 // ExternalRouter routes messages from the network to the
 // Handler of the consensus engine that the message is intended for
 type ExternalRouter interface {
@@ -38,7 +38,7 @@ func (s *Sender) GetAcceptedFrontier(validatorIDs ids.ShortSet, requestID uint32
 	validatorList := validatorIDs.List()
 	for _, validatorID := range validatorList {
 		vID := validatorID
-		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+		s.timeouts.RegisterRequest(timeout.GetAcceptedFrontierRequest, validatorID, s.ctx.ChainID, requestID, func() {
 			s.router.GetAcceptedFrontierFailed(vID, s.ctx.ChainID, requestID)
 		})
 	}
@@ -63,7 +63,7 @@ func (s *Sender) GetAccepted(validatorIDs ids.ShortSet, requestID uint32, contai
 	validatorList := validatorIDs.List()
 	for _, validatorID := range validatorList {
 		vID := validatorID
-		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+		s.timeouts.RegisterRequest(timeout.GetAcceptedRequest, validatorID, s.ctx.ChainID, requestID, func() {
 			s.router.GetAcceptedFailed(vID, s.ctx.ChainID, requestID)
 		})
 	}
@@ -87,7 +87,7 @@ func (s *Sender) Get(validatorID ids.ShortID, requestID uint32, containerID ids.
 	s.ctx.Log.Verbo("Sending Get to validator %s. RequestID: %d. ContainerID: %s", validatorID, requestID, containerID)
 	// Add a timeout -- if we don't get a response before the timeout expires,
 	// send this consensus engine a GetFailed message
-	s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+	s.timeouts.RegisterRequest(timeout.GetRequest, validatorID, s.ctx.ChainID, requestID, func() {
 		s.router.GetFailed(validatorID, s.ctx.ChainID, requestID)
 	})
 	s.sender.Get(validatorID, s.ctx.ChainID, requestID, containerID)
@@ -101,7 +101,7 @@ func (s *Sender) GetAncestors(validatorID ids.ShortID, requestID uint32, contain
 		go s.router.GetAncestorsFailed(validatorID, s.ctx.ChainID, requestID)
 		return
 	}
-	s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+	s.timeouts.RegisterRequest(timeout.GetAncestorsRequest, validatorID, s.ctx.ChainID, requestID, func() {
 		s.router.GetAncestorsFailed(validatorID, s.ctx.ChainID, requestID)
 	})
 	s.sender.GetAncestors(validatorID, s.ctx.ChainID, requestID, containerID)
@@ -142,7 +142,7 @@ func (s *Sender) PushQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 	validatorList := validatorIDs.List() // Convert set to list for easier iteration
 	for _, validatorID := range validatorList {
 		vID := validatorID
-		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+		s.timeouts.RegisterRequest(timeout.PushQueryRequest, validatorID, s.ctx.ChainID, requestID, func() {
 			s.router.QueryFailed(vID, s.ctx.ChainID, requestID)
 		})
 	}
@@ -167,7 +167,7 @@ func (s *Sender) PullQuery(validatorIDs ids.ShortSet, requestID uint32, containe
 	validatorList := validatorIDs.List() // Convert set to list for easier iteration
 	for _, validatorID := range validatorList {
 		vID := validatorID
-		s.timeouts.Register(validatorID, s.ctx.ChainID, requestID, func() {
+		s.timeouts.RegisterRequest(timeout.PullQueryRequest, validatorID, s.ctx.ChainID, requestID, func() {
 			s.router.QueryFailed(vID, s.ctx.ChainID, requestID)
 		})
 	}
@@ -0,0 +1,50 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timeout
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// Histogram counts round trip times in buckets
+type Histogram struct {
+	// Upper bounds of the buckets. The last bucket has no upper bound.
+	Bounds []time.Duration
+
+	// Number of round trips in each bucket. Has one more element than Bounds.
+	Counts []uint64
+}
+
+type histogram struct {
+	bounds []time.Duration
+	counts []uint64
+}
+
+func newHistogram() *histogram {
+	bounds := make([]time.Duration, len(timer.MillisecondsBuckets))
+	for i, bound := range timer.MillisecondsBuckets {
+		bounds[i] = time.Duration(bound) * time.Millisecond
+	}
+	return &histogram{
+		bounds: bounds,
+		counts: make([]uint64, len(bounds)+1),
+	}
+}
+
+func (h *histogram) observe(latency time.Duration) {
+	i := 0
+	for i < len(h.bounds) && latency > h.bounds[i] {
+		i++
+	}
+	h.counts[i]++
+}
+
+func (h *histogram) snapshot() Histogram {
+	return Histogram{
+		Bounds: append([]time.Duration(nil), h.bounds...),
+		Counts: append([]uint64(nil), h.counts...),
+	}
+}
@@ -4,6 +4,7 @@
 package timeout
 
 import (
+	"sync"
 	"time"
 
 	"github.com/ava-labs/gecko/ids"
@@ -13,7 +14,25 @@ import (
 )
 
 // Manager registers and fires timeouts for the snow API.
-type Manager struct{ tm timer.TimeoutManager }
+type Manager struct {
+	tm timer.TimeoutManager
+
+	lock sync.Mutex
+	// Maps a request to when it was registered, and its type
+	requests map[[32]byte]request
+	// Maps a validator to the round trip times of its responses
+	latencies map[[20]byte]*histogram
+	// Maps a type of request to the number of its requests that were
+	// responded to, that failed and that timed out
+	outcomes map[RequestType]outcomeMeters
+}
+
+// request is a request whose timeout is registered
+type request struct {
+	registered time.Time
+	// Empty if the request's outcome isn't recorded
+	requestType RequestType
+}
 
 // Initialize this timeout manager.
 //
@@ -22,20 +41,124 @@ type Manager struct{ tm timer.TimeoutManager }
 //
 // [duration] is the amount of time to allow for external requests
 // before the request times out.
-func (m *Manager) Initialize(duration time.Duration) { m.tm.Initialize(duration) }
+func (m *Manager) Initialize(duration time.Duration) {
+	m.tm.Initialize(duration)
+	m.requests = make(map[[32]byte]request)
+	m.latencies = make(map[[20]byte]*histogram)
+	m.outcomes = make(map[RequestType]outcomeMeters)
+}
 
 // Dispatch ...
 func (m *Manager) Dispatch() { m.tm.Dispatch() }
 
-// Register request to time out unless Manager.Cancel is called
+// Register request to time out unless Manager.Cancel, Respond or Fail is called
 // before the timeout duration passes, with the same request parameters.
 func (m *Manager) Register(validatorID ids.ShortID, chainID ids.ID, requestID uint32, timeout func()) {
-	m.tm.Put(createRequestID(validatorID, chainID, requestID), timeout)
+	m.RegisterRequest("", validatorID, chainID, requestID, timeout)
 }
 
-// Cancel request timeout with the specified parameters.
+// RegisterRequest is Register for a request of type [requestType], whose
+// outcome is recorded. If [requestType] is empty, the outcome isn't recorded.
+func (m *Manager) RegisterRequest(requestType RequestType, validatorID ids.ShortID, chainID ids.ID, requestID uint32, timeout func()) {
+	id := createRequestID(validatorID, chainID, requestID)
+	key := id.Key()
+
+	m.lock.Lock()
+	m.requests[key] = request{
+		registered:  time.Now(),
+		requestType: requestType,
+	}
+	m.lock.Unlock()
+
+	m.tm.Put(id, func() {
+		m.lock.Lock()
+		if req, ok := m.requests[key]; ok && req.requestType != "" {
+			m.meters(req.requestType).timedOut.Tick()
+		}
+		delete(m.requests, key)
+		m.lock.Unlock()
+
+		timeout()
+	})
+}
+
+// Cancel request timeout with the specified parameters. Neither the request's
+// outcome nor a round trip is recorded.
 func (m *Manager) Cancel(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
-	m.tm.Remove(createRequestID(validatorID, chainID, requestID))
+	m.remove(validatorID, chainID, requestID)
+}
+
+// Respond removes the timeout of the request with the specified parameters,
+// recording that it was responded to. If the request hasn't timed out, the
+// time since it was registered is recorded as a round trip to the validator.
+func (m *Manager) Respond(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	req, ok := m.remove(validatorID, chainID, requestID)
+	if !ok {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if req.requestType != "" {
+		m.meters(req.requestType).responded.Tick()
+	}
+
+	validatorKey := validatorID.Key()
+	latencies, ok := m.latencies[validatorKey]
+	if !ok {
+		latencies = newHistogram()
+		m.latencies[validatorKey] = latencies
+	}
+	latencies.observe(time.Since(req.registered))
+}
+
+// Fail removes the timeout of the request with the specified parameters,
+// recording that it failed, such as because it couldn't be sent. Unlike
+// Respond, no round trip is recorded, as the validator never responded.
+func (m *Manager) Fail(validatorID ids.ShortID, chainID ids.ID, requestID uint32) {
+	req, ok := m.remove(validatorID, chainID, requestID)
+	if !ok {
+		return
+	}
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if req.requestType != "" {
+		m.meters(req.requestType).failed.Tick()
+	}
+}
+
+// Latencies returns a histogram of the round trip times of requests to the
+// validator with ID [validatorID] that were responded to. If none were, the
+// histogram's counts are all 0.
+func (m *Manager) Latencies(validatorID ids.ShortID) Histogram {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	latencies, ok := m.latencies[validatorID.Key()]
+	if !ok {
+		latencies = newHistogram()
+	}
+	return latencies.snapshot()
+}
+
+// remove the timeout of the request with the specified parameters, returning
+// the request if it hadn't timed out
+func (m *Manager) remove(validatorID ids.ShortID, chainID ids.ID, requestID uint32) (request, bool) {
+	id := createRequestID(validatorID, chainID, requestID)
+	m.tm.Remove(id)
+
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	key := id.Key()
+	req, ok := m.requests[key]
+	if ok {
+		delete(m.requests, key)
+	}
+	return req, ok
 }
 
 func createRequestID(validatorID ids.ShortID, chainID ids.ID, requestID uint32) ids.ID {
@@ -46,3 +46,121 @@ func TestManagerCancel(t *testing.T) {
 		t.Fatalf("Should have cancelled the function")
 	}
 }
+
+func TestManagerLatencies(t *testing.T) {
+	manager := Manager{}
+	manager.Initialize(time.Millisecond)
+	go manager.Dispatch()
+
+	vdr0 := ids.NewShortID([20]byte{0})
+	vdr1 := ids.NewShortID([20]byte{1})
+	chainID := ids.NewID([32]byte{})
+
+	latencies := manager.Latencies(vdr0)
+	if len(latencies.Counts) != len(latencies.Bounds)+1 {
+		t.Fatalf("Should have had %d buckets but had %d", len(latencies.Bounds)+1, len(latencies.Counts))
+	}
+	for _, count := range latencies.Counts {
+		if count != 0 {
+			t.Fatalf("Shouldn't have recorded any round trips")
+		}
+	}
+
+	// A request that was responded to
+	manager.Register(vdr0, chainID, 0, func() {})
+	manager.Respond(vdr0, chainID, 0)
+
+	// A request that was cancelled without a response
+	manager.Register(vdr0, chainID, 2, func() {})
+	manager.Cancel(vdr0, chainID, 2)
+
+	// A request that timed out
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	manager.Register(vdr0, chainID, 1, wg.Done)
+	wg.Wait()
+	manager.Respond(vdr0, chainID, 1)
+
+	latencies = manager.Latencies(vdr0)
+	if latencies.Counts[0] != 1 {
+		t.Fatalf("Should have recorded the round trip in the first bucket")
+	}
+	total := uint64(0)
+	for _, count := range latencies.Counts {
+		total += count
+	}
+	if total != 1 {
+		t.Fatalf("Should have recorded 1 round trip but recorded %d", total)
+	}
+
+	for _, count := range manager.Latencies(vdr1).Counts {
+		if count != 0 {
+			t.Fatalf("Shouldn't have recorded any round trips to another validator")
+		}
+	}
+}
+
+func TestManagerOutcomes(t *testing.T) {
+	manager := Manager{}
+	manager.Initialize(time.Millisecond)
+	go manager.Dispatch()
+
+	vdr := ids.NewShortID([20]byte{0})
+	chainID := ids.NewID([32]byte{})
+
+	if outcomes := manager.Outcomes(); len(outcomes) != 0 {
+		t.Fatalf("Shouldn't have recorded any outcomes")
+	}
+
+	// A request that was responded to
+	manager.RegisterRequest(GetRequest, vdr, chainID, 0, func() {})
+	manager.Respond(vdr, chainID, 0)
+
+	// A request that timed out, and whose late response isn't counted
+	wg := sync.WaitGroup{}
+	wg.Add(1)
+	manager.RegisterRequest(GetRequest, vdr, chainID, 1, wg.Done)
+	wg.Wait()
+	manager.Respond(vdr, chainID, 1)
+
+	// A request whose outcome isn't recorded
+	manager.Register(vdr, chainID, 2, func() {})
+	manager.Respond(vdr, chainID, 2)
+
+	// A request that was cancelled without a response
+	manager.RegisterRequest(GetRequest, vdr, chainID, 3, func() {})
+	manager.Cancel(vdr, chainID, 3)
+
+	outcomes := manager.Outcomes()
+	if len(outcomes) != 1 {
+		t.Fatalf("Should have recorded the outcomes of 1 type of request but recorded %d", len(outcomes))
+	}
+	if outcome := outcomes[GetRequest]; outcome.Responded != 1 || outcome.TimedOut != 1 {
+		t.Fatalf("Should have recorded 1 response and 1 timeout but recorded %+v", outcome)
+	}
+}
+
+func TestManagerFail(t *testing.T) {
+	manager := Manager{}
+	manager.Initialize(time.Hour)
+	go manager.Dispatch()
+
+	vdr := ids.NewShortID([20]byte{0})
+	chainID := ids.NewID([32]byte{})
+
+	// A request that couldn't be sent
+	manager.RegisterRequest(GetRequest, vdr, chainID, 0, func() {})
+	manager.Fail(vdr, chainID, 0)
+
+	// A response to the failed request isn't counted
+	manager.Respond(vdr, chainID, 0)
+
+	if outcome := manager.Outcomes()[GetRequest]; outcome.Responded != 0 || outcome.Failed != 1 || outcome.TimedOut != 0 {
+		t.Fatalf("Should have recorded 1 failure but recorded %+v", outcome)
+	}
+	for _, count := range manager.Latencies(vdr).Counts {
+		if count != 0 {
+			t.Fatalf("Shouldn't have recorded a round trip for a failed request")
+		}
+	}
+}
@@ -0,0 +1,86 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timeout
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/utils/timer"
+)
+
+// RequestType is the type of a request sent to a validator, whose outcome is
+// recorded
+type RequestType string
+
+// The types of requests whose outcomes are recorded
+const (
+	GetAcceptedFrontierRequest RequestType = "getAcceptedFrontier"
+	GetAcceptedRequest         RequestType = "getAccepted"
+	GetRequest                 RequestType = "get"
+	GetAncestorsRequest        RequestType = "getAncestors"
+	PushQueryRequest           RequestType = "pushQuery"
+	PullQueryRequest           RequestType = "pullQuery"
+)
+
+const (
+	// Outcomes are counted in buckets of [outcomeBucketDuration], for up to
+	// [outcomeBuckets] buckets
+	outcomeBucketDuration = 10 * time.Second
+	outcomeBuckets        = 60
+
+	// OutcomeWindow is the span of time that outcomes are reported over
+	OutcomeWindow = outcomeBucketDuration * outcomeBuckets
+)
+
+// Outcomes are the number of requests of a type that were responded to, that
+// failed before they could be responded to, and that timed out, in the most
+// recent OutcomeWindow
+type Outcomes struct {
+	Responded int
+	Failed    int
+	TimedOut  int
+}
+
+// outcomeMeters count the requests of a type that were responded to, that
+// failed and that timed out
+type outcomeMeters struct {
+	responded, failed, timedOut *timer.BucketedMeter
+}
+
+func newOutcomeMeters() outcomeMeters {
+	return outcomeMeters{
+		responded: timer.NewBucketedMeter(outcomeBucketDuration, outcomeBuckets),
+		failed:    timer.NewBucketedMeter(outcomeBucketDuration, outcomeBuckets),
+		timedOut:  timer.NewBucketedMeter(outcomeBucketDuration, outcomeBuckets),
+	}
+}
+
+// meters returns the meters of requests of type [requestType].
+// assumes the lock is held
+func (m *Manager) meters(requestType RequestType) outcomeMeters {
+	meters, ok := m.outcomes[requestType]
+	if !ok {
+		meters = newOutcomeMeters()
+		m.outcomes[requestType] = meters
+	}
+	return meters
+}
+
+// Outcomes returns, for each type of request that was sent, the number of
+// requests that were responded to before they timed out, that failed and that
+// timed out, in the most recent OutcomeWindow
+func (m *Manager) Outcomes() map[RequestType]Outcomes {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	outcomes := make(map[RequestType]Outcomes, len(m.outcomes))
+	for requestType, meters := range m.outcomes {
+		outcomes[requestType] = Outcomes{
+			Responded: meters.responded.Ticks(),
+			Failed:    meters.failed.Ticks(),
+			TimedOut:  meters.timedOut.Ticks(),
+		}
+	}
+	return outcomes
+}
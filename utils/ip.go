@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net"
 	"strconv"
+	"strings"
 )
 
 // This was taken from: https://stackoverflow.com/a/50825191/3478466
@@ -83,7 +84,8 @@ func (ipDesc IPDesc) IsZero() bool {
 		ip.Equal(net.IPv6zero)
 }
 
-// ToIPDesc ...
+// ToIPDesc parses an ip port pair. IPv6 addresses must be bracketed, as in
+// [2001:db8::1]:9651. Any IPv6 zone is dropped.
 func ToIPDesc(str string) (IPDesc, error) {
 	host, portStr, err := net.SplitHostPort(str)
 	if err != nil {
@@ -94,12 +96,28 @@ func ToIPDesc(str string) (IPDesc, error) {
 		// TODO: Should this return a locally defined error? (e.g. errBadPort)
 		return IPDesc{}, err
 	}
-	ip := net.ParseIP(host)
-	if ip == nil {
-		return IPDesc{}, errBadIP
+	ip, err := ToIP(host)
+	if err != nil {
+		return IPDesc{}, err
 	}
 	return IPDesc{
 		IP:   ip,
 		Port: uint16(port),
 	}, nil
 }
+
+// ToIP parses an ip without a port. IPv6 addresses may optionally be
+// bracketed. Any IPv6 zone is dropped.
+func ToIP(str string) (net.IP, error) {
+	if strings.HasPrefix(str, "[") && strings.HasSuffix(str, "]") {
+		str = str[1 : len(str)-1]
+	}
+	if i := strings.LastIndexByte(str, '%'); i >= 0 && strings.Contains(str, ":") {
+		str = str[:i]
+	}
+	ip := net.ParseIP(str)
+	if ip == nil {
+		return nil, errBadIP
+	}
+	return ip, nil
+}
@@ -136,6 +136,8 @@ func TestToIPDesc(t *testing.T) {
 	}{
 		{"127.0.0.1:42", IPDesc{net.ParseIP("127.0.0.1"), 42}},
 		{"[::1]:42", IPDesc{net.ParseIP("::1"), 42}},
+		{"[::ffff:127.0.0.1]:42", IPDesc{net.ParseIP("127.0.0.1"), 42}},
+		{"[fe80::1%eth0]:42", IPDesc{net.ParseIP("fe80::1"), 42}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.in, func(t *testing.T) {
@@ -149,3 +151,66 @@ func TestToIPDesc(t *testing.T) {
 		})
 	}
 }
+
+func TestToIPDescRoundTrip(t *testing.T) {
+	tests := []struct {
+		in  string
+		out string
+	}{
+		{"1.2.3.4:9651", "1.2.3.4:9651"},
+		{"[2001:db8::1]:9651", "[2001:db8::1]:9651"},
+		{"[2001:DB8:0:0:0:0:0:1]:9651", "[2001:db8::1]:9651"},
+		{"[::ffff:1.2.3.4]:9651", "1.2.3.4:9651"},
+		{"[::ffff:102:304]:9651", "1.2.3.4:9651"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			ipDesc, err := ToIPDesc(tt.in)
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if result := ipDesc.String(); result != tt.out {
+				t.Fatalf("Expected %q, got %q", tt.out, result)
+			}
+			reparsed, err := ToIPDesc(ipDesc.String())
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if result := reparsed.String(); result != tt.out {
+				t.Fatalf("Expected %q after round trip, got %q", tt.out, result)
+			}
+		})
+	}
+}
+
+func TestToIP(t *testing.T) {
+	tests := []struct {
+		in  string
+		out net.IP
+	}{
+		{"1.2.3.4", net.ParseIP("1.2.3.4")},
+		{"2001:db8::1", net.ParseIP("2001:db8::1")},
+		{"[2001:db8::1]", net.ParseIP("2001:db8::1")},
+		{"::ffff:1.2.3.4", net.ParseIP("1.2.3.4")},
+		{"fe80::1%eth0", net.ParseIP("fe80::1")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			result, err := ToIP(tt.in)
+			if err != nil {
+				t.Fatalf("Unexpected error %v", err)
+			}
+			if !tt.out.Equal(result) {
+				t.Fatalf("Expected %v, got %v", tt.out, result)
+			}
+		})
+	}
+
+	for _, in := range []string{"", "[]", "1.2.3.4:9651", "[1.2.3.4", "abc"} {
+		t.Run(in, func(t *testing.T) {
+			if _, err := ToIP(in); err == nil {
+				t.Fatalf("Unexpected success")
+			}
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+// Package labels attributes goroutines to the parts of the node that spawned
+// them, using pprof labels
+package labels
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"runtime/pprof"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Chain labels the goroutines spawned for a chain with the chain's ID
+	Chain = "chain"
+
+	// Subsystem labels the goroutines spawned by a part of the node, such as
+	// the networking layer, with its name
+	Subsystem = "subsystem"
+
+	// Unlabeled is the label set of goroutines without labels
+	Unlabeled = "unlabeled"
+)
+
+const (
+	labelsPrefix = "# labels: "
+	totalPrefix  = "goroutine profile: total "
+)
+
+var errMalformedLabels = errors.New("malformed goroutine labels")
+
+// Do calls [f] with the current goroutine labeled with [labelPairs], which are
+// alternating keys and values. Goroutines started by [f] inherit the labels.
+// The goroutine's previous labels are restored once [f] returns.
+func Do(f func(), labelPairs ...string) {
+	pprof.Do(context.Background(), pprof.Labels(labelPairs...), func(context.Context) { f() })
+}
+
+// Count returns the number of goroutines with each label set, and the total
+// number of goroutines. Label sets are formatted as comma separated key=value
+// pairs, sorted by key, such as "chain=X,subsystem=Y". Goroutines without
+// labels are counted under Unlabeled.
+func Count() (map[string]int, int, error) {
+	profile := bytes.Buffer{}
+	if err := pprof.Lookup("goroutine").WriteTo(&profile, 1); err != nil {
+		return nil, 0, err
+	}
+	return parseProfile(profile.Bytes())
+}
+
+// parseProfile parses a goroutine profile written with debug level 1. The
+// profile is made of records that start with the number of goroutines with a
+// stack, followed by the labels of the goroutines, if any, and the stack.
+func parseProfile(profile []byte) (map[string]int, int, error) {
+	counts := make(map[string]int)
+	total := 0
+
+	// count of the record being parsed, which hasn't been added to [counts] yet
+	pending := 0
+	scanner := bufio.NewScanner(bytes.NewReader(profile))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, totalPrefix):
+			n, err := strconv.Atoi(strings.TrimPrefix(line, totalPrefix))
+			if err != nil {
+				return nil, 0, fmt.Errorf("couldn't parse goroutine total %q: %w", line, err)
+			}
+			total = n
+		case strings.HasPrefix(line, labelsPrefix):
+			labelSet, err := parseLabels(strings.TrimPrefix(line, labelsPrefix))
+			if err != nil {
+				return nil, 0, err
+			}
+			counts[labelSet] += pending
+			pending = 0
+		case strings.Contains(line, " @ ") && !strings.HasPrefix(line, "#"):
+			counts[Unlabeled] += pending
+			n, err := strconv.Atoi(line[:strings.Index(line, " @ ")])
+			if err != nil {
+				return nil, 0, fmt.Errorf("couldn't parse goroutine count %q: %w", line, err)
+			}
+			pending = n
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, 0, err
+	}
+	counts[Unlabeled] += pending
+	if counts[Unlabeled] == 0 {
+		delete(counts, Unlabeled)
+	}
+	return counts, total, nil
+}
+
+// parseLabels formats the labels of a record, such as
+// {"chain":"X", "subsystem":"Y"}, as a label set
+func parseLabels(labels string) (string, error) {
+	if !strings.HasPrefix(labels, "{") || !strings.HasSuffix(labels, "}") {
+		return "", errMalformedLabels
+	}
+	labels = labels[1 : len(labels)-1]
+
+	// The keys and values are quoted strings, separated by ":" within a pair
+	// and by ", " between pairs
+	strs := []string(nil)
+	for len(labels) > 0 {
+		str, rest, err := unquotePrefix(labels)
+		if err != nil {
+			return "", err
+		}
+		strs = append(strs, str)
+		labels = strings.TrimLeft(rest, ":, ")
+	}
+	if len(strs) == 0 {
+		return Unlabeled, nil
+	}
+	if len(strs)%2 != 0 {
+		return "", errMalformedLabels
+	}
+
+	pairs := make([]string, 0, len(strs)/2)
+	for i := 0; i < len(strs); i += 2 {
+		pairs = append(pairs, strs[i]+"="+strs[i+1])
+	}
+	sort.Strings(pairs)
+	return strings.Join(pairs, ","), nil
+}
+
+// unquotePrefix returns the quoted string that [s] starts with, unquoted, and
+// the rest of [s]
+func unquotePrefix(s string) (string, string, error) {
+	if !strings.HasPrefix(s, `"`) {
+		return "", "", errMalformedLabels
+	}
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			str, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", errMalformedLabels
+			}
+			return str, s[i+1:], nil
+		}
+	}
+	return "", "", errMalformedLabels
+}
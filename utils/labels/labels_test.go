@@ -0,0 +1,88 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package labels
+
+import (
+	"testing"
+)
+
+func TestCount(t *testing.T) {
+	started := make(chan struct{})
+	stop := make(chan struct{})
+	defer close(stop)
+
+	Do(func() {
+		for i := 0; i < 3; i++ {
+			go func() {
+				started <- struct{}{}
+				<-stop
+			}()
+		}
+	}, Subsystem, "test", Chain, "chain ID")
+	for i := 0; i < 3; i++ {
+		<-started
+	}
+
+	counts, total, err := Count()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count := counts["chain=chain ID,subsystem=test"]; count != 3 {
+		t.Fatalf("expected 3 labeled goroutines, got %d", count)
+	}
+	if counts[Unlabeled] == 0 {
+		t.Fatal("expected the test's own goroutines to be unlabeled")
+	}
+	sum := 0
+	for _, count := range counts {
+		sum += count
+	}
+	if sum != total {
+		t.Fatalf("expected the counts to sum to the total %d, got %d", total, sum)
+	}
+}
+
+func TestParseProfile(t *testing.T) {
+	profile := "goroutine profile: total 7\n" +
+		"2 @ 0x4449b1 0x48c9a1\n" +
+		"#\t0x4449b0\tmain.f+0x0\t/main.go:5\n" +
+		"\n" +
+		"1 @ 0x546901 0x48c9a1\n" +
+		"# labels: {\"chain\":\"a, \\\"b\\\"\", \"subsystem\":\"network\"}\n" +
+		"#\t0x546900\tmain.g+0x0\t/main.go:6\n" +
+		"\n" +
+		"3 @ 0x546902 0x48c9a1\n" +
+		"# labels: {\"subsystem\":\"network\", \"chain\":\"a, \\\"b\\\"\"}\n" +
+		"#\t0x546900\tmain.h+0x0\t/main.go:7\n" +
+		"\n" +
+		"1 @ 0x546903 0x48c9a1\n" +
+		"#\t0x546900\tmain.i+0x0\t/main.go:8\n"
+
+	counts, total, err := parseProfile([]byte(profile))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 7 {
+		t.Fatalf("expected a total of 7, got %d", total)
+	}
+	if len(counts) != 2 {
+		t.Fatalf("expected 2 label sets, got %v", counts)
+	}
+	if count := counts[`chain=a, "b",subsystem=network`]; count != 4 {
+		t.Fatalf("expected 4 labeled goroutines, got %v", counts)
+	}
+	if count := counts[Unlabeled]; count != 3 {
+		t.Fatalf("expected 3 unlabeled goroutines, got %v", counts)
+	}
+}
+
+func TestParseProfileMalformedLabels(t *testing.T) {
+	profile := "goroutine profile: total 1\n" +
+		"1 @ 0x546901 0x48c9a1\n" +
+		"# labels: {\"chain\":\"a}\n"
+
+	if _, _, err := parseProfile([]byte(profile)); err == nil {
+		t.Fatal("expected malformed labels to be rejected")
+	}
+}
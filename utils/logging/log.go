@@ -27,6 +27,22 @@ type Log struct {
 	w                                *bufio.Writer
 
 	closed bool
+
+	problemsLock sync.Mutex
+	problems     Problems
+}
+
+// Problems are the errors and warnings a Log was asked to log
+type Problems struct {
+	// Number of entries logged at the Fatal or Error levels, and at the Warn
+	// level, since the Log was created. Entries are counted even if they're
+	// filtered out by the log level.
+	Errors, Warnings uint64
+
+	// The most recent entry logged at the Fatal or Error levels, and when it
+	// was logged. Empty if no errors were logged.
+	LastError     string
+	LastErrorTime time.Time
 }
 
 // New ...
@@ -125,6 +141,8 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 		return
 	}
 
+	l.countProblem(level, format, args...)
+
 	l.configLock.Lock()
 	defer l.configLock.Unlock()
 
@@ -154,6 +172,32 @@ func (l *Log) log(level Level, format string, args ...interface{}) {
 	}
 }
 
+// countProblem counts the entry if it's an error or a warning
+func (l *Log) countProblem(level Level, format string, args ...interface{}) {
+	if level > Warn {
+		return
+	}
+
+	l.problemsLock.Lock()
+	defer l.problemsLock.Unlock()
+
+	if level == Warn {
+		l.problems.Warnings++
+		return
+	}
+	l.problems.Errors++
+	l.problems.LastError = fmt.Sprintf(format, args...)
+	l.problems.LastErrorTime = time.Now()
+}
+
+// Problems returns the errors and warnings this Log was asked to log
+func (l *Log) Problems() Problems {
+	l.problemsLock.Lock()
+	defer l.problemsLock.Unlock()
+
+	return l.problems
+}
+
 func (l *Log) format(level Level, format string, args ...interface{}) string {
 	loc := "?"
 	if _, file, no, ok := runtime.Caller(3); ok {
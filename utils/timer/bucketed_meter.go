@@ -0,0 +1,94 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"sync"
+	"time"
+)
+
+// BucketedMeter is a meter that counts ticks in a ring of fixed length time
+// buckets. Unlike TimedMeter, its memory usage doesn't grow with the number of
+// ticks. Old buckets are discarded one at a time, so counts over a window decay
+// smoothly rather than resetting.
+type BucketedMeter struct {
+	lock  sync.Mutex
+	clock Clock
+
+	bucketDuration time.Duration
+	buckets        []int
+
+	// index of the bucket that ticks are currently being counted in, and the
+	// time that bucket started at
+	head      int
+	headStart time.Time
+}
+
+// NewBucketedMeter returns a meter that remembers ticks for
+// [bucketDuration] * [numBuckets]
+func NewBucketedMeter(bucketDuration time.Duration, numBuckets int) *BucketedMeter {
+	return &BucketedMeter{
+		bucketDuration: bucketDuration,
+		buckets:        make([]int, numBuckets),
+	}
+}
+
+// Tick implements the Meter interface
+func (bm *BucketedMeter) Tick() { bm.TickN(1) }
+
+// TickN records [n] ticks at once
+func (bm *BucketedMeter) TickN(n int) {
+	bm.lock.Lock()
+	defer bm.lock.Unlock()
+
+	bm.advance()
+	bm.buckets[bm.head] += n
+}
+
+// Ticks implements the Meter interface
+func (bm *BucketedMeter) Ticks() int {
+	return bm.TicksIn(bm.bucketDuration * time.Duration(len(bm.buckets)))
+}
+
+// TicksIn returns the number of ticks in the most recent [window]. The window
+// is rounded up to a whole number of buckets.
+func (bm *BucketedMeter) TicksIn(window time.Duration) int {
+	bm.lock.Lock()
+	defer bm.lock.Unlock()
+
+	bm.advance()
+
+	numBuckets := int((window + bm.bucketDuration - 1) / bm.bucketDuration)
+	if numBuckets > len(bm.buckets) {
+		numBuckets = len(bm.buckets)
+	}
+	ticks := 0
+	for i := 0; i < numBuckets; i++ {
+		ticks += bm.buckets[(bm.head-i+len(bm.buckets))%len(bm.buckets)]
+	}
+	return ticks
+}
+
+// advance the head to the bucket that the current time falls in, clearing the
+// buckets that it passes over
+func (bm *BucketedMeter) advance() {
+	now := bm.clock.Time()
+	if bm.headStart.IsZero() {
+		bm.headStart = now
+		return
+	}
+
+	elapsed := int(now.Sub(bm.headStart) / bm.bucketDuration)
+	if elapsed <= 0 {
+		return
+	}
+	bm.headStart = bm.headStart.Add(time.Duration(elapsed) * bm.bucketDuration)
+	if elapsed > len(bm.buckets) {
+		elapsed = len(bm.buckets)
+	}
+	for i := 0; i < elapsed; i++ {
+		bm.head = (bm.head + 1) % len(bm.buckets)
+		bm.buckets[bm.head] = 0
+	}
+}
@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBucketedMeter(t *testing.T) {
+	meter := NewBucketedMeter(time.Second, 10)
+	start := time.Unix(1000000, 0)
+	meter.clock.Set(start)
+
+	if ticks := meter.Ticks(); ticks != 0 {
+		t.Fatalf("expected 0 ticks but got %d", ticks)
+	}
+
+	// One tick at the start of each of the first 5 seconds
+	for i := 0; i < 5; i++ {
+		meter.clock.Set(start.Add(time.Duration(i) * time.Second))
+		meter.Tick()
+	}
+	if ticks := meter.Ticks(); ticks != 5 {
+		t.Fatalf("expected 5 ticks but got %d", ticks)
+	}
+	if ticks := meter.TicksIn(2 * time.Second); ticks != 2 {
+		t.Fatalf("expected 2 ticks in the last 2s but got %d", ticks)
+	}
+
+	// The ticks should expire one bucket at a time
+	meter.clock.Set(start.Add(11 * time.Second))
+	if ticks := meter.Ticks(); ticks != 3 {
+		t.Fatalf("expected 3 ticks but got %d", ticks)
+	}
+
+	// Every tick should have expired
+	meter.clock.Set(start.Add(time.Hour))
+	if ticks := meter.Ticks(); ticks != 0 {
+		t.Fatalf("expected 0 ticks but got %d", ticks)
+	}
+	meter.Tick()
+	if ticks := meter.Ticks(); ticks != 1 {
+		t.Fatalf("expected 1 tick but got %d", ticks)
+	}
+	meter.TickN(3)
+	if ticks := meter.Ticks(); ticks != 4 {
+		t.Fatalf("expected 4 ticks but got %d", ticks)
+	}
+}
@@ -0,0 +1,67 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// LatencyWindow remembers the most recently observed latencies. Unlike a
+// histogram, old latencies are forgotten, so percentiles of the window reflect
+// recent behavior. Not safe for concurrent use.
+type LatencyWindow struct {
+	latencies []time.Duration
+
+	// index the next latency is stored at, and whether the window has wrapped
+	next int
+	full bool
+}
+
+// NewLatencyWindow returns a window that remembers the last [size] latencies
+func NewLatencyWindow(size int) *LatencyWindow {
+	return &LatencyWindow{latencies: make([]time.Duration, size)}
+}
+
+// Observe records [latency], forgetting the oldest latency if the window is
+// full
+func (lw *LatencyWindow) Observe(latency time.Duration) {
+	lw.latencies[lw.next] = latency
+	lw.next++
+	if lw.next == len(lw.latencies) {
+		lw.next = 0
+		lw.full = true
+	}
+}
+
+// Latencies returns a copy of the latencies in the window, oldest first
+func (lw *LatencyWindow) Latencies() []time.Duration {
+	if !lw.full {
+		return append([]time.Duration(nil), lw.latencies[:lw.next]...)
+	}
+	latencies := make([]time.Duration, 0, len(lw.latencies))
+	latencies = append(latencies, lw.latencies[lw.next:]...)
+	return append(latencies, lw.latencies[:lw.next]...)
+}
+
+// Percentiles returns the [percentiles], each in (0, 100], of [latencies]. Each
+// percentile is the smallest latency that's at least as large as that
+// percentage of [latencies]. [latencies] must not be empty, and isn't modified.
+func Percentiles(latencies []time.Duration, percentiles ...float64) []time.Duration {
+	sorted := append([]time.Duration(nil), latencies...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	results := make([]time.Duration, len(percentiles))
+	for i, percentile := range percentiles {
+		rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+		if rank < 1 {
+			rank = 1
+		} else if rank > len(sorted) {
+			rank = len(sorted)
+		}
+		results[i] = sorted[rank-1]
+	}
+	return results
+}
@@ -0,0 +1,58 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package timer
+
+import (
+	"testing"
+	"time"
+)
+
+func TestLatencyWindow(t *testing.T) {
+	window := NewLatencyWindow(3)
+	if latencies := window.Latencies(); len(latencies) != 0 {
+		t.Fatalf("expected no latencies but got %v", latencies)
+	}
+
+	window.Observe(1 * time.Second)
+	window.Observe(2 * time.Second)
+	if latencies := window.Latencies(); len(latencies) != 2 || latencies[0] != time.Second || latencies[1] != 2*time.Second {
+		t.Fatalf("expected [1s 2s] but got %v", latencies)
+	}
+
+	// The oldest latencies should be forgotten once the window is full
+	window.Observe(3 * time.Second)
+	window.Observe(4 * time.Second)
+	latencies := window.Latencies()
+	expected := []time.Duration{2 * time.Second, 3 * time.Second, 4 * time.Second}
+	if len(latencies) != len(expected) {
+		t.Fatalf("expected %v but got %v", expected, latencies)
+	}
+	for i, latency := range latencies {
+		if latency != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, latencies)
+		}
+	}
+}
+
+func TestPercentiles(t *testing.T) {
+	latencies := make([]time.Duration, 0, 100)
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+
+	percentiles := Percentiles(latencies, 50, 95, 99, 100)
+	expected := []time.Duration{50 * time.Millisecond, 95 * time.Millisecond, 99 * time.Millisecond, 100 * time.Millisecond}
+	for i, percentile := range percentiles {
+		if percentile != expected[i] {
+			t.Fatalf("expected %v but got %v", expected, percentiles)
+		}
+	}
+	if latencies[0] != 100*time.Millisecond {
+		t.Fatalf("latencies shouldn't have been sorted in place")
+	}
+
+	if percentiles := Percentiles(latencies[:1], 50, 99); percentiles[0] != 100*time.Millisecond || percentiles[1] != 100*time.Millisecond {
+		t.Fatalf("expected every percentile of one latency to be that latency but got %v", percentiles)
+	}
+}
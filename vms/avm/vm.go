@@ -39,6 +39,17 @@ const (
 	addressSep     = "-"
 )
 
+// Reasons transactions are rejected by IssueTx
+const (
+	// the chain hasn't finished bootstrapping
+	bootstrappingRejection = "bootstrapping"
+	// the transaction couldn't be parsed, or isn't well formed
+	malformedRejection = "malformed"
+	// the transaction is well formed, but isn't valid given the chain's state,
+	// such as if it spends funds it can't
+	invalidRejection = "invalid"
+)
+
 var (
 	errIncompatibleFx            = errors.New("incompatible feature extension")
 	errUnknownFx                 = errors.New("unknown feature extension")
@@ -77,6 +88,14 @@ type VM struct {
 	txs          []snowstorm.Tx
 	toEngine     chan<- common.Message
 
+	// Number of transactions rejected by IssueTx, keyed by why they were
+	// rejected
+	txRejections map[string]uint64
+
+	// When each transaction that was issued through this VM, but hasn't been
+	// accepted or rejected yet, was issued
+	undecidedTxs map[[32]byte]time.Time
+
 	baseDB database.Database
 	db     *versiondb.Database
 
@@ -300,20 +319,74 @@ func (vm *VM) GetTx(txID ids.ID) (snowstorm.Tx, error) {
 // go out of scope when the transaction is removed from memory.
 func (vm *VM) IssueTx(b []byte, onDecide func(choices.Status)) (ids.ID, error) {
 	if !vm.bootstrapped {
+		vm.rejectTx(bootstrappingRejection)
 		return ids.ID{}, errBootstrapping
 	}
 	tx, err := vm.parseTx(b)
 	if err != nil {
+		vm.rejectTx(malformedRejection)
 		return ids.ID{}, err
 	}
 	if err := tx.Verify(); err != nil {
+		vm.rejectTx(invalidRejection)
 		return ids.ID{}, err
 	}
+	vm.trackUndecidedTx(tx.ID())
 	vm.issueTx(tx)
 	tx.onDecide = onDecide
 	return tx.ID(), nil
 }
 
+// TxRejections implements the common.TxRejectionReporter interface
+func (vm *VM) TxRejections() map[string]uint64 {
+	rejections := make(map[string]uint64, len(vm.txRejections))
+	for reason, count := range vm.txRejections {
+		rejections[reason] = count
+	}
+	return rejections
+}
+
+// OldestPendingTx implements the common.PendingTxReporter interface. Only
+// transactions issued through this VM, rather than learned of from peers, are
+// considered.
+func (vm *VM) OldestPendingTx() (ids.ID, time.Time, bool) {
+	oldestID, oldest, found := ids.ID{}, time.Time{}, false
+	for key, seen := range vm.undecidedTxs {
+		if !found || seen.Before(oldest) {
+			oldestID, oldest, found = ids.NewID(key), seen, true
+		}
+	}
+	return oldestID, oldest, found
+}
+
+// trackUndecidedTx records that the verified transaction with ID [txID] is
+// being issued to consensus. Transactions are only tracked once they're
+// issued, as a transaction that was parsed, but failed verification or was
+// never issued, will never be decided.
+func (vm *VM) trackUndecidedTx(txID ids.ID) {
+	if vm.undecidedTxs == nil {
+		vm.undecidedTxs = make(map[[32]byte]time.Time)
+	}
+	key := txID.Key()
+	if _, tracked := vm.undecidedTxs[key]; !tracked {
+		vm.undecidedTxs[key] = vm.clock.Time()
+	}
+}
+
+// untrackUndecidedTx records that the transaction with ID [txID] was accepted
+// or rejected
+func (vm *VM) untrackUndecidedTx(txID ids.ID) {
+	delete(vm.undecidedTxs, txID.Key())
+}
+
+// rejectTx counts a transaction rejected by IssueTx for [reason]
+func (vm *VM) rejectTx(reason string) {
+	if vm.txRejections == nil {
+		vm.txRejections = make(map[string]uint64)
+	}
+	vm.txRejections[reason]++
+}
+
 // GetAtomicUTXOs returns the utxos that at least one of the provided addresses is
 // referenced in.
 func (vm *VM) GetAtomicUTXOs(addrs ids.Set) ([]*ava.UTXO, error) {
@@ -499,6 +499,54 @@ func TestIssueTx(t *testing.T) {
 	}
 }
 
+func TestOldestPendingTx(t *testing.T) {
+	genesisBytes, issuer, vm := GenesisVM(t)
+	defer func() {
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+
+	if _, _, pending := vm.OldestPendingTx(); pending {
+		t.Fatalf("Shouldn't have a pending tx before any were issued")
+	}
+
+	// A tx that's only parsed, such as one that fails verification, is never
+	// issued, so it isn't pending
+	newTx := NewTx(t, genesisBytes, vm)
+	if _, err := vm.ParseTx(newTx.Bytes()); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, pending := vm.OldestPendingTx(); pending {
+		t.Fatalf("Shouldn't have a pending tx that was only parsed")
+	}
+
+	if _, err := vm.IssueTx(newTx.Bytes(), nil); err != nil {
+		t.Fatal(err)
+	}
+	ctx.Lock.Unlock()
+	<-issuer
+	ctx.Lock.Lock()
+
+	txID, _, pending := vm.OldestPendingTx()
+	if !pending {
+		t.Fatalf("Should have a pending tx")
+	}
+	if !txID.Equals(newTx.ID()) {
+		t.Fatalf("Returned the wrong pending tx")
+	}
+
+	txs := vm.PendingTxs()
+	if len(txs) != 1 {
+		t.Fatalf("Should have returned %d tx(s)", 1)
+	}
+	if err := txs[0].Accept(); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, pending := vm.OldestPendingTx(); pending {
+		t.Fatalf("Shouldn't have a pending tx after it was accepted")
+	}
+}
+
 func TestGenesisGetUTXOs(t *testing.T) {
 	_, _, vm := GenesisVM(t)
 	defer func() {
@@ -1034,3 +1082,46 @@ func TestVMFormatAliased(t *testing.T) {
 		})
 	}
 }
+
+func TestIssueTxRejections(t *testing.T) {
+	genesisBytes, _, vm := GenesisVM(t)
+	defer func() {
+		vm.Shutdown()
+		ctx.Lock.Unlock()
+	}()
+
+	if rejections := vm.TxRejections(); len(rejections) != 0 {
+		t.Fatalf("expected no rejections but got %v", rejections)
+	}
+
+	if _, err := vm.IssueTx([]byte{1, 2, 3}, nil); err == nil {
+		t.Fatalf("should have rejected a malformed tx")
+	}
+
+	// A tx that isn't signed by the owner of the funds it spends
+	newTx := NewTx(t, genesisBytes, vm)
+	newTx.Creds[0].(*secp256k1fx.Credential).Sigs[0] = [crypto.SECP256K1RSigLen]byte{}
+	b, err := vm.codec.Marshal(newTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := vm.IssueTx(b, nil); err == nil {
+		t.Fatalf("should have rejected an invalid tx")
+	}
+
+	vm.bootstrapped = false
+	if _, err := vm.IssueTx(NewTx(t, genesisBytes, vm).Bytes(), nil); err == nil {
+		t.Fatalf("should have rejected a tx while bootstrapping")
+	}
+
+	rejections := vm.TxRejections()
+	for reason, expected := range map[string]uint64{
+		malformedRejection:     1,
+		invalidRejection:       1,
+		bootstrappingRejection: 1,
+	} {
+		if rejections[reason] != expected {
+			t.Fatalf("expected %d %q rejections but got %d", expected, reason, rejections[reason])
+		}
+	}
+}
@@ -43,6 +43,9 @@ type AtomicBlock struct {
 	inputs ids.Set
 }
 
+// NumTxs implements the snowman.TxBlock interface
+func (ab *AtomicBlock) NumTxs() int { return 1 }
+
 // initialize this block
 func (ab *AtomicBlock) initialize(vm *VM, bytes []byte) error {
 	if err := ab.CommonDecisionBlock.initialize(vm, bytes); err != nil {
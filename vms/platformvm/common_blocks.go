@@ -128,6 +128,10 @@ type CommonBlock struct {
 	children []Block
 }
 
+// NumTxs implements the snowman.TxBlock interface. Commit and abort blocks
+// don't contain transactions; they decide on their parent's proposal.
+func (cb *CommonBlock) NumTxs() int { return 0 }
+
 // Reject implements the snowman.Block interface
 func (cb *CommonBlock) Reject() error {
 	defer cb.free() // remove this block from memory
@@ -0,0 +1,60 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"time"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/engine/common"
+)
+
+// pendingTx is a transaction that hasn't been put into a block yet
+type pendingTx struct {
+	txID     ids.ID
+	received time.Time
+	size     int
+}
+
+// trackPendingTx records that the transaction with ID [txID] and size [size]
+// was received. Assumes the tx was added to the unissued txs.
+func (vm *VM) trackPendingTx(txID ids.ID, size int) {
+	vm.pendingTxs[txID.Key()] = pendingTx{
+		txID:     txID,
+		received: vm.clock.Time(),
+		size:     size,
+	}
+}
+
+// untrackPendingTx records that the transaction with ID [txID] was removed
+// from the unissued txs, either to be put into a block or to be dropped
+func (vm *VM) untrackPendingTx(txID ids.ID) {
+	delete(vm.pendingTxs, txID.Key())
+}
+
+// MempoolStats implements the common.MempoolReporter interface
+func (vm *VM) MempoolStats() common.MempoolStats {
+	stats := common.MempoolStats{}
+	for _, tx := range vm.pendingTxs {
+		stats.Txs++
+		stats.Bytes += tx.size
+		if stats.Oldest.IsZero() || tx.received.Before(stats.Oldest) {
+			stats.Oldest = tx.received
+		}
+	}
+	return stats
+}
+
+// OldestPendingTx implements the common.PendingTxReporter interface. Only
+// transactions that haven't been put into a block yet are considered, as
+// blocks are decided soon after they're built.
+func (vm *VM) OldestPendingTx() (ids.ID, time.Time, bool) {
+	oldestID, oldest, found := ids.ID{}, time.Time{}, false
+	for _, tx := range vm.pendingTxs {
+		if !found || tx.received.Before(oldest) {
+			oldestID, oldest, found = tx.txID, tx.received, true
+		}
+	}
+	return oldestID, oldest, found
+}
@@ -48,6 +48,9 @@ type ProposalBlock struct {
 	onAbortFunc func()
 }
 
+// NumTxs implements the snowman.TxBlock interface
+func (pb *ProposalBlock) NumTxs() int { return 1 }
+
 // Accept implements the snowman.Block interface
 func (pb *ProposalBlock) Accept() error {
 	pb.SetStatus(choices.Accepted)
@@ -1276,18 +1276,21 @@ func (service *Service) IssueTx(_ *http.Request, args *IssueTxArgs, response *Is
 			return fmt.Errorf("error initializing tx: %s", err)
 		}
 		service.vm.unissuedEvents.Add(tx)
+		service.vm.trackPendingTx(tx.ID(), len(args.Tx.Bytes))
 		response.TxID = tx.ID()
 	case DecisionTx:
 		if err := tx.initialize(service.vm); err != nil {
 			return fmt.Errorf("error initializing tx: %s", err)
 		}
 		service.vm.unissuedDecisionTxs = append(service.vm.unissuedDecisionTxs, tx)
+		service.vm.trackPendingTx(tx.ID(), len(args.Tx.Bytes))
 		response.TxID = tx.ID()
 	case AtomicTx:
 		if err := tx.initialize(service.vm); err != nil {
 			return fmt.Errorf("error initializing tx: %s", err)
 		}
 		service.vm.unissuedAtomicTxs = append(service.vm.unissuedAtomicTxs, tx)
+		service.vm.trackPendingTx(tx.ID(), len(args.Tx.Bytes))
 		response.TxID = tx.ID()
 	default:
 		return errors.New("Could not parse given tx. Provided tx needs to be a TimedTx, DecisionTx, or AtomicTx")
@@ -8,7 +8,9 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ava-labs/gecko/utils/crypto"
 	"github.com/ava-labs/gecko/utils/formatting"
+	"github.com/ava-labs/gecko/vms/timestampvm"
 )
 
 func TestAddDefaultSubnetValidator(t *testing.T) {
@@ -234,3 +236,55 @@ func TestIssueTxKeepsTimedEventsSorted(t *testing.T) {
 		currentEvent = nextEvent
 	}
 }
+
+func TestIssueTxTracksMempool(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	service := Service{vm: vm}
+
+	if stats := vm.MempoolStats(); stats.Txs != 0 || !stats.Oldest.IsZero() {
+		t.Fatalf("expected an empty mempool, got %+v", stats)
+	}
+
+	tx, err := vm.newCreateChainTx(
+		defaultNonce+1,
+		testSubnet1.id,
+		nil,
+		timestampvm.ID,
+		nil,
+		"name",
+		testNetworkID,
+		[]*crypto.PrivateKeySECP256K1R{testSubnet1ControlKeys[0], testSubnet1ControlKeys[1]},
+		keys[0],
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := Codec.Marshal(genericTx{Tx: tx})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := service.IssueTx(nil, &IssueTxArgs{Tx: formatting.CB58{Bytes: txBytes}}, &IssueTxResponse{}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := vm.MempoolStats()
+	if stats.Txs != 1 || stats.Bytes != len(txBytes) {
+		t.Fatalf("expected 1 pending tx of %d bytes, got %d of %d bytes", len(txBytes), stats.Txs, stats.Bytes)
+	}
+	if !stats.Oldest.Equal(vm.clock.Time()) {
+		t.Fatalf("expected the tx to be received at %s, got %s", vm.clock.Time(), stats.Oldest)
+	}
+
+	if _, err := vm.BuildBlock(); err != nil {
+		t.Fatal(err)
+	}
+	if stats := vm.MempoolStats(); stats.Txs != 0 || stats.Bytes != 0 {
+		t.Fatalf("expected the mempool to be empty after building a block, got %+v", stats)
+	}
+}
@@ -30,6 +30,9 @@ type StandardBlock struct {
 	Txs []DecisionTx `serialize:"true"`
 }
 
+// NumTxs implements the snowman.TxBlock interface
+func (sb *StandardBlock) NumTxs() int { return len(sb.Txs) }
+
 // initialize this block
 func (sb *StandardBlock) initialize(vm *VM, bytes []byte) error {
 	if err := sb.SingleDecisionBlock.initialize(vm, bytes); err != nil {
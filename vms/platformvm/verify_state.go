@@ -0,0 +1,82 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package platformvm
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ava-labs/gecko/ids"
+	"github.com/ava-labs/gecko/snow/choices"
+)
+
+// VerifyState implements the common.StateVerifier interface.
+//
+// Checks that the last accepted block is stored and accepted, that the
+// validators of every subnet stop, and the pending validators start, no
+// earlier than the chain's timestamp, as the timestamp can't be advanced past
+// either, and that every chain was created on a subnet that exists.
+func (vm *VM) VerifyState(ctx context.Context) error {
+	lastAcceptedID := vm.LastAccepted()
+	lastAccepted, err := vm.getBlock(lastAcceptedID)
+	if err != nil {
+		return fmt.Errorf("couldn't get the last accepted block %s: %w", lastAcceptedID, err)
+	}
+	if status := lastAccepted.Status(); status != choices.Accepted {
+		return fmt.Errorf("the last accepted block %s has status %s", lastAcceptedID, status)
+	}
+
+	timestamp, err := vm.getTimestamp(vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get the chain's timestamp: %w", err)
+	}
+	subnets, err := vm.getSubnets(vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get the subnets: %w", err)
+	}
+
+	subnetIDs := ids.Set{}
+	subnetIDs.Add(DefaultSubnetID)
+	for _, subnet := range subnets {
+		subnetIDs.Add(subnet.id)
+	}
+	for _, subnetID := range subnetIDs.List() {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		current, err := vm.getCurrentValidators(vm.DB, subnetID)
+		if err != nil {
+			return fmt.Errorf("couldn't get the validators of subnet %s: %w", subnetID, err)
+		}
+		for _, tx := range current.Txs {
+			if tx.EndTime().Before(timestamp) {
+				return fmt.Errorf("validator %s of subnet %s stopped validating at %s, before the chain's timestamp %s",
+					tx.Vdr().ID(), subnetID, tx.EndTime(), timestamp)
+			}
+		}
+
+		pending, err := vm.getPendingValidators(vm.DB, subnetID)
+		if err != nil {
+			return fmt.Errorf("couldn't get the pending validators of subnet %s: %w", subnetID, err)
+		}
+		for _, tx := range pending.Txs {
+			if tx.StartTime().Before(timestamp) {
+				return fmt.Errorf("pending validator %s of subnet %s should have started validating at %s, before the chain's timestamp %s",
+					tx.Vdr().ID(), subnetID, tx.StartTime(), timestamp)
+			}
+		}
+	}
+
+	chains, err := vm.getChains(vm.DB)
+	if err != nil {
+		return fmt.Errorf("couldn't get the chains: %w", err)
+	}
+	for _, chain := range chains {
+		if !subnetIDs.Contains(chain.SubnetID) {
+			return fmt.Errorf("chain %s was created on subnet %s, which doesn't exist", chain.ID(), chain.SubnetID)
+		}
+	}
+	return nil
+}
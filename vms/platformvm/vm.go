@@ -191,6 +191,9 @@ type VM struct {
 	unissuedEvents      *EventHeap
 	unissuedDecisionTxs []DecisionTx
 	unissuedAtomicTxs   []AtomicTx
+	// When each transaction that hasn't been put into a block yet was
+	// received, and its size
+	pendingTxs map[[32]byte]pendingTx
 
 	// This timer goes off when it is time for the next validator to add/leave the validator set
 	// When it goes off resetTimer() is called, triggering creation of a new block
@@ -302,6 +305,7 @@ func (vm *VM) Initialize(
 	// Transactions from clients that have not yet been put into blocks
 	// and added to consensus
 	vm.unissuedEvents = &EventHeap{SortByStartTime: true}
+	vm.pendingTxs = make(map[[32]byte]pendingTx)
 
 	vm.currentBlocks = make(map[[32]byte]Block)
 	vm.timer = timer.NewTimer(func() {
@@ -438,6 +442,9 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 		}
 		var txs []DecisionTx
 		txs, vm.unissuedDecisionTxs = vm.unissuedDecisionTxs[:numTxs], vm.unissuedDecisionTxs[numTxs:]
+		for _, tx := range txs {
+			vm.untrackPendingTx(tx.ID())
+		}
 		blk, err := vm.newStandardBlock(preferredID, txs)
 		if err != nil {
 			return nil, err
@@ -456,6 +463,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 	if len(vm.unissuedAtomicTxs) > 0 {
 		tx := vm.unissuedAtomicTxs[0]
 		vm.unissuedAtomicTxs = vm.unissuedAtomicTxs[1:]
+		vm.untrackPendingTx(tx.ID())
 		blk, err := vm.newAtomicBlock(preferredID, tx)
 		if err != nil {
 			return nil, err
@@ -549,6 +557,7 @@ func (vm *VM) BuildBlock() (snowman.Block, error) {
 	syncTime := localTime.Add(Delta)
 	for vm.unissuedEvents.Len() > 0 {
 		tx := vm.unissuedEvents.Remove()
+		vm.untrackPendingTx(tx.ID())
 		if !syncTime.After(tx.StartTime()) {
 			blk, err := vm.newProposalBlock(preferredID, tx)
 			if err != nil {
@@ -698,7 +707,7 @@ func (vm *VM) resetTimer() {
 			return
 		}
 		// If the tx doesn't meet the synchrony bound, drop it
-		vm.unissuedEvents.Remove()
+		vm.untrackPendingTx(vm.unissuedEvents.Remove().ID())
 		vm.Ctx.Log.Debug("dropping tx to add validator because its start time has passed")
 	}
 
@@ -829,6 +838,63 @@ func (vm *VM) updateValidators(subnetID ids.ID) error {
 	return nil
 }
 
+// StakerInfo describes the stake of a validator of the default subnet
+type StakerInfo struct {
+	StartTime time.Time
+	EndTime   time.Time
+
+	// Amount the validator staked, not including what was delegated to it
+	StakeAmount uint64
+
+	// Address the stake and the reward are sent to once the validator stops
+	// validating
+	Destination ids.ShortID
+
+	// true if the validator hasn't started validating yet
+	Pending bool
+}
+
+// DefaultSubnetStaker returns the stake of the node with ID [nodeID], if it's
+// a current or pending validator of the default subnet. Returns false if it
+// isn't. Delegations to the node aren't counted as its stake.
+func (vm *VM) DefaultSubnetStaker(nodeID ids.ShortID) (StakerInfo, bool, error) {
+	current, err := vm.getCurrentValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		return StakerInfo{}, false, err
+	}
+	pending, err := vm.getPendingValidators(vm.DB, DefaultSubnetID)
+	if err != nil {
+		return StakerInfo{}, false, err
+	}
+
+	for _, heap := range []*EventHeap{current, pending} {
+		for _, tx := range heap.Txs {
+			tx, ok := tx.(*addDefaultSubnetValidatorTx)
+			if !ok || !tx.Vdr().ID().Equals(nodeID) {
+				continue
+			}
+			return StakerInfo{
+				StartTime:   tx.StartTime(),
+				EndTime:     tx.EndTime(),
+				StakeAmount: tx.Weight(),
+				Destination: tx.Destination,
+				Pending:     heap == pending,
+			}, true, nil
+		}
+	}
+	return StakerInfo{}, false, nil
+}
+
+// SubnetControlKeys returns the addresses of the keys that control the subnet
+// with ID [subnetID]. Returns an error if the subnet doesn't exist.
+func (vm *VM) SubnetControlKeys(subnetID ids.ID) ([]ids.ShortID, error) {
+	subnet, err := vm.getSubnet(vm.DB, subnetID)
+	if err != nil {
+		return nil, err
+	}
+	return subnet.ControlKeys, nil
+}
+
 // Codec ...
 func (vm *VM) Codec() codec.Codec { return vm.codec }
 
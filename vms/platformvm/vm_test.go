@@ -5,6 +5,7 @@ package platformvm
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -232,6 +233,101 @@ func GenesisCurrentValidators() *EventHeap {
 	return validators
 }
 
+func TestDefaultSubnetStaker(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	nodeID := keys[0].PublicKey().Address()
+	staker, ok, err := vm.DefaultSubnetStaker(nodeID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatalf("expected %s to be a validator", nodeID)
+	}
+	if staker.Pending {
+		t.Fatal("expected a genesis validator to be validating")
+	}
+	if staker.StakeAmount != defaultStakeAmount {
+		t.Fatalf("expected a stake of %d, got %d", defaultStakeAmount, staker.StakeAmount)
+	}
+	if !staker.Destination.Equals(nodeID) {
+		t.Fatalf("expected the reward to be sent to %s, got %s", nodeID, staker.Destination)
+	}
+	if !staker.StartTime.Equal(defaultValidateStartTime) || !staker.EndTime.Equal(defaultValidateEndTime) {
+		t.Fatalf("expected to validate from %s to %s, got %s to %s",
+			defaultValidateStartTime, defaultValidateEndTime, staker.StartTime, staker.EndTime)
+	}
+
+	if _, ok, err := vm.DefaultSubnetStaker(ids.NewShortID([20]byte{1})); err != nil {
+		t.Fatal(err)
+	} else if ok {
+		t.Fatal("expected an unknown node not to be a validator")
+	}
+}
+
+func TestVerifyState(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	if err := vm.VerifyState(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	// A validator that should have stopped before the chain's timestamp
+	if err := vm.putTimestamp(vm.DB, defaultValidateEndTime.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.VerifyState(context.Background()); err == nil {
+		t.Fatal("expected a validator that should have stopped to be reported")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := vm.putTimestamp(vm.DB, defaultGenesisTime); err != nil {
+		t.Fatal(err)
+	}
+	if err := vm.VerifyState(ctx); err != context.Canceled {
+		t.Fatalf("expected the check to be cancelled but got %v", err)
+	}
+}
+
+func TestSubnetControlKeys(t *testing.T) {
+	vm := defaultVM()
+	vm.Ctx.Lock.Lock()
+	defer func() {
+		vm.Shutdown()
+		vm.Ctx.Lock.Unlock()
+	}()
+
+	controlKeys, err := vm.SubnetControlKeys(testSubnet1.id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(controlKeys) != len(testSubnet1ControlKeys) {
+		t.Fatalf("expected %d control keys, got %d", len(testSubnet1ControlKeys), len(controlKeys))
+	}
+	keySet := ids.ShortSet{}
+	keySet.Add(controlKeys...)
+	for _, key := range testSubnet1ControlKeys {
+		if !keySet.Contains(key.PublicKey().Address()) {
+			t.Fatalf("expected %s to be a control key", key.PublicKey().Address())
+		}
+	}
+
+	if _, err := vm.SubnetControlKeys(ids.NewID([32]byte{1})); err == nil {
+		t.Fatal("expected an unknown subnet to have no control keys")
+	}
+}
+
 // Ensure genesis state is parsed from bytes and stored correctly
 func TestGenesis(t *testing.T) {
 	vm := defaultVM()
@@ -10,6 +10,7 @@ import (
 	"github.com/hashicorp/go-plugin"
 
 	"github.com/ava-labs/gecko/snow"
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp"
 )
 
 var (
@@ -17,7 +18,12 @@ var (
 )
 
 // Factory ...
-type Factory struct{ Path string }
+type Factory struct {
+	Path string
+
+	// HTTP configures how the plugin's HTTP handlers are served
+	HTTP ghttp.Config
+}
 
 // New ...
 func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
@@ -56,5 +62,6 @@ func (f *Factory) New(ctx *snow.Context) (interface{}, error) {
 	}
 
 	vm.SetProcess(client)
+	vm.SetHTTPConfig(f.HTTP)
 	return vm, nil
 }
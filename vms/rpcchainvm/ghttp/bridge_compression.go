@@ -0,0 +1,51 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+
+	// Registers the gzip compressor, so that both the node and the plugin
+	// can decompress the messages the other compresses
+	_ "google.golang.org/grpc/encoding/gzip"
+)
+
+// DefaultBridgeCompressionMinSize is the size, in bytes, of the smallest part
+// of a response the plugin compresses when bridge compression is enabled
+const DefaultBridgeCompressionMinSize = 1024
+
+// bridgeCompressionKey is the metadata key the node sends the plugin, with a
+// request, the smallest part of the response the plugin should compress in.
+// Plugins that don't know the key ignore it and send responses uncompressed.
+const bridgeCompressionKey = "ghttp-bridge-compression-min-size"
+
+// withBridgeCompression returns [ctx] asking the plugin to compress the parts
+// of the response that are at least [minSize] bytes
+func withBridgeCompression(ctx context.Context, minSize int) context.Context {
+	if minSize <= 0 {
+		minSize = DefaultBridgeCompressionMinSize
+	}
+	return metadata.AppendToOutgoingContext(ctx, bridgeCompressionKey, strconv.Itoa(minSize))
+}
+
+// bridgeCompressionMinSize returns the smallest part of the response the node
+// asked to be compressed in [ctx], or 0 if it didn't ask for compression
+func bridgeCompressionMinSize(ctx context.Context) int {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0
+	}
+	values := md.Get(bridgeCompressionKey)
+	if len(values) == 0 {
+		return 0
+	}
+	minSize, err := strconv.Atoi(values[0])
+	if err != nil || minSize <= 0 {
+		return 0
+	}
+	return minSize
+}
@@ -0,0 +1,272 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultCaptureMaxBodySize is the number of bytes of each body that are
+	// captured if no bound is configured
+	DefaultCaptureMaxBodySize = 64 * 1024
+
+	// DefaultCaptureMaxPairs is the number of request/response pairs that are
+	// kept if no bound is configured
+	DefaultCaptureMaxPairs = 16
+)
+
+// CaptureConfig configures the capturing of requests and their responses, for
+// debugging. Captured bodies are held in memory, and may hold secrets such as
+// keystore passwords, so capturing should only be enabled while debugging a
+// problem.
+type CaptureConfig struct {
+	// Paths are the prefixes of the paths of the requests that are captured.
+	// If empty, no requests are captured.
+	Paths []string
+
+	// MaxBodySize is the number of bytes of each request and response body
+	// that are captured. The rest of the body is left out. If not positive,
+	// DefaultCaptureMaxBodySize is used.
+	MaxBodySize int
+
+	// MaxPairs is the number of request/response pairs that are kept. Once
+	// it's reached, the oldest pair is dropped to make room for a new one. If
+	// not positive, DefaultCaptureMaxPairs is used.
+	MaxPairs int
+}
+
+// Capture is a request and the response it was replied to with
+type Capture struct {
+	// Time the request was received at
+	Time time.Time
+
+	Method        string
+	URL           string
+	RequestHeader http.Header
+	// The part of the request body the handler read, up to the capture's
+	// maximum body size
+	RequestBody          []byte
+	RequestBodyTruncated bool
+
+	// 0 if the handler didn't reply
+	StatusCode     int
+	ResponseHeader http.Header
+	// The response body, up to the capture's maximum body size
+	ResponseBody          []byte
+	ResponseBodyTruncated bool
+}
+
+// captureStore keeps the most recent captures
+type captureStore struct {
+	paths       []string
+	maxBodySize int
+	maxPairs    int
+
+	lock     sync.Mutex
+	captures []Capture
+}
+
+func newCaptureStore(config CaptureConfig) *captureStore {
+	if len(config.Paths) == 0 {
+		return nil
+	}
+	if config.MaxBodySize <= 0 {
+		config.MaxBodySize = DefaultCaptureMaxBodySize
+	}
+	if config.MaxPairs <= 0 {
+		config.MaxPairs = DefaultCaptureMaxPairs
+	}
+	return &captureStore{
+		paths:       config.Paths,
+		maxBodySize: config.MaxBodySize,
+		maxPairs:    config.MaxPairs,
+	}
+}
+
+// matches returns true if [r] should be captured
+func (s *captureStore) matches(r *http.Request) bool {
+	for _, path := range s.paths {
+		if strings.HasPrefix(r.URL.Path, path) {
+			return true
+		}
+	}
+	return false
+}
+
+// capture starts capturing [r] and the response written to [w]. The returned
+// body and writer must be used in their place, and the returned function
+// called once the response is complete to keep the capture.
+func (s *captureStore) capture(w http.ResponseWriter, r *http.Request) (io.ReadCloser, http.ResponseWriter, func()) {
+	body, writer, finish := startCapture(w, r, s.maxBodySize)
+	return body, writer, func() { s.add(finish()) }
+}
+
+// startCapture starts capturing [r] and the response written to [w], keeping
+// up to [maxBodySize] bytes of each body. The returned body and writer must be
+// used in their place, and the returned function called once the response is
+// complete to get the capture.
+func startCapture(w http.ResponseWriter, r *http.Request, maxBodySize int) (io.ReadCloser, http.ResponseWriter, func() Capture) {
+	capture := Capture{
+		Time:          time.Now(),
+		Method:        r.Method,
+		URL:           r.URL.String(),
+		RequestHeader: r.Header.Clone(),
+	}
+	body := &captureReadCloser{
+		ReadCloser: r.Body,
+		buffer:     captureBuffer{maxSize: maxBodySize},
+	}
+	writer := &captureResponseWriter{
+		ResponseWriter: w,
+		buffer:         captureBuffer{maxSize: maxBodySize},
+	}
+	return body, writer, func() Capture {
+		capture.RequestBody, capture.RequestBodyTruncated = body.result()
+		capture.StatusCode = writer.statusCode
+		capture.ResponseHeader = writer.header
+		capture.ResponseBody, capture.ResponseBodyTruncated = writer.buffer.bytes, writer.buffer.truncated
+		return capture
+	}
+}
+
+// add [capture], dropping the oldest captures if there are too many
+func (s *captureStore) add(capture Capture) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.captures = append(s.captures, capture)
+	if len(s.captures) > s.maxPairs {
+		s.captures = append([]Capture(nil), s.captures[len(s.captures)-s.maxPairs:]...)
+	}
+}
+
+// list returns the captures, oldest first
+func (s *captureStore) list() []Capture {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return append([]Capture(nil), s.captures...)
+}
+
+// clear drops every capture
+func (s *captureStore) clear() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.captures = nil
+}
+
+// Captures returns the requests the handler captured and their responses,
+// oldest first. Returns nil if capturing isn't enabled.
+func (c *Client) Captures() []Capture {
+	if c.captures == nil {
+		return nil
+	}
+	return c.captures.list()
+}
+
+// ClearCaptures drops the requests the handler captured and their responses
+func (c *Client) ClearCaptures() {
+	if c.captures != nil {
+		c.captures.clear()
+	}
+}
+
+// captureBuffer keeps up to [maxSize] bytes written to it
+type captureBuffer struct {
+	maxSize   int
+	bytes     []byte
+	truncated bool
+}
+
+func (b *captureBuffer) write(payload []byte) {
+	if room := b.maxSize - len(b.bytes); len(payload) > room {
+		payload = payload[:room]
+		b.truncated = true
+	}
+	b.bytes = append(b.bytes, payload...)
+}
+
+// captureReadCloser keeps the start of the body read from it. The body may be
+// read by the plugin while the capture is being kept.
+type captureReadCloser struct {
+	io.ReadCloser
+
+	lock   sync.Mutex
+	buffer captureBuffer
+}
+
+// Read ...
+func (r *captureReadCloser) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+
+	r.lock.Lock()
+	r.buffer.write(p[:n])
+	r.lock.Unlock()
+	return n, err
+}
+
+func (r *captureReadCloser) result() ([]byte, bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	return append([]byte(nil), r.buffer.bytes...), r.buffer.truncated
+}
+
+// captureResponseWriter keeps the status, headers and the start of the body of
+// the response written to it
+type captureResponseWriter struct {
+	http.ResponseWriter
+
+	statusCode int
+	header     http.Header
+	buffer     captureBuffer
+}
+
+// recordHeader records [statusCode] and the headers as the response's, unless
+// the final status was already recorded
+func (w *captureResponseWriter) recordHeader(statusCode int) {
+	if w.statusCode == 0 && statusCode >= http.StatusOK {
+		w.statusCode = statusCode
+		w.header = w.Header().Clone()
+	}
+}
+
+// WriteHeader ...
+func (w *captureResponseWriter) WriteHeader(statusCode int) {
+	w.recordHeader(statusCode)
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the OK status a write implies, but leaves writing the header
+// to the writers below, so that they can still reply with an error instead
+func (w *captureResponseWriter) Write(payload []byte) (int, error) {
+	w.recordHeader(http.StatusOK)
+	n, err := w.ResponseWriter.Write(payload)
+	w.buffer.write(payload[:n])
+	return n, err
+}
+
+// Flush ...
+func (w *captureResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack ...
+func (w *captureResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	return hijacker.Hijack()
+}
@@ -0,0 +1,236 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"mime"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/gresponsewriter"
+)
+
+// DefaultCompressibleContentTypes are the content types that are compressed if
+// no allowlist is configured. Entries ending in "/" match every subtype.
+var DefaultCompressibleContentTypes = []string{
+	"text/",
+	"application/json",
+	"application/javascript",
+	"application/x-javascript",
+}
+
+var errHijackUnsupported = errors.New("response writer doesn't support hijacking")
+
+// acceptsGzip returns true if [r] allows the response to be gzipped
+func acceptsGzip(r *http.Request) bool {
+	for _, value := range r.Header.Values("Accept-Encoding") {
+		for _, coding := range strings.Split(value, ",") {
+			params := strings.Split(coding, ";")
+			if !strings.EqualFold(strings.TrimSpace(params[0]), "gzip") {
+				continue
+			}
+			accepted := true
+			for _, param := range params[1:] {
+				param = strings.TrimSpace(param)
+				if !strings.HasPrefix(param, "q=") {
+					continue
+				}
+				if q, err := strconv.ParseFloat(param[2:], 64); err == nil && q == 0 {
+					accepted = false
+				}
+			}
+			if accepted {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// compressible returns true if [contentType] is matched by [allowed]. gRPC-Web
+// responses are never compressed, as gRPC-Web frames carry their own
+// compression flag and clients expect to read the frames as they were written.
+func compressible(contentType string, allowed []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || strings.HasPrefix(mediaType, "application/grpc-web") {
+		return false
+	}
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if strings.HasSuffix(pattern, "/") {
+			if strings.HasPrefix(mediaType, pattern) {
+				return true
+			}
+		} else if mediaType == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// DefaultCompressMinSize is the size, in bytes, of the smallest response body
+// that is compressed by default when the node is configured to compress
+// responses. Smaller bodies rarely shrink enough to be worth compressing.
+const DefaultCompressMinSize = 1024
+
+// gzipResponseWriter gzips the body written to it if the headers in place when
+// the status code is written show that the body is compressible, and the body
+// is at least [minSize] bytes.
+type gzipResponseWriter struct {
+	http.ResponseWriter
+	contentTypes []string
+	// If positive, the body is held back until [minSize] bytes of it are
+	// written, or the response is flushed or finished, to find out whether
+	// it's large enough to be compressed
+	minSize int
+	// If not nil, returns true if the response's ETag was generated by the
+	// bridge. Only those tags are weakened once the body is compressed, as
+	// they're hashes of the uncompressed body. A tag the plugin set is passed
+	// through as it is, so that clients echo back the tag the plugin expects.
+	weakenETag func() bool
+
+	wroteHeader bool
+	gz          *gzip.Writer
+
+	// true if the status code and the start of the body are being held back
+	pending    bool
+	statusCode int
+	buffered   []byte
+}
+
+// WriteHeader decides whether the body should be compressed and, if so,
+// updates the headers to describe the compressed body. If the body's size
+// decides it, the status code is held back until the size is known.
+// Informational responses are passed through, as they don't have a body.
+func (w *gzipResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	if gresponsewriter.Informational(statusCode) {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	w.wroteHeader = true
+
+	header := w.Header()
+	if statusCode < http.StatusOK ||
+		statusCode == http.StatusNoContent ||
+		statusCode == http.StatusNotModified ||
+		header.Get("Content-Encoding") != "" ||
+		!compressible(header.Get("Content-Type"), w.contentTypes) {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	if w.minSize <= 0 {
+		w.startCompressing(statusCode)
+		return
+	}
+	if length, err := strconv.Atoi(header.Get("Content-Length")); err == nil {
+		if length < w.minSize {
+			w.ResponseWriter.WriteHeader(statusCode)
+		} else {
+			w.startCompressing(statusCode)
+		}
+		return
+	}
+	w.pending = true
+	w.statusCode = statusCode
+}
+
+// startCompressing updates the headers to describe the compressed body and
+// writes them with [statusCode]
+func (w *gzipResponseWriter) startCompressing(statusCode int) {
+	header := w.Header()
+	header.Set("Content-Encoding", "gzip")
+	header.Del("Content-Length")
+	header.Add("Vary", "Accept-Encoding")
+	// A strong ETag identifies the exact bytes of the body, which compressing
+	// changes, so the bridge's own tags are weakened
+	if etag := header.Get("ETag"); etag != "" && !strings.HasPrefix(etag, "W/") && w.weakenETag != nil && w.weakenETag() {
+		header.Set("ETag", "W/"+etag)
+	}
+	w.gz = gzip.NewWriter(w.ResponseWriter)
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// release writes the held back status code and body, compressing the body if
+// [compress] is true
+func (w *gzipResponseWriter) release(compress bool) error {
+	w.pending = false
+	buffered := w.buffered
+	w.buffered = nil
+	if compress {
+		w.startCompressing(w.statusCode)
+		_, err := w.gz.Write(buffered)
+		return err
+	}
+	w.ResponseWriter.WriteHeader(w.statusCode)
+	_, err := w.ResponseWriter.Write(buffered)
+	return err
+}
+
+// discard drops the held back status code and body, if there are any, so that
+// an error can be replied instead of the response
+func (w *gzipResponseWriter) discard() {
+	w.pending = false
+	w.buffered = nil
+}
+
+// Write ...
+func (w *gzipResponseWriter) Write(payload []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.pending {
+		w.buffered = append(w.buffered, payload...)
+		if len(w.buffered) < w.minSize {
+			return len(payload), nil
+		}
+		return len(payload), w.release(true)
+	}
+	if w.gz == nil {
+		return w.ResponseWriter.Write(payload)
+	}
+	return w.gz.Write(payload)
+}
+
+// Flush sends what has been written to the client. A body that is still
+// being held back is sent uncompressed, as it's smaller than the minimum size.
+func (w *gzipResponseWriter) Flush() {
+	if w.pending {
+		_ = w.release(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack ...
+func (w *gzipResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	return hijacker.Hijack()
+}
+
+// Close writes the end of the body. A body that is still being held back is
+// written uncompressed, as it's smaller than the minimum size.
+func (w *gzipResponseWriter) Close() error {
+	if w.pending {
+		return w.release(false)
+	}
+	if w.gz == nil {
+		return nil
+	}
+	return w.gz.Close()
+}
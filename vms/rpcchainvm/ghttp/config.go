@@ -0,0 +1,211 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Config contains the options for serving plugin HTTP handlers
+type Config struct {
+	// GenerateRequestIDs, if true, causes a UUID to be generated and attached
+	// as the X-Request-ID of any request that doesn't already have a request
+	// ID. The generated ID is echoed in the response headers.
+	GenerateRequestIDs bool
+
+	// CompressResponses, if true, causes response bodies to be gzipped when the
+	// client accepts gzip and the body's Content-Type is compressible. Bodies
+	// that the handler already encoded are left alone.
+	CompressResponses bool
+
+	// CompressibleContentTypes are the content types that may be compressed.
+	// Entries ending in "/" match every subtype. If empty,
+	// DefaultCompressibleContentTypes is used.
+	CompressibleContentTypes []string
+
+	// CompressMinSize is the size, in bytes, of the smallest response body
+	// that is compressed. Compressing small bodies costs more CPU time than
+	// the bandwidth it saves is worth. Unless the handler sets the body's
+	// Content-Length, the start of the body is held back until it's known
+	// whether the body reaches this size, or the handler flushes it. If not
+	// positive, every compressible body is compressed.
+	CompressMinSize int
+
+	// BridgeCompression, if true, causes the plugin to gzip the parts of
+	// responses it sends to the node, which are otherwise sent uncompressed.
+	// This saves bandwidth between the plugin and the node for large
+	// responses, at the cost of the CPU time spent compressing them.
+	BridgeCompression bool
+
+	// BridgeCompressionMinSize is the size, in bytes, of the smallest part of
+	// a response that is compressed when BridgeCompression is enabled. Smaller
+	// parts aren't worth compressing. If not positive,
+	// DefaultBridgeCompressionMinSize is used.
+	BridgeCompressionMinSize int
+
+	// StreamResponses, if true, causes responses to be streamed to the client
+	// in chunks as the plugin's handler writes them, whatever their content
+	// type. The handler's writes are gathered into chunks of StreamChunkSize
+	// bytes, each of which is flushed to the client as soon as it's full, and
+	// calling Flush on the handler's writer sends the partial chunk right
+	// away. A write that fills a chunk doesn't return until the chunk was
+	// written to the client, so a slow client slows the handler down rather
+	// than the response being buffered.
+	StreamResponses bool
+
+	// StreamChunkSize is the size, in bytes, of the chunks responses are
+	// streamed in. If not positive, DefaultStreamChunkSize is used.
+	StreamChunkSize int
+
+	// ConveyRequestStart, if true, causes the time each request was received
+	// at to be passed to the plugin, whose handler can get it with
+	// RequestStart. Together with the deadline of the request's context, this
+	// lets the handler find out how much of the request's latency budget
+	// remains, including the time the request spent waiting in the node.
+	ConveyRequestStart bool
+
+	// IdleTimeout, if positive, is how long a request may go without its body
+	// being read from or its response being written to before its context is
+	// cancelled and the resources bridging it to the plugin are released. If
+	// nothing was written, the client is sent a 504.
+	IdleTimeout time.Duration
+
+	// WriteTimeout, if positive, is how long writing a part of the response to
+	// the client may take. If a write takes longer, the plugin's handler is
+	// told that the write failed, its context is cancelled and the resources
+	// bridging it to the plugin are released. The client's connection is then
+	// closed, as the response is incomplete.
+	WriteTimeout time.Duration
+
+	// HandlerTimeout, if positive, is how long the plugin's handler may take
+	// to handle a request, unless the request's path matches a prefix in
+	// RouteTimeouts. The handler's context has the deadline, and is cancelled
+	// once it passes. If nothing was written, the client is sent a 504.
+	HandlerTimeout time.Duration
+
+	// RouteTimeouts are the timeouts of the handlers of requests whose paths
+	// start with each prefix, such as a longer timeout for a known slow
+	// endpoint. If a path starts with several prefixes, the longest one's
+	// timeout is used. A timeout that isn't positive means requests to the
+	// route never time out.
+	RouteTimeouts map[string]time.Duration
+
+	// MaxConcurrentRequests is the number of requests that may be handled by
+	// the plugin at once. If not positive, the number isn't limited.
+	MaxConcurrentRequests int
+
+	// MaxQueuedRequests is the number of requests that may wait for another
+	// request to finish being handled, if MaxConcurrentRequests is positive.
+	// Requests beyond this are rejected with a 503. If not positive, any
+	// number of requests may wait.
+	MaxQueuedRequests int
+
+	// MaxResponseBodySize is the number of bytes of response body the
+	// plugin's handler may write. Writes beyond it fail, so that a
+	// misbehaving plugin can't make the node send an unbounded response. If
+	// not positive, DefaultMaxResponseBodySize is used.
+	MaxResponseBodySize int64
+
+	// TruncateLargeResponses, if true, causes a response body that exceeds
+	// MaxResponseBodySize to be cut off at the limit, and the truncation
+	// logged. Otherwise, the request is replied to with a 500 if the response
+	// hasn't been started, or the connection is dropped if it has, so that
+	// the client doesn't think the response is complete.
+	TruncateLargeResponses bool
+
+	// ETags, if true, causes 200 responses to GET requests to be tagged with
+	// a strong hash of their body, unless the handler tagged them itself. A
+	// request whose If-None-Match matches the tag is replied to with a 304
+	// instead of the body, which saves bandwidth for handlers that don't
+	// implement conditional requests. The body is held back until it's
+	// complete, so responses that are flushed aren't tagged.
+	ETags bool
+
+	// ETagMaxBodySize is the size, in bytes, of the largest response body
+	// that is tagged. Larger bodies are written untagged once they exceed it.
+	// If not positive, DefaultETagMaxBodySize is used.
+	ETagMaxBodySize int
+
+	// Metrics, if not nil, records the number, status and latency of
+	// requests, labeled by the endpoint the plugin's handler named with
+	// SetMetricsEndpoint, or by the request's path if it didn't name one
+	Metrics *Metrics
+
+	// RequestLogging is how verbosely requests are logged. It may be shared
+	// with other handlers, so that their verbosity is changed at once. If
+	// nil, requests aren't logged.
+	RequestLogging *RequestLogging
+
+	// TrustedProxies are the networks of proxies whose Forwarded headers are
+	// honored. When a request is forwarded by a trusted proxy, the plugin is
+	// passed the address, scheme and host of the request as the client sent
+	// it, rather than as the proxy sent it.
+	TrustedProxies []*net.IPNet
+
+	// CORSAllowedOrigins, if not empty, causes CORS preflight requests from
+	// these origins to be answered without calling the plugin, and CORS
+	// headers to be added to the responses to other requests from them. "*"
+	// allows every origin. If empty, every request, including preflight
+	// requests, is passed to the plugin, so that the plugin can handle CORS
+	// itself.
+	CORSAllowedOrigins []string
+
+	// CORSAllowedMethods are the methods that may be used in cross origin
+	// requests. If empty, GET, POST and HEAD are allowed.
+	CORSAllowedMethods []string
+
+	// CORSAllowedHeaders are the headers that may be sent in cross origin
+	// requests, in addition to Origin, Accept and Content-Type.
+	CORSAllowedHeaders []string
+
+	// ResponseHeaders are added to every response, such as security headers
+	// that should be consistent across plugins. Headers the plugin's handler
+	// sets replace them. They're also added to the responses the node replies
+	// with without calling the plugin, such as when the plugin is busy.
+	ResponseHeaders http.Header
+
+	// Idempotency configures the replaying of responses to retried requests
+	Idempotency IdempotencyConfig
+
+	// Retry configures the retrying of requests the plugin's handler was
+	// briefly unavailable for
+	Retry RetryConfig
+
+	// Capture configures the capturing of requests and their responses, for
+	// debugging. Nothing is captured unless paths to capture are configured.
+	Capture CaptureConfig
+
+	// PanicDetails, if true, causes the 500 replied to a request whose
+	// handler panicked to include the value the handler panicked with and
+	// the plugin's stack. Otherwise, the details are only logged. This can
+	// leak the plugin's internals to clients, so it should only be enabled
+	// during development.
+	PanicDetails bool
+
+	// IsolatePanics, if true, causes a panic in the plugin's handler to be
+	// replied to with a 500 by the handler itself, rather than being raised
+	// again in the node for the node's HTTP server to recover from. The panic
+	// is logged, and the resources held for the request are released before
+	// the handler returns. A response the plugin started writing before it
+	// panicked is aborted, so that the client doesn't think it's complete.
+	IsolatePanics bool
+
+	// Connections is the number of connections the requests to each of the
+	// plugin's handlers are spread over. If not above 1, every request to a
+	// handler shares a single connection, which is enough for most plugins.
+	// Dedicated connections are opened when the handlers are created, so
+	// latency sensitive plugins don't wait on them to be established, and are
+	// kept open by the keepalive pings. Each one costs memory in both the node
+	// and the plugin for its read and write buffers, 32 KiB each by default,
+	// and its transport goroutines, and the plugin keeps a listener open for
+	// it, so they should only be used for plugins that need them. Plugins that
+	// don't support dedicated connections share one.
+	Connections int
+
+	// Keepalive configures the pings of the servers the node bridges
+	// requests to the plugin with
+	Keepalive KeepaliveConfig
+}
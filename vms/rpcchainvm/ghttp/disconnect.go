@@ -0,0 +1,82 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+)
+
+var errClientDisconnected = errors.New("the client disconnected")
+
+// disconnectResponseWriter cancels the request's context once writing the
+// response to the client fails, as the client has gone away and the rest of
+// the response would be written into the void. net/http only notices the
+// client going away on its own if it's reading from the connection, which it
+// may not be while a response is being written. Cancelling the context
+// cancels the call to the plugin, so the plugin's handler is told to stop.
+type disconnectResponseWriter struct {
+	http.ResponseWriter
+	cancel context.CancelFunc
+
+	// written to by the servers the plugin writes the response through, and
+	// read once they've stopped
+	disconnected uint32 // accessed atomically
+}
+
+// newDisconnectResponseWriter returns a writer that cancels the returned
+// context once writing to [w] fails
+func newDisconnectResponseWriter(ctx context.Context, w http.ResponseWriter) (context.Context, *disconnectResponseWriter) {
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, &disconnectResponseWriter{
+		ResponseWriter: w,
+		cancel:         cancel,
+	}
+}
+
+// Write fails without writing once a write has failed, so the rest of the
+// response isn't written into a broken connection
+func (w *disconnectResponseWriter) Write(payload []byte) (int, error) {
+	if w.isDisconnected() {
+		return 0, errClientDisconnected
+	}
+	n, err := w.ResponseWriter.Write(payload)
+	if err != nil {
+		atomic.StoreUint32(&w.disconnected, 1)
+		w.cancel()
+	}
+	return n, err
+}
+
+// Flush ...
+func (w *disconnectResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok && !w.isDisconnected() {
+		flusher.Flush()
+	}
+}
+
+// Hijack ...
+func (w *disconnectResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	return hijacker.Hijack()
+}
+
+// isDisconnected returns true if writing to the client failed
+func (w *disconnectResponseWriter) isDisconnected() bool {
+	return atomic.LoadUint32(&w.disconnected) == 1
+}
+
+// clientGone returns true if the client of the request whose context is [ctx]
+// went away before its response was written, or if writing the response to it
+// failed
+func clientGone(ctx context.Context, w *disconnectResponseWriter) bool {
+	return ctx.Err() == context.Canceled || w.isDisconnected()
+}
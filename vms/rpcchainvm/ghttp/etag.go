@@ -0,0 +1,179 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/gresponsewriter"
+)
+
+// DefaultETagMaxBodySize is the size, in bytes, of the largest response body
+// that is tagged if no bound is configured
+const DefaultETagMaxBodySize = 1 << 20 // 1 MiB
+
+// etagResponseWriter holds back a 200 response until it's complete, so that it
+// can be tagged with a hash of its body. If the request's If-None-Match matches
+// the tag, a 304 is replied instead of the body. Responses the handler tagged
+// itself, or that are flushed or grow beyond [maxSize] bytes, are passed
+// through untagged.
+type etagResponseWriter struct {
+	http.ResponseWriter
+	// The request's If-None-Match header
+	ifNoneMatch []string
+	maxSize     int
+
+	wroteHeader bool
+	// true once the response has been tagged
+	generated bool
+
+	// true if the status code and the body are being held back
+	pending    bool
+	statusCode int
+	buffered   []byte
+}
+
+// newETagResponseWriter returns a writer that tags the response to [r], or
+// nil if the response to [r] shouldn't be tagged. Only GET requests are
+// tagged, as they're safe to answer from a cache. The body of a response to a
+// HEAD request is discarded before it's written, so it can't be hashed.
+func newETagResponseWriter(w http.ResponseWriter, r *http.Request, maxSize int) *etagResponseWriter {
+	if r.Method != http.MethodGet {
+		return nil
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultETagMaxBodySize
+	}
+	return &etagResponseWriter{
+		ResponseWriter: w,
+		ifNoneMatch:    r.Header.Values("If-None-Match"),
+		maxSize:        maxSize,
+	}
+}
+
+// WriteHeader holds back the status code of a response that can be tagged.
+// Informational responses are passed through, as they don't have a body.
+func (w *etagResponseWriter) WriteHeader(statusCode int) {
+	if w.wroteHeader {
+		return
+	}
+	if gresponsewriter.Informational(statusCode) {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	w.wroteHeader = true
+
+	if statusCode != http.StatusOK || w.Header().Get("ETag") != "" {
+		w.ResponseWriter.WriteHeader(statusCode)
+		return
+	}
+	w.pending = true
+	w.statusCode = statusCode
+}
+
+// Write ...
+func (w *etagResponseWriter) Write(payload []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.pending {
+		return w.ResponseWriter.Write(payload)
+	}
+	w.buffered = append(w.buffered, payload...)
+	if len(w.buffered) <= w.maxSize {
+		return len(payload), nil
+	}
+	return len(payload), w.release()
+}
+
+// release writes the held back status code and body untagged
+func (w *etagResponseWriter) release() error {
+	w.pending = false
+	buffered := w.buffered
+	w.buffered = nil
+	w.ResponseWriter.WriteHeader(w.statusCode)
+	_, err := w.ResponseWriter.Write(buffered)
+	return err
+}
+
+// finish tags the held back response, if there is one, and writes it, or
+// replies with a 304 if the request's If-None-Match matches the tag. Must be
+// called once the handler is done writing the response.
+func (w *etagResponseWriter) finish() error {
+	if !w.wroteHeader {
+		// Matching net/http, a handler that didn't write anything replies
+		// with an empty 200, which can be tagged too
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.pending {
+		return nil
+	}
+	w.pending = false
+
+	hash := sha256.Sum256(w.buffered)
+	etag := `"` + hex.EncodeToString(hash[:]) + `"`
+	header := w.Header()
+	header.Set("ETag", etag)
+	w.generated = true
+	if !etagMatches(w.ifNoneMatch, etag) {
+		return w.release()
+	}
+
+	// Matching net/http, the headers describing the body are dropped from a
+	// 304, as it has no body
+	header.Del("Content-Type")
+	header.Del("Content-Length")
+	header.Del("Content-Encoding")
+	header.Del("Last-Modified")
+	w.buffered = nil
+	w.ResponseWriter.WriteHeader(http.StatusNotModified)
+	return nil
+}
+
+// Flush sends what has been written to the client. A body that is still being
+// held back is sent untagged, as it isn't complete.
+func (w *etagResponseWriter) Flush() {
+	if w.pending {
+		_ = w.release()
+	}
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack ...
+func (w *etagResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	return hijacker.Hijack()
+}
+
+// etagMatches returns true if an If-None-Match header with values [values]
+// matches [etag]. Matching ignores whether the tags are weak, as If-None-Match
+// is compared weakly.
+func etagMatches(values []string, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, value := range values {
+		for _, candidate := range strings.Split(value, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// tagged returns true if the response was tagged by this writer, rather than
+// by the handler
+func (w *etagResponseWriter) tagged() bool {
+	return w.generated
+}
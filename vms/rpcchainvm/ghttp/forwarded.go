@@ -0,0 +1,175 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ForwardedHeader is the header proxies describe the requests they forward
+// in, as defined by RFC 7239
+const ForwardedHeader = "Forwarded"
+
+// ParseTrustedProxies parses a list of IPs and CIDR ranges into the networks
+// of proxies whose Forwarded headers are trusted
+func ParseTrustedProxies(proxies []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(proxies))
+	for _, proxy := range proxies {
+		if ip := net.ParseIP(proxy); ip != nil {
+			bits := 8 * net.IPv6len
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 8*net.IPv4len
+			}
+			networks = append(networks, &net.IPNet{
+				IP:   ip,
+				Mask: net.CIDRMask(bits, bits),
+			})
+			continue
+		}
+		_, network, err := net.ParseCIDR(proxy)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse trusted proxy %q", proxy)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// forwardedElement is one proxy's description of the request it forwarded
+type forwardedElement struct {
+	// node the proxy received the request from
+	forNode string
+	// protocol the proxy received the request over
+	proto string
+	// Host header of the request the proxy received
+	host string
+}
+
+// parseForwarded parses the elements of Forwarded headers [values], in the
+// order the proxies that added them forwarded the request. Malformed pairs are
+// ignored.
+func parseForwarded(values []string) []forwardedElement {
+	elements := []forwardedElement(nil)
+	for _, value := range values {
+		for _, rawElement := range splitQuoted(value, ',') {
+			element := forwardedElement{}
+			for _, pair := range splitQuoted(rawElement, ';') {
+				eq := strings.IndexByte(pair, '=')
+				if eq < 0 {
+					continue
+				}
+				key := strings.ToLower(strings.TrimSpace(pair[:eq]))
+				val := unquote(strings.TrimSpace(pair[eq+1:]))
+				switch key {
+				case "for":
+					element.forNode = val
+				case "proto":
+					element.proto = strings.ToLower(val)
+				case "host":
+					element.host = val
+				}
+			}
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// splitQuoted splits [s] at every [sep] that isn't within a quoted string
+func splitQuoted(s string, sep byte) []string {
+	parts := []string(nil)
+	quoted, escaped := false, false
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case escaped:
+			escaped = false
+		case quoted && s[i] == '\\':
+			escaped = true
+		case s[i] == '"':
+			quoted = !quoted
+		case !quoted && s[i] == sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unquote returns [s] without its surrounding quotes and escapes, if it's a
+// quoted string
+func unquote(s string) string {
+	if len(s) < 2 || s[0] != '"' || s[len(s)-1] != '"' {
+		return s
+	}
+	s = s[1 : len(s)-1]
+	unquoted := strings.Builder{}
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+		}
+		unquoted.WriteByte(s[i])
+	}
+	return unquoted.String()
+}
+
+// parseNode returns the IP and port of a Forwarded node, such as
+// "192.0.2.43", "192.0.2.43:47011" or "[2001:db8:cafe::17]:4711". Returns a nil
+// IP if the node is unknown or obfuscated.
+func parseNode(node string) (net.IP, string) {
+	host, port, err := net.SplitHostPort(node)
+	if err != nil {
+		host, port = strings.TrimSuffix(strings.TrimPrefix(node, "["), "]"), ""
+	}
+	return net.ParseIP(host), port
+}
+
+// trusted returns true if [ip] is one of [c]'s trusted proxies
+func (c *Client) trusted(ip net.IP) bool {
+	for _, network := range c.config.TrustedProxies {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forwardedFor returns the address, scheme and host of the request [r] as it
+// was sent by the client, if it was forwarded by trusted proxies. Values that
+// the proxies didn't report are empty. Proxies' elements are followed from the
+// most recent back to the first element whose node isn't a trusted proxy,
+// which is the client.
+func (c *Client) forwardedFor(r *http.Request) (remoteAddr, scheme, host string) {
+	peer, _ := parseNode(r.RemoteAddr)
+	if peer == nil || !c.trusted(peer) {
+		return "", "", ""
+	}
+	elements := parseForwarded(r.Header.Values(ForwardedHeader))
+	for i := len(elements) - 1; i >= 0; i-- {
+		element := elements[i]
+		if element.proto != "" {
+			scheme = element.proto
+		}
+		if element.host != "" {
+			host = element.host
+		}
+		ip, port := parseNode(element.forNode)
+		if ip == nil {
+			// The proxy didn't say who sent it the request, so it can't be
+			// followed any further
+			break
+		}
+		if port == "" {
+			port = "0"
+		}
+		remoteAddr = net.JoinHostPort(ip.String(), port)
+		if !c.trusted(ip) {
+			break
+		}
+	}
+	return remoteAddr, scheme, host
+}
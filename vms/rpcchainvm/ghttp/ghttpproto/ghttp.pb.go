@@ -412,6 +412,7 @@ type Request struct {
 	RemoteAddr           string           `protobuf:"bytes,14,opt,name=remoteAddr,proto3" json:"remoteAddr,omitempty"`
 	RequestURI           string           `protobuf:"bytes,15,opt,name=requestURI,proto3" json:"requestURI,omitempty"`
 	Tls                  *ConnectionState `protobuf:"bytes,16,opt,name=tls,proto3" json:"tls,omitempty"`
+	LocalAddr            string           `protobuf:"bytes,17,opt,name=localAddr,proto3" json:"localAddr,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}         `json:"-"`
 	XXX_unrecognized     []byte           `json:"-"`
 	XXX_sizecache        int32            `json:"-"`
@@ -554,6 +555,13 @@ func (m *Request) GetTls() *ConnectionState {
 	return nil
 }
 
+func (m *Request) GetLocalAddr() string {
+	if m != nil {
+		return m.LocalAddr
+	}
+	return ""
+}
+
 type HTTPRequest struct {
 	ResponseWriter       uint32   `protobuf:"varint,1,opt,name=responseWriter,proto3" json:"responseWriter,omitempty"`
 	Request              *Request `protobuf:"bytes,2,opt,name=request,proto3" json:"request,omitempty"`
@@ -632,6 +640,84 @@ func (m *HTTPResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_HTTPResponse proto.InternalMessageInfo
 
+type RoutesRequest struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoutesRequest) Reset()         { *m = RoutesRequest{} }
+func (m *RoutesRequest) String() string { return proto.CompactTextString(m) }
+func (*RoutesRequest) ProtoMessage()    {}
+func (*RoutesRequest) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e26bba3d5e69055f, []int{8}
+}
+
+func (m *RoutesRequest) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoutesRequest.Unmarshal(m, b)
+}
+func (m *RoutesRequest) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoutesRequest.Marshal(b, m, deterministic)
+}
+func (m *RoutesRequest) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoutesRequest.Merge(m, src)
+}
+func (m *RoutesRequest) XXX_Size() int {
+	return xxx_messageInfo_RoutesRequest.Size(m)
+}
+func (m *RoutesRequest) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoutesRequest.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoutesRequest proto.InternalMessageInfo
+
+type RoutesResponse struct {
+	Introspectable       bool     `protobuf:"varint,1,opt,name=introspectable,proto3" json:"introspectable,omitempty"`
+	Patterns             []string `protobuf:"bytes,2,rep,name=patterns,proto3" json:"patterns,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *RoutesResponse) Reset()         { *m = RoutesResponse{} }
+func (m *RoutesResponse) String() string { return proto.CompactTextString(m) }
+func (*RoutesResponse) ProtoMessage()    {}
+func (*RoutesResponse) Descriptor() ([]byte, []int) {
+	return fileDescriptor_e26bba3d5e69055f, []int{9}
+}
+
+func (m *RoutesResponse) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_RoutesResponse.Unmarshal(m, b)
+}
+func (m *RoutesResponse) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_RoutesResponse.Marshal(b, m, deterministic)
+}
+func (m *RoutesResponse) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_RoutesResponse.Merge(m, src)
+}
+func (m *RoutesResponse) XXX_Size() int {
+	return xxx_messageInfo_RoutesResponse.Size(m)
+}
+func (m *RoutesResponse) XXX_DiscardUnknown() {
+	xxx_messageInfo_RoutesResponse.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_RoutesResponse proto.InternalMessageInfo
+
+func (m *RoutesResponse) GetIntrospectable() bool {
+	if m != nil {
+		return m.Introspectable
+	}
+	return false
+}
+
+func (m *RoutesResponse) GetPatterns() []string {
+	if m != nil {
+		return m.Patterns
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*Userinfo)(nil), "ghttpproto.Userinfo")
 	proto.RegisterType((*URL)(nil), "ghttpproto.URL")
@@ -641,64 +727,70 @@ func init() {
 	proto.RegisterType((*Request)(nil), "ghttpproto.Request")
 	proto.RegisterType((*HTTPRequest)(nil), "ghttpproto.HTTPRequest")
 	proto.RegisterType((*HTTPResponse)(nil), "ghttpproto.HTTPResponse")
+	proto.RegisterType((*RoutesRequest)(nil), "ghttpproto.RoutesRequest")
+	proto.RegisterType((*RoutesResponse)(nil), "ghttpproto.RoutesResponse")
 }
 
 func init() { proto.RegisterFile("ghttp.proto", fileDescriptor_e26bba3d5e69055f) }
 
 var fileDescriptor_e26bba3d5e69055f = []byte{
-	// 819 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x54, 0xdf, 0x8f, 0x1b, 0x35,
-	0x10, 0x56, 0xba, 0xb9, 0xfc, 0x98, 0x4d, 0xee, 0x0e, 0x53, 0x81, 0x75, 0x45, 0x28, 0xac, 0x10,
-	0x44, 0x40, 0x0f, 0x29, 0x7d, 0x44, 0x42, 0x45, 0xa1, 0xa8, 0x15, 0x57, 0x74, 0xf8, 0x2e, 0xe2,
-	0xd9, 0x5d, 0x4f, 0xb2, 0xa6, 0xbb, 0xf6, 0xd6, 0xf6, 0xe6, 0x74, 0xff, 0x13, 0xaf, 0xfc, 0x71,
-	0xbc, 0x21, 0x7b, 0x77, 0x93, 0x4d, 0xd3, 0xde, 0xdb, 0xcc, 0xf7, 0x8d, 0xc7, 0x9e, 0xf9, 0x66,
-	0x0c, 0xf1, 0x26, 0x73, 0xae, 0xbc, 0x2c, 0x8d, 0x76, 0x9a, 0x40, 0x70, 0x82, 0x9d, 0x08, 0x18,
-	0xad, 0x2c, 0x1a, 0xa9, 0xd6, 0x9a, 0x5c, 0xc0, 0xa8, 0xb2, 0x68, 0x14, 0x2f, 0x90, 0xf6, 0x66,
-	0xbd, 0xf9, 0x98, 0xed, 0x7c, 0xcf, 0x95, 0xdc, 0xda, 0x3b, 0x6d, 0x04, 0x7d, 0x54, 0x73, 0xad,
-	0x4f, 0x66, 0x10, 0xb7, 0xf6, 0x0d, 0x3a, 0x1a, 0xcd, 0x7a, 0xf3, 0x11, 0xeb, 0x42, 0xc9, 0x7f,
-	0x3d, 0x88, 0x56, 0xec, 0x8a, 0x7c, 0x06, 0x03, 0x9b, 0x66, 0xb8, 0xcb, 0xdf, 0x78, 0x1e, 0xd7,
-	0x25, 0x7f, 0x57, 0x61, 0x93, 0xbb, 0xf1, 0xc8, 0x1c, 0xfa, 0xfe, 0x05, 0x21, 0x65, 0xbc, 0x78,
-	0x7c, 0xb9, 0x7f, 0xf8, 0x65, 0xfb, 0x6a, 0x16, 0x22, 0x08, 0x81, 0x7e, 0xa6, 0xad, 0xa3, 0xfd,
-	0x70, 0x3e, 0xd8, 0x1e, 0x2b, 0xb9, 0xcb, 0xe8, 0x49, 0x8d, 0x79, 0x9b, 0x50, 0x18, 0x1a, 0x7e,
-	0x77, 0xed, 0xe1, 0x41, 0x80, 0x5b, 0x97, 0x7c, 0x09, 0xb0, 0xd6, 0x26, 0xc5, 0x3f, 0x2b, 0x34,
-	0xf7, 0x74, 0x18, 0x8a, 0xe8, 0x20, 0xbe, 0x03, 0x86, 0xdf, 0xd5, 0xec, 0xa8, 0xee, 0x40, 0xeb,
-	0x7b, 0x6e, 0x6d, 0xf8, 0xa6, 0x40, 0xe5, 0xe8, 0xb8, 0xe6, 0x5a, 0x3f, 0x79, 0x06, 0xc3, 0x17,
-	0x39, 0x7a, 0x93, 0x9c, 0x43, 0xf4, 0x16, 0xef, 0x9b, 0xda, 0xbd, 0xe9, 0x0b, 0xdf, 0xf2, 0xbc,
-	0x42, 0x4b, 0x1f, 0xcd, 0x22, 0x5f, 0x78, 0xed, 0x25, 0x09, 0x4c, 0x96, 0x68, 0x9c, 0x5c, 0xcb,
-	0x94, 0x3b, 0xb4, 0xbe, 0x94, 0x14, 0x8d, 0xa3, 0xbd, 0x59, 0x34, 0x9f, 0xb0, 0x60, 0x27, 0xff,
-	0xf6, 0xe1, 0x6c, 0xa9, 0x95, 0xc2, 0xd4, 0x49, 0xad, 0x6e, 0x1c, 0x77, 0xe8, 0xcb, 0xdb, 0xa2,
-	0xb1, 0x52, 0xab, 0x70, 0xcb, 0x94, 0xb5, 0x2e, 0xf9, 0x01, 0x3e, 0xc9, 0xb8, 0x12, 0x36, 0xe3,
-	0x6f, 0x71, 0xa9, 0x8b, 0x32, 0x47, 0x57, 0x77, 0x7b, 0xc4, 0x8e, 0x09, 0xf2, 0x05, 0x8c, 0x85,
-	0x14, 0x0c, 0x6d, 0x55, 0x60, 0x23, 0xe8, 0x1e, 0xf0, 0x82, 0xa7, 0xb2, 0xcc, 0xd0, 0xdc, 0x54,
-	0xd2, 0x61, 0xe8, 0xf9, 0x94, 0x75, 0x21, 0x72, 0x09, 0x44, 0xe1, 0x46, 0x3b, 0xc9, 0x1d, 0x8a,
-	0x6b, 0x2f, 0x58, 0xaa, 0xf3, 0x46, 0x88, 0x0f, 0x30, 0xe4, 0x67, 0xb8, 0x38, 0x46, 0x5f, 0xd9,
-	0xd7, 0x95, 0xab, 0x78, 0x1e, 0x94, 0x1a, 0xb1, 0x07, 0x22, 0xbc, 0x78, 0x16, 0xcd, 0x16, 0xcd,
-	0x1f, 0x7e, 0x78, 0x87, 0xe1, 0x9e, 0x0e, 0x42, 0x7e, 0x85, 0xf3, 0x12, 0xd1, 0x74, 0x7b, 0x1a,
-	0x44, 0x8c, 0x17, 0xb4, 0x3b, 0x54, 0x5d, 0x9e, 0x1d, 0x9d, 0x20, 0xcf, 0xe1, 0x74, 0x8b, 0x46,
-	0xae, 0x25, 0x8a, 0x65, 0xc6, 0xa5, 0xb2, 0x74, 0x3c, 0x8b, 0x1e, 0xcc, 0xf1, 0x5e, 0x3c, 0x79,
-	0x0e, 0x4f, 0xac, 0xdc, 0x28, 0x14, 0x9d, 0xa8, 0x5b, 0x59, 0xa0, 0x75, 0xbc, 0x28, 0x2d, 0x85,
-	0x20, 0xef, 0x43, 0x21, 0x24, 0x81, 0x89, 0x4e, 0x6d, 0xc9, 0xd0, 0x96, 0x5a, 0x59, 0xa4, 0xf1,
-	0xac, 0x37, 0x9f, 0xb0, 0x03, 0xcc, 0xab, 0xe7, 0x72, 0xbb, 0x52, 0xd2, 0x6f, 0xd4, 0x24, 0x04,
-	0xec, 0x81, 0xe4, 0x9f, 0x3e, 0x0c, 0x19, 0xbe, 0xab, 0xd0, 0x3a, 0x3f, 0x7f, 0x05, 0xba, 0x4c,
-	0x8b, 0x76, 0x21, 0x6b, 0x8f, 0x7c, 0x05, 0x51, 0x65, 0xf2, 0x30, 0x1f, 0xf1, 0xe2, 0xec, 0x60,
-	0xef, 0xd8, 0x15, 0xf3, 0x1c, 0x79, 0x0c, 0x27, 0x01, 0x09, 0xe3, 0x31, 0x66, 0xb5, 0xe3, 0x85,
-	0x08, 0xc6, 0x6b, 0xfe, 0xb7, 0x36, 0x61, 0x32, 0x4e, 0x58, 0x07, 0xd9, 0xf3, 0x52, 0x69, 0x13,
-	0x06, 0x62, 0xc7, 0x7b, 0x84, 0x7c, 0x0f, 0x83, 0x0c, 0xb9, 0x40, 0x43, 0x07, 0xa1, 0xb5, 0x9f,
-	0x76, 0xef, 0x6e, 0xf6, 0x88, 0x35, 0x21, 0x7e, 0x2b, 0xde, 0x68, 0x51, 0x2f, 0xeb, 0x94, 0x05,
-	0x9b, 0x7c, 0x0d, 0xd3, 0x54, 0x2b, 0x87, 0xca, 0x5d, 0xa1, 0xda, 0xb8, 0x2c, 0xc8, 0x1c, 0xb1,
-	0x43, 0x90, 0x7c, 0x07, 0xe7, 0xce, 0x70, 0x65, 0xd7, 0x68, 0x5e, 0xa8, 0x54, 0x0b, 0xa9, 0x36,
-	0x41, 0xcb, 0x31, 0x3b, 0xc2, 0x77, 0x5f, 0x0b, 0x74, 0xbe, 0x96, 0x6f, 0xa1, 0xbf, 0xd6, 0xa6,
-	0xa0, 0xf1, 0xc7, 0x1f, 0x19, 0x02, 0xc8, 0x8f, 0x30, 0x2a, 0xb5, 0x75, 0xbf, 0xf9, 0xe0, 0xc9,
-	0xc7, 0x83, 0x77, 0x41, 0x7e, 0xb7, 0x9c, 0xe1, 0x32, 0x47, 0xf3, 0x3b, 0xde, 0x5b, 0x3a, 0x0d,
-	0x8f, 0xea, 0x42, 0xbe, 0x85, 0x06, 0x0b, 0xed, 0xf0, 0x17, 0x21, 0x0c, 0x3d, 0xad, 0x67, 0x7d,
-	0x8f, 0xd4, 0x7c, 0x90, 0x77, 0xc5, 0x5e, 0xd1, 0xb3, 0x96, 0x6f, 0x11, 0xf2, 0x14, 0x22, 0x97,
-	0x5b, 0x7a, 0x1e, 0xb4, 0x7d, 0x72, 0x30, 0xba, 0x87, 0xbf, 0x09, 0xf3, 0x71, 0x89, 0x80, 0xf8,
-	0xe5, 0xed, 0xed, 0x75, 0x3b, 0x31, 0xdf, 0xc0, 0xa9, 0x69, 0xe6, 0xec, 0x2f, 0x23, 0x1d, 0x9a,
-	0xe6, 0xa3, 0x79, 0x0f, 0x25, 0x4f, 0x61, 0xd8, 0xdc, 0xd9, 0x4c, 0xd1, 0x41, 0xdd, 0x4d, 0x36,
-	0xd6, 0xc6, 0x24, 0xa7, 0x30, 0xa9, 0x6f, 0xa9, 0x93, 0x2c, 0x96, 0xd0, 0xf7, 0x3e, 0xf9, 0x09,
-	0x06, 0x2f, 0xb9, 0x12, 0x39, 0x92, 0xcf, 0xbb, 0xe7, 0x3b, 0x2f, 0xba, 0xa0, 0xc7, 0x44, 0x9d,
-	0xe4, 0xcd, 0x20, 0x60, 0xcf, 0xfe, 0x0f, 0x00, 0x00, 0xff, 0xff, 0x42, 0xa3, 0x7e, 0xab, 0xfe,
-	0x06, 0x00, 0x00,
+	// 892 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x7c, 0x55, 0x5f, 0x6f, 0x1c, 0x35,
+	0x10, 0xd7, 0x75, 0x2f, 0xf7, 0x67, 0xee, 0x4f, 0x52, 0x53, 0x81, 0xb9, 0x22, 0x74, 0xac, 0x50,
+	0x39, 0x01, 0x0d, 0x52, 0xfa, 0x88, 0x04, 0x45, 0xa1, 0xa8, 0x15, 0x29, 0x0a, 0x4e, 0x22, 0x9e,
+	0x9d, 0xdd, 0xb9, 0x5b, 0xd3, 0x5d, 0x7b, 0x6b, 0x7b, 0x2f, 0xca, 0x3b, 0x5f, 0x89, 0x2f, 0xc1,
+	0x37, 0xe2, 0xad, 0xb2, 0xbd, 0x7b, 0xb7, 0x97, 0x6b, 0xf2, 0x36, 0xf3, 0xfb, 0xcd, 0xda, 0x33,
+	0xf3, 0x9b, 0xf1, 0xc2, 0x68, 0x95, 0x59, 0x5b, 0x1e, 0x97, 0x5a, 0x59, 0x45, 0xc0, 0x3b, 0xde,
+	0x8e, 0x53, 0x18, 0x5c, 0x19, 0xd4, 0x42, 0x2e, 0x15, 0x99, 0xc1, 0xa0, 0x32, 0xa8, 0x25, 0x2f,
+	0x90, 0x76, 0xe6, 0x9d, 0xc5, 0x90, 0x6d, 0x7c, 0xc7, 0x95, 0xdc, 0x98, 0x1b, 0xa5, 0x53, 0xfa,
+	0x28, 0x70, 0x8d, 0x4f, 0xe6, 0x30, 0x6a, 0xec, 0x0b, 0xb4, 0x34, 0x9a, 0x77, 0x16, 0x03, 0xd6,
+	0x86, 0xe2, 0xff, 0x3b, 0x10, 0x5d, 0xb1, 0x33, 0xf2, 0x29, 0xf4, 0x4c, 0x92, 0xe1, 0xe6, 0xfc,
+	0xda, 0x73, 0xb8, 0x2a, 0xf9, 0xfb, 0x0a, 0xeb, 0xb3, 0x6b, 0x8f, 0x2c, 0xa0, 0xeb, 0x32, 0xf0,
+	0x47, 0x8e, 0x4e, 0x9e, 0x1c, 0x6f, 0x13, 0x3f, 0x6e, 0xb2, 0x66, 0x3e, 0x82, 0x10, 0xe8, 0x66,
+	0xca, 0x58, 0xda, 0xf5, 0xdf, 0x7b, 0xdb, 0x61, 0x25, 0xb7, 0x19, 0x3d, 0x08, 0x98, 0xb3, 0x09,
+	0x85, 0xbe, 0xe6, 0x37, 0xe7, 0x0e, 0xee, 0x79, 0xb8, 0x71, 0xc9, 0x97, 0x00, 0x4b, 0xa5, 0x13,
+	0xfc, 0xb3, 0x42, 0x7d, 0x4b, 0xfb, 0xbe, 0x88, 0x16, 0xe2, 0x3a, 0xa0, 0xf9, 0x4d, 0x60, 0x07,
+	0xa1, 0x03, 0x8d, 0xef, 0xb8, 0xa5, 0xe6, 0xab, 0x02, 0xa5, 0xa5, 0xc3, 0xc0, 0x35, 0x7e, 0xfc,
+	0x02, 0xfa, 0xaf, 0x72, 0x74, 0x26, 0x39, 0x82, 0xe8, 0x1d, 0xde, 0xd6, 0xb5, 0x3b, 0xd3, 0x15,
+	0xbe, 0xe6, 0x79, 0x85, 0x86, 0x3e, 0x9a, 0x47, 0xae, 0xf0, 0xe0, 0xc5, 0x31, 0x8c, 0x4f, 0x51,
+	0x5b, 0xb1, 0x14, 0x09, 0xb7, 0x68, 0x5c, 0x29, 0x09, 0x6a, 0x4b, 0x3b, 0xf3, 0x68, 0x31, 0x66,
+	0xde, 0x8e, 0xff, 0xed, 0xc2, 0xe1, 0xa9, 0x92, 0x12, 0x13, 0x2b, 0x94, 0xbc, 0xb0, 0xdc, 0xa2,
+	0x2b, 0x6f, 0x8d, 0xda, 0x08, 0x25, 0xfd, 0x2d, 0x13, 0xd6, 0xb8, 0xe4, 0x7b, 0x78, 0x9c, 0x71,
+	0x99, 0x9a, 0x8c, 0xbf, 0xc3, 0x53, 0x55, 0x94, 0x39, 0xda, 0xd0, 0xed, 0x01, 0xdb, 0x27, 0xc8,
+	0x17, 0x30, 0x4c, 0x45, 0xca, 0xd0, 0x54, 0x05, 0xd6, 0x82, 0x6e, 0x01, 0x27, 0x78, 0x22, 0xca,
+	0x0c, 0xf5, 0x45, 0x25, 0x2c, 0xfa, 0x9e, 0x4f, 0x58, 0x1b, 0x22, 0xc7, 0x40, 0x24, 0xae, 0x94,
+	0x15, 0xdc, 0x62, 0x7a, 0xee, 0x04, 0x4b, 0x54, 0x5e, 0x0b, 0xf1, 0x11, 0x86, 0xfc, 0x04, 0xb3,
+	0x7d, 0xf4, 0x8d, 0x79, 0x5b, 0xd9, 0x8a, 0xe7, 0x5e, 0xa9, 0x01, 0x7b, 0x20, 0xc2, 0x89, 0x67,
+	0x50, 0xaf, 0x51, 0xff, 0xe1, 0x86, 0xb7, 0xef, 0xef, 0x69, 0x21, 0xe4, 0x57, 0x38, 0x2a, 0x11,
+	0x75, 0xbb, 0xa7, 0x5e, 0xc4, 0xd1, 0x09, 0x6d, 0x0f, 0x55, 0x9b, 0x67, 0x7b, 0x5f, 0x90, 0x97,
+	0x30, 0x5d, 0xa3, 0x16, 0x4b, 0x81, 0xe9, 0x69, 0xc6, 0x85, 0x34, 0x74, 0x38, 0x8f, 0x1e, 0x3c,
+	0xe3, 0x4e, 0x3c, 0x79, 0x09, 0x4f, 0x8d, 0x58, 0x49, 0x4c, 0x5b, 0x51, 0x97, 0xa2, 0x40, 0x63,
+	0x79, 0x51, 0x1a, 0x0a, 0x5e, 0xde, 0x87, 0x42, 0x48, 0x0c, 0x63, 0x95, 0x98, 0x92, 0xa1, 0x29,
+	0x95, 0x34, 0x48, 0x47, 0xf3, 0xce, 0x62, 0xcc, 0x76, 0x30, 0xa7, 0x9e, 0xcd, 0xcd, 0x95, 0x14,
+	0x6e, 0xa3, 0xc6, 0x3e, 0x60, 0x0b, 0xc4, 0xff, 0x75, 0xa1, 0xcf, 0xf0, 0x7d, 0x85, 0xc6, 0xba,
+	0xf9, 0x2b, 0xd0, 0x66, 0x2a, 0x6d, 0x16, 0x32, 0x78, 0xe4, 0x2b, 0x88, 0x2a, 0x9d, 0xfb, 0xf9,
+	0x18, 0x9d, 0x1c, 0xee, 0xec, 0x1d, 0x3b, 0x63, 0x8e, 0x23, 0x4f, 0xe0, 0xc0, 0x23, 0x7e, 0x3c,
+	0x86, 0x2c, 0x38, 0x4e, 0x08, 0x6f, 0xbc, 0xe5, 0x7f, 0x2b, 0xed, 0x27, 0xe3, 0x80, 0xb5, 0x90,
+	0x2d, 0x2f, 0xa4, 0xd2, 0xf4, 0xa0, 0xcd, 0x3b, 0x84, 0x7c, 0x07, 0xbd, 0x0c, 0x79, 0x8a, 0x9a,
+	0xf6, 0x7c, 0x6b, 0x3f, 0x69, 0xdf, 0x5d, 0xef, 0x11, 0xab, 0x43, 0xdc, 0x56, 0x5c, 0xab, 0x34,
+	0x2c, 0xeb, 0x84, 0x79, 0x9b, 0x7c, 0x0d, 0x93, 0x44, 0x49, 0x8b, 0xd2, 0x9e, 0xa1, 0x5c, 0xd9,
+	0xcc, 0xcb, 0x1c, 0xb1, 0x5d, 0x90, 0x7c, 0x0b, 0x47, 0x56, 0x73, 0x69, 0x96, 0xa8, 0x5f, 0xc9,
+	0x44, 0xa5, 0x42, 0xae, 0xbc, 0x96, 0x43, 0xb6, 0x87, 0x6f, 0x9e, 0x16, 0x68, 0x3d, 0x2d, 0xdf,
+	0x40, 0x77, 0xa9, 0x74, 0x41, 0x47, 0xf7, 0x27, 0xe9, 0x03, 0xc8, 0x0f, 0x30, 0x28, 0x95, 0xb1,
+	0xbf, 0xb9, 0xe0, 0xf1, 0xfd, 0xc1, 0x9b, 0x20, 0xb7, 0x5b, 0x56, 0x73, 0x91, 0xa3, 0xfe, 0x1d,
+	0x6f, 0x0d, 0x9d, 0xf8, 0xa4, 0xda, 0x90, 0x6b, 0xa1, 0xc6, 0x42, 0x59, 0xfc, 0x25, 0x4d, 0x35,
+	0x9d, 0x86, 0x59, 0xdf, 0x22, 0x81, 0xf7, 0xf2, 0x5e, 0xb1, 0x37, 0xf4, 0xb0, 0xe1, 0x1b, 0x84,
+	0x3c, 0x87, 0xc8, 0xe6, 0x86, 0x1e, 0x79, 0x6d, 0x9f, 0xee, 0x8c, 0xee, 0xee, 0x6b, 0xc2, 0x5c,
+	0x9c, 0x1b, 0xa6, 0x5c, 0x25, 0x3c, 0xf7, 0xb7, 0x3d, 0xf6, 0xa7, 0x6d, 0x81, 0x38, 0x85, 0xd1,
+	0xeb, 0xcb, 0xcb, 0xf3, 0x66, 0x9e, 0x9e, 0xc1, 0x54, 0xd7, 0x53, 0xf8, 0x97, 0x16, 0x16, 0x75,
+	0xfd, 0x0c, 0xdd, 0x41, 0xc9, 0x73, 0xe8, 0xd7, 0x19, 0xd5, 0x33, 0xb6, 0xd3, 0x95, 0xfa, 0x34,
+	0xd6, 0xc4, 0xc4, 0x53, 0x18, 0x87, 0x5b, 0xc2, 0x21, 0xf1, 0x21, 0x4c, 0x98, 0xaa, 0xdc, 0x82,
+	0xd5, 0x01, 0x97, 0x30, 0x6d, 0x80, 0x7a, 0x07, 0x9e, 0xc1, 0x54, 0x48, 0xab, 0x95, 0x29, 0x31,
+	0xb1, 0xfc, 0x3a, 0x0f, 0xbf, 0x9c, 0x01, 0xbb, 0x83, 0x86, 0x1f, 0x9b, 0xb5, 0xa8, 0x65, 0xf3,
+	0x06, 0x6f, 0xfc, 0x93, 0x7f, 0x3a, 0xd0, 0x75, 0xf7, 0x92, 0x1f, 0xa1, 0xf7, 0x9a, 0xcb, 0x34,
+	0x47, 0xf2, 0x59, 0x3b, 0xcf, 0x56, 0xe5, 0x33, 0xba, 0x4f, 0xd4, 0x99, 0xfc, 0x0c, 0xbd, 0x90,
+	0x1b, 0xf9, 0x7c, 0xa7, 0xc8, 0x76, 0x01, 0xb3, 0xd9, 0xc7, 0xa8, 0x70, 0xc0, 0x75, 0xcf, 0xa3,
+	0x2f, 0x3e, 0x0c, 0x00, 0x1c, 0xce, 0xfe, 0xc9, 0xc5, 0x07, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
@@ -714,6 +806,7 @@ const _ = grpc.SupportPackageIsVersion6
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://godoc.org/google.golang.org/grpc#ClientConn.NewStream.
 type HTTPClient interface {
 	Handle(ctx context.Context, in *HTTPRequest, opts ...grpc.CallOption) (*HTTPResponse, error)
+	Routes(ctx context.Context, in *RoutesRequest, opts ...grpc.CallOption) (*RoutesResponse, error)
 }
 
 type hTTPClient struct {
@@ -733,9 +826,19 @@ func (c *hTTPClient) Handle(ctx context.Context, in *HTTPRequest, opts ...grpc.C
 	return out, nil
 }
 
+func (c *hTTPClient) Routes(ctx context.Context, in *RoutesRequest, opts ...grpc.CallOption) (*RoutesResponse, error) {
+	out := new(RoutesResponse)
+	err := c.cc.Invoke(ctx, "/ghttpproto.HTTP/Routes", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HTTPServer is the server API for HTTP service.
 type HTTPServer interface {
 	Handle(context.Context, *HTTPRequest) (*HTTPResponse, error)
+	Routes(context.Context, *RoutesRequest) (*RoutesResponse, error)
 }
 
 // UnimplementedHTTPServer can be embedded to have forward compatible implementations.
@@ -745,6 +848,9 @@ type UnimplementedHTTPServer struct {
 func (*UnimplementedHTTPServer) Handle(ctx context.Context, req *HTTPRequest) (*HTTPResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Handle not implemented")
 }
+func (*UnimplementedHTTPServer) Routes(ctx context.Context, req *RoutesRequest) (*RoutesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Routes not implemented")
+}
 
 func RegisterHTTPServer(s *grpc.Server, srv HTTPServer) {
 	s.RegisterService(&_HTTP_serviceDesc, srv)
@@ -768,6 +874,24 @@ func _HTTP_Handle_Handler(srv interface{}, ctx context.Context, dec func(interfa
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HTTP_Routes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RoutesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HTTPServer).Routes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/ghttpproto.HTTP/Routes",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HTTPServer).Routes(ctx, req.(*RoutesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 var _HTTP_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "ghttpproto.HTTP",
 	HandlerType: (*HTTPServer)(nil),
@@ -776,6 +900,10 @@ var _HTTP_serviceDesc = grpc.ServiceDesc{
 			MethodName: "Handle",
 			Handler:    _HTTP_Handle_Handler,
 		},
+		{
+			MethodName: "Routes",
+			Handler:    _HTTP_Routes_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "ghttp.proto",
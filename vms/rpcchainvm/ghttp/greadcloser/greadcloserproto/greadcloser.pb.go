@@ -64,12 +64,13 @@ func (m *ReadRequest) GetLength() int32 {
 }
 
 type ReadResponse struct {
-	Read                 []byte   `protobuf:"bytes,1,opt,name=read,proto3" json:"read,omitempty"`
-	Error                string   `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	Errored              bool     `protobuf:"varint,3,opt,name=errored,proto3" json:"errored,omitempty"`
-	XXX_NoUnkeyedLiteral struct{} `json:"-"`
-	XXX_unrecognized     []byte   `json:"-"`
-	XXX_sizecache        int32    `json:"-"`
+	Read                 []byte     `protobuf:"bytes,1,opt,name=read,proto3" json:"read,omitempty"`
+	Error                string     `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Errored              bool       `protobuf:"varint,3,opt,name=errored,proto3" json:"errored,omitempty"`
+	Trailer              []*Element `protobuf:"bytes,4,rep,name=trailer,proto3" json:"trailer,omitempty"`
+	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
+	XXX_unrecognized     []byte     `json:"-"`
+	XXX_sizecache        int32      `json:"-"`
 }
 
 func (m *ReadResponse) Reset()         { *m = ReadResponse{} }
@@ -118,6 +119,13 @@ func (m *ReadResponse) GetErrored() bool {
 	return false
 }
 
+func (m *ReadResponse) GetTrailer() []*Element {
+	if m != nil {
+		return m.Trailer
+	}
+	return nil
+}
+
 type CloseRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -180,30 +188,82 @@ func (m *CloseResponse) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CloseResponse proto.InternalMessageInfo
 
+type Element struct {
+	Key                  string   `protobuf:"bytes,1,opt,name=key,proto3" json:"key,omitempty"`
+	Values               []string `protobuf:"bytes,2,rep,name=values,proto3" json:"values,omitempty"`
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
+}
+
+func (m *Element) Reset()         { *m = Element{} }
+func (m *Element) String() string { return proto.CompactTextString(m) }
+func (*Element) ProtoMessage()    {}
+func (*Element) Descriptor() ([]byte, []int) {
+	return fileDescriptor_21bd394eba12f5e9, []int{4}
+}
+
+func (m *Element) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Element.Unmarshal(m, b)
+}
+func (m *Element) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Element.Marshal(b, m, deterministic)
+}
+func (m *Element) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Element.Merge(m, src)
+}
+func (m *Element) XXX_Size() int {
+	return xxx_messageInfo_Element.Size(m)
+}
+func (m *Element) XXX_DiscardUnknown() {
+	xxx_messageInfo_Element.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Element proto.InternalMessageInfo
+
+func (m *Element) GetKey() string {
+	if m != nil {
+		return m.Key
+	}
+	return ""
+}
+
+func (m *Element) GetValues() []string {
+	if m != nil {
+		return m.Values
+	}
+	return nil
+}
+
 func init() {
 	proto.RegisterType((*ReadRequest)(nil), "greadcloserproto.ReadRequest")
 	proto.RegisterType((*ReadResponse)(nil), "greadcloserproto.ReadResponse")
 	proto.RegisterType((*CloseRequest)(nil), "greadcloserproto.CloseRequest")
 	proto.RegisterType((*CloseResponse)(nil), "greadcloserproto.CloseResponse")
+	proto.RegisterType((*Element)(nil), "greadcloserproto.Element")
 }
 
 func init() { proto.RegisterFile("greadcloser.proto", fileDescriptor_21bd394eba12f5e9) }
 
 var fileDescriptor_21bd394eba12f5e9 = []byte{
-	// 208 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x74, 0x4f, 0xcb, 0x8a, 0xc2, 0x40,
-	0x10, 0x64, 0x76, 0x93, 0xec, 0x6e, 0x6f, 0xf6, 0xd5, 0x2c, 0x32, 0x04, 0xd4, 0x30, 0x20, 0xe4,
-	0x94, 0x83, 0x7e, 0x82, 0x08, 0x9e, 0xe7, 0x0f, 0xa2, 0x69, 0xe2, 0x21, 0x64, 0xe2, 0x4c, 0xfc,
-	0x18, 0xff, 0x56, 0xe6, 0x21, 0x04, 0x31, 0xb7, 0xaa, 0xea, 0xa2, 0xaa, 0x1a, 0xfe, 0x1a, 0x4d,
-	0x55, 0x7d, 0x6c, 0x95, 0x21, 0x5d, 0xf6, 0x5a, 0x0d, 0x0a, 0x7f, 0x47, 0x92, 0x53, 0xc4, 0x0a,
-	0x3e, 0x25, 0x55, 0xb5, 0xa4, 0xf3, 0x85, 0xcc, 0x80, 0x33, 0x48, 0x5a, 0xea, 0x9a, 0xe1, 0xc4,
-	0x59, 0xce, 0x8a, 0x58, 0x06, 0x26, 0x24, 0xa4, 0xde, 0x66, 0x7a, 0xd5, 0x19, 0x42, 0x84, 0xc8,
-	0x26, 0x39, 0x57, 0x2a, 0x1d, 0xc6, 0x7f, 0x88, 0x49, 0x6b, 0xa5, 0xf9, 0x4b, 0xce, 0x8a, 0x0f,
-	0xe9, 0x09, 0x72, 0x78, 0x73, 0x80, 0x6a, 0xfe, 0x9a, 0xb3, 0xe2, 0x5d, 0xde, 0xa9, 0xf8, 0x86,
-	0x74, 0x6b, 0x97, 0x84, 0x6e, 0xf1, 0x03, 0x5f, 0x81, 0xfb, 0x92, 0xf5, 0x95, 0x41, 0x62, 0x5b,
-	0x49, 0xe3, 0x0e, 0x22, 0x8b, 0x70, 0x5e, 0x3e, 0x7e, 0x50, 0x8e, 0xe6, 0x67, 0x8b, 0xa9, 0x73,
-	0x98, 0xbd, 0x87, 0xd8, 0x55, 0xe0, 0x13, 0xe3, 0x78, 0x4b, 0xb6, 0x9c, 0xbc, 0xfb, 0xa4, 0x43,
-	0xe2, 0xc4, 0xcd, 0x2d, 0x00, 0x00, 0xff, 0xff, 0x8e, 0xaf, 0xb3, 0x35, 0x65, 0x01, 0x00, 0x00,
+	// 258 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x75, 0x50, 0x5b, 0x4a, 0x03, 0x41,
+	0x10, 0x64, 0xdd, 0x47, 0x92, 0xce, 0xaa, 0xb1, 0x11, 0x19, 0x03, 0x6a, 0x18, 0x10, 0xf2, 0xb5,
+	0x1f, 0xc9, 0x11, 0x42, 0x20, 0xdf, 0x73, 0x83, 0xd5, 0x34, 0x51, 0x1c, 0x77, 0x62, 0xef, 0x28,
+	0x78, 0x02, 0xcf, 0xe0, 0x6d, 0x9d, 0x99, 0x9d, 0xc0, 0xa2, 0xe6, 0xaf, 0xaa, 0xba, 0xba, 0xa9,
+	0x6a, 0xb8, 0xd8, 0x31, 0xd5, 0xdb, 0x47, 0x6d, 0x5a, 0xe2, 0x6a, 0xcf, 0xc6, 0x1a, 0x9c, 0xf4,
+	0xa4, 0xa0, 0xc8, 0x7b, 0x18, 0x2b, 0x27, 0x29, 0x7a, 0x7b, 0xa7, 0xd6, 0xe2, 0x15, 0x14, 0x9a,
+	0x9a, 0x9d, 0x7d, 0x12, 0xc9, 0x2c, 0x99, 0xe7, 0x2a, 0x32, 0xf9, 0x95, 0x40, 0xd9, 0xf9, 0xda,
+	0xbd, 0x69, 0x5a, 0x42, 0x84, 0xcc, 0x9f, 0x0a, 0xb6, 0x52, 0x05, 0x8c, 0x97, 0x90, 0x13, 0xb3,
+	0x61, 0x71, 0xe2, 0xc4, 0x91, 0xea, 0x08, 0x0a, 0x18, 0x04, 0x40, 0x5b, 0x91, 0x3a, 0x7d, 0xa8,
+	0x0e, 0x14, 0x97, 0x30, 0xb0, 0x5c, 0x3f, 0x6b, 0x62, 0x91, 0xcd, 0xd2, 0xf9, 0x78, 0x71, 0x5d,
+	0xfd, 0xce, 0x57, 0xad, 0x35, 0xbd, 0x52, 0x63, 0xd5, 0xc1, 0x29, 0xcf, 0xa0, 0x5c, 0xf9, 0x79,
+	0x4c, 0x2c, 0xcf, 0xe1, 0x34, 0xf2, 0x2e, 0x99, 0x74, 0x57, 0xe3, 0x12, 0x4e, 0x20, 0x7d, 0xa1,
+	0xcf, 0x90, 0x71, 0xa4, 0x3c, 0xf4, 0xfd, 0x3e, 0x6a, 0xed, 0x16, 0x5d, 0xc6, 0xd4, 0x89, 0x91,
+	0x2d, 0xbe, 0x13, 0x28, 0x7c, 0x3f, 0x62, 0x5c, 0x43, 0xe6, 0x11, 0xde, 0xfc, 0x0d, 0xd3, 0xfb,
+	0xd4, 0xf4, 0xf6, 0xd8, 0x38, 0x3e, 0x68, 0x03, 0x79, 0xc8, 0x85, 0xff, 0x18, 0xfb, 0x05, 0xa6,
+	0x77, 0x47, 0xe7, 0xdd, 0xa5, 0x87, 0x22, 0x88, 0xcb, 0x1f, 0x4a, 0x02, 0x07, 0xa2, 0xd0, 0x01,
+	0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
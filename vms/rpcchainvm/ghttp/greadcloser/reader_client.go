@@ -6,16 +6,27 @@ package greadcloser
 import (
 	"context"
 	"errors"
+	"io"
+	"net/http"
 
 	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/greadcloser/greadcloserproto"
 )
 
 // Client is an implementation of a messenger channel that talks over RPC.
-type Client struct{ client greadcloserproto.ReaderClient }
+type Client struct {
+	client greadcloserproto.ReaderClient
+	// trailer is filled in once the body has been fully read. It may be nil.
+	trailer http.Header
+}
 
-// NewClient returns a database instance connected to a remote database instance
-func NewClient(client greadcloserproto.ReaderClient) *Client {
-	return &Client{client: client}
+// NewClient returns a database instance connected to a remote database
+// instance. Once the body is fully read, the trailer sent along with it is
+// written to [trailer].
+func NewClient(client greadcloserproto.ReaderClient, trailer http.Header) *Client {
+	return &Client{
+		client:  client,
+		trailer: trailer,
+	}
 }
 
 // Read ...
@@ -29,8 +40,19 @@ func (c *Client) Read(p []byte) (int, error) {
 
 	copy(p, resp.Read)
 
+	if c.trailer != nil {
+		for _, elem := range resp.Trailer {
+			c.trailer[elem.Key] = elem.Values
+		}
+	}
+
 	if resp.Errored {
-		err = errors.New(resp.Error)
+		// io.EOF is kept as is so that readers can tell the body was fully read
+		if resp.Error == io.EOF.Error() {
+			err = io.EOF
+		} else {
+			err = errors.New(resp.Error)
+		}
 	}
 	return len(resp.Read), err
 }
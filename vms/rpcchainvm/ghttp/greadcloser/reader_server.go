@@ -6,16 +6,28 @@ package greadcloser
 import (
 	"context"
 	"io"
+	"net/http"
 
 	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/greadcloser/greadcloserproto"
 )
 
 // Server is a http.Handler that is managed over RPC.
-type Server struct{ readCloser io.ReadCloser }
+type Server struct {
+	readCloser io.ReadCloser
+	// trailer is sent along with the end of the body. It may be nil.
+	trailer *http.Header
+}
 
-// NewServer returns a http.Handler instance manage remotely
-func NewServer(readCloser io.ReadCloser) *Server {
-	return &Server{readCloser: readCloser}
+// NewServer returns a http.Handler instance manage remotely. [trailer] points
+// to the header that's filled in once [readCloser] is fully read, such as the
+// trailer of a request. It's a pointer because net/http replaces a nil
+// trailer, rather than filling it in, when trailers that weren't declared are
+// sent.
+func NewServer(readCloser io.ReadCloser, trailer *http.Header) *Server {
+	return &Server{
+		readCloser: readCloser,
+		trailer:    trailer,
+	}
 }
 
 // Read ...
@@ -29,6 +41,15 @@ func (s *Server) Read(ctx context.Context, req *greadcloserproto.ReadRequest) (*
 		resp.Errored = true
 		resp.Error = err.Error()
 	}
+	// The trailer is only known once the whole body has been read
+	if err == io.EOF && s.trailer != nil {
+		for key, values := range *s.trailer {
+			resp.Trailer = append(resp.Trailer, &greadcloserproto.Element{
+				Key:    key,
+				Values: values,
+			})
+		}
+	}
 	return resp, nil
 }
 
@@ -9,6 +9,9 @@ import (
 	"net"
 	"net/http"
 
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding/gzip"
+
 	"github.com/hashicorp/go-plugin"
 
 	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/gconn"
@@ -25,6 +28,30 @@ type Client struct {
 	client gresponsewriterproto.WriterClient
 	header http.Header
 	broker *plugin.GRPCBroker
+
+	// wroteHeader is true iff the status code has been sent to the server
+	wroteHeader bool
+
+	// discardBody is true iff payloads shouldn't be sent to the server
+	discardBody bool
+
+	// compressMinSize, if positive, is the size of the smallest payload that
+	// is compressed when it's sent to the server
+	compressMinSize int
+
+	// chunkSize, if positive, is the size of the chunks payloads are gathered
+	// into before they're sent to the server. [chunk] holds the payloads that
+	// haven't been sent yet.
+	chunkSize int
+	chunk     []byte
+
+	// ctx is the context of the request being responded to. Calls to the
+	// server are cancelled once it's cancelled.
+	ctx context.Context
+
+	// err is why the response was abandoned, if it was. Nothing more is sent
+	// to the server once it's set.
+	err error
 }
 
 // NewClient returns a database instance connected to a remote database instance
@@ -33,14 +60,109 @@ func NewClient(client gresponsewriterproto.WriterClient, broker *plugin.GRPCBrok
 		client: client,
 		header: make(http.Header),
 		broker: broker,
+		ctx:    context.Background(),
 	}
 }
 
 // Header ...
 func (c *Client) Header() http.Header { return c.header }
 
+// DiscardBody drops all the payloads passed to Write from now on, rather than
+// sending them to the server. Headers are still sent.
+func (c *Client) DiscardBody() { c.discardBody = true }
+
+// CompressWrites causes payloads of at least [minSize] bytes passed to Write
+// to be gzipped when they're sent to the server
+func (c *Client) CompressWrites(minSize int) { c.compressMinSize = minSize }
+
+// StreamChunks causes the payloads passed to Write to be gathered into chunks
+// of [size] bytes, each of which is sent to the server once it's full. Flush
+// sends the partial chunk. FinishChunk must be called once the response is
+// written, to send the last chunk.
+func (c *Client) StreamChunks(size int) {
+	c.chunkSize = size
+	c.chunk = make([]byte, 0, size)
+}
+
+// WroteHeader returns true iff the status code has been sent to the server
+func (c *Client) WroteHeader() bool { return c.wroteHeader }
+
+// AbandonWith causes the response to be abandoned once [ctx] is cancelled,
+// such as when the client goes away. Calls to the server are cancelled, and
+// Write fails from then on rather than calling the server.
+func (c *Client) AbandonWith(ctx context.Context) { c.ctx = ctx }
+
+// abandoned returns why the response was abandoned, or nil if it wasn't. A
+// response is abandoned once its context is cancelled, or once sending it to
+// the server fails.
+func (c *Client) abandoned() error {
+	if c.err == nil {
+		c.err = c.ctx.Err()
+	}
+	return c.err
+}
+
 // Write ...
 func (c *Client) Write(payload []byte) (int, error) {
+	if err := c.abandoned(); err != nil {
+		return 0, err
+	}
+	if c.discardBody {
+		if !c.wroteHeader {
+			c.WriteHeader(http.StatusOK)
+		}
+		return len(payload), nil
+	}
+	if c.chunkSize > 0 {
+		return c.writeChunked(payload)
+	}
+	return c.write(payload)
+}
+
+// writeChunked adds [payload] to the chunk, and sends every chunk that fills
+// up
+func (c *Client) writeChunked(payload []byte) (int, error) {
+	if !c.wroteHeader {
+		// The headers can't be changed once the body is written to, so they're
+		// sent now rather than with the first chunk
+		c.WriteHeader(http.StatusOK)
+	}
+	written := 0
+	for len(payload) > 0 {
+		n := c.chunkSize - len(c.chunk)
+		if n > len(payload) {
+			n = len(payload)
+		}
+		c.chunk = append(c.chunk, payload[:n]...)
+		payload = payload[n:]
+		written += n
+
+		if len(c.chunk) == c.chunkSize {
+			if err := c.FinishChunk(); err != nil {
+				return written - len(c.chunk), err
+			}
+		}
+	}
+	return written, nil
+}
+
+// FinishChunk sends the payloads that have been gathered into the current
+// chunk, if any
+func (c *Client) FinishChunk() error {
+	if len(c.chunk) == 0 {
+		return nil
+	}
+	n, err := c.write(c.chunk)
+	c.chunk = c.chunk[:copy(c.chunk, c.chunk[n:])]
+	return err
+}
+
+// write sends [payload] to the server
+func (c *Client) write(payload []byte) (int, error) {
+	if err := c.abandoned(); err != nil {
+		return 0, err
+	}
+	c.wroteHeader = true
 	req := &gresponsewriterproto.WriteRequest{
 		Headers: make([]*gresponsewriterproto.Header, 0, len(c.header)),
 		Payload: payload,
@@ -51,33 +173,59 @@ func (c *Client) Write(payload []byte) (int, error) {
 			Values: values,
 		})
 	}
-	resp, err := c.client.Write(context.Background(), req)
+	opts := []grpc.CallOption(nil)
+	if c.compressMinSize > 0 && len(payload) >= c.compressMinSize {
+		opts = append(opts, grpc.UseCompressor(gzip.Name))
+	}
+	resp, err := c.client.Write(c.ctx, req, opts...)
 	if err != nil {
+		c.err = err
 		return 0, err
 	}
 	return int(resp.Written), nil
 }
 
-// WriteHeader ...
+// Informational returns true iff [statusCode] is that of an interim response,
+// such as 103 Early Hints, which is followed by further responses. 101
+// Switching Protocols is final, as the connection stops speaking HTTP after it.
+func Informational(statusCode int) bool {
+	return statusCode >= 100 && statusCode < 200 && statusCode != http.StatusSwitchingProtocols
+}
+
+// WriteHeader sends the status code and headers to the server. Any number of
+// informational responses may be sent before the final one.
 func (c *Client) WriteHeader(statusCode int) {
 	req := &gresponsewriterproto.WriteHeaderRequest{
 		Headers:    make([]*gresponsewriterproto.Header, 0, len(c.header)),
 		StatusCode: int32(statusCode),
 	}
+	if !Informational(statusCode) {
+		c.wroteHeader = true
+	}
 	for key, values := range c.header {
 		req.Headers = append(req.Headers, &gresponsewriterproto.Header{
 			Key:    key,
 			Values: values,
 		})
 	}
-	// TODO: How should we handle an error here?
-	c.client.WriteHeader(context.Background(), req)
+	if c.abandoned() != nil {
+		return
+	}
+	// WriteHeader can't fail, so a failure is reported by the next Write
+	if _, err := c.client.WriteHeader(c.ctx, req); err != nil {
+		c.err = err
+	}
 }
 
 // Flush ...
 func (c *Client) Flush() {
-	// TODO: How should we handle an error here?
-	c.client.Flush(context.Background(), &gresponsewriterproto.FlushRequest{})
+	if err := c.FinishChunk(); err != nil || c.abandoned() != nil {
+		return
+	}
+	// Flush can't fail, so a failure is reported by the next Write
+	if _, err := c.client.Flush(c.ctx, &gresponsewriterproto.FlushRequest{}); err != nil {
+		c.err = err
+	}
 }
 
 type addr struct {
@@ -90,10 +238,15 @@ func (a *addr) String() string  { return a.str }
 
 // Hijack ...
 func (c *Client) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	if err := c.FinishChunk(); err != nil {
+		return nil, nil, err
+	}
 	resp, err := c.client.Hijack(context.Background(), &gresponsewriterproto.HijackRequest{})
 	if err != nil {
 		return nil, nil, err
 	}
+	// The server's response writer can't be used after it was hijacked
+	c.wroteHeader = true
 
 	connConn, err := c.broker.Dial(resp.ConnServer)
 	if err != nil {
@@ -4,6 +4,7 @@
 package gresponsewriter
 
 import (
+	"bufio"
 	"context"
 	"errors"
 	"net/http"
@@ -25,30 +26,57 @@ import (
 type Server struct {
 	writer http.ResponseWriter
 	broker *plugin.GRPCBroker
+
+	// baseHeaders are the headers that were set on [writer] before the
+	// plugin's handler was called. They are included in every response, with
+	// the plugin's headers taking precedence.
+	baseHeaders http.Header
+
+	// flushWrites is true iff [writer] is flushed after every write
+	flushWrites bool
 }
 
 // NewServer returns a http.Handler instance manage remotely
 func NewServer(writer http.ResponseWriter, broker *plugin.GRPCBroker) *Server {
 	return &Server{
-		writer: writer,
-		broker: broker,
+		writer:      writer,
+		broker:      broker,
+		baseHeaders: writer.Header().Clone(),
 	}
 }
 
-// Write ...
-func (s *Server) Write(ctx context.Context, req *gresponsewriterproto.WriteRequest) (*gresponsewriterproto.WriteResponse, error) {
+// FlushWrites causes every payload to be flushed to the client once it's
+// written, so that a streamed response reaches the client as it's sent
+func (s *Server) FlushWrites() { s.flushWrites = true }
+
+// setHeaders replaces the headers of the response with the base headers
+// overridden by [elements]. Hop-by-hop headers are kept, as net/http relies on
+// them; a Connection: close set by the plugin's handler causes the client's
+// connection to be closed after the response rather than reused.
+func (s *Server) setHeaders(elements []*gresponsewriterproto.Header) {
 	headers := s.writer.Header()
 	for key := range headers {
 		delete(headers, key)
 	}
-	for _, header := range req.Headers {
+	for key, values := range s.baseHeaders {
+		headers[key] = values
+	}
+	for _, header := range elements {
 		headers[header.Key] = header.Values
 	}
+}
+
+// Write ...
+func (s *Server) Write(ctx context.Context, req *gresponsewriterproto.WriteRequest) (*gresponsewriterproto.WriteResponse, error) {
+	s.setHeaders(req.Headers)
 
 	n, err := s.writer.Write(req.Payload)
 	if err != nil {
 		return nil, err
 	}
+	if flusher, ok := s.writer.(http.Flusher); ok && s.flushWrites {
+		flusher.Flush()
+	}
 	return &gresponsewriterproto.WriteResponse{
 		Written: int32(n),
 	}, nil
@@ -56,13 +84,7 @@ func (s *Server) Write(ctx context.Context, req *gresponsewriterproto.WriteReque
 
 // WriteHeader ...
 func (s *Server) WriteHeader(ctx context.Context, req *gresponsewriterproto.WriteHeaderRequest) (*gresponsewriterproto.WriteHeaderResponse, error) {
-	headers := s.writer.Header()
-	for key := range headers {
-		delete(headers, key)
-	}
-	for _, header := range req.Headers {
-		headers[header.Key] = header.Values
-	}
+	s.setHeaders(req.Headers)
 	s.writer.WriteHeader(int(req.StatusCode))
 	return &gresponsewriterproto.WriteHeaderResponse{}, nil
 }
@@ -104,7 +126,7 @@ func (s *Server) Hijack(ctx context.Context, req *gresponsewriterproto.HijackReq
 	})
 	go s.broker.AcceptAndServe(writerID, func(opts []grpc.ServerOption) *grpc.Server {
 		writerServer := grpc.NewServer(opts...)
-		gwriterproto.RegisterWriterServer(writerServer, gwriter.NewServer(readWriter))
+		gwriterproto.RegisterWriterServer(writerServer, gwriter.NewServer(flushingWriter{readWriter.Writer}))
 		return writerServer
 	})
 
@@ -121,3 +143,17 @@ func (s *Server) Hijack(ctx context.Context, req *gresponsewriterproto.HijackReq
 		WriterServer:  writerID,
 	}, nil
 }
+
+// flushingWriter flushes the buffered writer of a hijacked connection after
+// every write. The plugin buffers its writes itself and flushes them when it
+// wants them sent, so buffering them again here would hold them back
+// indefinitely.
+type flushingWriter struct{ writer *bufio.Writer }
+
+func (w flushingWriter) Write(payload []byte) (int, error) {
+	n, err := w.writer.Write(payload)
+	if err != nil {
+		return n, err
+	}
+	return n, w.writer.Flush()
+}
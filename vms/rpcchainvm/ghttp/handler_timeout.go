@@ -0,0 +1,108 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/gresponsewriter"
+)
+
+var errHandlerTimeout = errors.New("the request timed out before it was handled")
+
+// ParseRouteTimeouts parses a JSON object mapping path prefixes to timeouts,
+// such as {"/query": "2m"}, into the timeouts of the handlers of the routes
+// with those prefixes. An empty string is parsed into no timeouts.
+func ParseRouteTimeouts(timeouts string) (map[string]time.Duration, error) {
+	if timeouts == "" {
+		return nil, nil
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal([]byte(timeouts), &values); err != nil {
+		return nil, fmt.Errorf("couldn't parse route timeouts %q: %w", timeouts, err)
+	}
+	routeTimeouts := make(map[string]time.Duration, len(values))
+	for prefix, value := range values {
+		timeout, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't parse the timeout of route %q: %w", prefix, err)
+		}
+		routeTimeouts[prefix] = timeout
+	}
+	return routeTimeouts, nil
+}
+
+// handlerTimeout returns how long the handler may take to handle a request for
+// [path]. That's the timeout of the longest prefix of [path] in
+// [RouteTimeouts], or [HandlerTimeout] if no prefix matches.
+func (c *Config) handlerTimeout(path string) time.Duration {
+	timeout := c.HandlerTimeout
+	longest := -1
+	for prefix, prefixTimeout := range c.RouteTimeouts {
+		if len(prefix) > longest && strings.HasPrefix(path, prefix) {
+			timeout = prefixTimeout
+			longest = len(prefix)
+		}
+	}
+	return timeout
+}
+
+// deadlineExceeded returns true if the handler ran out of time to handle the
+// request, given the context it was called with and the error it returned
+func deadlineExceeded(ctx context.Context, err error) bool {
+	return ctx.Err() == context.DeadlineExceeded || status.Code(err) == codes.DeadlineExceeded
+}
+
+// deadlineResponseWriter records whether the response was started, so that a
+// request whose handler ran out of time can be replied to with a 504 if it
+// wasn't
+type deadlineResponseWriter struct {
+	http.ResponseWriter
+
+	wroteHeader bool
+}
+
+// WriteHeader ...
+func (w *deadlineResponseWriter) WriteHeader(statusCode int) {
+	if !gresponsewriter.Informational(statusCode) {
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write ...
+func (w *deadlineResponseWriter) Write(payload []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(payload)
+}
+
+// Flush ...
+func (w *deadlineResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack ...
+func (w *deadlineResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	// The handler takes over managing the connection, so nothing is written
+	// to it here
+	w.wroteHeader = true
+	return hijacker.Hijack()
+}
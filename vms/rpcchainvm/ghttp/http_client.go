@@ -4,12 +4,19 @@
 package ghttp
 
 import (
+	"context"
 	"net/http"
+	"net/textproto"
+	"sync"
+	"time"
+
+	"github.com/rs/cors"
 
 	"google.golang.org/grpc"
 
 	"github.com/hashicorp/go-plugin"
 
+	"github.com/ava-labs/gecko/utils/logging"
 	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/ghttpproto"
 	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/greadcloser"
 	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/greadcloser/greadcloserproto"
@@ -19,34 +26,387 @@ import (
 
 // Client is an implementation of a messenger channel that talks over RPC.
 type Client struct {
-	client ghttpproto.HTTPClient
-	broker *plugin.GRPCBroker
+	client  ghttpproto.HTTPClient
+	broker  *plugin.GRPCBroker
+	log     logging.Logger
+	config  Config
+	limiter *limiter
+	cors    *cors.Cors
+	// nil if responses aren't replayed
+	idempotency *idempotencyCache
+	// nil if requests aren't captured
+	captures *captureStore
+
+	maintenance uint32 // accessed atomically
 }
 
 // NewClient returns a database instance connected to a remote database instance
-func NewClient(client ghttpproto.HTTPClient, broker *plugin.GRPCBroker) *Client {
-	return &Client{
-		client: client,
-		broker: broker,
+func NewClient(client ghttpproto.HTTPClient, broker *plugin.GRPCBroker, log logging.Logger, config Config) *Client {
+	c := &Client{
+		client:  client,
+		broker:  broker,
+		log:     log,
+		config:  config,
+		limiter: newLimiter(config.MaxConcurrentRequests, config.MaxQueuedRequests),
+
+		idempotency: newIdempotencyCache(config.Idempotency),
+		captures:    newCaptureStore(config.Capture),
+	}
+	if len(config.CORSAllowedOrigins) > 0 {
+		c.cors = cors.New(cors.Options{
+			AllowedOrigins: config.CORSAllowedOrigins,
+			AllowedMethods: config.CORSAllowedMethods,
+			AllowedHeaders: append([]string{"Origin", "Accept", "Content-Type"}, config.CORSAllowedHeaders...),
+		})
+	}
+	return c
+}
+
+// bridgeServers are the servers that bridge a request's body and response
+// writer to the plugin. The request may finish before the plugin connects to
+// them, so servers that are added after they were stopped are stopped
+// immediately.
+type bridgeServers struct {
+	lock    sync.Mutex
+	stopped bool
+	servers []*grpc.Server
+}
+
+func (s *bridgeServers) add(server *grpc.Server) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if s.stopped {
+		server.Stop()
+		return
 	}
+	s.servers = append(s.servers, server)
+}
+
+func (s *bridgeServers) stop() {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for _, server := range s.servers {
+		server.Stop()
+	}
+	s.servers = nil
+	s.stopped = true
+}
+
+// canonicalHeader returns [header] with every key in canonical form. Values of
+// keys that only differ in casing are merged.
+func canonicalHeader(header http.Header) http.Header {
+	canonical := make(http.Header, len(header))
+	for key, values := range header {
+		canonicalKey := textproto.CanonicalMIMEHeaderKey(key)
+		canonical[canonicalKey] = append(canonical[canonicalKey], values...)
+	}
+	return canonical
+}
+
+// receivedTrailerContextKey is the key the trailer of the request the handler
+// was given is stored under in the context of copies of that request
+type receivedTrailerContextKey struct{}
+
+// withReceivedTrailer returns [r], a copy of the request the handler was given,
+// with [trailer], the trailer of the given request, stored in its context.
+// Once the body has been read, net/http stores trailers that weren't declared
+// in a new trailer on the request it gave the handler, which copies don't see.
+func withReceivedTrailer(r *http.Request, trailer *http.Header) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), receivedTrailerContextKey{}, trailer))
+}
+
+// receivedTrailer returns the trailer of the request the handler was given,
+// which [r] is, or is a copy of
+func receivedTrailer(r *http.Request) *http.Header {
+	if trailer, ok := r.Context().Value(receivedTrailerContextKey{}).(*http.Header); ok {
+		return trailer
+	}
+	return &r.Trailer
 }
 
 // Handle ...
 func (c *Client) ServeHTTP(w http.ResponseWriter, r *http.Request) {
-	var reader *grpc.Server
-	var writer *grpc.Server
+	if c.config.ConveyRequestStart {
+		received := r
+		r = withReceivedTrailer(withRequestStart(r, time.Now()), &received.Trailer)
+	}
+	addResponseHeaders(w.Header(), c.config.ResponseHeaders)
+	if c.InMaintenance() {
+		c.log.Verbo("rejecting %s %s as the handler is in maintenance", r.Method, r.URL)
+		w.Header().Set("Retry-After", maintenanceRetryAfterSeconds)
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	if c.cors == nil {
+		c.serveHTTP(w, r)
+		return
+	}
+	// Preflight requests are answered without calling [c.serveHTTP]
+	c.cors.ServeHTTP(w, r, c.serveHTTP)
+}
+
+// serveHTTP passes the request to the plugin, unless the response to an
+// earlier request with the same idempotency key can be replayed
+func (c *Client) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if c.idempotency != nil {
+		if key := r.Header.Get(IdempotencyKeyHeader); key != "" {
+			c.idempotency.serve(w, r, key, c.handle)
+			return
+		}
+	}
+	c.handle(w, r)
+}
+
+// handle passes the request to the plugin
+func (c *Client) handle(w http.ResponseWriter, r *http.Request) {
+	if c.config.Metrics != nil {
+		metricsWriter := &metricsResponseWriter{
+			ResponseWriter: w,
+			endpoint:       normalizePath(r.URL.Path),
+		}
+		w = metricsWriter
+		start := time.Now()
+		defer func() {
+			endpoint, statusCode := metricsWriter.finish()
+			c.config.Metrics.observe(endpoint, statusCode, time.Since(start))
+		}()
+	}
+
+	if c.config.GenerateRequestIDs && !hasRequestID(r.Header) {
+		requestID, err := newRequestID()
+		if err != nil {
+			c.log.Error("failed to generate request ID: %s", err)
+		} else {
+			c.log.Verbo("generated request ID %s for %s %s", requestID, r.Method, r.URL)
+			r.Header.Set(RequestIDHeader, requestID)
+			w.Header().Set(RequestIDHeader, requestID)
+		}
+	}
+
+	if !c.limiter.acquire(r.Context()) {
+		c.log.Debug("rejecting %s %s as too many requests are being handled", r.Method, r.URL)
+		w.Header().Set("Retry-After", retryAfterSeconds)
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+		return
+	}
+	// The slot is released early if the response stalls, as the plugin's
+	// handler is released then
+	release := sync.Once{}
+	defer release.Do(c.limiter.release)
+
+	// The plugin's handler is told to stop once the client goes away
+	ctx, disconnectWriter := newDisconnectResponseWriter(r.Context(), w)
+	defer disconnectWriter.cancel()
+	w = disconnectWriter
+
+	var timeoutWriter *timeoutResponseWriter
+	if c.config.WriteTimeout > 0 {
+		ctx, timeoutWriter = newTimeoutResponseWriter(ctx, w, c.config.WriteTimeout)
+		w = timeoutWriter
+	}
+
+	timeout := c.config.handlerTimeout(r.URL.Path)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	// The request's context may have a deadline even if the handler has no
+	// timeout, so whether the response was started is always recorded
+	deadlineWriter := &deadlineResponseWriter{ResponseWriter: w}
+	w = deadlineWriter
+
+	var gzipWriter *gzipResponseWriter
+	if c.config.CompressResponses && r.Method != http.MethodHead && acceptsGzip(r) {
+		contentTypes := c.config.CompressibleContentTypes
+		if len(contentTypes) == 0 {
+			contentTypes = DefaultCompressibleContentTypes
+		}
+		gzipWriter = &gzipResponseWriter{
+			ResponseWriter: w,
+			contentTypes:   contentTypes,
+			minSize:        c.config.CompressMinSize,
+		}
+		defer func() {
+			if err := gzipWriter.Close(); err != nil {
+				c.log.Debug("failed to finish compressing the response: %s", err)
+			}
+		}()
+		w = gzipWriter
+	}
+
+	var idleWriter *idleResponseWriter
+	if c.config.IdleTimeout > 0 {
+		var timer *idleTimer
+		ctx, timer = newIdleTimer(ctx, c.config.IdleTimeout)
+		defer timer.cancel()
+		r.Body = &idleReadCloser{
+			ReadCloser: r.Body,
+			timer:      timer,
+		}
+		idleWriter = &idleResponseWriter{
+			ResponseWriter: w,
+			timer:          timer,
+		}
+		w = idleWriter
+	}
+
+	limitWriter := &limitedResponseWriter{
+		ResponseWriter: w,
+		remaining:      c.config.maxResponseBodySize(),
+		truncate:       c.config.TruncateLargeResponses,
+	}
+	w = limitWriter
+
+	var etagWriter *etagResponseWriter
+	if c.config.ETags {
+		etagWriter = newETagResponseWriter(w, r, c.config.ETagMaxBodySize)
+		if etagWriter != nil {
+			w = etagWriter
+			if gzipWriter != nil {
+				gzipWriter.weakenETag = etagWriter.tagged
+			}
+		}
+	}
+
+	if c.captures != nil && c.captures.matches(r) {
+		var keep func()
+		r.Body, w, keep = c.captures.capture(w, r)
+		defer keep()
+	}
+
+	if level := c.config.RequestLogging.Level(); level != RequestLogNone {
+		maxBodySize := 0
+		if level == RequestLogFull {
+			maxBodySize = c.config.RequestLogging.maxBodySize
+		}
+		var finish func() Capture
+		r.Body, w, finish = startCapture(w, r, maxBodySize)
+		start := time.Now()
+		defer func() {
+			logRequest(c.log, level, finish(), time.Since(start))
+		}()
+	}
+
+	err := c.callWithRetries(ctx, w, r)
+
+	// replyError replies with an error in place of the response, which must
+	// not have been started. The start of a response that's held back to
+	// decide whether to compress it is dropped, so it isn't written after the
+	// error.
+	replyError := func(message string, statusCode int) {
+		if gzipWriter != nil {
+			gzipWriter.discard()
+		}
+		http.Error(deadlineWriter.ResponseWriter, message, statusCode)
+	}
+
+	panicErr, panicked := parsePanicError(err)
+	if panicked {
+		if panicErr.Value == http.ErrAbortHandler.Error() {
+			panic(http.ErrAbortHandler)
+		}
+		panicErr.details = c.config.PanicDetails
+		if !c.config.IsolatePanics {
+			panic(panicErr)
+		}
+		c.log.Error("%s %s panicked: %s\n%s", r.Method, r.URL, panicErr.Value, panicErr.Stack)
+		// The panic is replied to below, once the request's resources have
+		// been released
+		err = nil
+	}
+	if clientGone(r.Context(), disconnectWriter) {
+		c.log.Debug("%s %s was abandoned as the client disconnected", r.Method, r.URL)
+	} else if err != nil {
+		c.log.Debug("%s %s failed with: %s", r.Method, r.URL, err)
+	}
+
+	// A response that failed, or whose handler panicked, may be incomplete,
+	// so it's dropped rather than tagged
+	if etagWriter != nil && err == nil && !panicked {
+		if err := etagWriter.finish(); err != nil {
+			c.log.Debug("failed to write the tagged response: %s", err)
+		}
+	}
+
+	if timeoutWriter != nil {
+		release.Do(c.limiter.release)
+		if timeoutWriter.wait() {
+			c.log.Debug("%s %s timed out writing the response after %s", r.Method, r.URL, c.config.WriteTimeout)
+			// The response was truncated, so the connection is dropped rather
+			// than letting the client think it was complete
+			panic(http.ErrAbortHandler)
+		}
+	}
+
+	if idleWriter != nil && idleWriter.timer.stop() {
+		c.log.Debug("%s %s was idle for %s", r.Method, r.URL, c.config.IdleTimeout)
+		if !idleWriter.wroteHeader {
+			idleWriter.ResponseWriter.WriteHeader(http.StatusGatewayTimeout)
+		}
+	}
+
+	if panicked {
+		if deadlineWriter.wroteHeader {
+			// The response was truncated, so the connection is dropped rather
+			// than letting the client think it was complete
+			panic(http.ErrAbortHandler)
+		}
+		body := panicErr.PanicResponse()
+		if body == "" {
+			body = http.StatusText(http.StatusInternalServerError)
+		}
+		replyError(body, http.StatusInternalServerError)
+		return
+	}
+
+	if limitWriter.exceeded {
+		if c.config.TruncateLargeResponses {
+			c.log.Warn("%s %s response was truncated to %d bytes", r.Method, r.URL, c.config.maxResponseBodySize())
+		} else {
+			c.log.Warn("%s %s response exceeded %d bytes", r.Method, r.URL, c.config.maxResponseBodySize())
+			if deadlineWriter.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+			replyError(errResponseTooLarge.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+
+	if deadlineExceeded(ctx, err) {
+		c.log.Debug("%s %s ran out of time to be handled", r.Method, r.URL)
+		if !deadlineWriter.wroteHeader {
+			// The gRPC error isn't passed on, as it would mean nothing to
+			// the client
+			replyError(errHandlerTimeout.Error(), http.StatusGatewayTimeout)
+		}
+	}
+}
+
+// call passes the request to the plugin once, with the response written to
+// [w]
+func (c *Client) call(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	servers := bridgeServers{}
 
 	readerID := c.broker.NextId()
 	go c.broker.AcceptAndServe(readerID, func(opts []grpc.ServerOption) *grpc.Server {
-		reader = grpc.NewServer(opts...)
-		greadcloserproto.RegisterReaderServer(reader, greadcloser.NewServer(r.Body))
+		reader := grpc.NewServer(append(opts, c.config.Keepalive.ServerOptions()...)...)
+		greadcloserproto.RegisterReaderServer(reader, greadcloser.NewServer(r.Body, receivedTrailer(r)))
+		servers.add(reader)
 
 		return reader
 	})
 	writerID := c.broker.NextId()
 	go c.broker.AcceptAndServe(writerID, func(opts []grpc.ServerOption) *grpc.Server {
-		writer = grpc.NewServer(opts...)
-		gresponsewriterproto.RegisterWriterServer(writer, gresponsewriter.NewServer(w, c.broker))
+		writer := grpc.NewServer(append(opts, c.config.Keepalive.ServerOptions()...)...)
+		writerServer := gresponsewriter.NewServer(w, c.broker)
+		if c.config.StreamResponses {
+			writerServer.FlushWrites()
+		}
+		gresponsewriterproto.RegisterWriterServer(writer, writerServer)
+		servers.add(writer)
 
 		return writer
 	})
@@ -64,10 +424,17 @@ func (c *Client) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			Host:             r.Host,
 			RemoteAddr:       r.RemoteAddr,
 			RequestURI:       r.RequestURI,
+			LocalAddr:        localAddr(r),
 		},
 	}
-	req.Request.Header = make([]*ghttpproto.Element, 0, len(r.Header))
-	for key, values := range r.Header {
+	// The values of the trailer are sent once the body has been read
+	req.Request.TrailerKeys = make([]string, 0, len(r.Trailer))
+	for key := range r.Trailer {
+		req.Request.TrailerKeys = append(req.Request.TrailerKeys, key)
+	}
+	header := canonicalHeader(r.Header)
+	req.Request.Header = make([]*ghttpproto.Element, 0, len(header))
+	for key, values := range header {
 		req.Request.Header = append(req.Request.Header, &ghttpproto.Element{
 			Key:    key,
 			Values: values,
@@ -144,8 +511,33 @@ func (c *Client) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
-	c.client.Handle(r.Context(), req)
+	if len(c.config.TrustedProxies) > 0 {
+		remoteAddr, scheme, host := c.forwardedFor(r)
+		if remoteAddr != "" {
+			req.Request.RemoteAddr = remoteAddr
+		}
+		if scheme != "" {
+			if req.Request.Url == nil {
+				req.Request.Url = &ghttpproto.URL{}
+			}
+			req.Request.Url.Scheme = scheme
+		}
+		if host != "" {
+			req.Request.Host = host
+		}
+	}
+
+	if c.config.BridgeCompression {
+		ctx = withBridgeCompression(ctx, c.config.BridgeCompressionMinSize)
+	}
+	if c.config.StreamResponses {
+		ctx = withStreamChunkSize(ctx, c.config.StreamChunkSize)
+	}
+	ctx = withOutgoingRequestStart(ctx, r)
+	ctx = withOutgoingPriority(ctx, r)
+	_, err := c.client.Handle(ctx, req)
 
-	reader.Stop()
-	writer.Stop()
+	// The writer must be stopped before the response can be written to here
+	servers.stop()
+	return err
 }
@@ -7,6 +7,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"fmt"
 	"net/http"
 	"net/url"
 
@@ -48,17 +49,37 @@ func (s *Server) Handle(ctx context.Context, req *ghttpproto.HTTPRequest) (*ghtt
 	defer readerConn.Close()
 
 	writer := gresponsewriter.NewClient(gresponsewriterproto.NewWriterClient(writerConn), s.broker)
-	reader := greadcloser.NewClient(greadcloserproto.NewReaderClient(readerConn))
+	// The context is cancelled once the client goes away, so the handler's
+	// writes fail rather than being sent nowhere
+	writer.AbandonWith(ctx)
+	if minSize := bridgeCompressionMinSize(ctx); minSize > 0 {
+		writer.CompressWrites(minSize)
+	}
+	if chunkSize := streamChunkSize(ctx); chunkSize > 0 {
+		writer.StreamChunks(chunkSize)
+	}
+	// The trailer is declared up front, like net/http does, and its values are
+	// filled in once the handler has read the whole body
+	trailer := make(http.Header, len(req.Request.TrailerKeys))
+	for _, key := range req.Request.TrailerKeys {
+		trailer[key] = nil
+	}
+	reader := greadcloser.NewClient(greadcloserproto.NewReaderClient(readerConn), trailer)
+
+	// Malformed requests are rejected here rather than confusing the handler
+	if err := validateRequest(req.Request); err != nil {
+		return badRequest(writer, err)
+	}
 
 	// create the request with the current context
 	request, err := http.NewRequestWithContext(
-		ctx,
+		withIncomingPriority(withIncomingRequestStart(withLocalAddr(ctx, req.Request.LocalAddr))),
 		req.Request.Method,
 		req.Request.RequestURI,
 		reader,
 	)
 	if err != nil {
-		return nil, err
+		return badRequest(writer, err)
 	}
 
 	if req.Request.Url != nil {
@@ -84,10 +105,14 @@ func (s *Server) Handle(ctx context.Context, req *ghttpproto.HTTPRequest) (*ghtt
 	request.Proto = req.Request.Proto
 	request.ProtoMajor = int(req.Request.ProtoMajor)
 	request.ProtoMinor = int(req.Request.ProtoMinor)
+	// The client sends every key in canonical form, so the keys are used as
+	// they were sent
 	request.Header = make(http.Header, len(req.Request.Header))
 	for _, elem := range req.Request.Header {
 		request.Header[elem.Key] = elem.Values
 	}
+	// -1 if the length of the body is unknown, such as if it's chunked. The
+	// body is streamed, so it isn't read to find out its length.
 	request.ContentLength = req.Request.ContentLength
 	request.TransferEncoding = req.Request.TransferEncoding
 	request.Host = req.Request.Host
@@ -99,7 +124,7 @@ func (s *Server) Handle(ctx context.Context, req *ghttpproto.HTTPRequest) (*ghtt
 	for _, elem := range req.Request.PostForm {
 		request.PostForm[elem.Key] = elem.Values
 	}
-	request.Trailer = make(http.Header)
+	request.Trailer = trailer
 	request.RemoteAddr = req.Request.RemoteAddr
 	request.RequestURI = req.Request.RequestURI
 
@@ -117,30 +142,67 @@ func (s *Server) Handle(ctx context.Context, req *ghttpproto.HTTPRequest) (*ghtt
 			TLSUnique:                   req.Request.Tls.TlsUnique,
 		}
 
-		request.TLS.PeerCertificates = make([]*x509.Certificate, len(req.Request.Tls.PeerCertificates.Cert))
-		for i, certBytes := range req.Request.Tls.PeerCertificates.Cert {
-			cert, err := x509.ParseCertificate(certBytes)
-			if err != nil {
-				return nil, err
+		// As with requests served by net/http, the certificates are left nil
+		// if the client didn't present any, so that plugins doing client
+		// certificate authentication can check for them the same way.
+		if certs := req.Request.Tls.GetPeerCertificates().GetCert(); len(certs) > 0 {
+			request.TLS.PeerCertificates = make([]*x509.Certificate, len(certs))
+			for i, certBytes := range certs {
+				cert, err := x509.ParseCertificate(certBytes)
+				if err != nil {
+					return badRequest(writer, fmt.Errorf("couldn't parse peer certificate: %w", err))
+				}
+				request.TLS.PeerCertificates[i] = cert
 			}
-			request.TLS.PeerCertificates[i] = cert
 		}
 
-		request.TLS.VerifiedChains = make([][]*x509.Certificate, len(req.Request.Tls.VerifiedChains))
-		for i, chain := range req.Request.Tls.VerifiedChains {
-			request.TLS.VerifiedChains[i] = make([]*x509.Certificate, len(chain.Cert))
-			for j, certBytes := range chain.Cert {
-				cert, err := x509.ParseCertificate(certBytes)
-				if err != nil {
-					return nil, err
+		if chains := req.Request.Tls.GetVerifiedChains(); len(chains) > 0 {
+			request.TLS.VerifiedChains = make([][]*x509.Certificate, len(chains))
+			for i, chain := range chains {
+				request.TLS.VerifiedChains[i] = make([]*x509.Certificate, len(chain.GetCert()))
+				for j, certBytes := range chain.GetCert() {
+					cert, err := x509.ParseCertificate(certBytes)
+					if err != nil {
+						return badRequest(writer, fmt.Errorf("couldn't parse verified chain certificate: %w", err))
+					}
+					request.TLS.VerifiedChains[i][j] = cert
 				}
-				request.TLS.VerifiedChains[i][j] = cert
 			}
 		}
 	}
 
-	s.handler.ServeHTTP(writer, request)
+	if request.Method == http.MethodHead {
+		// The body of a response to a HEAD request is never sent to the
+		// client, so there is no reason to send it over RPC.
+		writer.DiscardBody()
+	}
+
+	if err := serveRecovered(s.handler, writer, request); err != nil {
+		// The client raises the panic again, so that it is handled as if the
+		// handler panicked in the client's process
+		return nil, err
+	}
+
+	if err := writer.FinishChunk(); err != nil {
+		return nil, err
+	}
+
+	// Matching net/http, if the handler returned without writing anything,
+	// the headers it set are sent with an OK status.
+	if !writer.WroteHeader() {
+		writer.WriteHeader(http.StatusOK)
+	}
 
 	// return the response
 	return &ghttpproto.HTTPResponse{}, nil
 }
+
+// badRequest replies to the request with a 400 describing [err], without
+// calling the handler
+func badRequest(writer *gresponsewriter.Client, err error) (*ghttpproto.HTTPResponse, error) {
+	http.Error(writer, fmt.Sprintf("malformed request: %s", err), http.StatusBadRequest)
+	if err := writer.FinishChunk(); err != nil {
+		return nil, err
+	}
+	return &ghttpproto.HTTPResponse{}, nil
+}
@@ -0,0 +1,2508 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/encoding"
+
+	grpcgzip "google.golang.org/grpc/encoding/gzip"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/logging"
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/ghttpproto"
+)
+
+type testPlugin struct {
+	plugin.NetRPCUnsupportedPlugin
+	handler http.Handler
+	config  Config
+}
+
+func (p *testPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	ghttpproto.RegisterHTTPServer(s, NewServer(p.handler, broker))
+	return nil
+}
+
+func (p *testPlugin) GRPCClient(_ context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return NewClient(ghttpproto.NewHTTPClient(c), broker, logging.NoLog{}, p.config), nil
+}
+
+// newTestClient returns a client that serves requests by calling [handler]
+// over RPC
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	return newTestClientWithConfig(t, handler, Config{})
+}
+
+// newTestClientWithConfig is newTestClient with the client configured by
+// [config]
+func newTestClientWithConfig(t *testing.T, handler http.Handler, config Config) *Client {
+	client, server := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		"http": &testPlugin{
+			handler: handler,
+			config:  config,
+		},
+	})
+	t.Cleanup(func() {
+		client.Close()
+		server.Stop()
+	})
+
+	raw, err := client.Dispense("http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return raw.(*Client)
+}
+
+func TestHeadRequest(t *testing.T) {
+	body := "hello world"
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "11")
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(body))
+	}))
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		recorder := httptest.NewRecorder()
+		client.ServeHTTP(recorder, httptest.NewRequest(method, "/", nil))
+
+		if recorder.Code != http.StatusOK {
+			t.Fatalf("%s: expected status %d but got %d", method, http.StatusOK, recorder.Code)
+		}
+		if contentLength := recorder.Header().Get("Content-Length"); contentLength != "11" {
+			t.Fatalf("%s: expected Content-Length 11 but got %q", method, contentLength)
+		}
+		if contentType := recorder.Header().Get("Content-Type"); contentType != "text/plain" {
+			t.Fatalf("%s: expected Content-Type text/plain but got %q", method, contentType)
+		}
+
+		expectedBody := body
+		if method == http.MethodHead {
+			expectedBody = ""
+		}
+		if recorder.Body.String() != expectedBody {
+			t.Fatalf("%s: expected body %q but got %q", method, expectedBody, recorder.Body.String())
+		}
+	}
+}
+
+func TestHeadersWithoutWrite(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Length", "1024")
+	}))
+
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodHead, "/", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, recorder.Code)
+	}
+	if contentLength := recorder.Header().Get("Content-Length"); contentLength != "1024" {
+		t.Fatalf("expected Content-Length 1024 but got %q", contentLength)
+	}
+	if recorder.Body.Len() != 0 {
+		t.Fatalf("expected an empty body but got %q", recorder.Body.String())
+	}
+}
+
+var uuidRegexp = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+func TestRequestIDPropagated(t *testing.T) {
+	seenID := ""
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenID = r.Header.Get(RequestIDHeader)
+	}), Config{GenerateRequestIDs: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(RequestIDHeader, "my-request-id")
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, req)
+
+	if seenID != "my-request-id" {
+		t.Fatalf("expected the plugin to see request ID %q but it saw %q", "my-request-id", seenID)
+	}
+}
+
+func TestTraceparentPropagated(t *testing.T) {
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	seenTraceparent, seenID := "", ""
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenTraceparent = r.Header.Get("traceparent")
+		seenID = r.Header.Get(RequestIDHeader)
+	}), Config{GenerateRequestIDs: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("traceparent", traceparent)
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, req)
+
+	if seenTraceparent != traceparent {
+		t.Fatalf("expected the plugin to see traceparent %q but it saw %q", traceparent, seenTraceparent)
+	}
+	if seenID != "" {
+		t.Fatalf("expected no request ID to be generated but the plugin saw %q", seenID)
+	}
+}
+
+func TestRequestStart(t *testing.T) {
+	var (
+		seenStart time.Time
+		seen      bool
+	)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenStart, seen = RequestStart(r.Context())
+	})
+
+	client := newTestClientWithConfig(t, handler, Config{ConveyRequestStart: true})
+	before := time.Now()
+	client.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	after := time.Now()
+
+	if !seen {
+		t.Fatal("expected the plugin to see when the request was received")
+	}
+	if seenStart.Before(before) || seenStart.After(after) {
+		t.Fatalf("expected the request to have been received between %s and %s but the plugin saw %s", before, after, seenStart)
+	}
+
+	client = newTestClient(t, handler)
+	client.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if seen {
+		t.Fatalf("expected the plugin not to see when the request was received but it saw %s", seenStart)
+	}
+}
+
+func TestStreamPriority(t *testing.T) {
+	var (
+		seenPriority Priority
+		seen         bool
+	)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenPriority, seen = StreamPriority(r.Context())
+	}))
+
+	// The priority of an HTTP/2 stream reaches the handler
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.ProtoMajor, request.ProtoMinor, request.Proto = 2, 0, "HTTP/2.0"
+	request.Header.Set("Priority", "u=1, i")
+	client.ServeHTTP(httptest.NewRecorder(), request)
+	if !seen {
+		t.Fatal("expected the plugin to see the stream's priority")
+	}
+	if expected := (Priority{Urgency: 1, Incremental: true}); seenPriority != expected {
+		t.Fatalf("expected priority %s but the plugin saw %s", expected, seenPriority)
+	}
+
+	// Parameters the client didn't signal have their defaults
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.ProtoMajor, request.ProtoMinor, request.Proto = 2, 0, "HTTP/2.0"
+	request.Header.Set("Priority", "i")
+	client.ServeHTTP(httptest.NewRecorder(), request)
+	if expected := (Priority{Urgency: DefaultUrgency, Incremental: true}); !seen || seenPriority != expected {
+		t.Fatalf("expected priority %s but the plugin saw %s (seen: %t)", expected, seenPriority, seen)
+	}
+
+	// Without a signaled priority, the handler sees none rather than a zero
+	// value
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.ProtoMajor, request.ProtoMinor, request.Proto = 2, 0, "HTTP/2.0"
+	client.ServeHTTP(httptest.NewRecorder(), request)
+	if seen {
+		t.Fatalf("expected the plugin not to see a priority but it saw %s", seenPriority)
+	}
+
+	// HTTP/1 requests don't have streams to prioritize
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Priority", "u=1")
+	client.ServeHTTP(httptest.NewRecorder(), request)
+	if seen {
+		t.Fatalf("expected the plugin not to see a priority but it saw %s", seenPriority)
+	}
+}
+
+func TestRequestIDGenerated(t *testing.T) {
+	seenID := ""
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenID = r.Header.Get(RequestIDHeader)
+		w.Write([]byte("ok"))
+	}), Config{GenerateRequestIDs: true})
+
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	generatedID := recorder.Header().Get(RequestIDHeader)
+	if !uuidRegexp.MatchString(generatedID) {
+		t.Fatalf("expected a UUID to be echoed in the response but got %q", generatedID)
+	}
+	if seenID != generatedID {
+		t.Fatalf("expected the plugin to see request ID %q but it saw %q", generatedID, seenID)
+	}
+}
+
+func TestRequestIDNotGenerated(t *testing.T) {
+	seenID := ""
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seenID = r.Header.Get(RequestIDHeader)
+	}))
+
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if seenID != "" {
+		t.Fatalf("expected no request ID but the plugin saw %q", seenID)
+	}
+	if echoedID := recorder.Header().Get(RequestIDHeader); echoedID != "" {
+		t.Fatalf("expected no request ID in the response but got %q", echoedID)
+	}
+}
+
+func TestCompressResponses(t *testing.T) {
+	bodies := map[string]string{
+		"/json": `{"jsonrpc":"2.0","result":"hello world","id":1}`,
+		"/png":  "\x89PNG\r\n\x1a\nnot really a png",
+		"/gzip": "already encoded",
+	}
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		case "/png":
+			w.Header().Set("Content-Type", "image/png")
+		case "/gzip":
+			w.Header().Set("Content-Type", "text/plain")
+			w.Header().Set("Content-Encoding", "gzip")
+		}
+		w.Write([]byte(bodies[r.URL.Path]))
+	}), Config{CompressResponses: true})
+
+	tests := []struct {
+		path       string
+		compressed bool
+	}{
+		{path: "/json", compressed: true},
+		{path: "/png", compressed: false},
+		{path: "/gzip", compressed: false},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		req.Header.Set("Accept-Encoding", "gzip, deflate")
+		recorder := httptest.NewRecorder()
+		client.ServeHTTP(recorder, req)
+
+		body := recorder.Body.String()
+		if test.compressed {
+			if encoding := recorder.Header().Get("Content-Encoding"); encoding != "gzip" {
+				t.Fatalf("%s: expected Content-Encoding gzip but got %q", test.path, encoding)
+			}
+			reader, err := gzip.NewReader(recorder.Body)
+			if err != nil {
+				t.Fatalf("%s: %s", test.path, err)
+			}
+			decompressed, err := ioutil.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("%s: %s", test.path, err)
+			}
+			body = string(decompressed)
+		} else if encoding := recorder.Header().Get("Content-Encoding"); encoding == "gzip" && test.path != "/gzip" {
+			t.Fatalf("%s: expected the response not to be compressed", test.path)
+		}
+		if body != bodies[test.path] {
+			t.Fatalf("%s: expected body %q but got %q", test.path, bodies[test.path], body)
+		}
+	}
+}
+
+func TestCompressMinSize(t *testing.T) {
+	small := `{"jsonrpc":"2.0","result":"hello world","id":1}`
+	large := `{"jsonrpc":"2.0","result":"` + strings.Repeat("hello world ", 100) + `","id":1}`
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		body := small
+		if r.URL.Path == "/large" {
+			body = large
+		}
+		// Written in pieces, so the size isn't known from the first write
+		for i := 0; i < len(body); i += 100 {
+			end := i + 100
+			if end > len(body) {
+				end = len(body)
+			}
+			w.Write([]byte(body[i:end]))
+		}
+	}), Config{
+		CompressResponses: true,
+		CompressMinSize:   512,
+	})
+
+	tests := []struct {
+		path       string
+		body       string
+		compressed bool
+	}{
+		{path: "/small", body: small, compressed: false},
+		{path: "/large", body: large, compressed: true},
+	}
+	for _, test := range tests {
+		req := httptest.NewRequest(http.MethodGet, test.path, nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		recorder := httptest.NewRecorder()
+		client.ServeHTTP(recorder, req)
+
+		body := recorder.Body.String()
+		// The headers as they were when the response was started
+		encoding := recorder.Result().Header.Get("Content-Encoding")
+		if test.compressed {
+			if encoding != "gzip" {
+				t.Fatalf("%s: expected Content-Encoding gzip but got %q", test.path, encoding)
+			}
+			reader, err := gzip.NewReader(recorder.Body)
+			if err != nil {
+				t.Fatalf("%s: %s", test.path, err)
+			}
+			decompressed, err := ioutil.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("%s: %s", test.path, err)
+			}
+			body = string(decompressed)
+		} else if encoding != "" {
+			t.Fatalf("%s: expected the response not to be compressed but got Content-Encoding %q", test.path, encoding)
+		}
+		if body != test.body {
+			t.Fatalf("%s: expected body %q but got %q", test.path, test.body, body)
+		}
+	}
+}
+
+func TestCompressMinSizeError(t *testing.T) {
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Held back, as it's smaller than the minimum size
+		w.Write([]byte(`{"partial":`))
+		panic("oops")
+	}), Config{
+		CompressResponses: true,
+		CompressMinSize:   512,
+		IsolatePanics:     true,
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, recorder.Code)
+	}
+	if body := recorder.Body.String(); strings.Contains(body, "partial") {
+		t.Fatalf("expected the held back body to be dropped but got %q", body)
+	}
+}
+
+func TestCompressResponsesNotAccepted(t *testing.T) {
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}), Config{CompressResponses: true})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip;q=0, identity")
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, req)
+
+	if encoding := recorder.Header().Get("Content-Encoding"); encoding != "" {
+		t.Fatalf("expected no Content-Encoding but got %q", encoding)
+	}
+	if recorder.Body.String() != `{}` {
+		t.Fatalf("expected body %q but got %q", `{}`, recorder.Body.String())
+	}
+}
+
+func TestHijackSwitchingProtocols(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("expected the response writer to be a hijacker")
+			return
+		}
+		conn, readWriter, err := hijacker.Hijack()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		defer conn.Close()
+
+		readWriter.WriteString("HTTP/1.1 101 Switching Protocols\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")
+		if err := readWriter.Flush(); err != nil {
+			t.Error(err)
+			return
+		}
+		line, err := readWriter.ReadString('\n')
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		readWriter.WriteString(line)
+		if err := readWriter.Flush(); err != nil {
+			t.Error(err)
+		}
+	}))
+	server := httptest.NewServer(client)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("GET / HTTP/1.1\r\nHost: test\r\nConnection: Upgrade\r\nUpgrade: echo\r\n\r\n")); err != nil {
+		t.Fatal(err)
+	}
+	reader := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(reader, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		t.Fatalf("expected status %d but got %d", http.StatusSwitchingProtocols, resp.StatusCode)
+	}
+	if upgrade := resp.Header.Get("Upgrade"); upgrade != "echo" {
+		t.Fatalf("expected Upgrade echo but got %q", upgrade)
+	}
+
+	if _, err := conn.Write([]byte("ping\n")); err != nil {
+		t.Fatal(err)
+	}
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		t.Fatal(err)
+	}
+	if line != "ping\n" {
+		t.Fatalf("expected %q to be echoed but got %q", "ping\n", line)
+	}
+}
+
+func TestHijackUnsupported(t *testing.T) {
+	hijackErr := error(nil)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hijacker, ok := w.(http.Hijacker)
+		if !ok {
+			t.Error("expected the response writer to be a hijacker")
+			return
+		}
+		_, _, hijackErr = hijacker.Hijack()
+		if hijackErr != nil {
+			http.Error(w, "can't hijack", http.StatusNotImplemented)
+		}
+	}))
+
+	// A response recorder doesn't support hijacking
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if hijackErr == nil {
+		t.Fatal("expected hijacking to fail")
+	}
+	if recorder.Code != http.StatusNotImplemented {
+		t.Fatalf("expected status %d but got %d", http.StatusNotImplemented, recorder.Code)
+	}
+}
+
+func TestCachingHeaders(t *testing.T) {
+	etag := `"33a64df551425fcc55e4d42a148795d9f25f89d4"`
+	expires := "Wed, 21 Oct 2015 07:28:00 GMT"
+	lastModified := "Tue, 20 Oct 2015 07:28:00 GMT"
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Add("Cache-Control", "must-revalidate")
+		w.Header().Set("Expires", expires)
+		w.Header().Set("Last-Modified", lastModified)
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"cached":true}`))
+	}), Config{CompressResponses: true})
+
+	checkHeaders := func(recorder *httptest.ResponseRecorder) {
+		header := recorder.Header()
+		if values := header.Values("ETag"); len(values) != 1 || values[0] != etag {
+			t.Fatalf("expected ETag %q but got %q", etag, values)
+		}
+		if values := header.Values("Cache-Control"); len(values) != 2 || values[0] != "max-age=60" || values[1] != "must-revalidate" {
+			t.Fatalf("expected Cache-Control [max-age=60 must-revalidate] but got %q", values)
+		}
+		if values := header.Values("Expires"); len(values) != 1 || values[0] != expires {
+			t.Fatalf("expected Expires %q but got %q", expires, values)
+		}
+		if values := header.Values("Last-Modified"); len(values) != 1 || values[0] != lastModified {
+			t.Fatalf("expected Last-Modified %q but got %q", lastModified, values)
+		}
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, recorder.Code)
+	}
+	checkHeaders(recorder)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, req)
+	if recorder.Code != http.StatusNotModified {
+		t.Fatalf("expected status %d but got %d", http.StatusNotModified, recorder.Code)
+	}
+	checkHeaders(recorder)
+	if recorder.Body.Len() != 0 {
+		t.Fatalf("expected an empty body but got %q", recorder.Body.String())
+	}
+}
+
+func TestGRPCWebPassthrough(t *testing.T) {
+	// A data frame followed by a trailers frame, as written by a gRPC-Web
+	// server
+	dataFrame := []byte{0x00, 0x00, 0x00, 0x00, 0x03, 0x0a, 0x01, 0x61}
+	trailers := "grpc-status:0\r\ngrpc-message:\r\n"
+	trailerFrame := append([]byte{0x80, 0x00, 0x00, 0x00, byte(len(trailers))}, trailers...)
+
+	for _, contentType := range []string{"application/grpc-web", "application/grpc-web+proto"} {
+		client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", contentType)
+			w.WriteHeader(http.StatusOK)
+			w.Write(dataFrame)
+			w.(http.Flusher).Flush()
+			w.Write(trailerFrame)
+		}), Config{
+			CompressResponses:        true,
+			CompressibleContentTypes: []string{"application/"},
+		})
+
+		req := httptest.NewRequest(http.MethodPost, "/", nil)
+		req.Header.Set("Accept-Encoding", "gzip")
+		req.Header.Set("Content-Type", contentType)
+		recorder := httptest.NewRecorder()
+		client.ServeHTTP(recorder, req)
+
+		if got := recorder.Header().Get("Content-Type"); got != contentType {
+			t.Fatalf("expected Content-Type %q but got %q", contentType, got)
+		}
+		if encoding := recorder.Header().Get("Content-Encoding"); encoding != "" {
+			t.Fatalf("%s: expected no Content-Encoding but got %q", contentType, encoding)
+		}
+		expected := append(append([]byte{}, dataFrame...), trailerFrame...)
+		if !bytes.Equal(recorder.Body.Bytes(), expected) {
+			t.Fatalf("%s: expected body %x but got %x", contentType, expected, recorder.Body.Bytes())
+		}
+	}
+}
+
+func TestIdleTimeout(t *testing.T) {
+	reclaimed := make(chan struct{})
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+			close(reclaimed)
+		case <-time.After(10 * time.Second):
+		}
+	}), Config{IdleTimeout: 50 * time.Millisecond})
+
+	start := time.Now()
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the stalled request to be cancelled but it took %s", elapsed)
+	}
+	if recorder.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status %d but got %d", http.StatusGatewayTimeout, recorder.Code)
+	}
+	select {
+	case <-reclaimed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the handler's context to be cancelled")
+	}
+}
+
+func TestRouteTimeouts(t *testing.T) {
+	remaining := make(chan time.Duration, 1)
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		if !ok {
+			remaining <- 0
+			return
+		}
+		remaining <- time.Until(deadline)
+	}), Config{
+		HandlerTimeout: 5 * time.Second,
+		RouteTimeouts: map[string]time.Duration{
+			"/ext":       time.Minute,
+			"/ext/query": time.Hour,
+		},
+	})
+
+	tests := []struct {
+		path     string
+		min, max time.Duration
+	}{
+		{"/", 0, 5 * time.Second},
+		{"/ext/info", 5 * time.Second, time.Minute},
+		{"/ext/query/blocks", time.Minute, time.Hour},
+	}
+	for _, test := range tests {
+		client.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, test.path, nil))
+		if timeout := <-remaining; timeout <= test.min || timeout > test.max {
+			t.Fatalf("expected %s to have between %s and %s to be handled but it had %s", test.path, test.min, test.max, timeout)
+		}
+	}
+}
+
+func TestHandlerTimeout(t *testing.T) {
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}), Config{
+		RouteTimeouts: map[string]time.Duration{"/slow": 50 * time.Millisecond},
+	})
+
+	start := time.Now()
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected the request to time out but it took %s", elapsed)
+	}
+	if recorder.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status %d but got %d", http.StatusGatewayTimeout, recorder.Code)
+	}
+}
+
+func TestHandlerTimeoutStatus(t *testing.T) {
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}), Config{HandlerTimeout: 50 * time.Millisecond})
+	server := httptest.NewServer(client)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.StatusCode != http.StatusGatewayTimeout {
+		t.Fatalf("expected status %d but got %d", http.StatusGatewayTimeout, resp.StatusCode)
+	}
+	if !strings.Contains(string(body), errHandlerTimeout.Error()) {
+		t.Fatalf("expected the body to explain the timeout but got %q", body)
+	}
+	if strings.Contains(string(body), "rpc error") || strings.Contains(string(body), "DeadlineExceeded") {
+		t.Fatalf("expected the gRPC error not to be sent to the client but got %q", body)
+	}
+}
+
+func TestRequestDeadlineStatus(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(10 * time.Second):
+		}
+	}))
+
+	// The request's own deadline passes while the handler is running
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	if recorder.Code != http.StatusGatewayTimeout {
+		t.Fatalf("expected status %d but got %d", http.StatusGatewayTimeout, recorder.Code)
+	}
+	if body := recorder.Body.String(); !strings.Contains(body, errHandlerTimeout.Error()) {
+		t.Fatalf("expected the body to explain the timeout but got %q", body)
+	}
+}
+
+func TestIdleTimeoutResetByWrites(t *testing.T) {
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for i := 0; i < 5; i++ {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+			w.Write([]byte("tick\n"))
+			w.(http.Flusher).Flush()
+		}
+	}), Config{IdleTimeout: 500 * time.Millisecond})
+
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, recorder.Code)
+	}
+	if expected := strings.Repeat("tick\n", 5); recorder.Body.String() != expected {
+		t.Fatalf("expected body %q but got %q", expected, recorder.Body.String())
+	}
+}
+
+func TestHeaderCanonicalized(t *testing.T) {
+	var received http.Header
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header
+	}), Config{})
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header["x-amz-date"] = []string{"20200101T000000Z"}
+	req.Header["X-Amz-Date"] = []string{"20200102T000000Z"}
+	client.ServeHTTP(httptest.NewRecorder(), req)
+
+	if values := received["X-Amz-Date"]; len(values) != 2 {
+		t.Fatalf("expected the values of both keys to be merged under the canonical key but got %v", received)
+	}
+	if _, ok := received["x-amz-date"]; ok {
+		t.Fatalf("expected the non-canonical key to be canonicalized but got %v", received)
+	}
+}
+
+func TestMaxConcurrentRequests(t *testing.T) {
+	const (
+		maxConcurrent = 2
+		maxQueued     = 1
+		numRequests   = 10
+	)
+
+	started := make(chan struct{}, numRequests)
+	unblock := make(chan struct{})
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-unblock
+	}), Config{
+		MaxConcurrentRequests: maxConcurrent,
+		MaxQueuedRequests:     maxQueued,
+	})
+
+	responses := make(chan *httptest.ResponseRecorder, numRequests)
+	for i := 0; i < numRequests; i++ {
+		go func() {
+			recorder := httptest.NewRecorder()
+			client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			responses <- recorder
+		}()
+	}
+
+	// Every request beyond the ones being handled and queued should be
+	// rejected while the handlers are blocked
+	numRejected := numRequests - maxConcurrent - maxQueued
+	for i := 0; i < numRejected; i++ {
+		select {
+		case recorder := <-responses:
+			if recorder.Code != http.StatusServiceUnavailable {
+				t.Fatalf("expected status %d but got %d", http.StatusServiceUnavailable, recorder.Code)
+			}
+			if retryAfter := recorder.Header().Get("Retry-After"); retryAfter == "" {
+				t.Fatal("expected Retry-After to be set")
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected excess requests to be rejected")
+		}
+	}
+	if numStarted := len(started); numStarted > maxConcurrent {
+		t.Fatalf("expected at most %d handlers to be running but %d were", maxConcurrent, numStarted)
+	}
+
+	close(unblock)
+	for i := 0; i < maxConcurrent+maxQueued; i++ {
+		select {
+		case recorder := <-responses:
+			if recorder.Code != http.StatusOK {
+				t.Fatalf("expected status %d but got %d", http.StatusOK, recorder.Code)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("expected admitted requests to be handled")
+		}
+	}
+}
+
+func TestConcurrentRequestsUnlimitedByDefault(t *testing.T) {
+	const numRequests = 300
+
+	started := make(chan struct{}, numRequests)
+	unblock := make(chan struct{})
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-unblock
+	}), Config{})
+
+	responses := make(chan *httptest.ResponseRecorder, numRequests)
+	for i := 0; i < numRequests; i++ {
+		go func() {
+			recorder := httptest.NewRecorder()
+			client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+			responses <- recorder
+		}()
+	}
+
+	// Every request should be handled at once
+	for i := 0; i < numRequests; i++ {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("expected %d handlers to be running but %d were", numRequests, i)
+		}
+	}
+
+	close(unblock)
+	for i := 0; i < numRequests; i++ {
+		if recorder := <-responses; recorder.Code != http.StatusOK {
+			t.Fatalf("expected status %d but got %d", http.StatusOK, recorder.Code)
+		}
+	}
+}
+
+func TestHandlerPanic(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("oops")
+	}))
+
+	defer func() {
+		panicErr, ok := recover().(*PanicError)
+		if !ok {
+			t.Fatal("expected the handler's panic to be raised by the client")
+		}
+		if panicErr.Value != "oops" {
+			t.Fatalf("expected panic value %q but got %q", "oops", panicErr.Value)
+		}
+		if !strings.Contains(panicErr.PluginStacktrace(), "TestHandlerPanic") {
+			t.Fatalf("expected the stack of the handler but got %q", panicErr.PluginStacktrace())
+		}
+	}()
+	client.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestHandlerPanicDetails(t *testing.T) {
+	for _, details := range []bool{false, true} {
+		client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("oops")
+		}), Config{PanicDetails: details})
+
+		// Mimics the node's API server, which replies with a 500 when a
+		// handler panics
+		recorder := httptest.NewRecorder()
+		func() {
+			defer func() {
+				body := http.StatusText(http.StatusInternalServerError)
+				if response := recover().(*PanicError).PanicResponse(); response != "" {
+					body = response
+				}
+				http.Error(recorder, body, http.StatusInternalServerError)
+			}()
+			client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		}()
+
+		if recorder.Code != http.StatusInternalServerError {
+			t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, recorder.Code)
+		}
+		body := recorder.Body.String()
+		hasDetails := strings.Contains(body, "oops") && strings.Contains(body, "TestHandlerPanicDetails")
+		if hasDetails != details {
+			t.Fatalf("expected the details of the panic to be replied with: %v, got %q", details, body)
+		}
+	}
+}
+
+func TestIsolatePanics(t *testing.T) {
+	panicking := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("oops")
+	}), Config{IsolatePanics: true})
+	healthy := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+
+	serve := func(client *Client) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+		return recorder
+	}
+
+	// The connections to the plugins are established before counting the
+	// goroutines
+	serve(panicking)
+	serve(healthy)
+	goroutines := runtime.NumGoroutine()
+
+	const requests = 50
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < requests; i++ {
+			recorder := serve(panicking)
+			if recorder.Code != http.StatusInternalServerError {
+				t.Errorf("expected status %d but got %d", http.StatusInternalServerError, recorder.Code)
+				return
+			}
+			if body := recorder.Body.String(); strings.Contains(body, "oops") {
+				t.Errorf("expected the panic not to be described but got %q", body)
+				return
+			}
+		}
+	}()
+	for i := 0; i < requests; i++ {
+		recorder := serve(healthy)
+		if recorder.Code != http.StatusOK || recorder.Body.String() != "ok" {
+			t.Fatalf("expected the other plugin to reply %q with status %d but got %q with status %d", "ok", http.StatusOK, recorder.Body.String(), recorder.Code)
+		}
+	}
+	<-done
+
+	// The goroutines started for the panicking requests are all stopped
+	deadline := time.Now().Add(5 * time.Second)
+	for runtime.NumGoroutine() > goroutines+5 {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected at most %d goroutines but there are %d", goroutines+5, runtime.NumGoroutine())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestMaxResponseBodySize(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello "))
+		w.Write([]byte("world!!!"))
+	})
+
+	// Within the limit, the response is untouched
+	client := newTestClientWithConfig(t, handler, Config{MaxResponseBodySize: 14})
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "hello world!!!" {
+		t.Fatalf("expected %q with status %d but got %q with status %d", "hello world!!!", http.StatusOK, recorder.Body.String(), recorder.Code)
+	}
+
+	// Beyond the limit, the response is cut off at the limit
+	client = newTestClientWithConfig(t, handler, Config{
+		MaxResponseBodySize:    8,
+		TruncateLargeResponses: true,
+	})
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "hello wo" {
+		t.Fatalf("expected %q with status %d but got %q with status %d", "hello wo", http.StatusOK, recorder.Body.String(), recorder.Code)
+	}
+
+	// Unless truncating is enabled, the response is rejected
+	client = newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte{'a'}, 16))
+	}), Config{MaxResponseBodySize: 8})
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, recorder.Code)
+	}
+	if body := recorder.Body.String(); strings.Contains(body, "aaaa") {
+		t.Fatalf("expected the oversized body not to be sent but got %q", body)
+	}
+}
+
+func TestETag(t *testing.T) {
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tagged" {
+			w.Header().Set("ETag", `"v1"`)
+		}
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte("hello world"))
+	}), Config{ETags: true})
+
+	// The first request is replied to with the body and its tag
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "hello world" {
+		t.Fatalf("expected %q with status %d but got %q with status %d", "hello world", http.StatusOK, recorder.Body.String(), recorder.Code)
+	}
+	etag := recorder.Header().Get("ETag")
+	if etag == "" || strings.HasPrefix(etag, "W/") {
+		t.Fatalf("expected a strong ETag but got %q", etag)
+	}
+
+	// A request for the same body is replied to with a 304
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("If-None-Match", `"other", `+etag)
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNotModified {
+		t.Fatalf("expected status %d but got %d", http.StatusNotModified, recorder.Code)
+	}
+	if recorder.Body.Len() != 0 {
+		t.Fatalf("expected no body but got %q", recorder.Body.String())
+	}
+	if got := recorder.Header().Get("ETag"); got != etag {
+		t.Fatalf("expected ETag %q but got %q", etag, got)
+	}
+
+	// A request whose tag doesn't match is replied to with the body
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("If-None-Match", `"other"`)
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Body.String() != "hello world" {
+		t.Fatalf("expected %q with status %d but got %q with status %d", "hello world", http.StatusOK, recorder.Body.String(), recorder.Code)
+	}
+
+	// The handler's own tag is left alone
+	request = httptest.NewRequest(http.MethodGet, "/tagged", nil)
+	request.Header.Set("If-None-Match", `"v1"`)
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("ETag") != `"v1"` {
+		t.Fatalf("expected status %d with ETag %q but got status %d with ETag %q", http.StatusOK, `"v1"`, recorder.Code, recorder.Header().Get("ETag"))
+	}
+
+	// Unsafe methods aren't tagged
+	request = httptest.NewRequest(http.MethodPost, "/", nil)
+	request.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("ETag") != "" {
+		t.Fatalf("expected status %d without an ETag but got status %d with ETag %q", http.StatusOK, recorder.Code, recorder.Header().Get("ETag"))
+	}
+}
+
+func TestETagCompressed(t *testing.T) {
+	body := strings.Repeat("hello world ", 10)
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(body))
+	}), Config{ETags: true, CompressResponses: true})
+
+	// The bridge's tag is a hash of the uncompressed body, so it's weakened
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusOK || recorder.Header().Get("Content-Encoding") != "gzip" {
+		t.Fatalf("expected a gzipped response with status %d but got status %d", http.StatusOK, recorder.Code)
+	}
+	etag := recorder.Header().Get("ETag")
+	if !strings.HasPrefix(etag, "W/") {
+		t.Fatalf("expected a weak ETag but got %q", etag)
+	}
+
+	// A client echoing the weak tag is replied to with a 304
+	request = httptest.NewRequest(http.MethodGet, "/", nil)
+	request.Header.Set("Accept-Encoding", "gzip")
+	request.Header.Set("If-None-Match", etag)
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, request)
+	if recorder.Code != http.StatusNotModified {
+		t.Fatalf("expected status %d but got %d", http.StatusNotModified, recorder.Code)
+	}
+}
+
+// requestCount returns the number of requests [registry] recorded to
+// [endpoint] that were replied to with [code]
+func requestCount(t *testing.T, registry *prometheus.Registry, endpoint, code string) float64 {
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, family := range families {
+		if family.GetName() != "test_requests" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["endpoint"] == endpoint && labels["code"] == code {
+				return metric.GetCounter().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestMetrics(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	metrics, err := NewMetrics("test", registry, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rpc" {
+			SetMetricsEndpoint(w, "getBalance")
+			w.Write([]byte("ok"))
+			return
+		}
+		http.NotFound(w, r)
+	}), Config{Metrics: metrics})
+
+	// The endpoint the handler named is recorded, and isn't sent to the client
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/rpc", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, recorder.Code)
+	}
+	if endpoint := recorder.Header().Get(MetricsEndpointHeader); endpoint != "" {
+		t.Fatalf("expected the endpoint not to be sent but got %q", endpoint)
+	}
+	if count := requestCount(t, registry, "getBalance", "200"); count != 1 {
+		t.Fatalf("expected 1 request to getBalance but got %v", count)
+	}
+
+	// Requests whose handler didn't name an endpoint are recorded by path
+	client.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/a//b/", nil))
+	if count := requestCount(t, registry, "/a/b", "404"); count != 1 {
+		t.Fatalf("expected 1 request to /a/b but got %v", count)
+	}
+
+	// Beyond the limit, new endpoints are recorded together
+	client.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/c", nil))
+	client.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/d", nil))
+	if count := requestCount(t, registry, otherEndpoint, "404"); count != 2 {
+		t.Fatalf("expected 2 requests to %s but got %v", otherEndpoint, count)
+	}
+	if count := requestCount(t, registry, "getBalance", "200"); count != 1 {
+		t.Fatalf("expected 1 request to getBalance but got %v", count)
+	}
+}
+
+// recordingLog keeps the messages logged at the debug and info levels
+type recordingLog struct {
+	logging.NoLog
+
+	lock        sync.Mutex
+	debug, info []string
+}
+
+func (l *recordingLog) Debug(format string, args ...interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.debug = append(l.debug, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLog) Info(format string, args ...interface{}) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+	l.info = append(l.info, fmt.Sprintf(format, args...))
+}
+
+func TestRequestLogging(t *testing.T) {
+	requestLogging := NewRequestLogging(RequestLogNone, 4)
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Write([]byte("hello world"))
+	}), Config{RequestLogging: requestLogging})
+	log := &recordingLog{}
+	client.log = log
+
+	serve := func() {
+		request := httptest.NewRequest(http.MethodPost, "/rpc", strings.NewReader("request body"))
+		request.Header.Set("Authorization", "Basic secret")
+		client.ServeHTTP(httptest.NewRecorder(), request)
+	}
+
+	serve()
+	if len(log.debug) != 0 || len(log.info) != 0 {
+		t.Fatalf("expected nothing to be logged but got %q and %q", log.debug, log.info)
+	}
+
+	// Metadata is logged at the debug level
+	requestLogging.SetLevel(RequestLogMetadata)
+	serve()
+	if len(log.debug) != 1 || !strings.Contains(log.debug[0], "POST /rpc: 200") {
+		t.Fatalf("expected the request's metadata to be logged but got %q", log.debug)
+	}
+	if len(log.info) != 0 {
+		t.Fatalf("expected nothing to be logged at the info level but got %q", log.info)
+	}
+
+	// Bodies are cut off at the limit, and credentials are left out
+	requestLogging.SetLevel(RequestLogFull)
+	serve()
+	if len(log.info) != 1 {
+		t.Fatalf("expected the request to be logged at the info level but got %q", log.info)
+	}
+	logged := log.info[0]
+	for _, expected := range []string{`"requ" (truncated)`, `"hell" (truncated)`, "[redacted]"} {
+		if !strings.Contains(logged, expected) {
+			t.Fatalf("expected %q to be logged but got %q", expected, logged)
+		}
+	}
+	if strings.Contains(logged, "secret") || strings.Contains(logged, "hello world") {
+		t.Fatalf("expected credentials and full bodies not to be logged but got %q", logged)
+	}
+}
+
+func TestParseRequestLogLevel(t *testing.T) {
+	for _, level := range []RequestLogLevel{RequestLogNone, RequestLogMetadata, RequestLogHeaders, RequestLogFull} {
+		parsed, err := ParseRequestLogLevel(level.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if parsed != level {
+			t.Fatalf("expected %s but got %s", level, parsed)
+		}
+	}
+	if _, err := ParseRequestLogLevel("verbose"); err == nil {
+		t.Fatal("expected an unknown level to be rejected")
+	}
+	if level := (*RequestLogging)(nil).Level(); level != RequestLogNone {
+		t.Fatalf("expected requests to be logged at %s by default but got %s", RequestLogNone, level)
+	}
+}
+
+// stalledResponseWriter simulates a client that stops reading the response.
+// Writes block until [unblock] is closed.
+type stalledResponseWriter struct {
+	*httptest.ResponseRecorder
+	unblock chan struct{}
+}
+
+func (w *stalledResponseWriter) Write(payload []byte) (int, error) {
+	<-w.unblock
+	return w.ResponseRecorder.Write(payload)
+}
+
+func TestWriteTimeout(t *testing.T) {
+	released := make(chan error, 1)
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := bytes.Repeat([]byte{'a'}, 1024)
+		for {
+			if _, err := w.Write(chunk); err != nil {
+				<-r.Context().Done()
+				released <- err
+				return
+			}
+		}
+	}), Config{WriteTimeout: 50 * time.Millisecond})
+
+	writer := &stalledResponseWriter{
+		ResponseRecorder: httptest.NewRecorder(),
+		unblock:          make(chan struct{}),
+	}
+	aborted := make(chan interface{}, 1)
+	go func() {
+		defer func() {
+			aborted <- recover()
+		}()
+		client.ServeHTTP(writer, httptest.NewRequest(http.MethodGet, "/", nil))
+	}()
+
+	// The handler should be released while the client is still stalled
+	select {
+	case err := <-released:
+		if err == nil {
+			t.Fatal("expected the handler's write to fail")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the handler to be released after the write timeout")
+	}
+
+	close(writer.unblock)
+	select {
+	case recovered := <-aborted:
+		if recovered != http.ErrAbortHandler {
+			t.Fatalf("expected the response to be aborted but got %v", recovered)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the request to finish once the stalled write returned")
+	}
+}
+
+func TestRetry(t *testing.T) {
+	attempts := 0
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if attempts == 1 {
+			w.Header().Set("X-Attempt", "failed")
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("hello"))
+	}), Config{Retry: RetryConfig{
+		MaxRetries: 2,
+		Backoff:    time.Millisecond,
+	}})
+
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, recorder.Code)
+	}
+	if body := recorder.Body.String(); body != "hello" {
+		t.Fatalf("expected the retry's body but got %q", body)
+	}
+	if header := recorder.Header().Get("X-Attempt"); header != "" {
+		t.Fatalf("expected the failed attempt's headers to be dropped but got %q", header)
+	}
+	if attempts != 2 {
+		t.Fatalf("expected 2 attempts but got %d", attempts)
+	}
+}
+
+func TestRetryLimits(t *testing.T) {
+	attempts := 0
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}), Config{Retry: RetryConfig{
+		MaxRetries: 2,
+		Backoff:    time.Millisecond,
+	}})
+
+	// The last attempt's response is passed on once the retries run out
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d but got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+	if attempts != 3 {
+		t.Fatalf("expected 3 attempts but got %d", attempts)
+	}
+
+	// Requests that aren't safe to repeat aren't retried
+	attempts = 0
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/", strings.NewReader("body")))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d but got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected a POST to be attempted once but got %d attempts", attempts)
+	}
+
+	// A retry that couldn't start before the deadline isn't made
+	attempts = 0
+	client = newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		http.Error(w, "busy", http.StatusServiceUnavailable)
+	}), Config{Retry: RetryConfig{
+		MaxRetries: 2,
+		Backoff:    time.Hour,
+	}})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d but got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected no retries past the deadline but got %d attempts", attempts)
+	}
+}
+
+func TestKeepaliveDefaults(t *testing.T) {
+	config := KeepaliveConfig{}.withDefaults()
+	if config.Time != DefaultKeepaliveTime {
+		t.Fatalf("expected keepalive time %s but got %s", DefaultKeepaliveTime, config.Time)
+	}
+	if config.Timeout != DefaultKeepaliveTimeout {
+		t.Fatalf("expected keepalive timeout %s but got %s", DefaultKeepaliveTimeout, config.Timeout)
+	}
+
+	config = KeepaliveConfig{Time: time.Minute, Timeout: time.Second}.withDefaults()
+	if config.Time != time.Minute || config.Timeout != time.Second {
+		t.Fatalf("expected the configured values to be kept but got %+v", config)
+	}
+}
+
+func TestKeepalive(t *testing.T) {
+	// The bridge should stay up while the handler is quiet for longer than
+	// the keepalive time, as the plugin answers the pings
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(1500 * time.Millisecond)
+		w.Write([]byte("pong"))
+	}), Config{Keepalive: KeepaliveConfig{
+		Time:    time.Second,
+		Timeout: time.Second,
+	}})
+
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, recorder.Code)
+	}
+	if body := recorder.Body.String(); body != "pong" {
+		t.Fatalf("expected body %q but got %q", "pong", body)
+	}
+}
+
+func TestForwardedFromTrustedProxy(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies([]string{"192.0.2.0/24"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		remoteAddr     string
+		expectedIP     string
+		expectedScheme string
+	}{
+		// httptest's default remote address is in the trusted range
+		{remoteAddr: "192.0.2.1:1234", expectedIP: "203.0.113.1", expectedScheme: "https"},
+		{remoteAddr: "198.51.100.1:1234", expectedIP: "198.51.100.1", expectedScheme: ""},
+	}
+	for _, test := range tests {
+		var remoteAddr, scheme string
+		client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			remoteAddr = r.RemoteAddr
+			scheme = r.URL.Scheme
+		}), Config{TrustedProxies: trustedProxies})
+
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.RemoteAddr = test.remoteAddr
+		req.Header.Set(ForwardedHeader, "for=203.0.113.1;proto=https")
+		client.ServeHTTP(httptest.NewRecorder(), req)
+
+		ip, _, err := net.SplitHostPort(remoteAddr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ip != test.expectedIP {
+			t.Fatalf("expected client IP %s but got %s", test.expectedIP, ip)
+		}
+		if scheme != test.expectedScheme {
+			t.Fatalf("expected scheme %q but got %q", test.expectedScheme, scheme)
+		}
+	}
+}
+
+func TestForwardedProxyChain(t *testing.T) {
+	trustedProxies, err := ParseTrustedProxies([]string{"192.0.2.1", "10.0.0.0/8"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &Client{config: Config{TrustedProxies: trustedProxies}}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Add(ForwardedHeader, `for=198.51.100.7, for="[2001:db8::1]:4711";host=example.com;proto=https`)
+	req.Header.Add(ForwardedHeader, "for=10.1.2.3")
+
+	// The IPv6 client is the first node that isn't a trusted proxy, so the
+	// spoofable element before it is ignored
+	remoteAddr, scheme, host := client.forwardedFor(req)
+	if remoteAddr != "[2001:db8::1]:4711" {
+		t.Fatalf("expected client address %s but got %s", "[2001:db8::1]:4711", remoteAddr)
+	}
+	if scheme != "https" || host != "example.com" {
+		t.Fatalf("expected https://example.com but got %s://%s", scheme, host)
+	}
+}
+
+func TestConnectionClose(t *testing.T) {
+	tests := []struct {
+		config Config
+		write  func(w http.ResponseWriter)
+	}{
+		{write: func(w http.ResponseWriter) { w.Write([]byte("bye")) }},
+		{write: func(w http.ResponseWriter) { w.WriteHeader(http.StatusAccepted) }},
+		{write: func(w http.ResponseWriter) {}},
+		{
+			config: Config{CompressResponses: true},
+			write: func(w http.ResponseWriter) {
+				w.Header().Set("Content-Type", "text/plain")
+				w.Write([]byte("bye"))
+			},
+		},
+	}
+	for _, test := range tests {
+		write := test.write
+		client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Connection", "close")
+			write(w)
+		}), test.config)
+		server := httptest.NewServer(client)
+
+		reused := []bool(nil)
+		trace := &httptrace.ClientTrace{
+			GotConn: func(info httptrace.GotConnInfo) {
+				reused = append(reused, info.Reused)
+			},
+		}
+		for i := 0; i < 2; i++ {
+			req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL, nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := server.Client().Do(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := ioutil.ReadAll(resp.Body); err != nil {
+				t.Fatal(err)
+			}
+			resp.Body.Close()
+			if !resp.Close {
+				t.Fatal("expected the response to ask for the connection to be closed")
+			}
+		}
+		server.Close()
+
+		for _, reused := range reused {
+			if reused {
+				t.Fatal("expected the connection not to be reused")
+			}
+		}
+	}
+}
+
+func TestEarlyHints(t *testing.T) {
+	for _, config := range []Config{{}, {CompressResponses: true}} {
+		hinted := make(chan struct{})
+		client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Add("Link", "</style.css>; rel=preload; as=style")
+			w.WriteHeader(http.StatusEarlyHints)
+
+			// The final response is only sent once the client has received the
+			// early hints
+			select {
+			case <-hinted:
+			case <-time.After(5 * time.Second):
+			}
+			w.Header().Set("Content-Type", "text/plain")
+			w.Write([]byte("hello"))
+		}), config)
+		server := httptest.NewServer(client)
+
+		hints := []http.Header(nil)
+		trace := &httptrace.ClientTrace{
+			Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+				if code == http.StatusEarlyHints {
+					hints = append(hints, http.Header(header))
+					close(hinted)
+				}
+				return nil
+			},
+		}
+		req, err := http.NewRequestWithContext(httptrace.WithClientTrace(context.Background(), trace), http.MethodGet, server.URL, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		start := time.Now()
+		resp, err := server.Client().Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, err := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		server.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if time.Since(start) >= 5*time.Second {
+			t.Fatal("expected the early hints to reach the client before the final response")
+		}
+		if len(hints) != 1 {
+			t.Fatalf("expected 1 early hints response, got %d", len(hints))
+		}
+		if link := hints[0].Get("Link"); link != "</style.css>; rel=preload; as=style" {
+			t.Fatalf("unexpected Link header in the early hints: %q", link)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("expected status %d, got %d", http.StatusOK, resp.StatusCode)
+		}
+		if string(body) != "hello" {
+			t.Fatalf("unexpected body %q", body)
+		}
+		if config.CompressResponses && !resp.Uncompressed {
+			t.Fatal("expected the final response to be compressed")
+		}
+	}
+}
+
+// countingCompressor counts the messages compressed by the compressor it wraps
+type countingCompressor struct {
+	encoding.Compressor
+	lock       sync.Mutex
+	compressed int
+}
+
+func (c *countingCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	c.lock.Lock()
+	c.compressed++
+	c.lock.Unlock()
+	return c.Compressor.Compress(w)
+}
+
+func (c *countingCompressor) count() int {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.compressed
+}
+
+func TestBridgeCompression(t *testing.T) {
+	gzipCompressor := encoding.GetCompressor(grpcgzip.Name)
+	defer encoding.RegisterCompressor(gzipCompressor)
+
+	large := bytes.Repeat([]byte("large"), 1000)
+	tests := []struct {
+		config     Config
+		payload    []byte
+		compressed bool
+	}{
+		{config: Config{}, payload: large, compressed: false},
+		{config: Config{BridgeCompression: true}, payload: large, compressed: true},
+		{config: Config{BridgeCompression: true}, payload: []byte("small"), compressed: false},
+		{config: Config{BridgeCompression: true, BridgeCompressionMinSize: 1}, payload: []byte("small"), compressed: true},
+	}
+	for _, test := range tests {
+		compressor := &countingCompressor{Compressor: gzipCompressor}
+		encoding.RegisterCompressor(compressor)
+
+		payload := test.payload
+		client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(payload)
+		}), test.config)
+
+		w := httptest.NewRecorder()
+		client.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		if !bytes.Equal(w.Body.Bytes(), payload) {
+			t.Fatalf("unexpected body of %d bytes", w.Body.Len())
+		}
+		if compressed := compressor.count() > 0; compressed != test.compressed {
+			t.Fatalf("expected compressed to be %v with config %+v and a %d byte body", test.compressed, test.config, len(payload))
+		}
+	}
+}
+
+type routeListingHandler struct{ http.Handler }
+
+func (routeListingHandler) Routes() []string { return []string{"/listed"} }
+
+func TestRoutes(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/a", func(http.ResponseWriter, *http.Request) {})
+
+	tests := []struct {
+		handler        http.Handler
+		introspectable bool
+		patterns       []string
+	}{
+		{handler: mux, introspectable: false, patterns: nil},
+		{handler: routeListingHandler{Handler: mux}, introspectable: true, patterns: []string{"/listed"}},
+		{handler: http.NotFoundHandler(), introspectable: false, patterns: nil},
+	}
+	for _, test := range tests {
+		client := newTestClient(t, test.handler)
+		patterns, introspectable, err := client.Routes(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if introspectable != test.introspectable {
+			t.Fatalf("expected introspectable to be %v, got %v", test.introspectable, introspectable)
+		}
+		if strings.Join(patterns, " ") != strings.Join(test.patterns, " ") {
+			t.Fatalf("expected patterns %q, got %q", test.patterns, patterns)
+		}
+	}
+}
+
+func TestLocalAddr(t *testing.T) {
+	localAddrs := make(chan net.Addr, 1)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		addr, _ := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+		localAddrs <- addr
+	}))
+	server := httptest.NewServer(client)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	addr, ok := (<-localAddrs).(*net.TCPAddr)
+	if !ok {
+		t.Fatal("expected the local address to be a TCP address")
+	}
+	if addr.String() != server.Listener.Addr().String() {
+		t.Fatalf("expected local address %s, got %s", server.Listener.Addr(), addr)
+	}
+}
+
+func TestParseAddr(t *testing.T) {
+	tests := []struct {
+		addr    string
+		network string
+	}{
+		{"127.0.0.1:9650", "tcp"},
+		{"[::1]:9650", "tcp"},
+		{"[fe80::1%eth0]:9650", "tcp"},
+		{"/tmp/node.sock", "unknown"},
+		{"localhost:9650", "unknown"},
+	}
+	for _, test := range tests {
+		addr := parseAddr(test.addr)
+		if addr.Network() != test.network {
+			t.Fatalf("expected %s to be parsed as a %s address, got %s", test.addr, test.network, addr.Network())
+		}
+		if addr.String() != test.addr {
+			t.Fatalf("expected %s to be parsed as itself, got %s", test.addr, addr)
+		}
+	}
+}
+
+func TestUnknownContentLength(t *testing.T) {
+	type result struct {
+		contentLength    int64
+		transferEncoding []string
+		body             string
+	}
+	results := make(chan result, 1)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		results <- result{
+			contentLength:    r.ContentLength,
+			transferEncoding: r.TransferEncoding,
+			body:             string(body),
+		}
+	}))
+	server := httptest.NewServer(client)
+	defer server.Close()
+
+	// The body is hidden behind a pipe so that its length is unknown, and
+	// the request is sent chunked
+	bodyReader, bodyWriter := io.Pipe()
+	go func() {
+		bodyWriter.Write([]byte("hello "))
+		bodyWriter.Write([]byte("world"))
+		bodyWriter.Close()
+	}()
+	resp, err := http.Post(server.URL, "text/plain", bodyReader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	r := <-results
+	if r.contentLength != -1 {
+		t.Fatalf("expected an unknown content length, got %d", r.contentLength)
+	}
+	if len(r.transferEncoding) != 1 || r.transferEncoding[0] != "chunked" {
+		t.Fatalf("expected a chunked transfer encoding, got %v", r.transferEncoding)
+	}
+	if r.body != "hello world" {
+		t.Fatalf("expected body %q, got %q", "hello world", r.body)
+	}
+}
+
+func TestMalformedRequest(t *testing.T) {
+	tests := map[string]func(r *http.Request){
+		"bad URL": func(r *http.Request) {
+			r.RequestURI = "http://[::1/"
+		},
+		"negative content length": func(r *http.Request) {
+			r.ContentLength = -2
+		},
+		"malformed peer certificate": func(r *http.Request) {
+			r.TLS = &tls.ConnectionState{
+				PeerCertificates: []*x509.Certificate{{Raw: []byte("not a certificate")}},
+			}
+		},
+		"malformed verified chain certificate": func(r *http.Request) {
+			r.TLS = &tls.ConnectionState{
+				VerifiedChains: [][]*x509.Certificate{{{Raw: []byte("not a certificate")}}},
+			}
+		},
+	}
+	for name, malform := range tests {
+		called := false
+		client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			called = true
+		}))
+
+		request := httptest.NewRequest(http.MethodGet, "/", nil)
+		malform(request)
+		recorder := httptest.NewRecorder()
+		client.ServeHTTP(recorder, request)
+
+		if recorder.Code != http.StatusBadRequest {
+			t.Fatalf("%s: expected status %d but got %d", name, http.StatusBadRequest, recorder.Code)
+		}
+		if !strings.HasPrefix(recorder.Body.String(), "malformed request: ") {
+			t.Fatalf("%s: expected a description of the problem but got %q", name, recorder.Body.String())
+		}
+		if called {
+			t.Fatalf("%s: the handler shouldn't have been called", name)
+		}
+	}
+}
+
+func TestCapture(t *testing.T) {
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "text/plain")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte("goodbye world"))
+	}), Config{
+		Capture: CaptureConfig{
+			Paths:       []string{"/captured"},
+			MaxBodySize: 5,
+		},
+	})
+
+	for _, path := range []string{"/captured/path", "/other"} {
+		recorder := httptest.NewRecorder()
+		client.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, strings.NewReader("hello world")))
+		if recorder.Body.String() != "goodbye world" {
+			t.Fatalf("expected the whole response to be written, got %q", recorder.Body.String())
+		}
+	}
+
+	captures := client.Captures()
+	if len(captures) != 1 {
+		t.Fatalf("expected only the request to the captured path to be captured, got %d captures", len(captures))
+	}
+	capture := captures[0]
+	if capture.Method != http.MethodPost || capture.URL != "/captured/path" {
+		t.Fatalf("expected POST /captured/path to be captured, got %s %s", capture.Method, capture.URL)
+	}
+	if string(capture.RequestBody) != "hello" || !capture.RequestBodyTruncated {
+		t.Fatalf("expected the request body to be truncated to %q, got %q", "hello", capture.RequestBody)
+	}
+	if capture.StatusCode != http.StatusAccepted {
+		t.Fatalf("expected status %d to be captured, got %d", http.StatusAccepted, capture.StatusCode)
+	}
+	if contentType := capture.ResponseHeader.Get("Content-Type"); contentType != "text/plain" {
+		t.Fatalf("expected the response headers to be captured, got Content-Type %q", contentType)
+	}
+	if string(capture.ResponseBody) != "goodb" || !capture.ResponseBodyTruncated {
+		t.Fatalf("expected the response body to be truncated to %q, got %q", "goodb", capture.ResponseBody)
+	}
+
+	client.ClearCaptures()
+	if captures := client.Captures(); len(captures) != 0 {
+		t.Fatalf("expected the captures to be cleared, got %d captures", len(captures))
+	}
+}
+
+func TestCaptureKeepsErrorReplies(t *testing.T) {
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(bytes.Repeat([]byte{'a'}, 16))
+	}), Config{
+		MaxResponseBodySize: 8,
+		Capture:             CaptureConfig{Paths: []string{"/"}},
+	})
+
+	// Capturing the response mustn't start it, so the oversized response can
+	// still be replaced with an error
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusInternalServerError {
+		t.Fatalf("expected status %d but got %d", http.StatusInternalServerError, recorder.Code)
+	}
+	if captures := client.Captures(); len(captures) != 1 || captures[0].StatusCode != http.StatusOK {
+		t.Fatalf("expected the handler's implied status to be captured, got %v", captures)
+	}
+}
+
+func TestCaptureDisabled(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("hello world"))
+	}))
+
+	client.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if captures := client.Captures(); captures != nil {
+		t.Fatalf("expected nothing to be captured by default, got %d captures", len(captures))
+	}
+}
+
+func TestResponseHeaders(t *testing.T) {
+	headers, err := ParseResponseHeaders(`{"Server": "gecko", "x-content-type-options": "nosniff"}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Server", "plugin")
+		w.Write([]byte("hello"))
+	}), Config{ResponseHeaders: headers})
+
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if server := recorder.Header().Get("Server"); server != "plugin" {
+		t.Fatalf("expected the handler's Server header to win, got %q", server)
+	}
+	if options := recorder.Header().Get("X-Content-Type-Options"); options != "nosniff" {
+		t.Fatalf("expected X-Content-Type-Options to be injected, got %q", options)
+	}
+
+	if _, err := ParseResponseHeaders(`["Server"]`); err == nil {
+		t.Fatal("expected headers that aren't an object to be rejected")
+	}
+	if headers, err := ParseResponseHeaders(""); err != nil || headers != nil {
+		t.Fatalf("expected no headers, got %v, %v", headers, err)
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	calls := 0
+	release := make(chan struct{})
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		<-release
+		w.Header().Set("X-Call", strconv.Itoa(calls))
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("issued"))
+	}), Config{Idempotency: IdempotencyConfig{Enabled: true, TTL: time.Minute}})
+
+	serve := func(key string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/", nil)
+		if key != "" {
+			r.Header.Set(IdempotencyKeyHeader, key)
+		}
+		recorder := httptest.NewRecorder()
+		client.ServeHTTP(recorder, r)
+		return recorder
+	}
+
+	// The retry arrives while the first request is being handled, so it waits
+	// for the first request's response
+	responses := make(chan *httptest.ResponseRecorder, 2)
+	for i := 0; i < 2; i++ {
+		go func() { responses <- serve("key") }()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	for i := 0; i < 2; i++ {
+		recorder := <-responses
+		if recorder.Code != http.StatusCreated || recorder.Body.String() != "issued" || recorder.Header().Get("X-Call") != "1" {
+			t.Fatalf("expected the first call's response, got %d %q %q", recorder.Code, recorder.Body.String(), recorder.Header().Get("X-Call"))
+		}
+	}
+
+	// A later retry is replied to with the kept response
+	if recorder := serve("key"); recorder.Header().Get("X-Call") != "1" {
+		t.Fatalf("expected the first call's response to be replayed, got call %q", recorder.Header().Get("X-Call"))
+	}
+	if calls != 1 {
+		t.Fatalf("expected the handler to be called once, was called %d times", calls)
+	}
+
+	// Other keys, and requests without keys, are passed to the handler
+	if recorder := serve("other"); recorder.Header().Get("X-Call") != "2" {
+		t.Fatalf("expected a new call, got call %q", recorder.Header().Get("X-Call"))
+	}
+	if recorder := serve(""); recorder.Header().Get("X-Call") != "3" {
+		t.Fatalf("expected a new call, got call %q", recorder.Header().Get("X-Call"))
+	}
+}
+
+func TestIdempotencyKeyExpiry(t *testing.T) {
+	calls := 0
+	cache := newIdempotencyCache(IdempotencyConfig{Enabled: true, TTL: time.Millisecond, MaxKeys: 1})
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Write([]byte("ok"))
+	}
+	serve := func(key string) {
+		cache.serve(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/", nil), key, handler)
+	}
+
+	serve("key")
+	time.Sleep(5 * time.Millisecond)
+	serve("key")
+	if calls != 2 {
+		t.Fatalf("expected an expired response not to be replayed, handler was called %d times", calls)
+	}
+
+	serve("other")
+	if len(cache.responses) != 1 || len(cache.keys) != 1 {
+		t.Fatalf("expected 1 response to be kept, %d are", len(cache.responses))
+	}
+}
+
+func TestStreamResponses(t *testing.T) {
+	const (
+		chunkSize = 1024
+		chunks    = 64
+	)
+	proceed := make(chan struct{})
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		// Written in small pieces, so that they're gathered into chunks
+		for i := 0; i < chunkSize; i += 16 {
+			w.Write(bytes.Repeat([]byte{'a'}, 16))
+		}
+		<-proceed
+		w.Write(bytes.Repeat([]byte{'b'}, (chunks-1)*chunkSize))
+	}), Config{
+		StreamResponses: true,
+		StreamChunkSize: chunkSize,
+	})
+
+	server := httptest.NewServer(client)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	// The first chunk reaches the client while the handler is still writing
+	first := make(chan error, 1)
+	go func() {
+		chunk := make([]byte, chunkSize)
+		_, err := io.ReadFull(resp.Body, chunk)
+		if err == nil && !bytes.Equal(chunk, bytes.Repeat([]byte{'a'}, chunkSize)) {
+			err = errors.New("unexpected first chunk")
+		}
+		first <- err
+	}()
+	select {
+	case err := <-first:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the first chunk to be streamed before the handler finished")
+	}
+	close(proceed)
+
+	rest, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(rest, bytes.Repeat([]byte{'b'}, (chunks-1)*chunkSize)) {
+		t.Fatalf("expected the rest of the response, got %d bytes", len(rest))
+	}
+}
+
+func TestClientDisconnectMidResponse(t *testing.T) {
+	type outcome struct {
+		writeErr, ctxErr error
+	}
+	released := make(chan outcome, 1)
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		chunk := bytes.Repeat([]byte{'a'}, 1024)
+		for {
+			if _, err := w.Write(chunk); err != nil {
+				select {
+				case <-r.Context().Done():
+				case <-time.After(5 * time.Second):
+				}
+				released <- outcome{
+					writeErr: err,
+					ctxErr:   r.Context().Err(),
+				}
+				return
+			}
+		}
+	}), Config{StreamResponses: true})
+
+	server := httptest.NewServer(client)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The client goes away after reading the start of the endless response
+	if _, err := io.ReadFull(resp.Body, make([]byte, 1024)); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	select {
+	case outcome := <-released:
+		if outcome.writeErr == nil {
+			t.Fatal("expected the handler's write to fail")
+		}
+		if outcome.ctxErr == nil {
+			t.Fatal("expected the handler's context to be cancelled")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the handler to be released once the client disconnected")
+	}
+}
+
+func TestStreamResponsesFinishesPartialChunk(t *testing.T) {
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("less than a chunk"))
+	}), Config{StreamResponses: true})
+
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if body := recorder.Body.String(); body != "less than a chunk" {
+		t.Fatalf("expected the partial chunk to be sent, got %q", body)
+	}
+}
+
+func TestMaintenance(t *testing.T) {
+	called := false
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+
+	client.SetMaintenance(true)
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected status %d, got %d", http.StatusServiceUnavailable, recorder.Code)
+	}
+	if retryAfter := recorder.Header().Get("Retry-After"); retryAfter != maintenanceRetryAfterSeconds {
+		t.Fatalf("expected Retry-After %q, got %q", maintenanceRetryAfterSeconds, retryAfter)
+	}
+	if called {
+		t.Fatal("expected the handler not to be called during maintenance")
+	}
+
+	client.SetMaintenance(false)
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/", nil))
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, recorder.Code)
+	}
+	if !called {
+		t.Fatal("expected the handler to be called after maintenance")
+	}
+}
+
+// countingHTTPClient counts the calls made to it
+type countingHTTPClient struct {
+	ghttpproto.HTTPClient
+	calls int
+}
+
+func (c *countingHTTPClient) Routes(context.Context, *ghttpproto.RoutesRequest, ...grpc.CallOption) (*ghttpproto.RoutesResponse, error) {
+	c.calls++
+	return &ghttpproto.RoutesResponse{}, nil
+}
+
+func TestPool(t *testing.T) {
+	single := &countingHTTPClient{}
+	if NewPool([]ghttpproto.HTTPClient{single}) != single {
+		t.Fatal("expected a pool of one client to be the client")
+	}
+
+	clients := []*countingHTTPClient{{}, {}, {}}
+	pool := NewPool([]ghttpproto.HTTPClient{clients[0], clients[1], clients[2]})
+	for i := 0; i < 3*len(clients); i++ {
+		if _, err := pool.Routes(context.Background(), &ghttpproto.RoutesRequest{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i, client := range clients {
+		if client.calls != 3 {
+			t.Fatalf("expected client %d to be called 3 times, was called %d times", i, client.calls)
+		}
+	}
+}
+
+func TestCORSPreflightHandledByBridge(t *testing.T) {
+	called := false
+	client := newTestClientWithConfig(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}), Config{
+		CORSAllowedOrigins: []string{"https://example.com"},
+		CORSAllowedMethods: []string{http.MethodPost},
+		CORSAllowedHeaders: []string{"X-Custom"},
+	})
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	req.Header.Set("Access-Control-Request-Headers", "X-Custom")
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, req)
+
+	if called {
+		t.Fatal("expected the preflight request not to be passed to the plugin")
+	}
+	if origin := recorder.Header().Get("Access-Control-Allow-Origin"); origin != "https://example.com" {
+		t.Fatalf("expected Access-Control-Allow-Origin %q but got %q", "https://example.com", origin)
+	}
+	if methods := recorder.Header().Get("Access-Control-Allow-Methods"); methods != http.MethodPost {
+		t.Fatalf("expected Access-Control-Allow-Methods %q but got %q", http.MethodPost, methods)
+	}
+	if headers := recorder.Header().Get("Access-Control-Allow-Headers"); headers != "X-Custom" {
+		t.Fatalf("expected Access-Control-Allow-Headers %q but got %q", "X-Custom", headers)
+	}
+
+	// Requests that aren't preflight requests should reach the plugin, with the
+	// CORS headers added to the response
+	req = httptest.NewRequest(http.MethodPost, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+	recorder = httptest.NewRecorder()
+	client.ServeHTTP(recorder, req)
+
+	if !called {
+		t.Fatal("expected the request to be passed to the plugin")
+	}
+	if origin := recorder.Header().Get("Access-Control-Allow-Origin"); origin != "https://example.com" {
+		t.Fatalf("expected Access-Control-Allow-Origin %q but got %q", "https://example.com", origin)
+	}
+}
+
+func TestCORSPreflightPassedThrough(t *testing.T) {
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodOptions {
+			t.Errorf("expected method %s but got %s", http.MethodOptions, r.Method)
+		}
+		w.Header().Set("Access-Control-Allow-Origin", r.Header.Get("Origin"))
+		w.Header().Set("Access-Control-Allow-Methods", r.Header.Get("Access-Control-Request-Method"))
+		w.Header().Add("Access-Control-Allow-Headers", "X-Custom")
+		w.Header().Add("Access-Control-Allow-Headers", "X-Other")
+		w.Header().Set("Access-Control-Max-Age", "600")
+		w.Header().Add("Vary", "Origin")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set("Origin", "https://example.com")
+	req.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d but got %d", http.StatusNoContent, recorder.Code)
+	}
+	if origin := recorder.Header().Get("Access-Control-Allow-Origin"); origin != "https://example.com" {
+		t.Fatalf("expected Access-Control-Allow-Origin %q but got %q", "https://example.com", origin)
+	}
+	if methods := recorder.Header().Get("Access-Control-Allow-Methods"); methods != http.MethodPut {
+		t.Fatalf("expected Access-Control-Allow-Methods %q but got %q", http.MethodPut, methods)
+	}
+	if headers := recorder.Header().Values("Access-Control-Allow-Headers"); len(headers) != 2 || headers[0] != "X-Custom" || headers[1] != "X-Other" {
+		t.Fatalf("expected Access-Control-Allow-Headers [X-Custom X-Other] but got %v", headers)
+	}
+	if maxAge := recorder.Header().Get("Access-Control-Max-Age"); maxAge != "600" {
+		t.Fatalf("expected Access-Control-Max-Age 600 but got %q", maxAge)
+	}
+}
+
+// newTestCertificate returns a certificate for [name] signed by [parent], or
+// self-signed if [parent] is nil
+func newTestCertificate(t *testing.T, name string, parent *x509.Certificate, parentKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  parent == nil,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	if parent == nil {
+		parent, parentKey = template, key
+	}
+	certBytes, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(certBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestClientCertificates(t *testing.T) {
+	ca, caKey := newTestCertificate(t, "ca", nil, nil)
+	leaf, _ := newTestCertificate(t, "client", ca, caKey)
+
+	var seen *tls.ConnectionState
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.TLS
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{
+		Version:           tls.VersionTLS12,
+		HandshakeComplete: true,
+		ServerName:        "example.com",
+		PeerCertificates:  []*x509.Certificate{leaf, ca},
+		VerifiedChains:    [][]*x509.Certificate{{leaf, ca}, {leaf}},
+	}
+	recorder := httptest.NewRecorder()
+	client.ServeHTTP(recorder, req)
+
+	if recorder.Code != http.StatusOK {
+		t.Fatalf("expected status %d but got %d", http.StatusOK, recorder.Code)
+	}
+	if seen == nil {
+		t.Fatal("expected the plugin to see the TLS connection state")
+	}
+	if len(seen.PeerCertificates) != 2 ||
+		!seen.PeerCertificates[0].Equal(leaf) ||
+		!seen.PeerCertificates[1].Equal(ca) {
+		t.Fatalf("plugin saw the wrong peer certificates")
+	}
+	if len(seen.VerifiedChains) != 2 ||
+		len(seen.VerifiedChains[0]) != 2 ||
+		!seen.VerifiedChains[0][0].Equal(leaf) ||
+		!seen.VerifiedChains[0][1].Equal(ca) ||
+		len(seen.VerifiedChains[1]) != 1 ||
+		!seen.VerifiedChains[1][0].Equal(leaf) {
+		t.Fatalf("plugin saw the wrong verified chains")
+	}
+
+	// The plugin can verify the client's certificate itself
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	if _, err := seen.PeerCertificates[0].Verify(x509.VerifyOptions{
+		Roots:     roots,
+		KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}); err != nil {
+		t.Fatalf("couldn't verify the peer certificate: %s", err)
+	}
+
+	// Without client certificates, the plugin sees none
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.TLS = &tls.ConnectionState{
+		Version:           tls.VersionTLS12,
+		HandshakeComplete: true,
+	}
+	client.ServeHTTP(httptest.NewRecorder(), req)
+	if seen == nil {
+		t.Fatal("expected the plugin to see the TLS connection state")
+	}
+	if seen.PeerCertificates != nil || seen.VerifiedChains != nil {
+		t.Fatalf("expected no certificates but the plugin saw %d peer certificates and %d verified chains", len(seen.PeerCertificates), len(seen.VerifiedChains))
+	}
+}
+
+func TestRequestTrailer(t *testing.T) {
+	type seen struct {
+		body           string
+		beforeBody     http.Header
+		afterBody      http.Header
+		transferChunks bool
+	}
+	seenCh := make(chan seen, 1)
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s := seen{beforeBody: r.Trailer.Clone()}
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			t.Errorf("couldn't read the body: %s", err)
+		}
+		s.body = string(body)
+		s.afterBody = r.Trailer.Clone()
+		s.transferChunks = len(r.TransferEncoding) > 0 && r.TransferEncoding[0] == "chunked"
+		seenCh <- s
+	}))
+	server := httptest.NewServer(client)
+	defer server.Close()
+
+	req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("hello world"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// An unknown length makes the body chunked, which is what carries the
+	// trailer
+	req.ContentLength = -1
+	req.Trailer = http.Header{"X-Checksum": []string{"5eb63bbbe01eeed093cb22bb8f5acdc3"}}
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	s := <-seenCh
+	if !s.transferChunks {
+		t.Fatal("expected the body to be chunked")
+	}
+	if s.body != "hello world" {
+		t.Fatalf("expected body %q but got %q", "hello world", s.body)
+	}
+	if values, ok := s.beforeBody["X-Checksum"]; !ok || len(values) != 0 {
+		t.Fatalf("expected the trailer to be declared without a value before the body was read, but got %v", s.beforeBody)
+	}
+	if checksum := s.afterBody.Get("X-Checksum"); checksum != "5eb63bbbe01eeed093cb22bb8f5acdc3" {
+		t.Fatalf("expected the trailer to be %q after the body was read, but got %q", "5eb63bbbe01eeed093cb22bb8f5acdc3", checksum)
+	}
+}
+
+func TestUndeclaredRequestTrailer(t *testing.T) {
+	checksumCh := make(chan string, 1)
+	// Conveying the request start copies the request before it's passed on
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ioutil.ReadAll(r.Body); err != nil {
+			t.Errorf("couldn't read the body: %s", err)
+		}
+		checksumCh <- r.Trailer.Get("X-Checksum")
+	})
+	client := newTestClientWithConfig(t, handler, Config{ConveyRequestStart: true})
+	server := httptest.NewServer(client)
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", server.Listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	// The trailer isn't declared in a Trailer header
+	request := "POST / HTTP/1.1\r\nHost: test\r\nTransfer-Encoding: chunked\r\n\r\n" +
+		"b\r\nhello world\r\n0\r\nX-Checksum: 5eb63bbbe01eeed093cb22bb8f5acdc3\r\n\r\n"
+	if _, err := conn.Write([]byte(request)); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.ReadResponse(bufio.NewReader(conn), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	if checksum := <-checksumCh; checksum != "5eb63bbbe01eeed093cb22bb8f5acdc3" {
+		t.Fatalf("expected the trailer to be %q after the body was read, but got %q", "5eb63bbbe01eeed093cb22bb8f5acdc3", checksum)
+	}
+}
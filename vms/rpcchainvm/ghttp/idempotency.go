@@ -0,0 +1,276 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// IdempotencyKeyHeader is the header clients set to have retries of a
+	// request replied to with the response to the first attempt
+	IdempotencyKeyHeader = "Idempotency-Key"
+
+	// DefaultIdempotencyTTL is how long a response is replayed for if no TTL
+	// is configured
+	DefaultIdempotencyTTL = time.Minute
+
+	// DefaultIdempotencyMaxKeys is the number of responses that are kept if no
+	// bound is configured
+	DefaultIdempotencyMaxKeys = 1024
+
+	// maxIdempotentResponseSize is the size, in bytes, of the largest response
+	// body that is kept to be replayed. Larger responses aren't replayed, so
+	// retries of their requests are passed to the plugin.
+	maxIdempotentResponseSize = 1 << 20
+)
+
+// IdempotencyConfig configures the replaying of responses to requests that
+// have an Idempotency-Key header
+type IdempotencyConfig struct {
+	// Enabled, if true, causes the response to a request with an
+	// Idempotency-Key header to be kept, and replayed to the requests with the
+	// same key, method and path that arrive before it expires, rather than
+	// them being passed to the plugin. A request that arrives while the first
+	// request with its key is being handled waits for, and is replied to
+	// with, the first request's response. This keeps retries of requests that
+	// issue transactions from issuing them twice.
+	Enabled bool
+
+	// TTL is how long a response is replayed for. If not positive,
+	// DefaultIdempotencyTTL is used.
+	TTL time.Duration
+
+	// MaxKeys is the number of responses that are kept. Once it's reached,
+	// the oldest response is dropped to make room for a new one. If not
+	// positive, DefaultIdempotencyMaxKeys is used.
+	MaxKeys int
+}
+
+// idempotentResponse is the response to the first request with an idempotency
+// key
+type idempotentResponse struct {
+	// closed once the response is known
+	done chan struct{}
+
+	// false if the response can't be replayed, such as if the handler failed
+	// or the body was too large
+	replayable bool
+	expiry     time.Time
+
+	statusCode int
+	// headers the handler set, or that it set to different values than they
+	// had before it was called
+	header http.Header
+	body   []byte
+}
+
+// idempotencyCache keeps the responses to requests with idempotency keys
+type idempotencyCache struct {
+	ttl     time.Duration
+	maxKeys int
+
+	lock      sync.Mutex
+	responses map[string]*idempotentResponse
+	// keys of [responses], oldest first
+	keys []string
+}
+
+func newIdempotencyCache(config IdempotencyConfig) *idempotencyCache {
+	if !config.Enabled {
+		return nil
+	}
+	if config.TTL <= 0 {
+		config.TTL = DefaultIdempotencyTTL
+	}
+	if config.MaxKeys <= 0 {
+		config.MaxKeys = DefaultIdempotencyMaxKeys
+	}
+	return &idempotencyCache{
+		ttl:       config.TTL,
+		maxKeys:   config.MaxKeys,
+		responses: make(map[string]*idempotentResponse),
+	}
+}
+
+// serve replies to [r] with the response to the first request with its key,
+// if it's known and hasn't expired, and otherwise passes [r] to [next] and
+// keeps its response
+func (c *idempotencyCache) serve(w http.ResponseWriter, r *http.Request, idempotencyKey string, next http.HandlerFunc) {
+	key := r.Method + " " + r.URL.Path + " " + idempotencyKey
+
+	c.lock.Lock()
+	response, ok := c.responses[key]
+	if ok {
+		select {
+		case <-response.done:
+			if !response.replayable || !time.Now().Before(response.expiry) {
+				ok = false
+			}
+		default:
+			// The first request is still being handled
+		}
+	}
+	if !ok {
+		response = &idempotentResponse{done: make(chan struct{})}
+		c.add(key, response)
+	}
+	c.lock.Unlock()
+
+	if ok {
+		select {
+		case <-response.done:
+		case <-r.Context().Done():
+			return
+		}
+		if response.replayable {
+			response.replay(w)
+			return
+		}
+		// The first request's response can't be replayed, so this one is
+		// handled on its own
+		next(w, r)
+		return
+	}
+
+	recorder := &recordingResponseWriter{
+		ResponseWriter: w,
+		base:           w.Header().Clone(),
+	}
+	returned := false
+	defer func() {
+		// If the handler panicked, the response isn't replayed. Otherwise, if
+		// it didn't write anything, it's replied to with an empty 200.
+		if returned && recorder.statusCode == 0 && !recorder.unreplayable {
+			recorder.WriteHeader(http.StatusOK)
+		}
+		response.replayable = recorder.replayable()
+		response.statusCode = recorder.statusCode
+		response.header = recorder.header
+		response.body = recorder.body
+		response.expiry = time.Now().Add(c.ttl)
+		close(response.done)
+	}()
+	next(recorder, r)
+	returned = true
+}
+
+// add [response] under [key], dropping the oldest responses if there are too
+// many. Assumes [c.lock] is held.
+func (c *idempotencyCache) add(key string, response *idempotentResponse) {
+	if _, ok := c.responses[key]; !ok {
+		c.keys = append(c.keys, key)
+	} else {
+		for i, k := range c.keys {
+			if k == key {
+				c.keys = append(append(c.keys[:i:i], c.keys[i+1:]...), key)
+				break
+			}
+		}
+	}
+	c.responses[key] = response
+
+	for len(c.keys) > c.maxKeys {
+		delete(c.responses, c.keys[0])
+		c.keys = c.keys[1:]
+	}
+}
+
+// replay writes the response to [w]
+func (r *idempotentResponse) replay(w http.ResponseWriter) {
+	header := w.Header()
+	for key, values := range r.header {
+		header[key] = values
+	}
+	w.WriteHeader(r.statusCode)
+	_, _ = w.Write(r.body)
+}
+
+// recordingResponseWriter records the response written to it so that it can
+// be replayed
+type recordingResponseWriter struct {
+	http.ResponseWriter
+
+	// headers of the response before the handler was called
+	base http.Header
+
+	statusCode int
+	header     http.Header
+	body       []byte
+	// true if the response can't be replayed
+	unreplayable bool
+}
+
+func (w *recordingResponseWriter) WriteHeader(statusCode int) {
+	if w.statusCode == 0 && statusCode >= http.StatusOK {
+		w.statusCode = statusCode
+		w.header = http.Header{}
+		for key, values := range w.Header() {
+			if baseValues, ok := w.base[key]; !ok || !equalValues(values, baseValues) {
+				w.header[key] = append([]string(nil), values...)
+			}
+		}
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *recordingResponseWriter) Write(payload []byte) (int, error) {
+	if w.statusCode == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	n, err := w.ResponseWriter.Write(payload)
+	if err != nil {
+		w.unreplayable = true
+	}
+	if len(w.body)+n > maxIdempotentResponseSize {
+		w.unreplayable = true
+		w.body = nil
+	}
+	if !w.unreplayable {
+		w.body = append(w.body, payload[:n]...)
+	}
+	return n, err
+}
+
+// Flush ...
+func (w *recordingResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack ...
+func (w *recordingResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	// The rest of the response isn't seen
+	w.unreplayable = true
+	return hijacker.Hijack()
+}
+
+// replayable returns true if the recorded response can be replayed. Server
+// errors aren't replayed, so that requests that failed can be retried.
+func (w *recordingResponseWriter) replayable() bool {
+	return !w.unreplayable && w.statusCode != 0 && w.statusCode < http.StatusInternalServerError
+}
+
+// equalValues returns true if [a] and [b] hold the same values in the same
+// order
+func equalValues(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
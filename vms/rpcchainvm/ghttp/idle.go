@@ -0,0 +1,129 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/gresponsewriter"
+)
+
+// idleTimer cancels a request's context if the request's body isn't read from
+// and its response isn't written to for [timeout].
+type idleTimer struct {
+	timeout time.Duration
+	cancel  context.CancelFunc
+
+	lock     sync.Mutex
+	timer    *time.Timer
+	stopped  bool
+	timedOut bool
+}
+
+// newIdleTimer returns a timer that cancels the returned context if it isn't
+// touched for [timeout]
+func newIdleTimer(ctx context.Context, timeout time.Duration) (context.Context, *idleTimer) {
+	ctx, cancel := context.WithCancel(ctx)
+	t := &idleTimer{
+		timeout: timeout,
+		cancel:  cancel,
+	}
+	t.timer = time.AfterFunc(timeout, t.expire)
+	return ctx, t
+}
+
+func (t *idleTimer) expire() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.stopped {
+		t.timedOut = true
+		t.cancel()
+	}
+}
+
+// touch records activity on the request, restarting the timer
+func (t *idleTimer) touch() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	if !t.stopped && !t.timedOut {
+		t.timer.Reset(t.timeout)
+	}
+}
+
+// stop the timer. Returns true if the request's context was cancelled because
+// it was idle.
+func (t *idleTimer) stop() bool {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.stopped = true
+	t.timer.Stop()
+	return t.timedOut
+}
+
+// idleReadCloser touches [timer] whenever the body is read from
+type idleReadCloser struct {
+	io.ReadCloser
+	timer *idleTimer
+}
+
+// Read ...
+func (r *idleReadCloser) Read(p []byte) (int, error) {
+	r.timer.touch()
+	n, err := r.ReadCloser.Read(p)
+	r.timer.touch()
+	return n, err
+}
+
+// idleResponseWriter touches [timer] whenever the response is written to
+type idleResponseWriter struct {
+	http.ResponseWriter
+	timer *idleTimer
+
+	wroteHeader bool
+}
+
+// WriteHeader ...
+func (w *idleResponseWriter) WriteHeader(statusCode int) {
+	w.timer.touch()
+	if !gresponsewriter.Informational(statusCode) {
+		w.wroteHeader = true
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write ...
+func (w *idleResponseWriter) Write(payload []byte) (int, error) {
+	w.timer.touch()
+	w.wroteHeader = true
+	n, err := w.ResponseWriter.Write(payload)
+	w.timer.touch()
+	return n, err
+}
+
+// Flush ...
+func (w *idleResponseWriter) Flush() {
+	w.timer.touch()
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack stops the timer, as the handler takes over managing the connection
+func (w *idleResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	w.timer.stop()
+	return hijacker.Hijack()
+}
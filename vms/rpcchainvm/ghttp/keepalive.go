@@ -0,0 +1,72 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/keepalive"
+)
+
+const (
+	// DefaultKeepaliveTime is how long a bridge connection may go without
+	// activity before its peer is pinged
+	DefaultKeepaliveTime = 30 * time.Second
+
+	// DefaultKeepaliveTimeout is how long a ping may go unanswered before the
+	// connection is closed
+	DefaultKeepaliveTimeout = 10 * time.Second
+)
+
+// KeepaliveConfig configures the keepalive pings of the gRPC servers that
+// bridge HTTP requests between the node and a plugin, so that a connection to
+// a peer that went away without closing it is detected and closed.
+//
+// The bridge's connections are dialed by the plugin broker, which doesn't
+// accept dial options, so pings are sent by the serving side of each
+// connection. The node serves a request's body and response writer and the
+// plugin serves its handlers, so both directions are covered.
+type KeepaliveConfig struct {
+	// Time is how long a connection may go without activity before the peer is
+	// pinged. If not positive, DefaultKeepaliveTime is used.
+	Time time.Duration
+
+	// Timeout is how long a ping may go unanswered before the connection is
+	// closed. If not positive, DefaultKeepaliveTimeout is used.
+	Timeout time.Duration
+
+	// PermitWithoutStream, if true, allows the peer to send its own pings while
+	// no RPCs are in flight, rather than the connection being closed when it
+	// does.
+	PermitWithoutStream bool
+}
+
+// withDefaults returns [c] with unset values replaced by their defaults
+func (c KeepaliveConfig) withDefaults() KeepaliveConfig {
+	if c.Time <= 0 {
+		c.Time = DefaultKeepaliveTime
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = DefaultKeepaliveTimeout
+	}
+	return c
+}
+
+// ServerOptions returns the options a bridge's gRPC server should be created
+// with
+func (c KeepaliveConfig) ServerOptions() []grpc.ServerOption {
+	c = c.withDefaults()
+	return []grpc.ServerOption{
+		grpc.KeepaliveParams(keepalive.ServerParameters{
+			Time:    c.Time,
+			Timeout: c.Timeout,
+		}),
+		grpc.KeepaliveEnforcementPolicy(keepalive.EnforcementPolicy{
+			// Peers may ping as often as this side does
+			MinTime:             c.Time,
+			PermitWithoutStream: c.PermitWithoutStream,
+		}),
+	}
+}
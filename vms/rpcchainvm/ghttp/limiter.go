@@ -0,0 +1,66 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// retryAfterSeconds is the Retry-After sent with rejected requests
+const retryAfterSeconds = "1"
+
+// limiter bounds the number of requests that are handled at once. Requests
+// beyond the bound wait in a queue, which may be bounded, for a request to
+// finish. A nil limiter doesn't limit requests.
+type limiter struct {
+	slots     chan struct{}
+	queued    int64 // accessed atomically
+	maxQueued int64
+}
+
+// newLimiter returns a limiter that lets [maxConcurrent] requests be handled at
+// once, and [maxQueued] wait. Returns nil if [maxConcurrent] isn't positive. If
+// [maxQueued] isn't positive, any number of requests may wait.
+func newLimiter(maxConcurrent, maxQueued int) *limiter {
+	if maxConcurrent <= 0 {
+		return nil
+	}
+	return &limiter{
+		slots:     make(chan struct{}, maxConcurrent),
+		maxQueued: int64(maxQueued),
+	}
+}
+
+// acquire a slot to handle a request in. Returns false if the queue is full,
+// or [ctx] is done before a slot is free. If true is returned, release must be
+// called once the request has been handled.
+func (l *limiter) acquire(ctx context.Context) bool {
+	if l == nil {
+		return true
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	default:
+	}
+
+	defer atomic.AddInt64(&l.queued, -1)
+	if queued := atomic.AddInt64(&l.queued, 1); l.maxQueued > 0 && queued > l.maxQueued {
+		return false
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// release a slot acquired by acquire
+func (l *limiter) release() {
+	if l != nil {
+		<-l.slots
+	}
+}
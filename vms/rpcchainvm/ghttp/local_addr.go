@@ -0,0 +1,63 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strconv"
+)
+
+// localAddr returns the local address of the connection [r] was received on,
+// or the empty string if it isn't known
+func localAddr(r *http.Request) string {
+	addr, ok := r.Context().Value(http.LocalAddrContextKey).(net.Addr)
+	if !ok || addr == nil {
+		return ""
+	}
+	return addr.String()
+}
+
+// withLocalAddr returns [ctx] with [addr] stored under
+// http.LocalAddrContextKey, as the node's HTTP server does. [addr] is a
+// *net.TCPAddr if it's an IP and port, as it is for TCP connections.
+func withLocalAddr(ctx context.Context, addr string) context.Context {
+	if addr == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, http.LocalAddrContextKey, parseAddr(addr))
+}
+
+// parseAddr parses [addr] into a *net.TCPAddr, or a net.Addr that just reports
+// [addr] if it isn't an IP and port
+func parseAddr(addr string) net.Addr {
+	host, portStr, err := net.SplitHostPort(addr)
+	if err != nil {
+		return stringAddr(addr)
+	}
+	ip, zone := host, ""
+	for i := 0; i < len(host); i++ {
+		if host[i] == '%' {
+			ip, zone = host[:i], host[i+1:]
+			break
+		}
+	}
+	parsedIP := net.ParseIP(ip)
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if parsedIP == nil || err != nil {
+		return stringAddr(addr)
+	}
+	return &net.TCPAddr{
+		IP:   parsedIP,
+		Port: int(port),
+		Zone: zone,
+	}
+}
+
+// stringAddr is an address of an unknown network
+type stringAddr string
+
+func (a stringAddr) Network() string { return "unknown" }
+func (a stringAddr) String() string  { return string(a) }
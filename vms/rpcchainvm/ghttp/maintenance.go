@@ -0,0 +1,28 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"sync/atomic"
+)
+
+// maintenanceRetryAfterSeconds is the Retry-After sent with requests rejected
+// during maintenance, which is expected to last much longer than the plugin
+// is busy for
+const maintenanceRetryAfterSeconds = "60"
+
+// SetMaintenance sets whether the handler is in maintenance. While it is,
+// every request is replied to with a 503 without calling the plugin.
+func (c *Client) SetMaintenance(enabled bool) {
+	maintenance := uint32(0)
+	if enabled {
+		maintenance = 1
+	}
+	atomic.StoreUint32(&c.maintenance, maintenance)
+}
+
+// InMaintenance returns true if the handler is in maintenance
+func (c *Client) InMaintenance() bool {
+	return atomic.LoadUint32(&c.maintenance) == 1
+}
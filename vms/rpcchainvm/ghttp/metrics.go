@@ -0,0 +1,198 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"net/http"
+	"path"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/ava-labs/gecko/utils/timer"
+	"github.com/ava-labs/gecko/utils/wrappers"
+)
+
+const (
+	// MetricsEndpointHeader is the response header a plugin's handler sets to
+	// name the endpoint its request is recorded under. It's removed from the
+	// response before the response is sent to the client.
+	MetricsEndpointHeader = "X-Metrics-Endpoint"
+
+	// DefaultMaxMetricsEndpoints is the number of distinct endpoints requests
+	// are recorded under if no limit is configured
+	DefaultMaxMetricsEndpoints = 64
+
+	// otherEndpoint is the endpoint requests are recorded under once the
+	// limit on distinct endpoints has been reached
+	otherEndpoint = "other"
+)
+
+// Metrics records the number, status and latency of the requests handled by
+// plugins' handlers, labeled by the endpoint the handler named. Requests whose
+// handler didn't name an endpoint are labeled by their path. Every distinct
+// endpoint is a new time series, so once [maxEndpoints] endpoints have been
+// seen, requests to new endpoints are recorded under "other".
+type Metrics struct {
+	requests *prometheus.CounterVec
+	latency  *prometheus.HistogramVec
+
+	maxEndpoints int
+
+	lock      sync.Mutex
+	endpoints map[string]struct{}
+}
+
+// NewMetrics returns metrics registered with [registerer]. If [maxEndpoints]
+// isn't positive, DefaultMaxMetricsEndpoints is used.
+func NewMetrics(namespace string, registerer prometheus.Registerer, maxEndpoints int) (*Metrics, error) {
+	if maxEndpoints <= 0 {
+		maxEndpoints = DefaultMaxMetricsEndpoints
+	}
+	m := &Metrics{
+		requests: prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Namespace: namespace,
+				Name:      "requests",
+				Help:      "Number of requests handled, by endpoint and status code",
+			},
+			[]string{"endpoint", "code"},
+		),
+		latency: prometheus.NewHistogramVec(
+			prometheus.HistogramOpts{
+				Namespace: namespace,
+				Name:      "request_latency",
+				Help:      "Time spent handling a request in milliseconds, by endpoint",
+				Buckets:   timer.MillisecondsBuckets,
+			},
+			[]string{"endpoint"},
+		),
+		maxEndpoints: maxEndpoints,
+		endpoints:    make(map[string]struct{}),
+	}
+
+	errs := wrappers.Errs{}
+	if err := registerer.Register(m.requests); err != nil {
+		errs.Add(fmt.Errorf("failed to register requests statistics due to %s", err))
+	}
+	if err := registerer.Register(m.latency); err != nil {
+		errs.Add(fmt.Errorf("failed to register request_latency statistics due to %s", err))
+	}
+	return m, errs.Err
+}
+
+// SetMetricsEndpoint names the endpoint the request being replied to with [w]
+// is recorded under. Should be called by plugins' handlers before the
+// response is written.
+func SetMetricsEndpoint(w http.ResponseWriter, endpoint string) {
+	w.Header().Set(MetricsEndpointHeader, endpoint)
+}
+
+// label returns the label [endpoint] is recorded under
+func (m *Metrics) label(endpoint string) string {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if _, ok := m.endpoints[endpoint]; ok {
+		return endpoint
+	}
+	if len(m.endpoints) >= m.maxEndpoints {
+		return otherEndpoint
+	}
+	m.endpoints[endpoint] = struct{}{}
+	return endpoint
+}
+
+// observe records a request to [endpoint] that was replied to with
+// [statusCode] after [latency]
+func (m *Metrics) observe(endpoint string, statusCode int, latency time.Duration) {
+	endpoint = m.label(endpoint)
+	m.requests.WithLabelValues(endpoint, strconv.Itoa(statusCode)).Inc()
+	m.latency.WithLabelValues(endpoint).Observe(float64(latency) / float64(time.Millisecond))
+}
+
+// normalizePath returns the endpoint a request to [p] is recorded under if
+// its handler didn't name one
+func normalizePath(p string) string {
+	if p == "" {
+		return "/"
+	}
+	return path.Clean("/" + p)
+}
+
+// metricsResponseWriter records the status code of a response, and removes
+// the endpoint the handler named from the response's header
+type metricsResponseWriter struct {
+	http.ResponseWriter
+
+	endpoint    string
+	wroteHeader bool
+	statusCode  int
+}
+
+// takeEndpoint removes the endpoint the handler named, if it named one, from
+// the response's header
+func (w *metricsResponseWriter) takeEndpoint() {
+	header := w.Header()
+	if endpoint := header.Get(MetricsEndpointHeader); endpoint != "" {
+		w.endpoint = endpoint
+	}
+	header.Del(MetricsEndpointHeader)
+}
+
+// WriteHeader ...
+func (w *metricsResponseWriter) WriteHeader(statusCode int) {
+	// Informational responses send the header too
+	w.takeEndpoint()
+	if !w.wroteHeader && statusCode >= http.StatusOK {
+		w.wroteHeader = true
+		w.statusCode = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write ...
+func (w *metricsResponseWriter) Write(payload []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(payload)
+}
+
+// Flush ...
+func (w *metricsResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack ...
+func (w *metricsResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	w.takeEndpoint()
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.statusCode = http.StatusSwitchingProtocols
+	}
+	return hijacker.Hijack()
+}
+
+// finish returns the endpoint the request is recorded under and the status
+// code it was replied to with. Must be called once the response is complete.
+func (w *metricsResponseWriter) finish() (string, int) {
+	w.takeEndpoint()
+	if !w.wroteHeader {
+		// net/http replies with an empty 200 if nothing was written
+		return w.endpoint, http.StatusOK
+	}
+	return w.endpoint, w.statusCode
+}
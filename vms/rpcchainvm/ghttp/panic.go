@@ -0,0 +1,85 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// panicErrorPrefix starts the message of the error returned by the server when
+// the plugin's handler panics
+const panicErrorPrefix = "handler panicked: "
+
+// PanicError is raised by the client when the plugin's handler panicked, so
+// that the panic is handled the same way as if the handler were run by this
+// process
+type PanicError struct {
+	// Value the handler panicked with
+	Value string
+
+	// Stack of the plugin's goroutine that panicked
+	Stack string
+
+	// true if the 500 replied with should describe the panic
+	details bool
+}
+
+func (e *PanicError) Error() string { return panicErrorPrefix + e.Value }
+
+// PluginStacktrace returns the stack of the plugin's goroutine that panicked
+func (e *PanicError) PluginStacktrace() string { return e.Stack }
+
+// PanicResponse returns the body of the 500 replied with, which describes the
+// panic if Config.PanicDetails is set, or is empty otherwise
+func (e *PanicError) PanicResponse() string {
+	if !e.details {
+		return ""
+	}
+	return fmt.Sprintf("%s\n\n%s", e.Error(), e.Stack)
+}
+
+// serveRecovered calls [handler] and recovers from any panic it raises.
+// Returns an error describing the panic, if one was raised.
+func serveRecovered(handler http.Handler, w http.ResponseWriter, r *http.Request) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = status.Error(codes.Internal, fmt.Sprintf("%s%s\n%s",
+				panicErrorPrefix,
+				strconv.Quote(fmt.Sprint(recovered)),
+				debug.Stack(),
+			))
+		}
+	}()
+	handler.ServeHTTP(w, r)
+	return nil
+}
+
+// parsePanicError returns the panic described by [err], if [err] was returned
+// by the server because the plugin's handler panicked
+func parsePanicError(err error) (*PanicError, bool) {
+	s, ok := status.FromError(err)
+	if !ok || s.Code() != codes.Internal || !strings.HasPrefix(s.Message(), panicErrorPrefix) {
+		return nil, false
+	}
+	msg := strings.TrimPrefix(s.Message(), panicErrorPrefix)
+	i := strings.IndexByte(msg, '\n')
+	if i < 0 {
+		return nil, false
+	}
+	value, err := strconv.Unquote(msg[:i])
+	if err != nil {
+		return nil, false
+	}
+	return &PanicError{
+		Value: value,
+		Stack: msg[i+1:],
+	}, true
+}
@@ -0,0 +1,45 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"context"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/ghttpproto"
+)
+
+// pool spreads the calls to a plugin's handler over several connections, in
+// turn
+type pool struct {
+	clients []ghttpproto.HTTPClient
+	next    uint32 // accessed atomically
+}
+
+// NewPool returns a client that spreads its calls over [clients], which must
+// all be connected to the same handler. If there's only one client, it's
+// returned as is.
+func NewPool(clients []ghttpproto.HTTPClient) ghttpproto.HTTPClient {
+	if len(clients) == 1 {
+		return clients[0]
+	}
+	return &pool{clients: clients}
+}
+
+func (p *pool) client() ghttpproto.HTTPClient {
+	i := atomic.AddUint32(&p.next, 1)
+	return p.clients[int(i)%len(p.clients)]
+}
+
+// Handle implements the ghttpproto.HTTPClient interface
+func (p *pool) Handle(ctx context.Context, in *ghttpproto.HTTPRequest, opts ...grpc.CallOption) (*ghttpproto.HTTPResponse, error) {
+	return p.client().Handle(ctx, in, opts...)
+}
+
+// Routes implements the ghttpproto.HTTPClient interface
+func (p *pool) Routes(ctx context.Context, in *ghttpproto.RoutesRequest, opts ...grpc.CallOption) (*ghttpproto.RoutesResponse, error) {
+	return p.client().Routes(ctx, in, opts...)
+}
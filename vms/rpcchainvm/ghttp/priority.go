@@ -0,0 +1,121 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// priorityHeader is the header an HTTP/2 client signals the priority of a
+	// stream with (RFC 9218). The server doesn't expose the priority frames
+	// of earlier clients to handlers, so this is the only signal available.
+	priorityHeader = "Priority"
+
+	// priorityKey is the metadata key the node sends the plugin, with a
+	// request, the priority of the request's stream under
+	priorityKey = "ghttp-priority"
+
+	// DefaultUrgency is the urgency of a stream whose client signaled its
+	// priority without signaling its urgency
+	DefaultUrgency = 3
+
+	// maxUrgency is the urgency of the least urgent streams
+	maxUrgency = 7
+)
+
+// Priority is the priority a client signaled for an HTTP/2 stream
+type Priority struct {
+	// Urgency is in [0, 7]. Lower is more urgent.
+	Urgency int
+
+	// Incremental is true if the client can use the response as it arrives,
+	// so it may be interleaved with other responses of the same urgency
+	Incremental bool
+}
+
+// String returns [p] in the form of a Priority header
+func (p Priority) String() string {
+	if p.Incremental {
+		return fmt.Sprintf("u=%d, i", p.Urgency)
+	}
+	return fmt.Sprintf("u=%d", p.Urgency)
+}
+
+// parsePriority parses the values of a Priority header. Parameters that aren't
+// known, or are malformed, are ignored, as RFC 9218 requires.
+func parsePriority(values []string) Priority {
+	priority := Priority{Urgency: DefaultUrgency}
+	for _, value := range values {
+		for _, member := range strings.Split(value, ",") {
+			key, param := strings.TrimSpace(member), ""
+			if i := strings.IndexByte(key, '='); i >= 0 {
+				key, param = key[:i], key[i+1:]
+			}
+			switch key {
+			case "u":
+				urgency, err := strconv.Atoi(param)
+				if err == nil && urgency >= 0 && urgency <= maxUrgency {
+					priority.Urgency = urgency
+				}
+			case "i":
+				switch param {
+				case "", "?1":
+					priority.Incremental = true
+				case "?0":
+					priority.Incremental = false
+				}
+			}
+		}
+	}
+	return priority
+}
+
+// priorityContextKey is the key the priority of a request's stream is stored
+// under in the request's context
+type priorityContextKey struct{}
+
+// StreamPriority returns the priority the client signaled for the HTTP/2
+// stream of the request whose context is [ctx], so that a plugin's handler can
+// schedule its work accordingly. Returns false if the request wasn't made over
+// HTTP/2, the client didn't signal a priority, or the node predates conveying
+// it.
+func StreamPriority(ctx context.Context) (Priority, bool) {
+	priority, ok := ctx.Value(priorityContextKey{}).(Priority)
+	return priority, ok
+}
+
+// withOutgoingPriority returns [ctx] telling the plugin the priority of the
+// stream of [r], if the client signaled one
+func withOutgoingPriority(ctx context.Context, r *http.Request) context.Context {
+	if r.ProtoMajor < 2 {
+		return ctx
+	}
+	values := r.Header.Values(priorityHeader)
+	if len(values) == 0 {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, priorityKey, parsePriority(values).String())
+}
+
+// withIncomingPriority returns [ctx] with the priority the node said the
+// request's stream has, if it said, so the handler can get it with
+// StreamPriority
+func withIncomingPriority(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md.Get(priorityKey)
+	if len(values) == 0 {
+		return ctx
+	}
+	return context.WithValue(ctx, priorityContextKey{}, parsePriority(values))
+}
@@ -0,0 +1,36 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+)
+
+const (
+	// RequestIDHeader is the header used to correlate a request across the
+	// node and the plugin
+	RequestIDHeader = "X-Request-ID"
+
+	// traceparentHeader is the W3C trace context header. A request that
+	// carries one is already traceable, so no request ID is generated for it.
+	traceparentHeader = "Traceparent"
+)
+
+// hasRequestID returns true if [header] already identifies the request
+func hasRequestID(header http.Header) bool {
+	return header.Get(RequestIDHeader) != "" || header.Get(traceparentHeader) != ""
+}
+
+// newRequestID returns a random (version 4) UUID
+func newRequestID() (string, error) {
+	b := [16]byte{}
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = (b[6] & 0x0f) | 0x40 // version 4
+	b[8] = (b[8] & 0x3f) | 0x80 // RFC 4122 variant
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
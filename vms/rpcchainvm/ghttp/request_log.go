@@ -0,0 +1,157 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/ava-labs/gecko/utils/logging"
+)
+
+// DefaultRequestLogMaxBodySize is the number of bytes of each body that are
+// logged at RequestLogFull if no bound is configured
+const DefaultRequestLogMaxBodySize = 4 * 1024
+
+// redactedHeaders are the headers whose values are left out of the log, as
+// they carry credentials
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// RequestLogLevel is how verbosely the requests to a plugin's handlers are
+// logged
+type RequestLogLevel uint32
+
+// The request log levels, from the least to the most verbose
+const (
+	// Requests aren't logged
+	RequestLogNone RequestLogLevel = iota
+	// The method, URL, status code and duration of requests are logged
+	RequestLogMetadata
+	// The request and response headers are logged too
+	RequestLogHeaders
+	// The start of the request and response bodies are logged too
+	RequestLogFull
+)
+
+func (l RequestLogLevel) String() string {
+	switch l {
+	case RequestLogNone:
+		return "none"
+	case RequestLogMetadata:
+		return "metadata"
+	case RequestLogHeaders:
+		return "headers"
+	case RequestLogFull:
+		return "full"
+	default:
+		return "unknown"
+	}
+}
+
+// ParseRequestLogLevel returns the request log level named [name]
+func ParseRequestLogLevel(name string) (RequestLogLevel, error) {
+	switch strings.ToLower(name) {
+	case "none":
+		return RequestLogNone, nil
+	case "metadata":
+		return RequestLogMetadata, nil
+	case "headers":
+		return RequestLogHeaders, nil
+	case "full":
+		return RequestLogFull, nil
+	default:
+		return 0, fmt.Errorf("unknown request log level %q, expected one of none, metadata, headers or full", name)
+	}
+}
+
+// RequestLogging is how verbosely the requests to a plugin's handlers are
+// logged. It's shared by the handlers of every chain the plugin runs, so that
+// the level can be changed for the plugin at once. Safe for concurrent use.
+type RequestLogging struct {
+	level       uint32 // accessed atomically
+	maxBodySize int
+}
+
+// NewRequestLogging returns request logging at [level], that logs up to
+// [maxBodySize] bytes of each body at RequestLogFull. If [maxBodySize] isn't
+// positive, DefaultRequestLogMaxBodySize is used.
+func NewRequestLogging(level RequestLogLevel, maxBodySize int) *RequestLogging {
+	if maxBodySize <= 0 {
+		maxBodySize = DefaultRequestLogMaxBodySize
+	}
+	return &RequestLogging{
+		level:       uint32(level),
+		maxBodySize: maxBodySize,
+	}
+}
+
+// Level returns how verbosely requests are logged. Requests aren't logged if
+// [l] is nil.
+func (l *RequestLogging) Level() RequestLogLevel {
+	if l == nil {
+		return RequestLogNone
+	}
+	return RequestLogLevel(atomic.LoadUint32(&l.level))
+}
+
+// SetLevel sets how verbosely requests are logged. Requests handled from then
+// on are logged at [level].
+func (l *RequestLogging) SetLevel(level RequestLogLevel) {
+	atomic.StoreUint32(&l.level, uint32(level))
+}
+
+// logRequest logs [capture], a request that was handled in [duration], at
+// [level]. Metadata is logged at the debug level, so that it's only seen while
+// debugging the node. Headers and bodies are logged at the info level, so that
+// they're seen once an operator raises the verbosity of a plugin.
+func logRequest(log logging.Logger, level RequestLogLevel, capture Capture, duration time.Duration) {
+	status := "no response"
+	if capture.StatusCode != 0 {
+		status = strconv.Itoa(capture.StatusCode)
+	}
+	metadata := fmt.Sprintf("%s %s: %s in %s", capture.Method, capture.URL, status, duration)
+
+	switch level {
+	case RequestLogMetadata:
+		log.Debug("%s", metadata)
+	case RequestLogHeaders:
+		log.Info("%s\nrequest header: %v\nresponse header: %v",
+			metadata,
+			redactHeader(capture.RequestHeader),
+			redactHeader(capture.ResponseHeader),
+		)
+	case RequestLogFull:
+		log.Info("%s\nrequest header: %v\nrequest body: %s\nresponse header: %v\nresponse body: %s",
+			metadata,
+			redactHeader(capture.RequestHeader),
+			formatLoggedBody(capture.RequestBody, capture.RequestBodyTruncated),
+			redactHeader(capture.ResponseHeader),
+			formatLoggedBody(capture.ResponseBody, capture.ResponseBodyTruncated),
+		)
+	}
+}
+
+// formatLoggedBody returns [body] quoted, so that binary bodies can't garble
+// the log, and marked if it was cut off
+func formatLoggedBody(body []byte, truncated bool) string {
+	if truncated {
+		return fmt.Sprintf("%q (truncated)", body)
+	}
+	return fmt.Sprintf("%q", body)
+}
+
+// redactHeader replaces the values of the headers in [header] that carry
+// credentials, and returns [header]. [header] must be a copy.
+func redactHeader(header http.Header) http.Header {
+	for _, key := range redactedHeaders {
+		if _, ok := header[key]; ok {
+			header[key] = []string{"[redacted]"}
+		}
+	}
+	return header
+}
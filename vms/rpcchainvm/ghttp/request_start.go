@@ -0,0 +1,66 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// requestStartKey is the metadata key the node sends the plugin, with a
+// request, the time the request was received at, in nanoseconds since the
+// Unix epoch
+const requestStartKey = "ghttp-request-start"
+
+// requestStartContextKey is the key the time a request was received at is
+// stored under in the request's context
+type requestStartContextKey struct{}
+
+// RequestStart returns the time the node received the request whose context
+// is [ctx], which a plugin's handler can compare to the deadline of [ctx] to
+// find out how much of the request's latency budget remains. Returns false if
+// the node wasn't configured to convey it, or predates conveying it.
+func RequestStart(ctx context.Context) (time.Time, bool) {
+	start, ok := ctx.Value(requestStartContextKey{}).(time.Time)
+	return start, ok
+}
+
+// withRequestStart returns [r] with [start] stored in its context, to be sent
+// to the plugin
+func withRequestStart(r *http.Request, start time.Time) *http.Request {
+	return r.WithContext(context.WithValue(r.Context(), requestStartContextKey{}, start))
+}
+
+// withOutgoingRequestStart returns [ctx] telling the plugin when the request
+// was received, if it's known
+func withOutgoingRequestStart(ctx context.Context, r *http.Request) context.Context {
+	start, ok := RequestStart(r.Context())
+	if !ok {
+		return ctx
+	}
+	return metadata.AppendToOutgoingContext(ctx, requestStartKey, strconv.FormatInt(start.UnixNano(), 10))
+}
+
+// withIncomingRequestStart returns [ctx] with the time the node said the
+// request was received at, if it said, so the handler can get it with
+// RequestStart
+func withIncomingRequestStart(ctx context.Context) context.Context {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return ctx
+	}
+	values := md.Get(requestStartKey)
+	if len(values) == 0 {
+		return ctx
+	}
+	nanos, err := strconv.ParseInt(values[0], 10, 64)
+	if err != nil {
+		return ctx
+	}
+	return context.WithValue(ctx, requestStartContextKey{}, time.Unix(0, nanos))
+}
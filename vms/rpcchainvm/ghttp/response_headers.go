@@ -0,0 +1,39 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// ParseResponseHeaders parses a JSON object mapping header names to values,
+// such as {"X-Content-Type-Options": "nosniff"}, into the headers added to
+// every response. An empty string is parsed into no headers.
+func ParseResponseHeaders(headers string) (http.Header, error) {
+	if headers == "" {
+		return nil, nil
+	}
+	values := map[string]string{}
+	if err := json.Unmarshal([]byte(headers), &values); err != nil {
+		return nil, fmt.Errorf("couldn't parse response headers %q: %w", headers, err)
+	}
+	header := make(http.Header, len(values))
+	for key, value := range values {
+		header.Set(key, value)
+	}
+	return header, nil
+}
+
+// addResponseHeaders adds each header in [headers] that isn't already in
+// [header]. The headers are added before the plugin's handler is called, so
+// the handler's own values replace them.
+func addResponseHeaders(header, headers http.Header) {
+	for key, values := range headers {
+		if _, ok := header[key]; !ok {
+			header[key] = append([]string(nil), values...)
+		}
+	}
+}
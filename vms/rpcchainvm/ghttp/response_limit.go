@@ -0,0 +1,77 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// DefaultMaxResponseBodySize is the number of bytes of response body a plugin's
+// handler may write if no limit is configured
+const DefaultMaxResponseBodySize = 1 << 30 // 1 GiB
+
+var errResponseTooLarge = errors.New("response body is too large")
+
+// limitedResponseWriter fails writes once the response body would exceed
+// [remaining] bytes. Failing the writes, rather than silently dropping them,
+// tells the plugin's handler to stop writing. If [truncate] is set, the part
+// of the write that fits is written first.
+type limitedResponseWriter struct {
+	http.ResponseWriter
+	remaining int64
+	truncate  bool
+
+	// true once a write exceeded the limit
+	exceeded bool
+}
+
+// maxResponseBodySize returns the configured limit on response bodies
+func (c *Config) maxResponseBodySize() int64 {
+	if c.MaxResponseBodySize <= 0 {
+		return DefaultMaxResponseBodySize
+	}
+	return c.MaxResponseBodySize
+}
+
+// Write ...
+func (w *limitedResponseWriter) Write(payload []byte) (int, error) {
+	if w.exceeded {
+		return 0, errResponseTooLarge
+	}
+	if int64(len(payload)) <= w.remaining {
+		n, err := w.ResponseWriter.Write(payload)
+		w.remaining -= int64(n)
+		return n, err
+	}
+
+	w.exceeded = true
+	if !w.truncate {
+		return 0, errResponseTooLarge
+	}
+	n, err := w.ResponseWriter.Write(payload[:w.remaining])
+	w.remaining -= int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, errResponseTooLarge
+}
+
+// Flush ...
+func (w *limitedResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack ...
+func (w *limitedResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	return hijacker.Hijack()
+}
@@ -0,0 +1,185 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultRetryBackoff is how long is waited before the first retry of a request
+// if no backoff is configured
+const DefaultRetryBackoff = 50 * time.Millisecond
+
+// RetryConfig configures the retrying of requests the plugin's handler failed
+// to handle because it was briefly unavailable, such as during a garbage
+// collection pause
+type RetryConfig struct {
+	// MaxRetries is the number of times a GET or HEAD request is retried if
+	// the plugin's handler replies with a 503, or the call to the plugin fails
+	// because the plugin is unavailable. Only requests without a body are
+	// retried, as other requests may not be safe to repeat, and their body
+	// can't be sent again. The client only sees the last attempt's response.
+	// If not positive, requests aren't retried.
+	MaxRetries int
+
+	// Backoff is how long is waited before the first retry. The wait doubles
+	// for every further retry. A retry that couldn't start before the
+	// request's deadline isn't made. If not positive, DefaultRetryBackoff is
+	// used.
+	Backoff time.Duration
+}
+
+func (c RetryConfig) backoff() time.Duration {
+	if c.Backoff <= 0 {
+		return DefaultRetryBackoff
+	}
+	return c.Backoff
+}
+
+// retryable returns true if [r] is safe to send to the plugin again
+func retryable(r *http.Request) bool {
+	return (r.Method == http.MethodGet || r.Method == http.MethodHead) && r.ContentLength == 0
+}
+
+// startsBefore returns true if a retry made after [backoff] would start before
+// the deadline of [ctx], if it has one
+func startsBefore(ctx context.Context, backoff time.Duration) bool {
+	deadline, ok := ctx.Deadline()
+	return !ok || time.Until(deadline) > backoff
+}
+
+// callWithRetries passes the request to the plugin, retrying it while the
+// plugin is unavailable if retries are enabled and the request is safe to
+// retry
+func (c *Client) callWithRetries(ctx context.Context, w http.ResponseWriter, r *http.Request) error {
+	if c.config.Retry.MaxRetries <= 0 || !retryable(r) {
+		return c.call(ctx, w, r)
+	}
+
+	backoff := c.config.Retry.backoff()
+	for retries := 0; ; retries++ {
+		canRetry := func() bool {
+			return retries < c.config.Retry.MaxRetries && ctx.Err() == nil && startsBefore(ctx, backoff)
+		}
+		attempt := newRetryResponseWriter(w, canRetry)
+		err := c.call(ctx, attempt, r)
+
+		switch {
+		case attempt.unavailable:
+			c.log.Debug("retrying %s %s in %s as the handler replied that it's unavailable", r.Method, r.URL, backoff)
+		case err != nil && status.Code(err) == codes.Unavailable && !attempt.committed && canRetry():
+			c.log.Debug("retrying %s %s in %s as the plugin was unavailable: %s", r.Method, r.URL, backoff, err)
+		default:
+			return err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// retryResponseWriter holds the response to an attempt at a request back
+// until it's known whether the request will be retried. A 503 is dropped, if
+// the request can be retried, so that the client only sees the response to the
+// retry. Any other response is written as it arrives.
+type retryResponseWriter struct {
+	http.ResponseWriter
+	canRetry func() bool
+
+	// header of the response until it's committed to
+	header http.Header
+
+	// true once the response is being written to the client
+	committed bool
+	// true if the handler replied with a 503 that was dropped
+	unavailable bool
+}
+
+func newRetryResponseWriter(w http.ResponseWriter, canRetry func() bool) *retryResponseWriter {
+	return &retryResponseWriter{
+		ResponseWriter: w,
+		canRetry:       canRetry,
+		header:         w.Header().Clone(),
+	}
+}
+
+// Header returns a header that's only copied to the response once the
+// response is committed to, so that a dropped attempt leaves no trace
+func (w *retryResponseWriter) Header() http.Header {
+	if w.committed {
+		return w.ResponseWriter.Header()
+	}
+	return w.header
+}
+
+// commit to writing this attempt's response to the client
+func (w *retryResponseWriter) commit() {
+	if w.committed {
+		return
+	}
+	w.committed = true
+	header := w.ResponseWriter.Header()
+	for key := range header {
+		delete(header, key)
+	}
+	for key, values := range w.header {
+		header[key] = values
+	}
+}
+
+// WriteHeader ...
+func (w *retryResponseWriter) WriteHeader(statusCode int) {
+	if w.unavailable {
+		return
+	}
+	if !w.committed && statusCode == http.StatusServiceUnavailable && w.canRetry() {
+		w.unavailable = true
+		return
+	}
+	w.commit()
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write ...
+func (w *retryResponseWriter) Write(payload []byte) (int, error) {
+	if w.unavailable {
+		return len(payload), nil
+	}
+	w.commit()
+	return w.ResponseWriter.Write(payload)
+}
+
+// Flush ...
+func (w *retryResponseWriter) Flush() {
+	if w.unavailable {
+		return
+	}
+	w.commit()
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Hijack ...
+func (w *retryResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	w.commit()
+	return hijacker.Hijack()
+}
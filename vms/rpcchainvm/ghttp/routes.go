@@ -0,0 +1,55 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"context"
+	"net/http"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/ghttpproto"
+)
+
+// RouteLister can be implemented by a plugin's handler to report the patterns
+// of the routes it serves, which the node can ask for to help operators find
+// the plugin's endpoints
+type RouteLister interface {
+	Routes() []string
+}
+
+// Routes returns the patterns of the routes the plugin's handler serves.
+// Returns false if the handler can't list its routes, including if the plugin
+// predates route listing.
+func (c *Client) Routes(ctx context.Context) ([]string, bool, error) {
+	resp, err := c.client.Routes(ctx, &ghttpproto.RoutesRequest{})
+	if status.Code(err) == codes.Unimplemented {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return resp.Patterns, resp.Introspectable, nil
+}
+
+// Routes implements the ghttpproto.HTTPServer interface
+func (s *Server) Routes(context.Context, *ghttpproto.RoutesRequest) (*ghttpproto.RoutesResponse, error) {
+	patterns, ok := handlerRoutes(s.handler)
+	return &ghttpproto.RoutesResponse{
+		Introspectable: ok,
+		Patterns:       patterns,
+	}, nil
+}
+
+// handlerRoutes returns the patterns of the routes [handler] serves, if it's a
+// RouteLister. A ServeMux can't list its routes, as it doesn't expose its
+// patterns, so a plugin serving one must wrap it in a RouteLister.
+func handlerRoutes(handler http.Handler) ([]string, bool) {
+	lister, ok := handler.(RouteLister)
+	if !ok {
+		return nil, false
+	}
+	return lister.Routes(), true
+}
@@ -0,0 +1,48 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"context"
+	"strconv"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// DefaultStreamChunkSize is the size, in bytes, of the chunks responses are
+// streamed in when streaming is enabled
+const DefaultStreamChunkSize = 32 * 1024
+
+// streamChunkSizeKey is the metadata key the node sends the plugin, with a
+// request, the size of the chunks the plugin should stream the response in.
+// Plugins that don't know the key ignore it and send every write to the node
+// as it's made.
+const streamChunkSizeKey = "ghttp-stream-chunk-size"
+
+// withStreamChunkSize returns [ctx] asking the plugin to stream the response
+// in chunks of [size] bytes
+func withStreamChunkSize(ctx context.Context, size int) context.Context {
+	if size <= 0 {
+		size = DefaultStreamChunkSize
+	}
+	return metadata.AppendToOutgoingContext(ctx, streamChunkSizeKey, strconv.Itoa(size))
+}
+
+// streamChunkSize returns the size of the chunks the node asked the response
+// to be streamed in in [ctx], or 0 if it didn't ask for streaming
+func streamChunkSize(ctx context.Context) int {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return 0
+	}
+	values := md.Get(streamChunkSizeKey)
+	if len(values) == 0 {
+		return 0
+	}
+	size, err := strconv.Atoi(values[0])
+	if err != nil || size <= 0 {
+		return 0
+	}
+	return size
+}
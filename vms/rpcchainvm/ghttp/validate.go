@@ -0,0 +1,34 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp/ghttpproto"
+)
+
+// validateRequest returns an error describing why [req] can't be passed to the
+// plugin's handler, or nil if it can be
+func validateRequest(req *ghttpproto.Request) error {
+	// -1 means the length of the body is unknown
+	if req.ContentLength < -1 {
+		return fmt.Errorf("invalid content length %d", req.ContentLength)
+	}
+	if req.RequestURI != "" {
+		if _, err := url.Parse(req.RequestURI); err != nil {
+			return fmt.Errorf("invalid request URI %q", req.RequestURI)
+		}
+	}
+	if req.Url != nil && req.Url.RawPath != "" {
+		if _, err := url.PathUnescape(req.Url.RawPath); err != nil {
+			return fmt.Errorf("invalid request path %q", req.Url.RawPath)
+		}
+	}
+	if req.ProtoMajor < 0 || req.ProtoMinor < 0 {
+		return fmt.Errorf("invalid protocol version %d.%d", req.ProtoMajor, req.ProtoMinor)
+	}
+	return nil
+}
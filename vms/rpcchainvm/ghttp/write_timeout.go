@@ -0,0 +1,142 @@
+// (c) 2019-2020, Ava Labs, Inc. All rights reserved.
+// See the file LICENSE for licensing terms.
+
+package ghttp
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+var errWriteTimeout = errors.New("timed out writing the response")
+
+// timeoutResponseWriter fails a write to the response, and cancels the
+// request's context, if the write doesn't complete within [timeout]. This
+// keeps a client that reads the response slowly from pinning the plugin's
+// handler.
+//
+// A write that timed out can't be interrupted, so it's left running. Once it
+// has timed out, the response is no longer written to, so that the stalled
+// write is the only user of the response.
+type timeoutResponseWriter struct {
+	http.ResponseWriter
+	timeout time.Duration
+	cancel  context.CancelFunc
+
+	lock     sync.Mutex
+	timedOut bool
+	// closed once the write that timed out returns
+	stalled chan struct{}
+}
+
+// newTimeoutResponseWriter returns a writer that cancels the returned context
+// if writing to [w] takes longer than [timeout]
+func newTimeoutResponseWriter(ctx context.Context, w http.ResponseWriter, timeout time.Duration) (context.Context, *timeoutResponseWriter) {
+	ctx, cancel := context.WithCancel(ctx)
+	return ctx, &timeoutResponseWriter{
+		ResponseWriter: w,
+		timeout:        timeout,
+		cancel:         cancel,
+	}
+}
+
+// do runs [f] with the timeout. Returns false if [f] wasn't run, or didn't
+// finish running, before the timeout.
+func (w *timeoutResponseWriter) do(f func()) bool {
+	w.lock.Lock()
+	timedOut := w.timedOut
+	w.lock.Unlock()
+	if timedOut {
+		return false
+	}
+
+	done := make(chan struct{})
+	go func() {
+		f()
+		close(done)
+	}()
+
+	timer := time.NewTimer(w.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-done:
+		return true
+	case <-timer.C:
+		w.lock.Lock()
+		w.timedOut = true
+		w.stalled = done
+		w.lock.Unlock()
+
+		w.cancel()
+		return false
+	}
+}
+
+// Header returns a detached header once the write timed out, so that it
+// isn't modified while the stalled write may be reading it
+func (w *timeoutResponseWriter) Header() http.Header {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if w.timedOut {
+		return http.Header{}
+	}
+	return w.ResponseWriter.Header()
+}
+
+// WriteHeader ...
+func (w *timeoutResponseWriter) WriteHeader(statusCode int) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if !w.timedOut {
+		w.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+// Write ...
+func (w *timeoutResponseWriter) Write(payload []byte) (int, error) {
+	var (
+		n   int
+		err error
+	)
+	if !w.do(func() { n, err = w.ResponseWriter.Write(payload) }) {
+		return 0, errWriteTimeout
+	}
+	return n, err
+}
+
+// Flush ...
+func (w *timeoutResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.do(flusher.Flush)
+	}
+}
+
+// Hijack ...
+func (w *timeoutResponseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	hijacker, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackUnsupported
+	}
+	return hijacker.Hijack()
+}
+
+// wait for the write that timed out, if any, to return. Returns true if a
+// write timed out.
+func (w *timeoutResponseWriter) wait() bool {
+	w.lock.Lock()
+	timedOut, stalled := w.timedOut, w.stalled
+	w.lock.Unlock()
+
+	if timedOut {
+		<-stalled
+	}
+	return timedOut
+}
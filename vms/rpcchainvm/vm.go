@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/go-plugin"
 
 	"github.com/ava-labs/gecko/snow/engine/snowman"
+	"github.com/ava-labs/gecko/vms/rpcchainvm/ghttp"
 	"github.com/ava-labs/gecko/vms/rpcchainvm/vmproto"
 )
 
@@ -33,14 +34,29 @@ type Plugin struct {
 	// Concrete implementation, written in Go. This is only used for plugins
 	// that are written in Go.
 	vm snowman.ChainVM
+
+	// Configures the pings of the servers that the VM's HTTP handlers are
+	// served by
+	httpKeepalive ghttp.KeepaliveConfig
 }
 
 // New ...
 func New(vm snowman.ChainVM) *Plugin { return &Plugin{vm: vm} }
 
+// NewWithHTTPKeepalive returns a plugin whose HTTP handlers are served with
+// the keepalive options [config], rather than the defaults
+func NewWithHTTPKeepalive(vm snowman.ChainVM, config ghttp.KeepaliveConfig) *Plugin {
+	return &Plugin{
+		vm:            vm,
+		httpKeepalive: config,
+	}
+}
+
 // GRPCServer ...
 func (p *Plugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
-	vmproto.RegisterVMServer(s, NewServer(p.vm, broker))
+	server := NewServer(p.vm, broker)
+	server.httpKeepalive = p.httpKeepalive
+	vmproto.RegisterVMServer(s, server)
 	return nil
 }
 
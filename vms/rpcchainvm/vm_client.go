@@ -6,6 +6,8 @@ package rpcchainvm
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
 
 	"google.golang.org/grpc"
@@ -29,7 +31,8 @@ import (
 )
 
 var (
-	errUnsupportedFXs = errors.New("unsupported feature extensions")
+	errUnsupportedFXs              = errors.New("unsupported feature extensions")
+	errRequestLoggingNotConfigured = errors.New("request logging wasn't configured for the plugin")
 )
 
 // VMClient is an implementation of VM that talks over RPC.
@@ -38,6 +41,8 @@ type VMClient struct {
 	broker *plugin.GRPCBroker
 	proc   *plugin.Client
 
+	httpConfig ghttp.Config
+
 	db        *rpcdb.DatabaseServer
 	messenger *messenger.Server
 
@@ -46,6 +51,12 @@ type VMClient struct {
 	servers []*grpc.Server
 	conns   []*grpc.ClientConn
 
+	// handlers are the clients of the plugin's HTTP handlers, keyed by the
+	// extension of the chain's endpoint they serve
+	handlers map[string]*ghttp.Client
+	// true if the plugin's HTTP handlers are in maintenance
+	maintenance bool
+
 	ctx  *snow.Context
 	blks map[[32]byte]*BlockClient
 }
@@ -64,6 +75,149 @@ func (vm *VMClient) SetProcess(proc *plugin.Client) {
 	vm.proc = proc
 }
 
+// PluginStatus describes the plugin process a VMClient is talking to
+type PluginStatus struct {
+	// ID of the plugin process, or 0 if it isn't known
+	PID int
+
+	// True iff the plugin process hasn't exited
+	Alive bool
+}
+
+// PluginStatus returns the status of the plugin process
+func (vm *VMClient) PluginStatus() PluginStatus {
+	status := PluginStatus{}
+	if vm.proc == nil {
+		return status
+	}
+	status.Alive = !vm.proc.Exited()
+	if reattach := vm.proc.ReattachConfig(); reattach != nil {
+		status.PID = reattach.Pid
+	}
+	return status
+}
+
+// HandlerRoutes describes the routes served by one of the plugin's HTTP
+// handlers
+type HandlerRoutes struct {
+	// Extension of the chain's endpoint the handler serves
+	Prefix string
+
+	// False if the handler can't list its routes
+	Introspectable bool
+
+	// Patterns of the routes the handler serves
+	Patterns []string
+}
+
+// Routes returns the routes served by each of the plugin's HTTP handlers,
+// sorted by prefix
+func (vm *VMClient) Routes(ctx context.Context) ([]HandlerRoutes, error) {
+	vm.lock.Lock()
+	handlers := make(map[string]*ghttp.Client, len(vm.handlers))
+	for prefix, handler := range vm.handlers {
+		handlers[prefix] = handler
+	}
+	vm.lock.Unlock()
+
+	routes := make([]HandlerRoutes, 0, len(handlers))
+	for prefix, handler := range handlers {
+		patterns, introspectable, err := handler.Routes(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't get the routes of handler %q: %w", prefix, err)
+		}
+		routes = append(routes, HandlerRoutes{
+			Prefix:         prefix,
+			Introspectable: introspectable,
+			Patterns:       patterns,
+		})
+	}
+	sort.Slice(routes, func(i, j int) bool { return routes[i].Prefix < routes[j].Prefix })
+	return routes, nil
+}
+
+// SetMaintenance sets whether the plugin's HTTP handlers are in maintenance.
+// While they are, requests to them are replied to with a 503 without calling
+// the plugin. The chain keeps running.
+func (vm *VMClient) SetMaintenance(enabled bool) {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+
+	vm.maintenance = enabled
+	for _, handler := range vm.handlers {
+		handler.SetMaintenance(enabled)
+	}
+}
+
+// HandlerCaptures are the requests one of the plugin's HTTP handlers captured
+// and their responses
+type HandlerCaptures struct {
+	// Extension of the chain's endpoint the handler serves
+	Prefix string
+
+	// Oldest first
+	Captures []ghttp.Capture
+}
+
+// HTTPCaptures returns the requests each of the plugin's HTTP handlers
+// captured and their responses, sorted by prefix
+func (vm *VMClient) HTTPCaptures() []HandlerCaptures {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+
+	captures := make([]HandlerCaptures, 0, len(vm.handlers))
+	for prefix, handler := range vm.handlers {
+		captures = append(captures, HandlerCaptures{
+			Prefix:   prefix,
+			Captures: handler.Captures(),
+		})
+	}
+	sort.Slice(captures, func(i, j int) bool { return captures[i].Prefix < captures[j].Prefix })
+	return captures
+}
+
+// ClearHTTPCaptures drops the requests the plugin's HTTP handlers captured and
+// their responses
+func (vm *VMClient) ClearHTTPCaptures() {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+
+	for _, handler := range vm.handlers {
+		handler.ClearCaptures()
+	}
+}
+
+// HTTPRequestLogLevel returns how verbosely the requests to the plugin's HTTP
+// handlers are logged
+func (vm *VMClient) HTTPRequestLogLevel() ghttp.RequestLogLevel {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+
+	return vm.httpConfig.RequestLogging.Level()
+}
+
+// SetHTTPRequestLogLevel sets how verbosely the requests to the plugin's HTTP
+// handlers are logged. The level is shared with every chain run by the same
+// plugin, so it's changed for all of them.
+func (vm *VMClient) SetHTTPRequestLogLevel(level ghttp.RequestLogLevel) error {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+
+	if vm.httpConfig.RequestLogging == nil {
+		return errRequestLoggingNotConfigured
+	}
+	vm.httpConfig.RequestLogging.SetLevel(level)
+	return nil
+}
+
+// SetHTTPConfig sets the options used to serve the plugin's HTTP handlers
+func (vm *VMClient) SetHTTPConfig(config ghttp.Config) {
+	vm.lock.Lock()
+	defer vm.lock.Unlock()
+
+	vm.httpConfig = config
+}
+
 // Initialize ...
 func (vm *VMClient) Initialize(
 	ctx *snow.Context,
@@ -178,18 +332,35 @@ func (vm *VMClient) CreateHandlers() map[string]*common.HTTPHandler {
 		return nil
 	}
 
-	resp, err := vm.client.CreateHandlers(context.Background(), &vmproto.CreateHandlersRequest{})
+	connections := uint32(1)
+	if vm.httpConfig.Connections > 1 {
+		connections = uint32(vm.httpConfig.Connections)
+	}
+	resp, err := vm.client.CreateHandlers(context.Background(), &vmproto.CreateHandlersRequest{
+		Connections: connections,
+	})
 	vm.ctx.Log.AssertNoError(err)
 
 	handlers := make(map[string]*common.HTTPHandler, len(resp.Handlers))
+	vm.handlers = make(map[string]*ghttp.Client, len(resp.Handlers))
 	for _, handler := range resp.Handlers {
-		conn, err := vm.broker.Dial(handler.Server)
-		vm.ctx.Log.AssertNoError(err)
-
-		vm.conns = append(vm.conns, conn)
+		// Plugins that don't support dedicated connections don't return any
+		// extra servers, so their requests share one connection
+		servers := append([]uint32{handler.Server}, handler.ExtraServers...)
+		pool := make([]ghttpproto.HTTPClient, len(servers))
+		for i, server := range servers {
+			conn, err := vm.broker.Dial(server)
+			vm.ctx.Log.AssertNoError(err)
+
+			vm.conns = append(vm.conns, conn)
+			pool[i] = ghttpproto.NewHTTPClient(conn)
+		}
+		client := ghttp.NewClient(ghttp.NewPool(pool), vm.broker, vm.ctx.Log, vm.httpConfig)
+		client.SetMaintenance(vm.maintenance)
+		vm.handlers[handler.Prefix] = client
 		handlers[handler.Prefix] = &common.HTTPHandler{
 			LockOptions: common.LockOption(handler.LockOptions),
-			Handler:     ghttp.NewClient(ghttpproto.NewHTTPClient(conn), vm.broker),
+			Handler:     client,
 		}
 	}
 	return handlers
@@ -5,6 +5,7 @@ package rpcchainvm
 
 import (
 	"context"
+	"net/http"
 	"sync"
 
 	"google.golang.org/grpc"
@@ -30,6 +31,10 @@ type VMServer struct {
 	vm     snowman.ChainVM
 	broker *plugin.GRPCBroker
 
+	// Configures the pings of the servers that the VM's HTTP handlers are
+	// served by
+	httpKeepalive ghttp.KeepaliveConfig
+
 	lock    sync.Mutex
 	closed  bool
 	servers []*grpc.Server
@@ -119,36 +124,44 @@ func (vm *VMServer) Shutdown(context.Context, *vmproto.ShutdownRequest) (*vmprot
 func (vm *VMServer) CreateHandlers(_ context.Context, req *vmproto.CreateHandlersRequest) (*vmproto.CreateHandlersResponse, error) {
 	handlers := vm.vm.CreateHandlers()
 	resp := &vmproto.CreateHandlersResponse{}
-	for prefix, h := range handlers {
-		handler := h
-
-		// start the messenger server
-		serverID := vm.broker.NextId()
-		go vm.broker.AcceptAndServe(serverID, func(opts []grpc.ServerOption) *grpc.Server {
-			vm.lock.Lock()
-			defer vm.lock.Unlock()
-
-			server := grpc.NewServer(opts...)
-
-			if vm.closed {
-				server.Stop()
-			} else {
-				vm.servers = append(vm.servers, server)
-			}
-
-			ghttpproto.RegisterHTTPServer(server, ghttp.NewServer(handler.Handler, vm.broker))
-			return server
-		})
-
-		resp.Handlers = append(resp.Handlers, &vmproto.Handler{
+	for prefix, handler := range handlers {
+		h := &vmproto.Handler{
 			Prefix:      prefix,
 			LockOptions: uint32(handler.LockOptions),
-			Server:      serverID,
-		})
+			Server:      vm.serveHandler(handler.Handler),
+		}
+		// The node asked for dedicated connections to each handler. Every
+		// connection needs its own server, as the node can only dial each
+		// server once.
+		for i := uint32(1); i < req.Connections; i++ {
+			h.ExtraServers = append(h.ExtraServers, vm.serveHandler(handler.Handler))
+		}
+		resp.Handlers = append(resp.Handlers, h)
 	}
 	return resp, nil
 }
 
+// serveHandler starts a server for [handler] and returns the server's ID
+func (vm *VMServer) serveHandler(handler http.Handler) uint32 {
+	serverID := vm.broker.NextId()
+	go vm.broker.AcceptAndServe(serverID, func(opts []grpc.ServerOption) *grpc.Server {
+		vm.lock.Lock()
+		defer vm.lock.Unlock()
+
+		server := grpc.NewServer(append(opts, vm.httpKeepalive.ServerOptions()...)...)
+
+		if vm.closed {
+			server.Stop()
+		} else {
+			vm.servers = append(vm.servers, server)
+		}
+
+		ghttpproto.RegisterHTTPServer(server, ghttp.NewServer(handler, vm.broker))
+		return server
+	})
+	return serverID
+}
+
 // BuildBlock ...
 func (vm *VMServer) BuildBlock(_ context.Context, _ *vmproto.BuildBlockRequest) (*vmproto.BuildBlockResponse, error) {
 	blk, err := vm.vm.BuildBlock()
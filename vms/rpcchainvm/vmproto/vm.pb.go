@@ -297,6 +297,7 @@ func (m *ShutdownResponse) XXX_DiscardUnknown() {
 var xxx_messageInfo_ShutdownResponse proto.InternalMessageInfo
 
 type CreateHandlersRequest struct {
+	Connections          uint32   `protobuf:"varint,1,opt,name=connections,proto3" json:"connections,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -327,6 +328,13 @@ func (m *CreateHandlersRequest) XXX_DiscardUnknown() {
 
 var xxx_messageInfo_CreateHandlersRequest proto.InternalMessageInfo
 
+func (m *CreateHandlersRequest) GetConnections() uint32 {
+	if m != nil {
+		return m.Connections
+	}
+	return 0
+}
+
 type CreateHandlersResponse struct {
 	Handlers             []*Handler `protobuf:"bytes,1,rep,name=handlers,proto3" json:"handlers,omitempty"`
 	XXX_NoUnkeyedLiteral struct{}   `json:"-"`
@@ -370,6 +378,7 @@ type Handler struct {
 	Prefix               string   `protobuf:"bytes,1,opt,name=prefix,proto3" json:"prefix,omitempty"`
 	LockOptions          uint32   `protobuf:"varint,2,opt,name=lockOptions,proto3" json:"lockOptions,omitempty"`
 	Server               uint32   `protobuf:"varint,3,opt,name=server,proto3" json:"server,omitempty"`
+	ExtraServers         []uint32 `protobuf:"varint,4,rep,packed,name=extraServers,proto3" json:"extraServers,omitempty"`
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
 	XXX_sizecache        int32    `json:"-"`
@@ -421,6 +430,13 @@ func (m *Handler) GetServer() uint32 {
 	return 0
 }
 
+func (m *Handler) GetExtraServers() []uint32 {
+	if m != nil {
+		return m.ExtraServers
+	}
+	return nil
+}
+
 type BuildBlockRequest struct {
 	XXX_NoUnkeyedLiteral struct{} `json:"-"`
 	XXX_unrecognized     []byte   `json:"-"`
@@ -1078,49 +1094,51 @@ func init() {
 func init() { proto.RegisterFile("vm.proto", fileDescriptor_cab246c8c7c5372d) }
 
 var fileDescriptor_cab246c8c7c5372d = []byte{
-	// 672 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x61, 0x4f, 0x13, 0x41,
-	0x10, 0x4d, 0x4b, 0x84, 0x3a, 0xb4, 0x40, 0x97, 0x16, 0xea, 0x09, 0x5a, 0x2f, 0x86, 0x60, 0x62,
-	0xf8, 0x80, 0x3f, 0x80, 0x58, 0x45, 0x21, 0x2a, 0xe2, 0x91, 0x10, 0x13, 0xfd, 0x72, 0xf4, 0x06,
-	0x38, 0x2d, 0x77, 0xe7, 0xee, 0xb6, 0x88, 0x3f, 0xd0, 0xdf, 0x65, 0xee, 0x6e, 0xef, 0x6e, 0x76,
-	0xbb, 0x17, 0x12, 0xbf, 0x75, 0x67, 0xde, 0xbc, 0x99, 0x9b, 0xe9, 0x7b, 0xd0, 0x9a, 0xdd, 0xec,
-	0x25, 0x3c, 0x96, 0x31, 0x5b, 0x9a, 0xdd, 0x64, 0x3f, 0xdc, 0x5b, 0xe8, 0x1e, 0x47, 0xa1, 0x0c,
-	0xfd, 0x49, 0xf8, 0x07, 0x3d, 0xfc, 0x35, 0x45, 0x21, 0x99, 0x03, 0xad, 0xe0, 0xe2, 0x0c, 0xf9,
-	0x0c, 0xf9, 0xa0, 0x31, 0x6c, 0xec, 0x76, 0xbc, 0xf2, 0xcd, 0x5c, 0x68, 0x5f, 0x61, 0x84, 0x22,
-	0x14, 0xa3, 0x3b, 0x89, 0x62, 0xd0, 0x1c, 0x36, 0x76, 0xdb, 0x9e, 0x16, 0x4b, 0x31, 0x18, 0x5d,
-	0x85, 0x11, 0x2a, 0x8e, 0x85, 0x8c, 0x43, 0x8b, 0xb9, 0x3d, 0x60, 0xb4, 0xb1, 0x48, 0xe2, 0x48,
-	0xa0, 0xbb, 0x01, 0xbd, 0x51, 0x1c, 0x4b, 0x21, 0xb9, 0x9f, 0x24, 0x61, 0x74, 0xa5, 0x26, 0x72,
-	0x37, 0xa1, 0x6f, 0xc4, 0x55, 0x41, 0x1f, 0xd6, 0xab, 0x04, 0x06, 0x05, 0x5e, 0xe3, 0x49, 0xc3,
-	0x0a, 0xde, 0x85, 0xd5, 0xb3, 0xeb, 0xa9, 0x0c, 0xe2, 0xdb, 0xa8, 0x80, 0x32, 0x58, 0xab, 0x42,
-	0x0a, 0xb6, 0x09, 0xfd, 0x37, 0x1c, 0x7d, 0x89, 0x47, 0x7e, 0x14, 0x4c, 0x90, 0x8b, 0x02, 0xfc,
-	0x0e, 0x36, 0xcc, 0x44, 0x5e, 0xc2, 0x5e, 0x42, 0xeb, 0x5a, 0xc5, 0x06, 0x8d, 0xe1, 0xc2, 0xee,
-	0xf2, 0xfe, 0xda, 0x9e, 0x5a, 0xf2, 0x9e, 0x02, 0x7b, 0x25, 0xc2, 0xfd, 0x06, 0x4b, 0x2a, 0xc8,
-	0x36, 0x60, 0x31, 0xe1, 0x78, 0x19, 0xfe, 0xce, 0x56, 0xfd, 0xd0, 0x53, 0x2f, 0x36, 0x84, 0xe5,
-	0x49, 0x3c, 0xfe, 0xf9, 0x39, 0x91, 0x61, 0x1c, 0xe5, 0x7b, 0xee, 0x78, 0x34, 0x94, 0x56, 0x0a,
-	0xba, 0x60, 0xf5, 0x72, 0xd7, 0xa1, 0x3b, 0x9a, 0x86, 0x93, 0x60, 0x94, 0x82, 0x8b, 0xc9, 0xcf,
-	0x81, 0xd1, 0xa0, 0x9a, 0x7a, 0x05, 0x9a, 0x61, 0x90, 0x35, 0x6e, 0x7b, 0xcd, 0x30, 0x48, 0x2f,
-	0x9f, 0xf8, 0x1c, 0x23, 0x79, 0xfc, 0x56, 0x5d, 0xb6, 0x7c, 0xb3, 0x1e, 0x3c, 0xb8, 0xc8, 0x4e,
-	0xbe, 0x90, 0x25, 0xf2, 0x87, 0xfb, 0x02, 0xba, 0xa7, 0x3e, 0x17, 0x48, 0x9b, 0x55, 0xd0, 0x06,
-	0x85, 0x7e, 0x05, 0x46, 0xa1, 0xff, 0x31, 0x42, 0xfa, 0xc5, 0xd2, 0x97, 0x53, 0x51, 0x7e, 0x71,
-	0xf6, 0x72, 0x9f, 0xc1, 0xea, 0x7b, 0x94, 0xda, 0x08, 0x06, 0xad, 0xfb, 0x1d, 0xd6, 0x2a, 0x88,
-	0x6a, 0x4d, 0x5b, 0x35, 0xea, 0xbe, 0xb6, 0x49, 0x3e, 0xa1, 0x76, 0x80, 0x1d, 0xe8, 0x9d, 0xa1,
-	0x3c, 0xe5, 0x78, 0x89, 0x1c, 0xa3, 0x31, 0xd6, 0x4d, 0xb1, 0x09, 0x7d, 0x03, 0x57, 0xfd, 0x8f,
-	0x3f, 0xfa, 0x42, 0xbe, 0x1e, 0x8f, 0x31, 0x91, 0xd5, 0xff, 0x78, 0x07, 0x7a, 0x7a, 0xd8, 0xbe,
-	0x34, 0xf7, 0x39, 0xb0, 0xec, 0xd3, 0xce, 0x91, 0x87, 0x97, 0x77, 0x75, 0xdd, 0x53, 0xb1, 0x50,
-	0x94, 0xea, 0x5d, 0x14, 0xe7, 0x5d, 0xee, 0x2b, 0x2e, 0x50, 0x46, 0xb1, 0x87, 0x3f, 0x70, 0x7c,
-	0x6f, 0x71, 0x81, 0xca, 0x8b, 0xf7, 0xff, 0x2e, 0x41, 0xf3, 0xfc, 0x13, 0x3b, 0x04, 0xa8, 0xbc,
-	0x80, 0x39, 0xa5, 0x6e, 0xe6, 0x9c, 0xc9, 0x79, 0x6c, 0xcd, 0xa9, 0xa5, 0x9c, 0x40, 0x47, 0x33,
-	0x09, 0xb6, 0x5d, 0xa2, 0x6d, 0xa6, 0xe2, 0x3c, 0xa9, 0x4b, 0x2b, 0xbe, 0x0f, 0xd0, 0xa6, 0x26,
-	0xc2, 0xb6, 0x2c, 0xf8, 0xf2, 0x54, 0xce, 0x76, 0x4d, 0x56, 0x91, 0x1d, 0x40, 0xab, 0xb0, 0x19,
-	0x36, 0x28, 0xa1, 0x86, 0x19, 0x39, 0x8f, 0x2c, 0x19, 0x45, 0xf0, 0x05, 0x56, 0x74, 0xeb, 0x61,
-	0xd5, 0xfc, 0x56, 0xb3, 0x72, 0x9e, 0xd6, 0xe6, 0x15, 0xe5, 0x21, 0x40, 0xe5, 0x09, 0x64, 0xef,
-	0x73, 0xee, 0x41, 0xf6, 0x6e, 0x31, 0x91, 0x43, 0x80, 0x4a, 0xd7, 0x84, 0x66, 0xce, 0x17, 0x08,
-	0x8d, 0xc5, 0x08, 0x0e, 0xa0, 0x55, 0x28, 0x94, 0x6c, 0xc8, 0xd0, 0x35, 0xd9, 0xd0, 0x9c, 0x9c,
-	0x4f, 0xa0, 0xa3, 0x89, 0x8b, 0xdc, 0xdf, 0x26, 0x4e, 0x72, 0x7f, 0xab, 0x26, 0xd3, 0xfb, 0x53,
-	0xf1, 0x91, 0xfb, 0x5b, 0xa4, 0x4a, 0xee, 0x6f, 0x55, 0xec, 0x11, 0x2c, 0x13, 0xed, 0x31, 0xb2,
-	0xd0, 0x39, 0xdd, 0x3a, 0x5b, 0xf6, 0xa4, 0xc1, 0x94, 0xb7, 0x30, 0x99, 0x34, 0x11, 0x9b, 0x4c,
-	0xba, 0x76, 0x4b, 0xa6, 0x5c, 0x95, 0x26, 0x93, 0xa6, 0x68, 0x93, 0x49, 0x17, 0xf2, 0xc5, 0x62,
-	0x96, 0x7a, 0xf5, 0x2f, 0x00, 0x00, 0xff, 0xff, 0x92, 0xa7, 0x99, 0xc8, 0x62, 0x08, 0x00, 0x00,
+	// 697 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0x9c, 0x54, 0x4d, 0x6f, 0xd3, 0x40,
+	0x10, 0x55, 0x12, 0x68, 0xc3, 0x34, 0xfd, 0xc8, 0x36, 0x69, 0xd3, 0x6d, 0x5a, 0x82, 0x85, 0xaa,
+	0x22, 0xa1, 0x1e, 0xca, 0x89, 0x13, 0x22, 0x50, 0x68, 0xc5, 0x57, 0x71, 0xa5, 0x8a, 0x03, 0x17,
+	0xd7, 0x9e, 0xb6, 0x86, 0x74, 0x6d, 0x76, 0x37, 0xfd, 0xe0, 0xc2, 0xbf, 0xe3, 0x77, 0x21, 0xdb,
+	0x6b, 0xef, 0xda, 0x59, 0xab, 0x12, 0xb7, 0xec, 0xbc, 0x37, 0x6f, 0xc6, 0x33, 0x79, 0x03, 0xed,
+	0xeb, 0xab, 0xbd, 0x98, 0x47, 0x32, 0x22, 0xf3, 0xd7, 0x57, 0xe9, 0x0f, 0xe7, 0x06, 0xba, 0x47,
+	0x2c, 0x94, 0xa1, 0x37, 0x09, 0x7f, 0xa3, 0x8b, 0xbf, 0xa6, 0x28, 0x24, 0xa1, 0xd0, 0x0e, 0xce,
+	0x4e, 0x90, 0x5f, 0x23, 0x1f, 0x34, 0x46, 0x8d, 0xdd, 0x45, 0xb7, 0x78, 0x13, 0x07, 0x3a, 0x17,
+	0xc8, 0x50, 0x84, 0x62, 0x7c, 0x27, 0x51, 0x0c, 0x9a, 0xa3, 0xc6, 0x6e, 0xc7, 0x2d, 0xc5, 0x12,
+	0x0e, 0xb2, 0x8b, 0x90, 0xa1, 0xd2, 0x68, 0xa5, 0x1a, 0xa5, 0x98, 0xd3, 0x03, 0x62, 0x16, 0x16,
+	0x71, 0xc4, 0x04, 0x3a, 0x6b, 0xd0, 0x1b, 0x47, 0x91, 0x14, 0x92, 0x7b, 0x71, 0x1c, 0xb2, 0x0b,
+	0xd5, 0x91, 0xb3, 0x0e, 0xfd, 0x4a, 0x5c, 0x25, 0xf4, 0x61, 0x55, 0x03, 0x18, 0xe4, 0xfc, 0x92,
+	0x0e, 0x06, 0x05, 0xbd, 0x0b, 0xcb, 0x27, 0x97, 0x53, 0x19, 0x44, 0x37, 0x2c, 0xa7, 0x12, 0x58,
+	0xd1, 0x21, 0x45, 0x7b, 0x09, 0xfd, 0x37, 0x1c, 0x3d, 0x89, 0x87, 0x1e, 0x0b, 0x26, 0xc8, 0x45,
+	0x3e, 0x99, 0x11, 0x2c, 0xf8, 0x11, 0x63, 0xe8, 0xcb, 0x30, 0x62, 0x42, 0x0d, 0xc7, 0x0c, 0x39,
+	0xef, 0x60, 0xad, 0x9a, 0x9a, 0x89, 0x92, 0xe7, 0xd0, 0xbe, 0x54, 0xb1, 0x41, 0x63, 0xd4, 0xda,
+	0x5d, 0xd8, 0x5f, 0xd9, 0x53, 0x6b, 0xd8, 0x53, 0x64, 0xb7, 0x60, 0x38, 0x7f, 0x60, 0x5e, 0x05,
+	0xc9, 0x1a, 0xcc, 0xc5, 0x1c, 0xcf, 0xc3, 0xdb, 0xb4, 0xde, 0x23, 0x57, 0xbd, 0x92, 0x66, 0x26,
+	0x91, 0xff, 0xf3, 0x4b, 0x9c, 0x35, 0xd3, 0xcc, 0x9a, 0x31, 0x42, 0x49, 0xa6, 0x30, 0x57, 0xa0,
+	0x5e, 0xe9, 0x82, 0x6e, 0x25, 0xf7, 0xb2, 0x5d, 0x88, 0xc1, 0x83, 0x51, 0x2b, 0x5d, 0x90, 0x11,
+	0x73, 0x56, 0xa1, 0x3b, 0x9e, 0x86, 0x93, 0x60, 0x9c, 0x08, 0xe6, 0xc3, 0x3a, 0x05, 0x62, 0x06,
+	0xd5, 0x97, 0x2d, 0x41, 0x33, 0x0c, 0xd2, 0xe6, 0x3a, 0x6e, 0x33, 0x0c, 0x92, 0xff, 0x4f, 0xec,
+	0x71, 0x64, 0xf2, 0xe8, 0xad, 0xfa, 0x7f, 0x14, 0x6f, 0xd2, 0x83, 0x87, 0x67, 0xe9, 0x1f, 0xa7,
+	0x95, 0x02, 0xd9, 0xc3, 0x79, 0x06, 0xdd, 0x63, 0x8f, 0x0b, 0x34, 0x8b, 0x69, 0x6a, 0xc3, 0xa4,
+	0x7e, 0x03, 0x62, 0x52, 0xff, 0xa3, 0x85, 0x64, 0x2a, 0xd2, 0x93, 0x53, 0x51, 0x4c, 0x25, 0x7d,
+	0x39, 0x4f, 0x60, 0xf9, 0x3d, 0xca, 0x52, 0x0b, 0x15, 0x59, 0xe7, 0x3b, 0xac, 0x68, 0x8a, 0x2a,
+	0x6d, 0x96, 0x6a, 0xd4, 0x7d, 0x6d, 0xd3, 0xf8, 0x84, 0xda, 0x06, 0x76, 0xa0, 0x77, 0x82, 0xf2,
+	0x98, 0xe3, 0x39, 0x72, 0x64, 0x3e, 0xd6, 0x75, 0xb1, 0x0e, 0xfd, 0x0a, 0x4f, 0xbb, 0xe1, 0xa3,
+	0x27, 0xe4, 0x6b, 0xdf, 0xc7, 0x58, 0x6a, 0x37, 0xec, 0x40, 0xaf, 0x1c, 0xb6, 0x0f, 0xcd, 0x79,
+	0x0a, 0x24, 0xfd, 0xb4, 0x53, 0xe4, 0xe1, 0xf9, 0x5d, 0x5d, 0xf5, 0xc4, 0x72, 0x26, 0x4b, 0xd5,
+	0xce, 0x93, 0xb3, 0x2a, 0xf7, 0x25, 0xe7, 0xac, 0x4a, 0xb2, 0x8b, 0x3f, 0xd0, 0xbf, 0x37, 0x39,
+	0x67, 0x65, 0xc9, 0xfb, 0x7f, 0xe7, 0xa1, 0x79, 0xfa, 0x89, 0x1c, 0x00, 0xe8, 0x8b, 0x42, 0x68,
+	0xe1, 0xad, 0x99, 0xfb, 0x46, 0x37, 0xad, 0x98, 0x1a, 0xca, 0x67, 0x58, 0x2c, 0x9d, 0x1a, 0xb2,
+	0x55, 0xb0, 0x6d, 0xa7, 0x89, 0x6e, 0xd7, 0xc1, 0x4a, 0xef, 0x03, 0x74, 0xcc, 0x53, 0x44, 0x86,
+	0x16, 0x7e, 0xb1, 0x2a, 0xba, 0x55, 0x83, 0x2a, 0xb1, 0x57, 0xd0, 0xce, 0x8f, 0x15, 0x19, 0x14,
+	0xd4, 0xca, 0x49, 0xa3, 0x1b, 0x16, 0x44, 0x09, 0x7c, 0x85, 0xa5, 0xf2, 0x79, 0x22, 0xba, 0x7f,
+	0xeb, 0xc9, 0xa3, 0x8f, 0x6b, 0x71, 0x25, 0x79, 0x00, 0xa0, 0x6f, 0x82, 0x31, 0xf7, 0x99, 0xeb,
+	0x41, 0x37, 0xad, 0x98, 0x96, 0xd1, 0xbe, 0x36, 0x64, 0x66, 0xee, 0x02, 0xdd, 0xb4, 0x62, 0x7a,
+	0x42, 0xb9, 0x43, 0x8d, 0x09, 0x55, 0x7c, 0x4d, 0x37, 0x2c, 0x88, 0xde, 0x7f, 0xc9, 0x5c, 0xc6,
+	0xfe, 0x6d, 0xe6, 0xa4, 0xdb, 0x75, 0xb0, 0xde, 0xbf, 0x69, 0x3e, 0x63, 0xff, 0x16, 0xab, 0xd2,
+	0xad, 0x1a, 0x54, 0x89, 0x1d, 0xc2, 0x82, 0xe1, 0x3d, 0x62, 0x0c, 0x74, 0xc6, 0xb7, 0x74, 0x68,
+	0x07, 0x2b, 0x4a, 0x59, 0x89, 0xaa, 0x52, 0xc9, 0xc4, 0x74, 0x68, 0x07, 0x2b, 0x4a, 0x99, 0x2b,
+	0xab, 0x4a, 0x25, 0x47, 0xd3, 0xa1, 0x1d, 0xcc, 0x94, 0xce, 0xe6, 0x52, 0xe8, 0xc5, 0xbf, 0x01,
+	0x00, 0x12, 0x48, 0x45, 0xf6, 0xa8, 0x08, 0x00, 0x00,
 }
 
 // Reference imports to suppress errors if they are not otherwise used.
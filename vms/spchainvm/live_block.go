@@ -45,6 +45,9 @@ type LiveBlock struct {
 // ID returns the blkID
 func (lb *LiveBlock) ID() ids.ID { return lb.block.id }
 
+// NumTxs returns the number of transactions in this block
+func (lb *LiveBlock) NumTxs() int { return len(lb.block.txs) }
+
 // Accept is called when this block is finalized as accepted by consensus
 func (lb *LiveBlock) Accept() error {
 	bID := lb.ID()